@@ -1,19 +1,34 @@
 package gosseract
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
 	"encoding/xml"
+	"expvar"
+	"hash/crc32"
 	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/chennqqi/gosseract/v2/document"
 	. "github.com/otiai10/mint"
 )
 
 func TestMain(m *testing.M) {
+	// AutoTune benchmarks by children of the test binary.
+	RunAutoTuneChild()
 	beforeTest()
 	code := m.Run()
 	os.Exit(code)
@@ -34,6 +49,18 @@ func TestVersion(t *testing.T) {
 	Expect(t, version).Match("[0-9]{1}.[0-9]{1,2}(.[0-9a-z_-]*)?")
 }
 
+func TestBuildInfo(t *testing.T) {
+	info := BuildInfo()
+	Expect(t, info.Version).ToBe(Version())
+	Expect(t, info.ThreadLimit).ToBe(ThreadLimit())
+
+	client := NewClient()
+	defer client.Close()
+	Expect(t, client.OpenCLActive()).ToBe(info.OpenCL)
+	client.DisableOpenCL = true
+	Expect(t, client.OpenCLActive()).ToBe(false)
+}
+
 func TestClearPersistentCache(t *testing.T) {
 	client := NewClient()
 	defer client.Close()
@@ -41,6 +68,139 @@ func TestClearPersistentCache(t *testing.T) {
 	ClearPersistentCache()
 }
 
+func TestClient_Warmup(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+
+	err := client.Warmup(context.Background())
+	Expect(t, err).ToBe(nil)
+	Expect(t, client.shouldInit).ToBe(false)
+
+	client.SetImage("./test/data/001-helloworld.png")
+	err = client.Warmup(context.Background())
+	Expect(t, err).ToBe(nil)
+	text, err := client.Text()
+	Expect(t, err).ToBe(nil)
+	Expect(t, text).ToBe("Hello, World!")
+
+	When(t, "context is already done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := client.Warmup(ctx)
+		Expect(t, err).ToBe(context.Canceled)
+	})
+}
+
+func TestPreloadLanguages(t *testing.T) {
+	err := PreloadLanguages("eng")
+	Expect(t, err).ToBe(nil)
+	Expect(t, preloaded.languages["eng"]).ToBe(true)
+
+	err = PreloadLanguages()
+	Expect(t, err).Not().ToBe(nil)
+
+	err = PreloadLanguages("undefined-language")
+	Expect(t, err).Not().ToBe(nil)
+
+	ClearPersistentCache()
+	Expect(t, preloaded.languages["eng"]).ToBe(false)
+}
+
+func TestReclaim(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+
+	client.SetImage("./test/data/001-helloworld.png")
+	client.Preprocess.NormalizeBackground = true
+	_, err := client.Text()
+	Expect(t, err).ToBe(nil)
+
+	reclaimed := make(chan bool, 1)
+	OnReclaim(func() {
+		client.Reclaim()
+		select {
+		case reclaimed <- true:
+		default:
+		}
+	})
+	Reclaim()
+	<-reclaimed
+	Expect(t, client.preparedImage).ToBe(nil)
+
+	text, err := client.Text()
+	Expect(t, err).ToBe(nil)
+	Expect(t, text).ToBe("Hello, World!")
+
+	When(t, "resident memory exceeds the limit", func(t *testing.T) {
+		if _, err := residentMemory(); err != nil {
+			t.Skip(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-reclaimed
+			cancel()
+		}()
+		err := WatchMemory(ctx, 1, 10*time.Millisecond)
+		Expect(t, err).ToBe(context.Canceled)
+	})
+}
+
+func TestNativeStats(t *testing.T) {
+	before := NativeStats()
+
+	client := NewClient()
+	client.SetImage("./test/data/001-helloworld.png")
+	client.Preprocess.NormalizeBackground = true
+	_, err := client.Text()
+	Expect(t, err).ToBe(nil)
+
+	stats := NativeStats()
+	Expect(t, stats.Clients).ToBe(before.Clients + 1)
+	Expect(t, stats.PixImages).ToBe(before.PixImages + 2)
+	Expect(t, stats.PixImagesBytes > before.PixImagesBytes).ToBe(true)
+	Expect(t, stats.Iterators).ToBe(before.Iterators)
+
+	it, err := client.Iterator(RIL_WORD)
+	Expect(t, err).ToBe(nil)
+	Expect(t, NativeStats().Iterators).ToBe(before.Iterators + 1)
+	it.Close()
+	Expect(t, NativeStats().Iterators).ToBe(before.Iterators)
+	_, err = client.Iterator(RIL_WORD)
+	Expect(t, err).ToBe(nil)
+	Expect(t, NativeStats().Iterators).ToBe(before.Iterators + 1)
+
+	client.Close()
+	Expect(t, NativeStats()).ToBe(before)
+
+	Because(t, "it's published as an expvar", func(t *testing.T) {
+		PublishNativeStats("gosseract")
+		Expect(t, expvar.Get("gosseract")).Not().ToBe(nil)
+	})
+}
+
+func TestDetectLanguages(t *testing.T) {
+	data, err := os.ReadFile("./test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+
+	scores, err := DetectLanguages(data, []string{"eng"})
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(scores)).ToBe(1)
+	Expect(t, scores[0].Language).ToBe("eng")
+	Expect(t, scores[0].Score > 50).ToBe(true)
+
+	_, err = DetectLanguages(data, []string{"eng", "undefined-language"})
+	Expect(t, err).Not().ToBe(nil)
+
+	_, err = DetectLanguages(data, nil)
+	Expect(t, err).Not().ToBe(nil)
+}
+
+func TestSortLanguageScores(t *testing.T) {
+	scores := []LanguageScore{{"deu", 40}, {"eng", 90}, {"fra", 40}}
+	sortLanguageScores(scores)
+	Expect(t, scores).ToBe([]LanguageScore{{"eng", 90}, {"deu", 40}, {"fra", 40}})
+}
+
 func TestNewClient(t *testing.T) {
 	client := NewClient()
 	defer client.Close()
@@ -81,6 +241,161 @@ func TestClient_SetTessdataPrefix(t *testing.T) {
 	Expect(t, text).ToBe("Hello, World!")
 }
 
+func TestClient_Close(t *testing.T) {
+	client := NewClient()
+	client.SetImage("./test/data/001-helloworld.png")
+	_, err := client.Text()
+	Expect(t, err).ToBe(nil)
+
+	err = client.Close()
+	Expect(t, err).ToBe(nil)
+
+	Because(t, "nothing can touch the freed API after Close", func(t *testing.T) {
+		Expect(t, client.Close()).ToBe(ErrClientClosed)
+		Expect(t, client.SetImage("./test/data/001-helloworld.png")).ToBe(ErrClientClosed)
+		Expect(t, client.SetImageFromBytes([]byte("foo"))).ToBe(ErrClientClosed)
+		Expect(t, client.SetPageSegMode(PSM_SINGLE_BLOCK)).ToBe(ErrClientClosed)
+		Expect(t, client.SetWhitelist("HeloWrd,")).ToBe(ErrClientClosed)
+		Expect(t, client.Warmup(context.Background())).ToBe(ErrClientClosed)
+		_, err := client.Text()
+		Expect(t, err).ToBe(ErrClientClosed)
+		_, err = client.HOCRText()
+		Expect(t, err).ToBe(ErrClientClosed)
+		_, err = client.GetBoundingBoxes(RIL_WORD)
+		Expect(t, err).ToBe(ErrClientClosed)
+		_, err = client.GetBoundingBoxesVerbose()
+		Expect(t, err).ToBe(ErrClientClosed)
+		_, err = client.Iterator(RIL_WORD)
+		Expect(t, err).ToBe(ErrClientClosed)
+		Expect(t, client.SetThresholdingMethod(THRESHOLD_SAUVOLA)).ToBe(ErrClientClosed)
+		Expect(t, client.Version()).ToBe("")
+		client.Reclaim()
+	})
+
+	When(t, "clients are used and closed in parallel", func(t *testing.T) {
+		// Run with -race to check nothing is shared between clients.
+		done := make(chan error)
+		for i := 0; i < 4; i++ {
+			go func() {
+				client := NewClient()
+				defer client.Close()
+				client.SetImage("./test/data/001-helloworld.png")
+				client.Preprocess.NormalizeBackground = true
+				_, err := client.GetBoundingBoxes(RIL_WORD)
+				done <- err
+			}()
+		}
+		for i := 0; i < 4; i++ {
+			Expect(t, <-done).ToBe(nil)
+		}
+	})
+}
+
+func TestClient_Iterator(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+	client.SetImage("./test/data/001-helloworld.png")
+	it, err := client.Iterator(RIL_WORD)
+	Expect(t, err).ToBe(nil)
+	words := []string{}
+	for it.Next() {
+		word, err := it.Text()
+		Expect(t, err).ToBe(nil)
+		words = append(words, word)
+		box, err := it.BoundingBox()
+		Expect(t, err).ToBe(nil)
+		Expect(t, box.Empty()).ToBe(false)
+		confidence, err := it.Confidence()
+		Expect(t, err).ToBe(nil)
+		Expect(t, confidence > 0).ToBe(true)
+	}
+	Expect(t, it.Err()).ToBe(nil)
+	Expect(t, words).ToBe([]string{"Hello,", "World!"})
+	Expect(t, it.Close()).ToBe(nil)
+	_, err = it.Text()
+	Expect(t, err).ToBe(ErrIteratorReleased)
+
+	When(t, "the client recognizes again", func(t *testing.T) {
+		it, err := client.Iterator(RIL_WORD)
+		Expect(t, err).ToBe(nil)
+		defer it.Close()
+		Expect(t, it.Next()).ToBe(true)
+		_, err = client.Text()
+		Expect(t, err).ToBe(nil)
+		Expect(t, it.Next()).ToBe(false)
+		Expect(t, it.Err()).ToBe(ErrIteratorReleased)
+		_, err = it.Text()
+		Expect(t, err).ToBe(ErrIteratorReleased)
+	})
+
+	When(t, "iterators are used after Close of clients in parallel", func(t *testing.T) {
+		// Run with -race to check iterators never touch the results freed by Close.
+		done := make(chan error)
+		for i := 0; i < 4; i++ {
+			go func() {
+				client := NewClient()
+				client.SetImage("./test/data/001-helloworld.png")
+				it, err := client.Iterator(RIL_WORD)
+				if err != nil {
+					client.Close()
+					done <- err
+					return
+				}
+				defer it.Close()
+				it.Next()
+				client.Close()
+				if it.Next() {
+					done <- nil
+					return
+				}
+				_, err = it.BoundingBox()
+				done <- err
+			}()
+		}
+		for i := 0; i < 4; i++ {
+			Expect(t, <-done).ToBe(ErrClientClosed)
+		}
+	})
+}
+
+func TestTesseractError(t *testing.T) {
+	var err error = &TesseractError{Method: "Recognize", Message: "std::bad_alloc"}
+	Expect(t, err.Error()).ToBe("tesseract threw an exception in Recognize: std::bad_alloc")
+}
+
+func TestClient_CreateTemp(t *testing.T) {
+	client := NewClient()
+	client.TempDir = t.TempDir()
+
+	f, err := client.CreateTemp("*.config")
+	Expect(t, err).ToBe(nil)
+	_, err = f.WriteString("tessedit_char_whitelist HW\n")
+	Expect(t, err).ToBe(nil)
+	f.Close()
+	Expect(t, filepath.Dir(filepath.Dir(f.Name()))).ToBe(client.TempDir)
+
+	info, err := os.Stat(f.Name())
+	Expect(t, err).ToBe(nil)
+	if runtime.GOOS != "windows" {
+		Expect(t, info.Mode().Perm()).ToBe(os.FileMode(0600))
+	}
+
+	err = client.SetConfigFile(f.Name())
+	Expect(t, err).ToBe(nil)
+
+	client.Close()
+	_, err = os.Stat(f.Name())
+	Expect(t, os.IsNotExist(err)).ToBe(true)
+
+	Because(t, "no directory is left behind after Close", func(t *testing.T) {
+		_, err := client.CreateTemp("*.config")
+		Expect(t, err).ToBe(ErrClientClosed)
+		entries, err := os.ReadDir(client.TempDir)
+		Expect(t, err).ToBe(nil)
+		Expect(t, len(entries)).ToBe(0)
+	})
+}
+
 func TestClient_Version(t *testing.T) {
 	client := NewClient()
 	defer client.Close()
@@ -156,6 +471,169 @@ func TestClient_SetImageFromBytes(t *testing.T) {
 	})
 }
 
+// pngHeader returns the signature and the header chunk of PNG of the dimensions, without any pixels.
+func pngHeader(width, height uint32) []byte {
+	chunk := make([]byte, 17)
+	copy(chunk, "IHDR")
+	binary.BigEndian.PutUint32(chunk[4:], width)
+	binary.BigEndian.PutUint32(chunk[8:], height)
+	chunk[12], chunk[13] = 8, 2
+	data := append([]byte("\x89PNG\r\n\x1a\n"), 0, 0, 0, 13)
+	data = append(data, chunk...)
+	sum := make([]byte, 4)
+	binary.BigEndian.PutUint32(sum, crc32.ChecksumIEEE(chunk))
+	return append(data, sum...)
+}
+
+func TestClient_SetImageFromBytes_Malformed(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+	content, err := os.ReadFile("./test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+
+	for name, data := range map[string][]byte{
+		"garbage":   []byte("not an image at all"),
+		"truncated": content[:64],
+		"signature": content[:8],
+		"bomb":      pngHeader(1<<20, 1<<20),
+	} {
+		err := client.SetImageFromBytes(data)
+		invalid, ok := err.(*ImageError)
+		Expect(t, ok).ToBe(true)
+		Expect(t, invalid.TooLarge).ToBe(name == "bomb")
+		_, err = client.Text()
+		Expect(t, err).Not().ToBe(nil)
+	}
+
+	When(t, "MaxImagePixels is set", func(t *testing.T) {
+		client.MaxImagePixels = 100
+		err := client.SetImageFromBytes(content)
+		Expect(t, err.(*ImageError).TooLarge).ToBe(true)
+		client.MaxImagePixels = -1
+		Expect(t, client.SetImageFromBytes(content)).ToBe(nil)
+	})
+}
+
+func TestCheckDimensions(t *testing.T) {
+	Expect(t, checkDimensions(1000, 1000, 0)).ToBe(nil)
+	Expect(t, checkDimensions(0, 1000, 0).(*ImageError).TooLarge).ToBe(false)
+	Expect(t, checkDimensions(1<<16, 1<<16, 0).(*ImageError).TooLarge).ToBe(true)
+	Expect(t, checkDimensions(1<<16, 1<<16, -1)).ToBe(nil)
+	Expect(t, checkDimensions(11, 10, 100).(*ImageError).TooLarge).ToBe(true)
+
+	// Go decoders are guarded too.
+	Expect(t, grayscale(pngHeader(1<<20, 1<<20), 0) == nil).ToBe(true)
+	fp, err := ImageFingerprint(pngHeader(1<<20, 1<<20))
+	Expect(t, err).ToBe(nil)
+	Expect(t, fp.HasPerceptual).ToBe(false)
+}
+
+// FuzzClient_SetImageFromBytes feeds arbitrary data through Leptonica and tesseract, which must reject it
+// by errors rather than crash or hang. The corpus is in testdata/fuzz, run by "go test -fuzz FuzzClient_SetImageFromBytes".
+func FuzzClient_SetImageFromBytes(f *testing.F) {
+	for _, name := range []string{"001-helloworld.png", "002-confusing.png"} {
+		data, err := os.ReadFile(filepath.Join("./test/data", name))
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+		f.Add(data[:len(data)/2])
+	}
+	f.Add(pngHeader(1<<20, 1<<20))
+	f.Add(pngHeader(0, 0))
+
+	client := NewClient()
+	defer client.Close()
+	client.MaxImagePixels = 1 << 22
+	f.Fuzz(func(t *testing.T, data []byte) {
+		err := client.SetImageFromBytes(data)
+		if err != nil {
+			if _, ok := err.(*ImageError); !ok && len(data) != 0 {
+				t.Fatalf("unexpected error for %d bytes: %v", len(data), err)
+			}
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		client.TextWithOptions(ctx, data, Options{})
+	})
+}
+
+func TestClient_Preprocess(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+
+	client.SetImage("./test/data/001-helloworld.png")
+	client.SetPageSegMode(PSM_SINGLE_BLOCK)
+
+	When(t, "no document quadrilateral is found", func(t *testing.T) {
+		client.Preprocess.Rectify = true
+		text, err := client.Text()
+		Expect(t, err).ToBe(nil)
+		Expect(t, text).ToBe("Hello, World!")
+		Expect(t, client.preparedImage).ToBe(client.pixImage)
+	})
+
+	When(t, "background is normalized", func(t *testing.T) {
+		client.Preprocess = PreprocessOptions{NormalizeBackground: true}
+		text, err := client.Text()
+		Expect(t, err).ToBe(nil)
+		Expect(t, text).ToBe("Hello, World!")
+		Expect(t, client.preparedImage).Not().ToBe(client.pixImage)
+	})
+
+	When(t, "the image is larger than MaxDimension", func(t *testing.T) {
+		if os.Getenv("TESS_BOX_DISABLED") == "1" {
+			t.Skip()
+		}
+		client.Preprocess = PreprocessOptions{}
+		original, err := client.GetBoundingBoxes(RIL_WORD)
+		Expect(t, err).ToBe(nil)
+		client.Preprocess.MaxDimension = 600
+		boxes, err := client.GetBoundingBoxes(RIL_WORD)
+		Expect(t, err).ToBe(nil)
+		Expect(t, client.ImageScale() < 1).ToBe(true)
+		Expect(t, len(boxes)).ToBe(len(original))
+		for i := range boxes {
+			// mapped back to the original coordinates, within rounding errors of downscaling
+			Expect(t, abs(boxes[i].Box.Min.X-original[i].Box.Min.X) < 10).ToBe(true)
+			Expect(t, abs(boxes[i].Box.Max.Y-original[i].Box.Max.Y) < 10).ToBe(true)
+		}
+	})
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func TestClient_SetThresholdingMethod(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+
+	client.SetImage("./test/data/001-helloworld.png")
+	client.SetPageSegMode(PSM_SINGLE_BLOCK)
+
+	err := client.SetThresholdingMethod(THRESHOLD_SAUVOLA)
+	Expect(t, err).ToBe(nil)
+	text, err := client.Text()
+	Expect(t, err).ToBe(nil)
+	Expect(t, text).ToBe("Hello, World!")
+
+	err = client.SetThresholdingMethod(ThresholdingMethod(10))
+	Expect(t, err).Not().ToBe(nil)
+
+	Because(t, "tesseract 5 doesn't need gosseract's fallback", func(t *testing.T) {
+		if majorVersion(client.Version()) < 5 {
+			t.Skip()
+		}
+		Expect(t, client.Preprocess.Sauvola).ToBe(false)
+		Expect(t, client.Variables[THRESHOLDING_METHOD]).ToBe("2")
+	})
+}
+
 func TestClient_SetWhitelist(t *testing.T) {
 
 	if os.Getenv("TESS_LSTM_DISABLED") == "1" {
@@ -196,6 +674,527 @@ func TestClient_SetBlacklist(t *testing.T) {
 	Expect(t, text).Match("He(110|tto|o), Wor(I|t)?d!")
 }
 
+func TestClient_TextWithOptions(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+
+	data, err := os.ReadFile("./test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+
+	text, err := client.TextWithOptions(context.Background(), data, Options{Blacklist: "l"})
+	Expect(t, err).ToBe(nil)
+	Expect(t, text).Match("He(110|tto|o), Wor(I|t)?d!")
+	Expect(t, len(client.Variables)).ToBe(0)
+
+	When(t, "overrides are restored", func(t *testing.T) {
+		text, err := client.Text()
+		Expect(t, err).ToBe(nil)
+		Expect(t, text).ToBe("Hello, World!")
+	})
+
+	When(t, "languages are overridden", func(t *testing.T) {
+		_, err := client.TextWithOptions(context.Background(), data, Options{Languages: []string{"undefined-language"}})
+		Expect(t, err).Not().ToBe(nil)
+		Expect(t, client.Languages).ToBe([]string{"eng"})
+	})
+
+	When(t, "variable is unknown", func(t *testing.T) {
+		_, err := client.TextWithOptions(context.Background(), data, Options{Variables: map[SettableVariable]string{"undefined_variable": "1"}})
+		Expect(t, err).Not().ToBe(nil)
+	})
+
+	When(t, "context is already done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := client.TextWithOptions(ctx, data, Options{})
+		Expect(t, err).ToBe(context.Canceled)
+	})
+}
+
+func TestGrayscale(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	img.Set(0, 0, color.RGBA{255, 255, 255, 255})
+	img.Set(1, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(2, 0, color.RGBA{0, 0, 0, 255})
+	buf := bytes.NewBuffer(nil)
+	Expect(t, png.Encode(buf, img)).ToBe(nil)
+	Expect(t, string(grayscale(buf.Bytes(), 0))).ToBe("P5\n3 1\n255\n\xff\x4c\x00")
+
+	buf.Reset()
+	Expect(t, jpeg.Encode(buf, image.NewRGBA(image.Rect(0, 0, 16, 8)), nil)).ToBe(nil)
+	Expect(t, len(grayscale(buf.Bytes(), 0))).ToBe(len("P5\n16 8\n255\n") + 16*8)
+	// The limit of the client applies.
+	Expect(t, grayscale(buf.Bytes(), 100) == nil).ToBe(true)
+
+	When(t, "the image is grayscale already", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		Expect(t, png.Encode(buf, image.NewGray(image.Rect(0, 0, 3, 1)))).ToBe(nil)
+		Expect(t, grayscale(buf.Bytes(), 0) == nil).ToBe(true)
+	})
+
+	client := NewClient()
+	defer client.Close()
+	client.Grayscale = true
+	Expect(t, client.SetImage("./test/data/001-helloworld.png")).ToBe(nil)
+	text, err := client.Text()
+	Expect(t, err).ToBe(nil)
+	Expect(t, text).ToBe("Hello, World!")
+}
+
+func TestClient_RemoveGridLines(t *testing.T) {
+	// A grid of 3 x 2 cells, without text.
+	img := image.NewGray(image.Rect(0, 0, 300, 200))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for _, y := range []int{20, 100, 180} {
+		for x := 20; x < 280; x++ {
+			img.Pix[y*img.Stride+x], img.Pix[(y+1)*img.Stride+x] = 0, 0
+		}
+	}
+	for _, x := range []int{20, 106, 193, 279} {
+		for y := 20; y < 182; y++ {
+			img.Pix[y*img.Stride+x], img.Pix[y*img.Stride+x+1] = 0, 0
+		}
+	}
+	buf := bytes.NewBuffer(nil)
+	Expect(t, png.Encode(buf, img)).ToBe(nil)
+
+	client := NewClient()
+	defer client.Close()
+	client.Preprocess.RemoveGridLines = true
+	Expect(t, client.SetImageFromBytes(buf.Bytes())).ToBe(nil)
+	doc, err := client.Document()
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(doc.GridLines)).ToBe(1)
+	Expect(t, doc.GridLines[0]).ToBe(image.Rect(20, 20, 281, 182))
+}
+
+func TestClient_JoinDots(t *testing.T) {
+	// A dotted "L", which has no strokes to recognize without joining.
+	img := image.NewGray(image.Rect(0, 0, 120, 120))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	dot := func(x, y int) {
+		for dy := 0; dy < 4; dy++ {
+			for dx := 0; dx < 4; dx++ {
+				img.Pix[(y+dy)*img.Stride+x+dx] = 0
+			}
+		}
+	}
+	for i := 0; i < 7; i++ {
+		dot(40, 30+8*i)
+	}
+	for i := 1; i < 5; i++ {
+		dot(40+8*i, 78)
+	}
+	buf := bytes.NewBuffer(nil)
+	Expect(t, png.Encode(buf, img)).ToBe(nil)
+
+	client := NewClient()
+	defer client.Close()
+	Expect(t, client.ApplyPreset(PresetDotMatrix)).ToBe(nil)
+	Expect(t, client.SetImageFromBytes(buf.Bytes())).ToBe(nil)
+	Expect(t, client.preparedPixImage() != client.pixImage).ToBe(true)
+	_, err := client.Text()
+	Expect(t, err).ToBe(nil)
+}
+
+func TestClient_SevenSegment(t *testing.T) {
+	// A lit "1" of an LED display, i.e. two segments apart by a gap, light on dark.
+	img := image.NewGray(image.Rect(0, 0, 120, 120))
+	for _, top := range []int{20, 64} {
+		for y := top; y < top+36; y++ {
+			for x := 60; x < 66; x++ {
+				img.Pix[y*img.Stride+x] = 0xff
+			}
+		}
+	}
+	buf := bytes.NewBuffer(nil)
+	Expect(t, png.Encode(buf, img)).ToBe(nil)
+
+	client := NewClient()
+	defer client.Close()
+	Expect(t, client.ApplyPreset(PresetSevenSegment)).ToBe(nil)
+	Expect(t, client.Preprocess.SevenSegment).ToBe(true)
+	Expect(t, client.SetImageFromBytes(buf.Bytes())).ToBe(nil)
+	Expect(t, client.preparedPixImage() != client.pixImage).ToBe(true)
+	_, err := client.Text()
+	Expect(t, err).ToBe(nil)
+}
+
+func TestClient_Policy(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+	calls := 0
+	client.Policy = func(ctx context.Context, admission Admission) error {
+		calls++
+		Expect(t, admission.Width).Not().ToBe(0)
+		if admission.Metadata["key"] == "banned" {
+			return &PolicyError{Reason: "banned key"}
+		}
+		return nil
+	}
+	data, err := os.ReadFile("./test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+
+	ctx := WithMetadata(context.Background(), map[string]string{"key": "banned"})
+	_, err = client.TextWithOptions(ctx, data, Options{})
+	_, rejected := err.(*PolicyError)
+	Expect(t, rejected).ToBe(true)
+
+	ctx = WithMetadata(context.Background(), map[string]string{"key": "granted"})
+	text, err := client.TextWithOptions(ctx, data, Options{})
+	Expect(t, err).ToBe(nil)
+	Expect(t, text).ToBe("Hello, World!")
+	// Admitted images aren't asked again.
+	_, err = client.Text()
+	Expect(t, err).ToBe(nil)
+	Expect(t, calls).ToBe(2)
+}
+
+func TestRejectCaptchas(t *testing.T) {
+	ctx := context.Background()
+	Expect(t, RejectCaptchas(ctx, Admission{Width: 200, Height: 60, Entropy: 7.2})).Not().ToBe(nil)
+	Expect(t, RejectCaptchas(ctx, Admission{Width: 200, Height: 60, Entropy: 1.3})).ToBe(nil)
+	Expect(t, RejectCaptchas(ctx, Admission{Width: 2480, Height: 3508, Entropy: 7.2})).ToBe(nil)
+	Expect(t, MetadataFrom(ctx) == nil).ToBe(true)
+}
+
+func TestClient_ApplyPreset(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+
+	err := client.ApplyPreset(Options{PageSegMode: PSM_SINGLE_LINE, Whitelist: "HeloWrd, !"})
+	Expect(t, err).ToBe(nil)
+	Expect(t, client.Variables[TESSEDIT_CHAR_WHITELIST]).ToBe("HeloWrd, !")
+	Expect(t, client.Languages).ToBe([]string{"eng"})
+
+	client.SetImage("./test/data/001-helloworld.png")
+	text, err := client.Text()
+	Expect(t, err).ToBe(nil)
+	Expect(t, text).ToBe("Hello, World!")
+
+	When(t, "the preset changes languages", func(t *testing.T) {
+		err := client.ApplyPreset(PresetMICR)
+		Expect(t, err).ToBe(nil)
+		Expect(t, client.Languages).ToBe([]string{"mcr"})
+		Expect(t, client.Variables[TESSEDIT_CHAR_WHITELIST]).ToBe("0123456789ABCD")
+	})
+
+	When(t, "the client has preprocessing of its own", func(t *testing.T) {
+		client := NewClient()
+		defer client.Close()
+		client.Preprocess.MaxDimension = 2000
+		err := client.ApplyPreset(PresetDotMatrix)
+		Expect(t, err).ToBe(nil)
+		Expect(t, client.Preprocess.MaxDimension).ToBe(2000)
+		Expect(t, client.Preprocess.JoinDots).ToBe(true)
+		Expect(t, client.Preprocess.Contrast).ToBe(true)
+	})
+}
+
+func TestConfig_Build(t *testing.T) {
+	data, err := os.ReadFile("./test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+
+	rec, err := Config{Trim: true, PageSegMode: PSM_SINGLE_LINE}.Build()
+	Expect(t, err).ToBe(nil)
+	defer rec.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			text, err := rec.Text(data)
+			Expect(t, err).ToBe(nil)
+			Expect(t, text).ToBe("Hello, World!")
+		}()
+	}
+	wg.Wait()
+
+	When(t, "language is unknown", func(t *testing.T) {
+		_, err := Config{Languages: []string{"undefined-language"}}.Build()
+		Expect(t, err).Not().ToBe(nil)
+	})
+}
+
+func TestConfig_JSON(t *testing.T) {
+	cfg := Config{Languages: []string{"eng"}, Policy: func(ctx context.Context, admission Admission) error { return nil }}
+	b, err := json.Marshal(cfg)
+	Expect(t, err).ToBe(nil)
+	decoded := Config{}
+	Expect(t, json.Unmarshal(b, &decoded)).ToBe(nil)
+	Expect(t, decoded.Languages).ToBe([]string{"eng"})
+	Expect(t, decoded.Policy == nil).ToBe(true)
+
+	Because(t, "AutoTune sends the configuration to its children", func(t *testing.T) {
+		_, err := json.Marshal(autoTuneRequest{Config: cfg, Samples: [][]byte{{1}}})
+		Expect(t, err).ToBe(nil)
+	})
+}
+
+func TestConfig_BuildPool(t *testing.T) {
+	data, err := os.ReadFile("./test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+
+	pool, err := Config{Trim: true, PageSegMode: PSM_SINGLE_LINE, Tuning: Tuning{Clients: 2}}.BuildPool()
+	Expect(t, err).ToBe(nil)
+	Expect(t, pool.Size()).ToBe(2)
+
+	result, err := benchmarkPool(context.Background(), pool, [][]byte{data})
+	Expect(t, err).ToBe(nil)
+	Expect(t, result.Pages).ToBe(4)
+	Expect(t, pool.Close()).ToBe(nil)
+
+	_, err = pool.TextWithOptions(context.Background(), data, Options{})
+	Expect(t, err).ToBe(ErrClientClosed)
+
+	When(t, "OMP_THREAD_LIMIT isn't set as tuned", func(t *testing.T) {
+		_, err := Config{Tuning: Tuning{Clients: 1, Threads: ThreadLimit() + 1}}.BuildPool()
+		Expect(t, err).Not().ToBe(nil)
+	})
+}
+
+func TestPool_Priority(t *testing.T) {
+	pool, err := Config{Tuning: Tuning{Clients: 1}}.BuildPool()
+	Expect(t, err).ToBe(nil)
+	defer pool.Close()
+	held, err := pool.acquire(context.Background())
+	Expect(t, err).ToBe(nil)
+
+	// A backfill waiting first, then users waiting for the pool.
+	order := make(chan string, 8)
+	var wg sync.WaitGroup
+	wait := func(name string, ctx context.Context) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec, err := pool.acquire(ctx)
+			Expect(t, err).ToBe(nil)
+			order <- name
+			pool.release(rec)
+		}()
+		time.Sleep(10 * time.Millisecond)
+	}
+	wait("batch", WithPriority(context.Background(), PriorityBatch))
+	for _, name := range []string{"user1", "user2", "user3", "user4", "user5", "user6"} {
+		wait(name, context.Background())
+	}
+	pool.release(held)
+	wg.Wait()
+	close(order)
+	got := []string{}
+	for name := range order {
+		got = append(got, name)
+	}
+	Expect(t, got).ToBe([]string{"user1", "user2", "user3", "user4", "batch", "user5", "user6"})
+
+	When(t, "waiting is cancelled", func(t *testing.T) {
+		held, err := pool.acquire(context.Background())
+		Expect(t, err).ToBe(nil)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = pool.acquire(WithPriority(ctx, PriorityBatch))
+		Expect(t, err).ToBe(context.DeadlineExceeded)
+		pool.release(held)
+		Expect(t, len(pool.idle)).ToBe(1)
+	})
+}
+
+func TestPool_Reclaim(t *testing.T) {
+	data, err := os.ReadFile("./test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+	dir := t.TempDir()
+	pool, err := Config{Trim: true, PageSegMode: PSM_SINGLE_LINE, TempDir: dir, Tuning: Tuning{Clients: 2}}.BuildPool()
+	Expect(t, err).ToBe(nil)
+	Expect(t, pool.idle[0].client.TempDir).ToBe(dir)
+
+	Reclaim()
+	Expect(t, len(pool.idle)).ToBe(0)
+	Expect(t, pool.built).ToBe(0)
+	Because(t, "Recognizers are built again on demand", func(t *testing.T) {
+		text, err := pool.TextWithOptions(context.Background(), data, Options{})
+		Expect(t, err).ToBe(nil)
+		Expect(t, text).ToBe("Hello, World!")
+		Expect(t, pool.built).ToBe(1)
+	})
+	Because(t, "Warmup builds the rest of them ahead", func(t *testing.T) {
+		Expect(t, pool.Warmup(context.Background())).ToBe(nil)
+		Expect(t, pool.built).ToBe(2)
+		Expect(t, len(pool.idle)).ToBe(2)
+	})
+
+	hooks := len(reclaimHooks.funcs)
+	Expect(t, pool.Close()).ToBe(nil)
+	Expect(t, len(reclaimHooks.funcs)).ToBe(hooks - 1)
+	Expect(t, pool.Warmup(context.Background())).ToBe(ErrClientClosed)
+}
+
+// confidentFunc is a ConfidentRecognizer answering after the delay.
+type confidentFunc struct {
+	text       string
+	confidence float64
+	delay      time.Duration
+}
+
+func (f confidentFunc) TextWithConfidence(ctx context.Context, data []byte, opts Options) (string, float64, error) {
+	select {
+	case <-time.After(f.delay):
+		return f.text, f.confidence, nil
+	case <-ctx.Done():
+		return "", 0, ctx.Err()
+	}
+}
+
+func TestSpeculate(t *testing.T) {
+	ctx := context.Background()
+	fast := confidentFunc{text: "fast", confidence: 85}
+	best := confidentFunc{text: "best", confidence: 95, delay: time.Second}
+
+	result, err := Speculate(ctx, fast, best, nil, Options{}, 80)
+	Expect(t, err).ToBe(nil)
+	Expect(t, result.Text).ToBe("fast")
+	Expect(t, result.Fast).ToBe(true)
+
+	When(t, "the fast isn't confident enough", func(t *testing.T) {
+		best.delay = 10 * time.Millisecond
+		result, err := Speculate(ctx, fast, best, nil, Options{}, 90)
+		Expect(t, err).ToBe(nil)
+		Expect(t, result.Text).ToBe("best")
+		Expect(t, result.Confidence).ToBe(95.0)
+	})
+}
+
+func TestTuning(t *testing.T) {
+	Expect(t, TuningCandidates(8)).ToBe([]Tuning{{8, 1}, {4, 2}, {2, 4}})
+	Expect(t, TuningCandidates(2)).ToBe([]Tuning{{2, 1}, {1, 2}})
+	Expect(t, TuningCandidates(1)).ToBe([]Tuning{{1, 1}})
+	Expect(t, Tuning{Clients: 2, Threads: 4}.Environ()).ToBe([]string{"OMP_THREAD_LIMIT=4"})
+	Expect(t, len(Tuning{}.Environ())).ToBe(0)
+	Expect(t, Tuning{Threads: 1}.resolve().Clients).ToBe(runtime.NumCPU())
+}
+
+func TestAutoTune(t *testing.T) {
+	data, err := os.ReadFile("./test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+	candidates := []Tuning{{Clients: 2, Threads: 1}, {Clients: 1, Threads: 2}}
+	cfg := Config{Trim: true, PageSegMode: PSM_SINGLE_LINE, Policy: func(ctx context.Context, admission Admission) error { return nil }}
+	best, results, err := AutoTune(context.Background(), cfg, [][]byte{data, data}, candidates)
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(results)).ToBe(2)
+	// Samples are repeated to keep all the clients busy twice.
+	pages := []int{4, 2}
+	for i, result := range results {
+		Expect(t, result.Tuning).ToBe(candidates[i])
+		Expect(t, result.Pages).ToBe(pages[i])
+		Expect(t, result.PagesPerSecond > 0).ToBe(true)
+	}
+	Expect(t, best == candidates[0] || best == candidates[1]).ToBe(true)
+
+	When(t, "no sample is given", func(t *testing.T) {
+		_, _, err := AutoTune(context.Background(), cfg, nil, candidates)
+		Expect(t, err).Not().ToBe(nil)
+	})
+}
+
+func TestClient_Document(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+
+	client.SetImage("./test/data/001-helloworld.png")
+	doc, err := client.Document()
+	Expect(t, err).ToBe(nil)
+	Expect(t, doc.Text()).ToBe("Hello, World!")
+	Expect(t, len(doc.Blocks)).ToBe(1)
+	Expect(t, len(doc.Words())).ToBe(2)
+
+	Because(t, "the outline of the block surrounds its words", func(t *testing.T) {
+		block := doc.Blocks[0]
+		Expect(t, len(block.Polygon) >= 4).ToBe(true)
+		for _, word := range doc.Words() {
+			Expect(t, word.Box.In(block.Box)).ToBe(true)
+		}
+	})
+}
+
+func TestBuildDocument(t *testing.T) {
+	doc := buildDocument([]layoutElement{
+		{level: RIL_BLOCK, polygon: []image.Point{{0, 0}, {10, 0}, {10, 10}}},
+		{level: RIL_PARA},
+		{level: RIL_TEXTLINE},
+		{level: RIL_WORD, text: "foo", dictionary: true},
+		{level: RIL_WORD, text: "bar"},
+		{level: RIL_TEXTLINE},
+		{level: RIL_WORD, text: "42", numeric: true},
+	})
+	Expect(t, doc.Text()).ToBe("foo bar\n42")
+	Expect(t, len(doc.Blocks[0].Polygon)).ToBe(3)
+	words := doc.Words()
+	Expect(t, words[0].FromDictionary).ToBe(true)
+	Expect(t, words[1].FromDictionary).ToBe(false)
+	Expect(t, words[2].Numeric).ToBe(true)
+
+	When(t, "elements have no parents", func(t *testing.T) {
+		doc := buildDocument([]layoutElement{{level: RIL_WORD, text: "foo"}})
+		Expect(t, doc.Text()).ToBe("foo")
+	})
+}
+
+func TestDropUnrecognized(t *testing.T) {
+	doc := buildDocument([]layoutElement{
+		{level: RIL_BLOCK},
+		{level: RIL_PARA},
+		{level: RIL_TEXTLINE},
+		{level: RIL_WORD, text: "foo"},
+		{level: RIL_WORD, text: " "},
+		{level: RIL_TEXTLINE},
+		{level: RIL_WORD, text: "  "},
+		{level: RIL_BLOCK},
+		{level: RIL_PARA},
+		{level: RIL_TEXTLINE},
+		{level: RIL_WORD, text: ""},
+		{level: RIL_BLOCK, blockType: document.BlockFlowingImage},
+	})
+	dropUnrecognized(doc)
+	Expect(t, doc.Text()).ToBe("foo")
+	Expect(t, len(doc.Blocks)).ToBe(2)
+	Expect(t, len(doc.Blocks[0].Paragraphs[0].Lines)).ToBe(1)
+	Expect(t, doc.Blocks[1].Type).ToBe(document.BlockFlowingImage)
+}
+
+func TestClient_DocumentWithContext(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+	Expect(t, client.SetImage("./test/data/001-helloworld.png")).ToBe(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	doc, err := client.DocumentWithContext(ctx)
+	Expect(t, err).ToBe(context.Canceled)
+	Expect(t, doc.Partial).ToBe(true)
+
+	doc, err = client.DocumentWithContext(context.Background())
+	Expect(t, err).ToBe(nil)
+	Expect(t, doc.Partial).ToBe(false)
+	Expect(t, doc.Text()).ToBe("Hello, World!")
+}
+
+func TestRotatedQuad(t *testing.T) {
+	Expect(t, rotatedQuad(image.Rect(0, 0, 100, 20), 0)).ToBe([4]image.Point{{0, 0}, {100, 0}, {100, 20}, {0, 20}})
+	Expect(t, rotatedQuad(image.Rect(0, 0, 20, 100), 90)).ToBe([4]image.Point{{0, 100}, {0, 0}, {20, 0}, {20, 100}})
+	Expect(t, rotatedQuad(image.Rect(0, 0, 97, 67), 30)).ToBe([4]image.Point{{0, 51}, {87, 0}, {97, 16}, {10, 67}})
+
+	When(t, "baseline is skewed", func(t *testing.T) {
+		angle := wordAngle(document.OrientationPageUp, 0, []image.Point{{0, 10}, {100, 0}})
+		Expect(t, angle > 5 && angle < 6).ToBe(true)
+		Expect(t, wordAngle(document.OrientationPageLeft, 0, []image.Point{{0, 100}, {0, 0}})).ToBe(90.0)
+		Expect(t, wordAngle(document.OrientationPageDown, 0, nil)).ToBe(180.0)
+	})
+}
+
 func TestClient_SetLanguage(t *testing.T) {
 	client := NewClient()
 	defer client.Close()
@@ -315,9 +1314,230 @@ func TestClient_HTML(t *testing.T) {
 	})
 }
 
+func TestImageFingerprint(t *testing.T) {
+	data, err := ioutil.ReadFile("./test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+	fp, err := ImageFingerprint(data)
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(fp.Content)).ToBe(64)
+	Expect(t, fp.HasPerceptual).ToBe(true)
+
+	When(t, "the same page is re-encoded", func(t *testing.T) {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		Expect(t, err).ToBe(nil)
+		buf := bytes.NewBuffer(nil)
+		jpeg.Encode(buf, img, &jpeg.Options{Quality: 50})
+		reencoded, err := ImageFingerprint(buf.Bytes())
+		Expect(t, err).ToBe(nil)
+		Expect(t, reencoded.Content).Not().ToBe(fp.Content)
+		Expect(t, fp.Distance(reencoded) <= 10).ToBe(true)
+	})
+
+	When(t, "it's another page", func(t *testing.T) {
+		data, err := ioutil.ReadFile("./test/data/003-longer-text.png")
+		Expect(t, err).ToBe(nil)
+		other, err := ImageFingerprint(data)
+		Expect(t, err).ToBe(nil)
+		Expect(t, fp.Distance(other) > 10).ToBe(true)
+	})
+
+	When(t, "the format is not decodable by Go", func(t *testing.T) {
+		fp, err := ImageFingerprint([]byte("II*\x00"))
+		Expect(t, err).ToBe(nil)
+		Expect(t, fp.HasPerceptual).ToBe(false)
+		Expect(t, fp.Distance(fp)).ToBe(-1)
+	})
+
+	_, err = ImageFingerprint(nil)
+	Expect(t, err).Not().ToBe(nil)
+}
+
 func TestGetAvailableLangs(t *testing.T) {
 	t.Skip("TODO")
 	// langs, err := GetAvailableLanguages()
 	// Expect(t, err).ToBe(nil)
 	// Expect(t, len(langs)).ToBe(1) // eng only
 }
+
+func TestConstraint(t *testing.T) {
+	symbol := func(word int, choices ...Choice) Symbol { return Symbol{Word: word, Choices: choices} }
+	symbols := []Symbol{
+		symbol(0, Choice{"A", 95}), symbol(0, Choice{"8", 90}, Choice{"B", 60}),
+		symbol(0, Choice{"O", 80}, Choice{"0", 40}), symbol(0, Choice{"1", 90}),
+		symbol(1, Choice{"2", 90}), symbol(1, Choice{"3", 90}), symbol(1, Choice{"4", 90}),
+		symbol(1, Choice{"S", 90}, Choice{"5", 30}),
+	}
+	text, confidence, ok := MustConstraint(`[A-Z]{2}\d{6}`).Decode(symbols)
+	Expect(t, ok).ToBe(true)
+	Expect(t, text).ToBe("AB012345")
+	Expect(t, confidence).ToBe((95 + 60 + 40 + 90 + 90 + 90 + 90 + 30) / 8.0)
+
+	When(t, "the constraint allows a space between words", func(t *testing.T) {
+		text, _, ok := MustConstraint(`[A-Z]{2}\d{2} \d{4}`).Decode(symbols)
+		Expect(t, ok).ToBe(true)
+		Expect(t, text).ToBe("AB01 2345")
+	})
+
+	_, _, ok = MustConstraint(`\d+`).Decode(symbols)
+	Expect(t, ok).ToBe(false)
+	_, _, err := decodeSymbols(MustConstraint(`\d+`), symbols)
+	Expect(t, err).ToBe(ErrNoMatch)
+
+	_, err = NewConstraint(`[A-Z`)
+	Expect(t, err).Not().ToBe(nil)
+	Expect(t, MustConstraint(`\d+`).String()).ToBe(`\d+`)
+	Expect(t, Options{Constraint: `\d+`}.variables()[LSTM_CHOICE_MODE]).ToBe("2")
+}
+
+func TestClient_DecodeText(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+	client.SetImage("./test/data/001-helloworld.png")
+
+	symbols, err := client.Symbols()
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(symbols)).ToBe(len("Hello,World!"))
+	Expect(t, symbols[len(symbols)-1].Word).ToBe(1)
+
+	text, _, err := client.DecodeText(MustConstraint(`[A-Za-z]+, [A-Za-z]+!`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, text).ToBe("Hello, World!")
+	_, _, err = client.DecodeText(MustConstraint(`\d+`))
+	Expect(t, err).ToBe(ErrNoMatch)
+
+	data, err := os.ReadFile("./test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+	text, err = client.TextWithOptions(context.Background(), data, Options{Constraint: `Hello, [A-Z][a-z]+!`})
+	Expect(t, err).ToBe(nil)
+	Expect(t, text).ToBe("Hello, World!")
+}
+
+func TestClient_TextOfZones(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+	client.SetImage("./test/data/001-helloworld.png")
+
+	texts, err := client.TextOfZones(context.Background(), []Zone{
+		{Name: "hello", Rect: image.Rect(0, 0, 587, 236), Options: Options{PageSegMode: PSM_SINGLE_WORD}},
+		{Name: "world", Rect: image.Rect(587, 0, 1174, 236), Options: Options{PageSegMode: PSM_SINGLE_WORD}},
+		{Name: "outside", Rect: image.Rect(2000, 0, 2100, 100)},
+	})
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(texts)).ToBe(3)
+	Expect(t, texts[0].Name).ToBe("hello")
+	Expect(t, texts[0].Err).ToBe(nil)
+	Expect(t, strings.Contains(texts[0].Text, "Hello")).ToBe(true)
+	Expect(t, texts[1].Err).ToBe(nil)
+	Expect(t, strings.Contains(texts[1].Text, "World")).ToBe(true)
+	Expect(t, texts[2].Err).Not().ToBe(nil)
+
+	When(t, "zones override the thresholded image", func(t *testing.T) {
+		texts, err := client.TextOfZones(context.Background(), []Zone{
+			{Name: "preprocessed", Rect: image.Rect(0, 0, 587, 236), Options: Options{Preprocess: &PreprocessOptions{}}},
+			{Name: "thresholded", Rect: image.Rect(0, 0, 587, 236), Options: Options{Variables: map[SettableVariable]string{THRESHOLDING_METHOD: "2"}}},
+		})
+		Expect(t, err).ToBe(nil)
+		Expect(t, texts[0].Err).Not().ToBe(nil)
+		Expect(t, texts[1].Err).Not().ToBe(nil)
+	})
+
+	When(t, "the thresholding changes", func(t *testing.T) {
+		with := client.thresholdedWith
+		Expect(t, client.SetVariable("thresholding_kfactor", "0.5")).ToBe(nil)
+		_, err := client.TextOfZones(context.Background(), []Zone{{Name: "hello", Rect: image.Rect(0, 0, 587, 236)}})
+		Expect(t, err).ToBe(nil)
+		Expect(t, client.thresholdedWith == with).ToBe(false)
+	})
+
+	When(t, "the whole image is recognized after zones", func(t *testing.T) {
+		text, err := client.Text()
+		Expect(t, err).ToBe(nil)
+		Expect(t, text).ToBe("Hello, World!")
+	})
+}
+
+func TestClient_Recorder(t *testing.T) {
+	client := NewClient()
+	defer client.Close()
+	client.Trim = true
+	var recorded *Bundle
+	client.Recorder = func(ctx context.Context, bundle *Bundle) { recorded = bundle }
+	data, err := os.ReadFile("./test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+	ctx := WithMetadata(context.Background(), map[string]string{"user": "alice"})
+	text, err := client.TextWithOptions(ctx, data, Options{PageSegMode: PSM_SINGLE_LINE, Whitelist: "HeloWrd, !"})
+	Expect(t, err).ToBe(nil)
+	Expect(t, recorded.Text).ToBe(text)
+	Expect(t, recorded.Build.Version).ToBe(client.Version())
+	Expect(t, recorded.Options.Whitelist).ToBe("HeloWrd, !")
+	Expect(t, recorded.Metadata["user"]).ToBe("alice")
+	Expect(t, recorded.Trim).ToBe(true)
+	Expect(t, bytes.Equal(recorded.Image, data)).ToBe(true)
+
+	buf := bytes.NewBuffer(nil)
+	Expect(t, recorded.Write(buf)).ToBe(nil)
+	bundle, err := ReadBundle(buf)
+	Expect(t, err).ToBe(nil)
+	replayed, err := Replay(context.Background(), bundle)
+	Expect(t, err).ToBe(nil)
+	Expect(t, replayed.Text).ToBe(recorded.Text)
+	Expect(t, replayed.PageSegMode).ToBe(recorded.PageSegMode)
+
+	_, err = ReadBundle(strings.NewReader(`{"text":"no image"}`))
+	Expect(t, err).Not().ToBe(nil)
+}
+
+func TestRecordFailures(t *testing.T) {
+	dir := t.TempDir()
+	record := RecordFailures(dir, 60)
+	record(context.Background(), &Bundle{Image: []byte("image"), Confidence: 90})
+	record(context.Background(), &Bundle{Image: []byte("image"), Confidence: 30})
+	record(context.Background(), &Bundle{Image: []byte("other"), Confidence: 90, Err: "failed", Recorded: time.Now()})
+	entries, err := os.ReadDir(dir)
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(entries)).ToBe(2)
+	file, err := os.Open(filepath.Join(dir, entries[0].Name()))
+	Expect(t, err).ToBe(nil)
+	defer file.Close()
+	bundle, err := ReadBundle(file)
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(bundle.Image) != 0).ToBe(true)
+}
+
+func TestLanguageVariables(t *testing.T) {
+	defer delete(languageVariables.registered, "chi_sim")
+	defer delete(languageVariables.registered, "deu")
+	Expect(t, LanguageVariables("chi_sim")["preserve_interword_spaces"]).ToBe("1")
+	Expect(t, len(LanguageVariables("eng"))).ToBe(0)
+
+	RegisterLanguageVariables("chi_sim", map[SettableVariable]string{"chop_enable": "1"})
+	RegisterLanguageVariables("deu", map[SettableVariable]string{"chop_enable": "0", "textord_heavy_nr": "0"})
+	vars := LanguageVariables("chi_sim")
+	Expect(t, vars["chop_enable"]).ToBe("1")
+	Expect(t, vars["preserve_interword_spaces"]).ToBe("1")
+	vars = LanguageVariables("deu", "chi_sim")
+	Expect(t, vars["chop_enable"]).ToBe("0")
+	Expect(t, vars["textord_heavy_nr"]).ToBe("0")
+	Expect(t, vars["preserve_interword_spaces"]).ToBe("1")
+}
+
+func TestClient_LanguageVariables(t *testing.T) {
+	defer delete(languageVariables.registered, "eng")
+	RegisterLanguageVariables("eng", map[SettableVariable]string{"user_words_suffix": "from-language", "unknown_variable": "x"})
+	client := NewClient()
+	defer client.Close()
+	Expect(t, client.SetImage("./test/data/001-helloworld.png")).ToBe(nil)
+	_, err := client.Text()
+	Expect(t, err).ToBe(nil)
+	value, _ := client.getAPIVariable("user_words_suffix")
+	Expect(t, value).ToBe("from-language")
+
+	When(t, "the client sets the variable", func(t *testing.T) {
+		client.SetVariable("user_words_suffix", "from-client")
+		client.SetLanguage("eng")
+		_, err := client.Text()
+		Expect(t, err).ToBe(nil)
+		value, _ := client.getAPIVariable("user_words_suffix")
+		Expect(t, value).ToBe("from-client")
+	})
+}
@@ -0,0 +1,263 @@
+// Package archive persists recognized Documents keyed by the hashes of their images, with full-text search
+// over the text recognized, turning OCR results into a small searchable archive for desktop digitization tools.
+//
+// Records are persisted in an embedded key-value Store, such as of BoltDB or SQLite plugged in by OpenStore,
+// and indexed in memory. Open uses File, a single file of JSON lines needing no database, which suits archives
+// of up to hundreds of thousands of pages: all the records and their index are held in memory, taking about
+// twice the size of the records in JSON.
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/chennqqi/gosseract/v2/document"
+)
+
+// Store is the embedded key-value store records are persisted in, keyed by their hashes, with the values
+// of records in JSON. Changes must be durable once Set or Delete returns.
+type Store interface {
+	// Load calls fn with each key and value stored.
+	Load(fn func(key string, value []byte)) error
+	// Set stores the value of the key, replacing the one of the key, if any.
+	Set(key string, value []byte) error
+	// Delete removes the value of the key, if any.
+	Delete(key string) error
+	Close() error
+}
+
+// Record is a Document archived.
+type Record struct {
+	// Hash is the hex-encoded SHA-256 of the image data, the same as gosseract.Fingerprint.Content.
+	Hash string `json:"hash"`
+
+	// Name of the image, such as the path of the file scanned.
+	Name string `json:"name,omitempty"`
+
+	Added    time.Time          `json:"added"`
+	Document *document.Document `json:"document"`
+}
+
+// Hit is a Record matching a search.
+type Hit struct {
+	Record
+	// Score is the relevance by TF-IDF, higher first.
+	Score float64
+	// Snippet is the first line of the document matching the query.
+	Snippet string
+}
+
+// Archive is the archive of a Store. It's safe to share among goroutines.
+type Archive struct {
+	mu      sync.RWMutex
+	store   Store
+	records map[string]*Record
+	// terms maps terms to the hashes of the records containing them, with their frequencies
+	terms map[string]map[string]int
+}
+
+// Open opens the archive of the File of the path, creating it if it doesn't exist.
+// It's due to caller to Close the Archive.
+func Open(path string) (*Archive, error) {
+	file, err := OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	a, err := OpenStore(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+// OpenStore opens the archive of the store, loading and indexing all the records. Values which aren't records
+// are ignored. It's due to caller to Close the Archive, which closes the store.
+func OpenStore(store Store) (*Archive, error) {
+	a := &Archive{store: store, records: map[string]*Record{}, terms: map[string]map[string]int{}}
+	err := store.Load(func(key string, value []byte) {
+		record := &Record{}
+		if json.Unmarshal(value, record) != nil || record.Document == nil {
+			return
+		}
+		record.Hash = key
+		a.unindex(key)
+		a.index(record)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load archive: %v", err)
+	}
+	return a, nil
+}
+
+// Hash returns the hash of the image data archives key the documents by.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Put archives the document recognized from the image data, replacing the one of the same image, if any.
+// It returns the hash of the image.
+func (a *Archive) Put(data []byte, name string, doc *document.Document) (string, error) {
+	if doc == nil {
+		return "", fmt.Errorf("failed to archive %s: no document", name)
+	}
+	record := &Record{Hash: Hash(data), Name: name, Added: time.Now().UTC(), Document: doc}
+	value, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.store.Set(record.Hash, value); err != nil {
+		return "", err
+	}
+	a.unindex(record.Hash)
+	a.index(record)
+	return record.Hash, nil
+}
+
+// Get returns the record of the hash, see Hash.
+func (a *Archive) Get(hash string) (Record, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	record, ok := a.records[hash]
+	if !ok {
+		return Record{}, false
+	}
+	return *record, true
+}
+
+// Delete removes the record of the hash, if any.
+func (a *Archive) Delete(hash string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.records[hash]; !ok {
+		return nil
+	}
+	if err := a.store.Delete(hash); err != nil {
+		return err
+	}
+	a.unindex(hash)
+	return nil
+}
+
+// Len returns the number of records.
+func (a *Archive) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.records)
+}
+
+// Search returns the records containing all the words of the query, case-insensitively, most relevant first,
+// as many as limit at most, or all of them if limit is zero.
+func (a *Archive) Search(query string, limit int) []Hit {
+	words := terms(query)
+	if len(words) == 0 {
+		return nil
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	scores := map[string]float64{}
+	for i, word := range words {
+		postings := a.terms[word]
+		idf := math.Log(1 + float64(len(a.records))/float64(len(postings)+1))
+		next := map[string]float64{}
+		for hash, count := range postings {
+			if score, ok := scores[hash]; ok || i == 0 {
+				next[hash] = score + (1+math.Log(float64(count)))*idf
+			}
+		}
+		scores = next
+	}
+	hits := make([]Hit, 0, len(scores))
+	for hash, score := range scores {
+		record := a.records[hash]
+		hits = append(hits, Hit{Record: *record, Score: score, Snippet: snippet(record.Document, words)})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Hash < hits[j].Hash
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// Compact compacts the store, if it can be, such as File.
+func (a *Archive) Compact() error {
+	if c, ok := a.store.(interface{ Compact() error }); ok {
+		return c.Compact()
+	}
+	return nil
+}
+
+// Close closes the store of the archive.
+func (a *Archive) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.store.Close()
+}
+
+func (a *Archive) index(record *Record) {
+	a.records[record.Hash] = record
+	for _, term := range terms(record.Document.Text()) {
+		postings, ok := a.terms[term]
+		if !ok {
+			postings = map[string]int{}
+			a.terms[term] = postings
+		}
+		postings[record.Hash]++
+	}
+}
+
+func (a *Archive) unindex(hash string) {
+	record, ok := a.records[hash]
+	if !ok {
+		return
+	}
+	delete(a.records, hash)
+	for _, term := range terms(record.Document.Text()) {
+		delete(a.terms[term], hash)
+		if len(a.terms[term]) == 0 {
+			delete(a.terms, term)
+		}
+	}
+}
+
+// terms splits the text into lower-case words of letters and digits.
+func terms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// snippet returns the text of the first line containing any of the words.
+func snippet(doc *document.Document, words []string) string {
+	for _, block := range doc.Blocks {
+		for _, para := range block.Paragraphs {
+			for _, line := range para.Lines {
+				text := line.Text()
+				for _, term := range terms(text) {
+					for _, word := range words {
+						if term == word {
+							return text
+						}
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,172 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chennqqi/gosseract/v2/document"
+	. "github.com/otiai10/mint"
+)
+
+// page builds a document of the lines, of words separated by spaces.
+func page(lines ...string) *document.Document {
+	para := document.Paragraph{}
+	for _, text := range lines {
+		line := document.Line{}
+		for _, word := range strings.Fields(text) {
+			line.Words = append(line.Words, document.Word{Text: word, Confidence: 90})
+		}
+		para.Lines = append(para.Lines, line)
+	}
+	return &document.Document{Blocks: []document.Block{{Paragraphs: []document.Paragraph{para}}}}
+}
+
+func TestArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.jsonl")
+	a, err := Open(path)
+	Expect(t, err).ToBe(nil)
+
+	invoice, err := a.Put([]byte("invoice.png"), "scans/invoice.png", page("ACME Corp", "Invoice No. 42", "Total 1,234.56 EUR"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, invoice).ToBe(Hash([]byte("invoice.png")))
+	letter, err := a.Put([]byte("letter.png"), "scans/letter.png", page("Dear ACME,", "thank you for the invoice, and the invoice again."))
+	Expect(t, err).ToBe(nil)
+	_, err = a.Put([]byte("memo.png"), "scans/memo.png", page("Memo", "Nothing to see"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, a.Len()).ToBe(3)
+
+	hits := a.Search("acme INVOICE", 0)
+	Expect(t, len(hits)).ToBe(2)
+	Expect(t, hits[0].Hash).ToBe(letter)
+	Expect(t, hits[0].Snippet).ToBe("Dear ACME,")
+	Expect(t, len(a.Search("invoice total", 0))).ToBe(1)
+	Expect(t, len(a.Search("acme", 1))).ToBe(1)
+	Expect(t, len(a.Search("missing", 0))).ToBe(0)
+	Expect(t, len(a.Search("  ", 0))).ToBe(0)
+
+	record, ok := a.Get(invoice)
+	Expect(t, ok).ToBe(true)
+	Expect(t, record.Name).ToBe("scans/invoice.png")
+	Expect(t, record.Document.Text()).ToBe("ACME Corp\nInvoice No. 42\nTotal 1,234.56 EUR")
+
+	// Putting the same image again replaces the record.
+	_, err = a.Put([]byte("invoice.png"), "scans/invoice-2.png", page("ACME Corp", "Credit note"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(a.Search("invoice", 0))).ToBe(1)
+	Expect(t, a.Delete(letter)).ToBe(nil)
+	Because(t, "nil documents aren't archived", func(t *testing.T) {
+		_, err := a.Put([]byte("blank.png"), "blank.png", nil)
+		Expect(t, err).Not().ToBe(nil)
+		Expect(t, a.Len()).ToBe(2)
+	})
+	Expect(t, a.Close()).ToBe(nil)
+
+	When(t, "the archive is opened again", func(t *testing.T) {
+		// A line broken by a crash.
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		Expect(t, err).ToBe(nil)
+		f.WriteString(`{"hash":"broken`)
+		f.Close()
+
+		a, err := Open(path)
+		Expect(t, err).ToBe(nil)
+		defer a.Close()
+		Expect(t, a.Len()).ToBe(2)
+		record, ok := a.Get(invoice)
+		Expect(t, ok).ToBe(true)
+		Expect(t, record.Name).ToBe("scans/invoice-2.png")
+		_, ok = a.Get(letter)
+		Expect(t, ok).ToBe(false)
+		Expect(t, len(a.Search("credit", 0))).ToBe(1)
+		Expect(t, len(a.Search("invoice", 0))).ToBe(0)
+	})
+}
+
+func TestArchive_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.jsonl")
+	a, err := Open(path)
+	Expect(t, err).ToBe(nil)
+	defer a.Close()
+	kept, err := a.Put([]byte("kept.png"), "kept.png", page("kept"))
+	Expect(t, err).ToBe(nil)
+	for i := 0; i < 3; i++ {
+		_, err := a.Put([]byte("replaced.png"), "replaced.png", page("replaced"))
+		Expect(t, err).ToBe(nil)
+	}
+	deleted, err := a.Put([]byte("deleted.png"), "deleted.png", page("deleted"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, a.Delete(deleted)).ToBe(nil)
+	Expect(t, a.store.(*File).lines).ToBe(6)
+
+	Expect(t, a.Compact()).ToBe(nil)
+	Expect(t, a.store.(*File).lines).ToBe(2)
+	b, err := os.ReadFile(path)
+	Expect(t, err).ToBe(nil)
+	Expect(t, strings.Count(string(b), "\n")).ToBe(2)
+	Because(t, "the archive is appended to the compacted file", func(t *testing.T) {
+		_, err := a.Put([]byte("new.png"), "new.png", page("new"))
+		Expect(t, err).ToBe(nil)
+		reopened, err := Open(path)
+		Expect(t, err).ToBe(nil)
+		defer reopened.Close()
+		Expect(t, reopened.Len()).ToBe(3)
+		Expect(t, reopened.store.(*File).lines).ToBe(3)
+		_, ok := reopened.Get(kept)
+		Expect(t, ok).ToBe(true)
+		Expect(t, len(reopened.Search("replaced", 0))).ToBe(1)
+	})
+
+	When(t, "stale lines outnumber the records", func(t *testing.T) {
+		for i := 0; i < compactMin+3; i++ {
+			_, err := a.Put([]byte("replaced.png"), "replaced.png", page("replaced"))
+			Expect(t, err).ToBe(nil)
+		}
+		Expect(t, a.store.(*File).lines < compactMin).ToBe(true)
+		Expect(t, a.Len()).ToBe(3)
+	})
+}
+
+// memStore is a Store in memory, like those of databases.
+type memStore map[string][]byte
+
+func (m memStore) Load(fn func(key string, value []byte)) error {
+	for key, value := range m {
+		fn(key, value)
+	}
+	return nil
+}
+
+func (m memStore) Set(key string, value []byte) error {
+	m[key] = value
+	return nil
+}
+
+func (m memStore) Delete(key string) error {
+	delete(m, key)
+	return nil
+}
+
+func (m memStore) Close() error { return nil }
+
+func TestOpenStore(t *testing.T) {
+	store := memStore{"garbage": []byte("{")}
+	a, err := OpenStore(store)
+	Expect(t, err).ToBe(nil)
+	hash, err := a.Put([]byte("invoice.png"), "invoice.png", page("Invoice No. 42"))
+	Expect(t, err).ToBe(nil)
+	_, err = a.Put([]byte("memo.png"), "memo.png", page("Memo"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(store)).ToBe(3)
+	Expect(t, a.Delete(Hash([]byte("memo.png")))).ToBe(nil)
+	Expect(t, len(store)).ToBe(2)
+
+	reopened, err := OpenStore(store)
+	Expect(t, err).ToBe(nil)
+	Expect(t, reopened.Len()).ToBe(1)
+	hits := reopened.Search("invoice", 0)
+	Expect(t, len(hits)).ToBe(1)
+	Expect(t, hits[0].Hash).ToBe(hash)
+	Expect(t, reopened.Compact()).ToBe(nil)
+}
@@ -0,0 +1,256 @@
+package archive
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// maxLine is the limit of the size of a line, i.e. a value in JSON with its key.
+const maxLine = 256 * 1024 * 1024
+
+// compactMin is the number of lines of values replaced or deleted to Compact automatically at least,
+// not to rewrite small files over and over again.
+const compactMin = 1000
+
+// File is the Store of a single file of JSON lines, appended by each change and replayed by OpenFile.
+// Only the offsets of the lines are held in memory, and values are read by Load. Values must be JSON,
+// each limited to 256 MiB. Lines of values replaced or deleted are dropped by Compact, which runs once
+// they outnumber the values, so that the file stays within about twice the size of the values.
+// It's safe to share among goroutines.
+type File struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	// size is the offset of the end of the file, where the next line is appended
+	size int64
+	// lines is the number of lines in the file, including those of values replaced or deleted
+	lines int
+	spans map[string]span
+}
+
+// span is the offset and the length of the line of a value, without the newline.
+type span struct {
+	offset int64
+	length int
+}
+
+type fileEntry struct {
+	Key     string          `json:"key"`
+	Value   json.RawMessage `json:"value,omitempty"`
+	Deleted bool            `json:"deleted,omitempty"`
+}
+
+// OpenFile opens the File of the path, creating it if it doesn't exist. Lines broken by crashes are ignored.
+// It's due to caller to Close the File.
+func OpenFile(path string) (*File, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{path: path, file: file, spans: map[string]span{}}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxLine)
+	offset := int64(0)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		f.lines++
+		e := fileEntry{}
+		if json.Unmarshal(line, &e) == nil && e.Key != "" {
+			if e.Deleted {
+				delete(f.spans, e.Key)
+			} else {
+				f.spans[e.Key] = span{offset, len(line)}
+			}
+		}
+		offset += int64(len(line)) + 1
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read archive %s: %v", path, err)
+	}
+	if err := f.terminate(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Load calls fn with each key and value, in the order of keys.
+func (f *File) Load(fn func(key string, value []byte)) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, key := range f.keys() {
+		e, err := f.read(key)
+		if err != nil {
+			return err
+		}
+		fn(key, e.Value)
+	}
+	return nil
+}
+
+// Set appends the value of the key, synced to the disk not to be lost by crashes.
+func (f *File) Set(key string, value []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	offset, length, err := f.append(fileEntry{Key: key, Value: value})
+	if err != nil {
+		return err
+	}
+	f.spans[key] = span{offset, length}
+	f.compactIfStale()
+	return nil
+}
+
+// Delete appends the deletion of the key, if any, synced to the disk not to be lost by crashes.
+func (f *File) Delete(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.spans[key]; !ok {
+		return nil
+	}
+	if _, _, err := f.append(fileEntry{Key: key, Deleted: true}); err != nil {
+		return err
+	}
+	delete(f.spans, key)
+	f.compactIfStale()
+	return nil
+}
+
+// Compact rewrites the file with the values only, dropping the lines of those replaced or deleted.
+// The file is replaced atomically by renaming, so crashes leave either the old file or the new one.
+// It runs automatically by Set and Delete once the lines dropped would outnumber the values.
+func (f *File) Compact() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.compact()
+}
+
+// Close closes the file.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// compactIfStale compacts the file once the stale lines outnumber the values.
+// Changes are already in the file, so failures are left to be retried by the next change.
+func (f *File) compactIfStale() {
+	if stale := f.lines - len(f.spans); stale >= compactMin && stale > len(f.spans) {
+		f.compact()
+	}
+}
+
+func (f *File) compact() error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to compact archive %s: %v", f.path, err)
+	}
+	defer os.Remove(tmp.Name())
+	spans := make(map[string]span, len(f.spans))
+	offset := int64(0)
+	w := bufio.NewWriter(tmp)
+	for _, key := range f.keys() {
+		s := f.spans[key]
+		line := make([]byte, s.length+1)
+		_, err := f.file.ReadAt(line[:s.length], s.offset)
+		if err == nil {
+			line[s.length] = '\n'
+			_, err = w.Write(line)
+		}
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to compact archive %s: %v", f.path, err)
+		}
+		spans[key] = span{offset, s.length}
+		offset += int64(len(line))
+	}
+	if err := w.Flush(); err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0o644)
+	}
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to compact archive %s: %v", f.path, err)
+	}
+	tmp.Close()
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to compact archive %s: %v", f.path, err)
+	}
+	file, err := os.OpenFile(f.path, os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to reopen archive %s: %v", f.path, err)
+	}
+	f.file.Close()
+	f.file, f.spans, f.size, f.lines = file, spans, offset, len(spans)
+	return nil
+}
+
+// append writes the entry, synced to the disk, returning the offset and the length of its line.
+func (f *File) append(e fileEntry) (int64, int, error) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(b) > maxLine {
+		return 0, 0, fmt.Errorf("failed to write %s to archive %s: %d bytes over the limit", e.Key, f.path, len(b))
+	}
+	if _, err := f.file.Write(append(b, '\n')); err != nil {
+		f.terminate()
+		return 0, 0, err
+	}
+	offset := f.size
+	f.size += int64(len(b)) + 1
+	f.lines++
+	return offset, len(b), f.file.Sync()
+}
+
+// terminate terminates the line broken by crashes or failed writes, if any, not to break the next one,
+// and finds the end of the file.
+func (f *File) terminate() error {
+	info, err := f.file.Stat()
+	if err != nil {
+		return err
+	}
+	f.size = info.Size()
+	if f.size == 0 {
+		return nil
+	}
+	last := make([]byte, 1)
+	if _, err := f.file.ReadAt(last, f.size-1); err == nil && last[0] != '\n' {
+		if _, err := f.file.Write([]byte{'\n'}); err == nil {
+			f.size++
+		}
+	}
+	return nil
+}
+
+// read reads the entry of the key.
+func (f *File) read(key string) (fileEntry, error) {
+	s := f.spans[key]
+	line := make([]byte, s.length)
+	if _, err := f.file.ReadAt(line, s.offset); err != nil {
+		return fileEntry{}, fmt.Errorf("failed to read %s of archive %s: %v", key, f.path, err)
+	}
+	e := fileEntry{}
+	if err := json.Unmarshal(line, &e); err != nil {
+		return fileEntry{}, fmt.Errorf("failed to read %s of archive %s: %v", key, f.path, err)
+	}
+	return e, nil
+}
+
+func (f *File) keys() []string {
+	keys := make([]string, 0, len(f.spans))
+	for key := range f.spans {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
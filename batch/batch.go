@@ -0,0 +1,450 @@
+// Package batch recognizes many pages in a run, such as a directory of scans, recording the result of each page
+// rather than stopping at the first failure, for backfills and ingestion pipelines.
+package batch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2"
+	"github.com/chennqqi/gosseract/v2/export"
+	"github.com/chennqqi/gosseract/v2/extract"
+	"github.com/chennqqi/gosseract/v2/store"
+)
+
+// Recognizer recognizes text of image data with options, which gosseract.Client implements.
+type Recognizer interface {
+	TextWithOptions(ctx context.Context, data []byte, opts gosseract.Options) (string, error)
+}
+
+// Page is an image to be recognized in a batch.
+type Page struct {
+	// Name identifies the page in results, such as the path of its file.
+	Name string
+	Data []byte
+}
+
+// Result is the result of a page.
+type Result struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+
+	// Err is the error recognizing the page, nil if recognized.
+	Err error `json:"-"`
+
+	// Duplicate is the name of the earlier page this page is a duplicate of, see Options.Duplicates,
+	// and Similarity the similarity of their texts from 0 to 1.
+	Duplicate  string  `json:"duplicate,omitempty"`
+	Similarity float64 `json:"similarity,omitempty"`
+
+	// Duration of the recognition, zero for pages copied from identical ones.
+	Duration time.Duration `json:"duration"`
+
+	// BudgetExceeded is whether the recognition of the page exceeded Options.MaxPerPageDuration,
+	// and Downgraded whether the text is recognized with Options.Downgrade instead.
+	BudgetExceeded bool `json:"budget_exceeded,omitempty"`
+	Downgraded     bool `json:"downgraded,omitempty"`
+
+	// Degraded is how far the recognition of the page is degraded to meet Options.Degradation.Deadline.
+	Degraded Degraded `json:"degraded,omitempty"`
+
+	// Masked are the spans of personally identifiable information masked in Text, see Options.MaskPII.
+	Masked []extract.PIISpan `json:"masked,omitempty"`
+
+	// Resumed is whether the result is restored from Options.Journal, rather than recognized by this run.
+	Resumed bool `json:"-"`
+}
+
+// ErrBudgetExceeded is the error of pages skipped for exceeding Options.MaxPerPageDuration.
+var ErrBudgetExceeded = errors.New("recognition exceeded the time budget of the page")
+
+// BudgetAction is what to do with pages exceeding Options.MaxPerPageDuration.
+type BudgetAction int
+
+const (
+	// BudgetSkip skips the page, failing with ErrBudgetExceeded.
+	BudgetSkip BudgetAction = iota
+	// BudgetDowngrade recognizes the page again with Options.Downgrade, such as faster models,
+	// within the budget again, and skips it if it's exceeded again.
+	BudgetDowngrade
+	// BudgetDefer queues the page to be recognized again after all the other pages of the run,
+	// without the budget, so that it doesn't hold up the others.
+	BudgetDefer
+)
+
+// Options specifies how pages are processed.
+type Options struct {
+	// Recognition is the options to recognize each page, see gosseract.Client.TextWithOptions.
+	Recognition gosseract.Options
+
+	// Duplicates detects pages duplicating earlier ones in the run, such as double-fed pages of scanners.
+	// Nil not to detect them.
+	Duplicates *DuplicateOptions
+
+	// MaxPerPageDuration is the time budget to recognize each page, zero for no limit.
+	// Recognitions over the budget are cancelled, and the pages are handled by OnBudgetExceeded,
+	// so that a pathological page can't stall a long run.
+	MaxPerPageDuration time.Duration
+	OnBudgetExceeded   BudgetAction
+
+	// Downgrade is the options to recognize pages over the budget again by BudgetDowngrade,
+	// such as Languages of the models of tessdata_fast installed as "eng_fast", or PSM_SINGLE_BLOCK.
+	Downgrade gosseract.Options
+
+	// Degradation degrades the recognition of the remaining pages as the soft deadline of the run approaches.
+	// Nil not to degrade them.
+	Degradation *Degradation
+
+	// Journal records the pages completed, and skips those recorded by interrupted runs, restoring their results.
+	// Nil not to record them.
+	Journal *Journal
+
+	// Config identifies the configuration of the recognizer not in the options, such as languages and tessdata
+	// of gosseract.Client, whose changes invalidate the pages recorded in Journal, e.g. "eng+deu tessdata_best".
+	Config string
+
+	// Progress is notified of each page, such as NewProgressBar(os.Stderr). Nil not to be notified.
+	Progress Progress
+
+	// Output stores the text of each page recognized as the sidecar named by the page with ".txt" appended,
+	// such as "scans/scan-1.png.txt" of "scans/scan-1.png", or "scan-1.png.txt" of it by ProcessDir("scans"),
+	// e.g. to store.Dir or store.S3, so that pages of the same base names don't overwrite each other's.
+	// Nil not to store them.
+	// Pages resumed from Journal aren't stored again.
+	Output store.OutputStore
+	// Encoding encodes the sidecars of Output, UTF-8 with LF if zero, such as store.Encoding{Charset: store.UTF16LE,
+	// BOM: true, Newline: store.CRLF} for legacy Windows consumers.
+	Encoding store.Encoding
+
+	// Export receives the text of each page recognized in chunks, by ExportOptions, such as to feed search indexes,
+	// after Output and before Journal. Nil not to export them. Pages resumed from Journal aren't exported again.
+	Export        export.Sink
+	ExportOptions export.Options
+
+	// MaskPII masks personally identifiable information in the texts, such as emails, phone numbers and national IDs,
+	// before they're stored in Output and Journal, recording the spans in Result.Masked, see extract.MaskPII.
+	MaskPII bool
+
+	// root is the directory of the run of ProcessDir, which sidecars are named relative to.
+	root string
+}
+
+// Extensions are the file extensions of images ProcessDir processes, in lower case.
+var Extensions = []string{".png", ".jpg", ".jpeg", ".gif", ".tif", ".tiff", ".bmp", ".webp", ".pnm", ".jp2"}
+
+// ProcessPages recognizes the pages in order by the recognizer and returns their results.
+// Failures of pages are recorded in their results, and the run goes on.
+// It stops when ctx is done, returning the results so far and ctx.Err().
+func ProcessPages(ctx context.Context, rec Recognizer, pages []Page, opts Options) ([]Result, error) {
+	return process(ctx, rec, pageSource(pages), opts)
+}
+
+// source is the pages of a run, loaded one by one as they're processed.
+type source interface {
+	len() int
+	name(i int) string
+	// stat identifies the version of the page without loading it, such as of the size and the modification time
+	// of the file, or "" if unknown.
+	stat(i int) string
+	load(i int) ([]byte, error)
+}
+
+type pageSource []Page
+
+func (pages pageSource) len() int                   { return len(pages) }
+func (pages pageSource) name(i int) string          { return pages[i].Name }
+func (pages pageSource) stat(i int) string          { return "" }
+func (pages pageSource) load(i int) ([]byte, error) { return pages[i].Data, nil }
+
+// fileSource is the image files of the paths, read when they're processed.
+type fileSource []string
+
+func (paths fileSource) len() int          { return len(paths) }
+func (paths fileSource) name(i int) string { return paths[i] }
+
+func (paths fileSource) stat(i int) string {
+	info, err := os.Stat(paths[i])
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d@%d", info.Size(), info.ModTime().UnixNano())
+}
+
+func (paths fileSource) load(i int) ([]byte, error) {
+	return os.ReadFile(paths[i])
+}
+
+func process(ctx context.Context, rec Recognizer, src source, opts Options) ([]Result, error) {
+	total := src.len()
+	results := make([]Result, 0, total)
+	detector := newDuplicateDetector(opts.Duplicates)
+	// deferred pages keep their fingerprints to be checked for duplicates once recognized.
+	type deferredPage struct {
+		index       int
+		fingerprint gosseract.Fingerprint
+	}
+	deferred := []deferredPage{}
+	config := ""
+	if opts.Journal != nil {
+		config = configFingerprint(opts)
+	}
+	progress := newTracker(opts.Progress, total)
+	ladder := newLadder(opts.Degradation)
+	for i := 0; i < total; i++ {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		name, stat := src.name(i), src.stat(i)
+		progress.started(i, name)
+		result := Result{Name: name}
+		// Pages of the same version are resumed without loading them, unless duplicates are detected by their contents.
+		if opts.Journal != nil && opts.Duplicates == nil && stat != "" {
+			if recorded, ok := opts.Journal.completedStat(name, stat, config); ok {
+				recorded.Resumed = true
+				progress.done(i, recorded, false)
+				results = append(results, recorded)
+				continue
+			}
+		}
+		data, err := src.load(i)
+		if err != nil {
+			result.Err = err
+			progress.done(i, result, false)
+			results = append(results, result)
+			continue
+		}
+		page := Page{Name: name, Data: data}
+		fp, original, ok := detector.identical(page)
+		if opts.Journal != nil {
+			if recorded, ok := opts.Journal.completed(opts.Journal.key(page, config)); ok {
+				recorded.Resumed = true
+				if recorded.Duplicate == "" {
+					detector.check(fp, &recorded)
+				}
+				progress.done(i, recorded, false)
+				results = append(results, recorded)
+				continue
+			}
+		}
+		if ok {
+			result.Text, result.Duplicate, result.Similarity = original.Text, original.Name, 1
+			result.Masked = original.Masked
+			if err := opts.deliver(ctx, result); err != nil {
+				return results, err
+			}
+			progress.done(i, result, false)
+			results = append(results, result)
+			continue
+		}
+		recognition, degraded := ladder.options(opts.Recognition, total-i+len(deferred))
+		if result.Degraded = degraded; degraded == DegradedSkipped {
+			result.Err = ErrSoftDeadline
+			progress.done(i, result, false)
+			results = append(results, result)
+			continue
+		}
+		measured := ladder.start(degraded)
+		exceeded := recognize(ctx, rec, page, recognition, opts.MaxPerPageDuration, &result)
+		measured()
+		if result.Err != nil && ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		retried := false
+		if exceeded {
+			result.BudgetExceeded = true
+			switch opts.OnBudgetExceeded {
+			case BudgetDowngrade:
+				duration := result.Duration
+				if recognize(ctx, rec, page, opts.Downgrade, opts.MaxPerPageDuration, &result) {
+					result.Err = ErrBudgetExceeded
+				}
+				result.Downgraded = result.Err == nil
+				result.Duration += duration
+			case BudgetDefer:
+				result.Err = ErrBudgetExceeded
+				deferred = append(deferred, deferredPage{len(results), fp})
+				retried = true
+			default:
+				result.Err = ErrBudgetExceeded
+			}
+		}
+		if result.Err == nil {
+			opts.mask(&result)
+			detector.check(fp, &result)
+			if err := opts.complete(ctx, page, stat, config, result); err != nil {
+				return results, err
+			}
+		}
+		progress.done(i, result, retried)
+		results = append(results, result)
+	}
+	for k, d := range deferred {
+		i := d.index
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		progress.started(i, src.name(i))
+		result := &results[i]
+		recognition, degraded := ladder.options(opts.Recognition, len(deferred)-k)
+		if result.Degraded = degraded; degraded == DegradedSkipped {
+			result.Err = ErrSoftDeadline
+			progress.done(i, *result, false)
+			continue
+		}
+		// Deferred pages are loaded again, not to hold all of them until the end of the run.
+		stat := src.stat(i)
+		data, err := src.load(i)
+		if err != nil {
+			result.Err = err
+			progress.done(i, *result, false)
+			continue
+		}
+		page := Page{Name: src.name(i), Data: data}
+		duration := result.Duration
+		measured := ladder.start(degraded)
+		recognize(ctx, rec, page, recognition, 0, result)
+		measured()
+		result.Duration += duration
+		if result.Err == nil {
+			opts.mask(result)
+			detector.check(d.fingerprint, result)
+			if err := opts.complete(ctx, page, stat, config, *result); err != nil {
+				return results, err
+			}
+		}
+		progress.done(i, *result, false)
+	}
+	return results, ctx.Err()
+}
+
+// complete stores the sidecar of the page recognized, exports it and records the result in the journal, if any,
+// in this order so that pages recorded always have their sidecars and chunks.
+func (opts Options) complete(ctx context.Context, page Page, stat, config string, result Result) error {
+	if err := opts.deliver(ctx, result); err != nil {
+		return err
+	}
+	if opts.Journal == nil {
+		return nil
+	}
+	return opts.Journal.record(opts.Journal.key(page, config), stat, result)
+}
+
+// mask masks personally identifiable information in the text of the result, if MaskPII.
+func (opts Options) mask(result *Result) {
+	if opts.MaskPII {
+		result.Text, result.Masked = extract.MaskPII(result.Text)
+	}
+}
+
+// deliver stores the sidecar of the result and exports it, if any.
+func (opts Options) deliver(ctx context.Context, result Result) error {
+	if err := opts.output(ctx, result); err != nil {
+		return err
+	}
+	if opts.Export == nil {
+		return nil
+	}
+	if err := export.Text(ctx, opts.Export, result.Name, 0, result.Text, opts.ExportOptions); err != nil {
+		return fmt.Errorf("failed to export the text of %s: %v", result.Name, err)
+	}
+	return nil
+}
+
+// output stores the text of the result as the sidecar, if any.
+func (opts Options) output(ctx context.Context, result Result) error {
+	if opts.Output == nil {
+		return nil
+	}
+	if err := opts.Output.Put(ctx, sidecarName(opts.root, result.Name), opts.Encoding.ContentType(), bytes.NewReader(opts.Encoding.Encode(result.Text))); err != nil {
+		return fmt.Errorf("failed to store the text of %s: %v", result.Name, err)
+	}
+	return nil
+}
+
+// sidecarName names the sidecar of the page by the name of the page relative to the root, if any, with ".txt"
+// appended, such as "a/b.png.txt" of "a/b.png". Names out of the store, such as absolute ones or of parents,
+// are kept within it, such as "a/b.png.txt" of "/a/b.png" or of "../a/b.png".
+func sidecarName(root, name string) string {
+	if root != "" {
+		if rel, err := filepath.Rel(root, name); err == nil {
+			name = rel
+		}
+	}
+	parts := []string{}
+	for _, part := range strings.Split(path.Clean(filepath.ToSlash(name)), "/") {
+		if part != "" && part != "." && part != ".." {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/") + ".txt"
+}
+
+// recognize recognizes the page into the result within the budget, if any, reporting whether it's exceeded.
+func recognize(ctx context.Context, rec Recognizer, page Page, opts gosseract.Options, budget time.Duration, result *Result) bool {
+	pageCtx := ctx
+	if budget > 0 {
+		var cancel context.CancelFunc
+		pageCtx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+	start := time.Now()
+	result.Text, result.Err = rec.TextWithOptions(pageCtx, page.Data, opts)
+	result.Duration = time.Since(start)
+	return result.Err != nil && ctx.Err() == nil && pageCtx.Err() == context.DeadlineExceeded
+}
+
+// ProcessDir recognizes the images in the directory, i.e. files of Extensions, in order of names by ProcessPages.
+// Subdirectories are not walked. Results are named by the paths of files.
+func ProcessDir(ctx context.Context, rec Recognizer, dir string, opts Options) ([]Result, error) {
+	paths, err := ImageFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	opts.root = dir
+	return BatchText(ctx, rec, paths, opts)
+}
+
+// BatchText recognizes the image files of the paths in order as ProcessPages does, such as files listed by
+// manifests of backfills, resuming from Options.Journal as ProcessDir does. Results are named by the paths.
+// Files are read one by one as they're processed, and those failing to be read are recorded in their results.
+// Files recorded in Journal of the same size and modification time are resumed without reading them,
+// unless Options.Duplicates compares their contents.
+func BatchText(ctx context.Context, rec Recognizer, paths []string, opts Options) ([]Result, error) {
+	return process(ctx, rec, fileSource(paths), opts)
+}
+
+// ImageFiles returns the paths of the images in the directory, i.e. files of Extensions, in order of names,
+// as ProcessDir processes them.
+func ImageFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !isImage(entry.Name()) {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func isImage(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range Extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
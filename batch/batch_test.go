@@ -0,0 +1,428 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2"
+	"github.com/chennqqi/gosseract/v2/export"
+	"github.com/chennqqi/gosseract/v2/extract"
+	"github.com/chennqqi/gosseract/v2/store"
+	. "github.com/otiai10/mint"
+)
+
+// fakeRecognizer recognizes the text by the last byte of the data, counting the recognitions.
+type fakeRecognizer struct {
+	texts map[byte]string
+	calls int
+}
+
+func (f *fakeRecognizer) TextWithOptions(ctx context.Context, data []byte, opts gosseract.Options) (string, error) {
+	f.calls++
+	// The page marked by 8 takes long, unless it's downgraded to PSM_SINGLE_BLOCK or has no time limit.
+	if _, limited := ctx.Deadline(); data[len(data)-1] == 8 && limited && opts.PageSegMode != gosseract.PSM_SINGLE_BLOCK {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	if text, ok := f.texts[data[len(data)-1]]; ok {
+		return text, nil
+	}
+	return "", errors.New("unreadable")
+}
+
+// page encodes a PNG of a gradient, marking the text of the fake recognizer by a trailing byte,
+// which PNG decoders ignore.
+func page(t *testing.T, shade uint8, mark byte) []byte {
+	img := image.NewGray(image.Rect(0, 0, 90, 80))
+	for y := 0; y < 80; y++ {
+		for x := 0; x < 90; x++ {
+			img.SetGray(x, y, color.Gray{uint8((x*255/90+y)%256) ^ shade})
+		}
+	}
+	buf := bytes.NewBuffer(nil)
+	Expect(t, png.Encode(buf, img)).ToBe(nil)
+	return append(buf.Bytes(), mark)
+}
+
+func TestProcessPages(t *testing.T) {
+	rec := &fakeRecognizer{texts: map[byte]string{
+		1: "It was the best of times, it was the worst of times",
+		2: "It was the best of times, it was the worst of times",
+		3: "Call me Ishmael. Some years ago, never mind how long",
+	}}
+	a, b, c := page(t, 0, 1), page(t, 0, 2), page(t, 0xff, 3)
+	pages := []Page{{"a", a}, {"a-again", a}, {"b", b}, {"c", c}, {"broken", page(t, 0, 9)}}
+
+	results, err := ProcessPages(context.Background(), rec, pages, Options{Duplicates: &DuplicateOptions{}})
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(results)).ToBe(5)
+	Expect(t, results[0].Duplicate).ToBe("")
+	Expect(t, results[1].Duplicate).ToBe("a")
+	Expect(t, results[1].Text).ToBe(results[0].Text)
+	Expect(t, results[2].Duplicate).ToBe("a")
+	Expect(t, results[2].Similarity).ToBe(1.0)
+	Expect(t, results[3].Duplicate).ToBe("")
+	Expect(t, results[4].Err).Not().ToBe(nil)
+	Because(t, "identical pages aren't recognized again", func(t *testing.T) {
+		Expect(t, rec.calls).ToBe(4)
+	})
+
+	When(t, "duplicates are not detected", func(t *testing.T) {
+		results, _ := ProcessPages(context.Background(), rec, pages, Options{})
+		Expect(t, results[1].Duplicate).ToBe("")
+		Expect(t, results[2].Duplicate).ToBe("")
+	})
+
+	When(t, "the run is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := ProcessPages(ctx, rec, pages, Options{})
+		Expect(t, err).ToBe(context.Canceled)
+		Expect(t, len(results)).ToBe(0)
+	})
+}
+
+func TestProcessDir(t *testing.T) {
+	dir := t.TempDir()
+	Expect(t, os.WriteFile(filepath.Join(dir, "2.PNG"), page(t, 0, 2), 0o644)).ToBe(nil)
+	Expect(t, os.WriteFile(filepath.Join(dir, "1.png"), page(t, 0, 1), 0o644)).ToBe(nil)
+	Expect(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not an image"), 0o644)).ToBe(nil)
+	rec := &fakeRecognizer{texts: map[byte]string{1: "one", 2: "two"}}
+	out := t.TempDir()
+	results, err := ProcessDir(context.Background(), rec, dir, Options{Output: store.Dir(out)})
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(results)).ToBe(2)
+	// Sidecars are named relative to the directory.
+	text, err := os.ReadFile(filepath.Join(out, "1.png.txt"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(text)).ToBe("one")
+	Expect(t, results[0].Name).ToBe(filepath.Join(dir, "1.png"))
+	Expect(t, results[0].Text).ToBe("one")
+	Expect(t, results[1].Text).ToBe("two")
+
+	_, err = ProcessDir(context.Background(), rec, filepath.Join(dir, "missing"), Options{})
+	Expect(t, err).Not().ToBe(nil)
+}
+
+func TestBatchText(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{filepath.Join(dir, "b.png"), filepath.Join(dir, "a.png")}
+	Expect(t, os.WriteFile(paths[0], page(t, 0, 2), 0o644)).ToBe(nil)
+	Expect(t, os.WriteFile(paths[1], page(t, 0, 1), 0o644)).ToBe(nil)
+	journal, err := OpenJournal(filepath.Join(dir, "journal.jsonl"))
+	Expect(t, err).ToBe(nil)
+	defer journal.Close()
+	rec := &fakeRecognizer{texts: map[byte]string{1: "one", 2: "two"}}
+	results, err := BatchText(context.Background(), rec, paths[:1], Options{Journal: journal})
+	Expect(t, err).ToBe(nil)
+	Expect(t, results[0].Name).ToBe(paths[0])
+	Expect(t, results[0].Text).ToBe("two")
+
+	Because(t, "the files are recognized in order of the paths, resuming from the journal", func(t *testing.T) {
+		// Of the same size and modification time, the file recorded isn't read again.
+		info, err := os.Stat(paths[0])
+		Expect(t, err).ToBe(nil)
+		Expect(t, os.WriteFile(paths[0], page(t, 0, 1), 0o644)).ToBe(nil)
+		Expect(t, os.Chtimes(paths[0], info.ModTime(), info.ModTime())).ToBe(nil)
+		rec.calls = 0
+		results, err := BatchText(context.Background(), rec, paths, Options{Journal: journal})
+		Expect(t, err).ToBe(nil)
+		Expect(t, results[0].Resumed).ToBe(true)
+		Expect(t, results[0].Text).ToBe("two")
+		Expect(t, results[1].Text).ToBe("one")
+		Expect(t, rec.calls).ToBe(1)
+	})
+
+	results, err = BatchText(context.Background(), rec, []string{filepath.Join(dir, "missing.png"), paths[1]}, Options{})
+	Expect(t, err).ToBe(nil)
+	Expect(t, os.IsNotExist(results[0].Err)).ToBe(true)
+	Expect(t, results[1].Text).ToBe("one")
+}
+
+func TestProcessPages_Budget(t *testing.T) {
+	rec := &fakeRecognizer{texts: map[byte]string{1: "quick", 8: "slow"}}
+	pages := []Page{{"slow", page(t, 0, 8)}, {"quick", page(t, 0, 1)}}
+	opts := Options{MaxPerPageDuration: 10 * time.Millisecond}
+
+	results, err := ProcessPages(context.Background(), rec, pages, opts)
+	Expect(t, err).ToBe(nil)
+	Expect(t, results[0].Err).ToBe(ErrBudgetExceeded)
+	Expect(t, results[0].BudgetExceeded).ToBe(true)
+	Expect(t, results[1].Text).ToBe("quick")
+	Expect(t, results[1].BudgetExceeded).ToBe(false)
+
+	When(t, "pages over the budget are downgraded", func(t *testing.T) {
+		opts := opts
+		opts.OnBudgetExceeded = BudgetDowngrade
+		opts.Downgrade = gosseract.Options{PageSegMode: gosseract.PSM_SINGLE_BLOCK}
+		results, err := ProcessPages(context.Background(), rec, pages, opts)
+		Expect(t, err).ToBe(nil)
+		Expect(t, results[0].Err).ToBe(nil)
+		Expect(t, results[0].Text).ToBe("slow")
+		Expect(t, results[0].Downgraded).ToBe(true)
+	})
+
+	When(t, "pages over the budget are deferred", func(t *testing.T) {
+		opts := opts
+		opts.OnBudgetExceeded = BudgetDefer
+		rec.calls = 0
+		results, err := ProcessPages(context.Background(), rec, pages, opts)
+		Expect(t, err).ToBe(nil)
+		Expect(t, results[0].Err).ToBe(nil)
+		Expect(t, results[0].Text).ToBe("slow")
+		Expect(t, results[0].BudgetExceeded).ToBe(true)
+		Expect(t, results[0].Downgraded).ToBe(false)
+		Expect(t, rec.calls).ToBe(3)
+	})
+
+	When(t, "duplicates of pages deferred are detected", func(t *testing.T) {
+		rec := &fakeRecognizer{texts: map[byte]string{1: "It was the best of times", 8: "It was the best of times"}}
+		pages := []Page{{"a", page(t, 0, 1)}, {"slow", page(t, 0, 8)}}
+		opts := opts
+		opts.OnBudgetExceeded = BudgetDefer
+		opts.Duplicates = &DuplicateOptions{}
+		results, err := ProcessPages(context.Background(), rec, pages, opts)
+		Expect(t, err).ToBe(nil)
+		Expect(t, results[1].Err).ToBe(nil)
+		Expect(t, results[1].Duplicate).ToBe("a")
+		Expect(t, results[1].Similarity).ToBe(1.0)
+	})
+}
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	rec := &fakeRecognizer{texts: map[byte]string{1: "one", 2: "two"}}
+	pages := []Page{{"1", page(t, 0, 1)}, {"2", page(t, 0, 2)}, {"broken", page(t, 0, 9)}}
+
+	journal, err := OpenJournal(path)
+	Expect(t, err).ToBe(nil)
+	results, err := ProcessPages(context.Background(), rec, pages[:1], Options{Journal: journal})
+	Expect(t, err).ToBe(nil)
+	Expect(t, results[0].Resumed).ToBe(false)
+	Expect(t, journal.Close()).ToBe(nil)
+
+	Because(t, "an interrupted run leaves a broken line", func(t *testing.T) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		Expect(t, err).ToBe(nil)
+		f.WriteString(`{"name":"2","cont`)
+		f.Close()
+	})
+
+	journal, err = OpenJournal(path)
+	Expect(t, err).ToBe(nil)
+	defer journal.Close()
+	Expect(t, journal.Len()).ToBe(1)
+	rec.calls = 0
+	results, err = ProcessPages(context.Background(), rec, pages, Options{Journal: journal})
+	Expect(t, err).ToBe(nil)
+	Expect(t, results[0].Resumed).ToBe(true)
+	Expect(t, results[0].Text).ToBe("one")
+	Expect(t, results[1].Text).ToBe("two")
+	Expect(t, results[2].Err).Not().ToBe(nil)
+	Expect(t, rec.calls).ToBe(2)
+	Expect(t, journal.Len()).ToBe(2)
+	Because(t, "the entry after the broken line is read", func(t *testing.T) {
+		reopened, err := OpenJournal(path)
+		Expect(t, err).ToBe(nil)
+		Expect(t, reopened.Len()).ToBe(2)
+		reopened.Close()
+	})
+
+	When(t, "the configuration is changed", func(t *testing.T) {
+		rec.calls = 0
+		results, _ := ProcessPages(context.Background(), rec, pages[:2], Options{Journal: journal, Config: "deu"})
+		Expect(t, results[0].Resumed).ToBe(false)
+		Expect(t, rec.calls).ToBe(2)
+	})
+}
+
+type recordedProgress struct {
+	events []string
+	stats  Stats
+}
+
+func (p *recordedProgress) OnPageStart(index, total int, name string) {
+	p.events = append(p.events, fmt.Sprintf("start %d/%d %s", index, total, name))
+}
+
+func (p *recordedProgress) OnPageDone(index, total int, result Result, stats Stats) {
+	p.events = append(p.events, fmt.Sprintf("done %d/%d %s", index, total, result.Text))
+	p.stats = stats
+}
+
+func TestProcessPages_Progress(t *testing.T) {
+	rec := &fakeRecognizer{texts: map[byte]string{1: "one", 8: "slow"}}
+	a := page(t, 0, 1)
+	pages := []Page{{"slow", page(t, 0, 8)}, {"a", a}, {"a-again", a}, {"broken", page(t, 0, 9)}}
+	progress := &recordedProgress{}
+	_, err := ProcessPages(context.Background(), rec, pages, Options{
+		Duplicates:         &DuplicateOptions{},
+		MaxPerPageDuration: 10 * time.Millisecond,
+		OnBudgetExceeded:   BudgetDefer,
+		Progress:           progress,
+	})
+	Expect(t, err).ToBe(nil)
+	Expect(t, progress.events).ToBe([]string{
+		"start 0/4 slow", "done 0/4 ",
+		"start 1/4 a", "done 1/4 one",
+		"start 2/4 a-again", "done 2/4 one",
+		"start 3/4 broken", "done 3/4 ",
+		"start 0/4 slow", "done 0/4 slow",
+	})
+	Expect(t, progress.stats.Done).ToBe(4)
+	Expect(t, progress.stats.Failed).ToBe(1)
+	Expect(t, progress.stats.Duplicates).ToBe(1)
+	Expect(t, progress.stats.Remaining).ToBe(time.Duration(0))
+}
+
+func TestProgressBar(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	bar := NewProgressBar(buf)
+	bar.Width = 10
+	bar.OnPageDone(0, 4, Result{}, Stats{Done: 1, Failed: 1, Remaining: 90 * time.Second})
+	Expect(t, buf.String()).ToBe("\r[==>       ] 1/4  25%  1 failed  ETA 1m30s")
+	buf.Reset()
+	bar.OnPageDone(3, 4, Result{}, Stats{Done: 4})
+	Expect(t, buf.String()).ToBe("\r[==========] 4/4 100%\n")
+}
+
+func TestProcessPages_Output(t *testing.T) {
+	rec := &fakeRecognizer{texts: map[byte]string{1: "one"}}
+	a := page(t, 0, 1)
+	dir := t.TempDir()
+	pages := []Page{{"scans/a.png", a}, {"scans/a-again.png", a}, {"scans/broken.png", page(t, 0, 9)}}
+	_, err := ProcessPages(context.Background(), rec, pages, Options{Duplicates: &DuplicateOptions{}, Output: store.Dir(dir)})
+	Expect(t, err).ToBe(nil)
+	for _, name := range []string{"scans/a.png.txt", "scans/a-again.png.txt"} {
+		text, err := os.ReadFile(filepath.Join(dir, name))
+		Expect(t, err).ToBe(nil)
+		Expect(t, string(text)).ToBe("one")
+	}
+	_, err = os.Stat(filepath.Join(dir, "scans", "broken.png.txt"))
+	Expect(t, os.IsNotExist(err)).ToBe(true)
+
+	When(t, "pages are of the same base names", func(t *testing.T) {
+		rec := &fakeRecognizer{texts: map[byte]string{1: "one", 2: "two", 3: "three"}}
+		dir := t.TempDir()
+		pages := []Page{{"a/x.png", page(t, 0, 1)}, {"b/x.png", page(t, 0, 2)}, {"a/x.jpg", page(t, 0, 3)}, {"/abs/../../x.png", page(t, 0, 1)}}
+		_, err := ProcessPages(context.Background(), rec, pages, Options{Output: store.Dir(dir)})
+		Expect(t, err).ToBe(nil)
+		for name, want := range map[string]string{"a/x.png.txt": "one", "b/x.png.txt": "two", "a/x.jpg.txt": "three", "x.png.txt": "one"} {
+			text, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+			Expect(t, err).ToBe(nil)
+			Expect(t, string(text)).ToBe(want)
+		}
+	})
+}
+
+func TestProcessPages_Encoding(t *testing.T) {
+	rec := &fakeRecognizer{texts: map[byte]string{1: "o\nk"}}
+	dir := t.TempDir()
+	enc := store.Encoding{Charset: store.UTF16LE, BOM: true, Newline: store.CRLF}
+	_, err := ProcessPages(context.Background(), rec, []Page{{"a.png", page(t, 0, 1)}}, Options{Output: store.Dir(dir), Encoding: enc})
+	Expect(t, err).ToBe(nil)
+	text, err := os.ReadFile(filepath.Join(dir, "a.png.txt"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(text)).ToBe("\xff\xfeo\x00\r\x00\n\x00k\x00")
+}
+
+func TestProcessPages_MaskPII(t *testing.T) {
+	rec := &fakeRecognizer{texts: map[byte]string{1: "mail jane@example.com"}}
+	dir := t.TempDir()
+	results, err := ProcessPages(context.Background(), rec, []Page{{"a.png", page(t, 0, 1)}}, Options{Output: store.Dir(dir), MaskPII: true})
+	Expect(t, err).ToBe(nil)
+	Expect(t, results[0].Text).ToBe("mail ****@*******.***")
+	Expect(t, len(results[0].Masked)).ToBe(1)
+	Expect(t, results[0].Masked[0].Kind).ToBe(extract.PIIEmail)
+	text, err := os.ReadFile(filepath.Join(dir, "a.png.txt"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(text)).ToBe(results[0].Text)
+}
+
+func TestProcessPages_Export(t *testing.T) {
+	rec := &fakeRecognizer{texts: map[byte]string{1: "one", 2: "two"}}
+	a := page(t, 0, 1)
+	chunks := []export.Chunk{}
+	sink := export.SinkFunc(func(ctx context.Context, c []export.Chunk) error {
+		chunks = append(chunks, c...)
+		return nil
+	})
+	pages := []Page{{"a.png", a}, {"a-again.png", a}, {"b.png", page(t, 0, 2)}}
+	opts := Options{Duplicates: &DuplicateOptions{}, Export: sink, ExportOptions: export.Options{Metadata: map[string]string{"run": "1"}}}
+	_, err := ProcessPages(context.Background(), rec, pages, opts)
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(chunks)).ToBe(3)
+	Expect(t, chunks[1].Source).ToBe("a-again.png")
+	Expect(t, chunks[2].Text).ToBe("two")
+	Expect(t, chunks[2].Metadata["run"]).ToBe("1")
+
+	When(t, "the sink fails", func(t *testing.T) {
+		opts.Export = export.SinkFunc(func(ctx context.Context, c []export.Chunk) error { return errors.New("index is down") })
+		_, err := ProcessPages(context.Background(), rec, pages, opts)
+		Expect(t, err).Not().ToBe(nil)
+	})
+}
+
+// clockRecognizer advances the clock by the cost of the page segmentation mode of each recognition.
+type clockRecognizer struct {
+	now   time.Time
+	costs map[gosseract.PageSegMode]time.Duration
+}
+
+func (c *clockRecognizer) TextWithOptions(ctx context.Context, data []byte, opts gosseract.Options) (string, error) {
+	c.now = c.now.Add(c.costs[opts.PageSegMode])
+	return fmt.Sprintf("psm %d", opts.PageSegMode), nil
+}
+
+func TestProcessPages_Degradation(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	rec := &clockRecognizer{now: start, costs: map[gosseract.PageSegMode]time.Duration{
+		gosseract.PSM_AUTO:         10 * time.Second,
+		gosseract.PSM_SINGLE_BLOCK: 6 * time.Second,
+		gosseract.PSM_AUTO_ONLY:    time.Second,
+	}}
+	pages := []Page{}
+	for i := 0; i < 6; i++ {
+		pages = append(pages, Page{Name: fmt.Sprint(i), Data: page(t, 0, byte(i))})
+	}
+	degradation := &Degradation{
+		Deadline: start.Add(35 * time.Second),
+		Fast:     &gosseract.Options{PageSegMode: gosseract.PSM_SINGLE_BLOCK},
+		Layout:   &gosseract.Options{PageSegMode: gosseract.PSM_AUTO_ONLY},
+		now:      func() time.Time { return rec.now },
+	}
+	opts := Options{Recognition: gosseract.Options{PageSegMode: gosseract.PSM_AUTO}, Degradation: degradation}
+	results, err := ProcessPages(context.Background(), rec, pages, opts)
+	Expect(t, err).ToBe(nil)
+	degraded := []Degraded{}
+	for _, result := range results {
+		Expect(t, result.Err).ToBe(nil)
+		degraded = append(degraded, result.Degraded)
+	}
+	// At 10s, the 5 pages left are estimated to take 50s of 25s left, and at 16s, the 4 pages left by Fast 24s of 19s.
+	Expect(t, degraded).ToBe([]Degraded{NotDegraded, DegradedFast, DegradedLayout, DegradedLayout, DegradedLayout, DegradedLayout})
+	Expect(t, results[1].Text).ToBe("psm 6")
+
+	When(t, "the deadline passes", func(t *testing.T) {
+		rec.now = start
+		degradation.Deadline = start.Add(15 * time.Second)
+		degradation.Layout = nil
+		results, err := ProcessPages(context.Background(), rec, pages, opts)
+		Expect(t, err).ToBe(nil)
+		Expect(t, len(results)).ToBe(6)
+		Expect(t, results[0].Degraded).ToBe(NotDegraded)
+		Expect(t, results[1].Degraded).ToBe(DegradedFast)
+		Expect(t, results[2].Degraded).ToBe(DegradedSkipped)
+		Expect(t, results[2].Err).ToBe(ErrSoftDeadline)
+		Expect(t, results[5].Err).ToBe(ErrSoftDeadline)
+	})
+}
@@ -0,0 +1,149 @@
+package batch
+
+import (
+	"errors"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2"
+)
+
+// Degraded is how far the recognition of a page is degraded to meet Degradation.Deadline.
+type Degraded int
+
+const (
+	// NotDegraded pages are recognized by Options.Recognition.
+	NotDegraded Degraded = iota
+	// DegradedFast pages are recognized by Degradation.Fast.
+	DegradedFast
+	// DegradedLayout pages are analyzed by Degradation.Layout.
+	DegradedLayout
+	// DegradedSkipped pages are skipped past the deadline, failing with ErrSoftDeadline.
+	DegradedSkipped
+)
+
+func (d Degraded) String() string {
+	switch d {
+	case DegradedFast:
+		return "fast"
+	case DegradedLayout:
+		return "layout"
+	case DegradedSkipped:
+		return "skipped"
+	}
+	return "none"
+}
+
+// ErrSoftDeadline is the error of pages skipped past Degradation.Deadline.
+var ErrSoftDeadline = errors.New("page skipped past the soft deadline of the run")
+
+// Degradation is the ladder of degrading the recognition of the remaining pages of a run as its soft deadline
+// approaches, so that bulk runs finish in time predictably under load: once the pages left are estimated not
+// to be recognized in time, by the mean time of the pages so far, they're recognized by Fast, then by Layout
+// once even Fast is estimated too slow, and skipped past the deadline, returning the partial results.
+// Steps without options are skipped, and steps not yet taken are estimated to fit. Pages are never promoted
+// back to better steps in the run, so that results degrade once, in order of pages.
+type Degradation struct {
+	// Deadline is the soft deadline of the run. Unlike the deadline of ctx, the run returns on time
+	// with all the pages, those skipped failing with ErrSoftDeadline.
+	Deadline time.Time
+
+	// Fast are the options of the fast model, such as Languages of tessdata_fast installed as "eng_fast".
+	// Nil not to take the step.
+	Fast *gosseract.Options
+
+	// Layout are the options of analyzing the layout only, such as PageSegMode of gosseract.PSM_AUTO_ONLY,
+	// whose texts are empty or of the blocks found, for pages to be recognized again later. Nil not to take the step.
+	Layout *gosseract.Options
+
+	// now is time.Now but for tests
+	now func() time.Time
+}
+
+// ladder is the degradation of a run in progress.
+type ladder struct {
+	d       *Degradation
+	now     func() time.Time
+	step    Degraded
+	elapsed [DegradedSkipped]time.Duration
+	counts  [DegradedSkipped]int
+}
+
+func newLadder(d *Degradation) *ladder {
+	if d == nil {
+		return nil
+	}
+	l := &ladder{d: d, now: d.now}
+	if l.now == nil {
+		l.now = time.Now
+	}
+	return l
+}
+
+// options returns the options of the next page, of the remaining pages including it, by the step of the ladder.
+func (l *ladder) options(recognition gosseract.Options, remaining int) (gosseract.Options, Degraded) {
+	if l == nil {
+		return recognition, NotDegraded
+	}
+	left := l.d.Deadline.Sub(l.now())
+	if left <= 0 {
+		l.step = DegradedSkipped
+		return recognition, DegradedSkipped
+	}
+	for ; l.step < DegradedSkipped; l.step++ {
+		opts, ok := l.stepOptions(recognition, l.step)
+		if !ok {
+			continue
+		}
+		// The cheapest step is taken anyway until the deadline.
+		if l.fits(l.step, remaining, left) || !l.cheaper(l.step) {
+			return opts, l.step
+		}
+	}
+	return recognition, DegradedSkipped
+}
+
+func (l *ladder) stepOptions(recognition gosseract.Options, step Degraded) (gosseract.Options, bool) {
+	switch step {
+	case DegradedFast:
+		if l.d.Fast == nil {
+			return recognition, false
+		}
+		return *l.d.Fast, true
+	case DegradedLayout:
+		if l.d.Layout == nil {
+			return recognition, false
+		}
+		return *l.d.Layout, true
+	}
+	return recognition, true
+}
+
+// fits reports whether the remaining pages are estimated to be done by the step in the time left.
+func (l *ladder) fits(step Degraded, remaining int, left time.Duration) bool {
+	if l.counts[step] == 0 {
+		return true
+	}
+	return l.elapsed[step]/time.Duration(l.counts[step])*time.Duration(remaining) <= left
+}
+
+// cheaper reports whether a step cheaper than the step is available.
+func (l *ladder) cheaper(step Degraded) bool {
+	for next := step + 1; next < DegradedSkipped; next++ {
+		if _, ok := l.stepOptions(gosseract.Options{}, next); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// start returns the func to record the time of the page recognized by the step.
+func (l *ladder) start(step Degraded) func() {
+	if l == nil || step == DegradedSkipped {
+		return func() {}
+	}
+	start := l.now()
+	return func() {
+		l.elapsed[step] += l.now().Sub(start)
+		l.counts[step]++
+	}
+}
@@ -0,0 +1,114 @@
+package batch
+
+import (
+	"strings"
+
+	"github.com/chennqqi/gosseract/v2"
+)
+
+// DuplicateOptions specifies how near-duplicate pages are detected. Pages are duplicates if their images
+// are identical, or look alike by perceptual hashes and their texts are similar. Identical pages aren't
+// recognized again, and near-duplicates are recognized to compare their texts.
+type DuplicateOptions struct {
+	// MaxDistance is the largest distance of perceptual hashes of duplicates, see gosseract.Fingerprint.Distance.
+	// Zero means 10.
+	MaxDistance int
+
+	// MinSimilarity is the smallest similarity of texts of duplicates, i.e. the ratio of word trigrams
+	// they share, from 0 to 1. Zero means 0.9.
+	MinSimilarity float64
+}
+
+type fingerprinted struct {
+	result      Result
+	fingerprint gosseract.Fingerprint
+	shingles    map[string]bool
+}
+
+type duplicateDetector struct {
+	opts *DuplicateOptions
+	// exact maps the hashes of the contents of the pages remembered to their results, for identical pages
+	exact map[string]Result
+	// pages are compared pairwise for pages alike
+	pages []fingerprinted
+}
+
+func newDuplicateDetector(opts *DuplicateOptions) *duplicateDetector {
+	if opts == nil {
+		return &duplicateDetector{}
+	}
+	o := *opts
+	if o.MaxDistance <= 0 {
+		o.MaxDistance = 10
+	}
+	if o.MinSimilarity <= 0 || o.MinSimilarity > 1 {
+		o.MinSimilarity = 0.9
+	}
+	return &duplicateDetector{opts: &o, exact: map[string]Result{}}
+}
+
+// identical returns the fingerprint of the page, and the result of the earlier page of the same image data, if any.
+func (d *duplicateDetector) identical(page Page) (gosseract.Fingerprint, Result, bool) {
+	if d.opts == nil {
+		return gosseract.Fingerprint{}, Result{}, false
+	}
+	fp, err := gosseract.ImageFingerprint(page.Data)
+	if err != nil {
+		return fp, Result{}, false
+	}
+	result, ok := d.exact[fp.Content]
+	return fp, result, ok
+}
+
+// check marks the result of the page of the fingerprint as a duplicate of the most similar earlier page alike,
+// if any, and remembers the page.
+func (d *duplicateDetector) check(fp gosseract.Fingerprint, result *Result) {
+	if d.opts == nil || fp.Content == "" {
+		return
+	}
+	shingles := wordShingles(result.Text)
+	for _, p := range d.pages {
+		if distance := fp.Distance(p.fingerprint); distance < 0 || distance > d.opts.MaxDistance {
+			continue
+		}
+		if similarity := jaccard(shingles, p.shingles); similarity >= d.opts.MinSimilarity && similarity > result.Similarity {
+			result.Duplicate, result.Similarity = p.result.Name, similarity
+		}
+	}
+	if result.Duplicate == "" {
+		d.pages = append(d.pages, fingerprinted{result: *result, fingerprint: fp, shingles: shingles})
+		if _, ok := d.exact[fp.Content]; !ok {
+			d.exact[fp.Content] = *result
+		}
+	}
+}
+
+// wordShingles returns the trigrams of words of the text in lower case, or the words themselves for short texts.
+func wordShingles(text string) map[string]bool {
+	words := strings.Fields(strings.ToLower(text))
+	shingles := map[string]bool{}
+	if len(words) < 3 {
+		for _, w := range words {
+			shingles[w] = true
+		}
+		return shingles
+	}
+	for i := 0; i+3 <= len(words); i++ {
+		shingles[strings.Join(words[i:i+3], " ")] = true
+	}
+	return shingles
+}
+
+// jaccard returns the ratio of the shingles in common to all of them, 1 for two empty texts.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	common := 0
+	for s := range a {
+		if b[s] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
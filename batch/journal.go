@@ -0,0 +1,140 @@
+package batch
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Journal records the pages completed by runs in a file, so that interrupted runs resume where they left off,
+// see Options.Journal. Pages are identified by their names, the contents of their data and the fingerprint
+// of the configuration, so that changed files or configurations are recognized again. Files of BatchText are
+// identified by their sizes and modification times too, to be resumed without reading them.
+// Failed pages aren't recorded, to be retried.
+type Journal struct {
+	mu   sync.Mutex
+	file *os.File
+	done map[journalKey]Result
+	// stats are the keys of the pages recorded by their versions, such as of files of BatchText
+	stats map[statKey]journalKey
+}
+
+type journalKey struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	Config  string `json:"config"`
+}
+
+// statKey identifies pages by their names and versions, without their contents.
+type statKey struct {
+	Name, Stat, Config string
+}
+
+type journalEntry struct {
+	journalKey
+	Stat   string `json:"stat,omitempty"`
+	Result Result `json:"result"`
+}
+
+// OpenJournal opens the journal of the path, creating it if it doesn't exist, to append the pages completed.
+// Lines broken by interruptions, such as the last one, are ignored. It's due to caller to Close the Journal.
+func OpenJournal(path string) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	j := &Journal{file: file, done: map[journalKey]Result{}, stats: map[statKey]journalKey{}}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		entry := journalEntry{}
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			j.done[entry.journalKey] = entry.Result
+			if entry.Stat != "" {
+				j.stats[statKey{entry.Name, entry.Stat, entry.Config}] = entry.journalKey
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read journal %s: %v", path, err)
+	}
+	// Terminate the broken line, if any, not to break the next entry.
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			file.Write([]byte{'\n'})
+		}
+	}
+	return j, nil
+}
+
+// Len returns the number of pages recorded.
+func (j *Journal) Len() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.done)
+}
+
+// Close closes the file of the journal.
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+func (j *Journal) key(page Page, config string) journalKey {
+	sum := sha256.Sum256(page.Data)
+	return journalKey{Name: page.Name, Content: hex.EncodeToString(sum[:]), Config: config}
+}
+
+// completed returns the result of the page recorded, if any.
+func (j *Journal) completed(key journalKey) (Result, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	result, ok := j.done[key]
+	return result, ok
+}
+
+// completedStat returns the result of the page of the name recorded by the version, if any.
+func (j *Journal) completedStat(name, stat, config string) (Result, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	key, ok := j.stats[statKey{name, stat, config}]
+	if !ok {
+		return Result{}, false
+	}
+	result, ok := j.done[key]
+	return result, ok
+}
+
+// record appends the result of the page of the version, if known, synced to the disk not to be lost by crashes.
+func (j *Journal) record(key journalKey, stat string, result Result) error {
+	b, err := json.Marshal(journalEntry{journalKey: key, Stat: stat, Result: result})
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	j.done[key] = result
+	if stat != "" {
+		j.stats[statKey{key.Name, stat, key.Config}] = key
+	}
+	return j.file.Sync()
+}
+
+// configFingerprint identifies the configuration of the run affecting results.
+func configFingerprint(opts Options) string {
+	b, _ := json.Marshal(struct {
+		Recognition, Downgrade interface{}
+		Config                 string
+		MaskPII                bool
+	}{opts.Recognition, opts.Downgrade, opts.Config, opts.MaskPII})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,109 @@
+package batch
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Progress is notified of the progress of runs, such as to draw progress bars or push the progress of jobs.
+// Methods are called by the goroutine of the run, in order of pages, with the index of the page from 0.
+// Deferred pages, see BudgetDefer, are notified again when they're recognized at the end of runs.
+type Progress interface {
+	OnPageStart(index, total int, name string)
+	OnPageDone(index, total int, result Result, stats Stats)
+}
+
+// Stats are the statistics of a run so far.
+type Stats struct {
+	// Done is the number of pages done, including Failed, Duplicates and Resumed ones.
+	Done       int `json:"done"`
+	Failed     int `json:"failed"`
+	Duplicates int `json:"duplicates"`
+	Resumed    int `json:"resumed"`
+
+	// Elapsed is the time since the start of the run, and Remaining the estimate of the time left,
+	// by the mean time of the pages recognized, rather than resumed, so far.
+	Elapsed   time.Duration `json:"elapsed"`
+	Remaining time.Duration `json:"remaining"`
+}
+
+// tracker updates the stats of a run and notifies the progress, if any.
+type tracker struct {
+	progress Progress
+	total    int
+	start    time.Time
+	stats    Stats
+}
+
+func newTracker(progress Progress, total int) *tracker {
+	return &tracker{progress: progress, total: total, start: time.Now()}
+}
+
+func (t *tracker) started(index int, name string) {
+	if t.progress != nil {
+		t.progress.OnPageStart(index, t.total, name)
+	}
+}
+
+// done counts the result of the page, unless it's retried later, such as pages deferred.
+func (t *tracker) done(index int, result Result, retried bool) {
+	if !retried {
+		t.stats.Done++
+		switch {
+		case result.Err != nil:
+			t.stats.Failed++
+		case result.Resumed:
+			t.stats.Resumed++
+		case result.Duplicate != "":
+			t.stats.Duplicates++
+		}
+	}
+	t.stats.Elapsed = time.Since(t.start)
+	if recognized := t.stats.Done - t.stats.Resumed; recognized > 0 {
+		t.stats.Remaining = t.stats.Elapsed / time.Duration(recognized) * time.Duration(t.total-t.stats.Done)
+	}
+	if t.progress != nil {
+		t.progress.OnPageDone(index, t.total, result, t.stats)
+	}
+}
+
+// ProgressBar draws a progress bar of runs on a terminal, such as os.Stderr,
+// e.g. "[=========>          ]  45/100  45%  3 failed  ETA 1m20s".
+type ProgressBar struct {
+	w io.Writer
+	// Width is the number of characters of the bar.
+	Width int
+}
+
+// NewProgressBar creates a ProgressBar drawing on w.
+func NewProgressBar(w io.Writer) *ProgressBar {
+	return &ProgressBar{w: w, Width: 30}
+}
+
+// OnPageStart does nothing, drawing the bar when pages are done.
+func (bar *ProgressBar) OnPageStart(index, total int, name string) {}
+
+// OnPageDone redraws the bar, ending the line when all the pages are done.
+func (bar *ProgressBar) OnPageDone(index, total int, result Result, stats Stats) {
+	filled := bar.Width
+	percent := 100
+	if total > 0 {
+		filled = bar.Width * stats.Done / total
+		percent = 100 * stats.Done / total
+	}
+	line := strings.Repeat("=", filled)
+	if filled < bar.Width {
+		line += ">" + strings.Repeat(" ", bar.Width-filled-1)
+	}
+	fmt.Fprintf(bar.w, "\r[%s] %*d/%d %3d%%", line, len(fmt.Sprint(total)), stats.Done, total, percent)
+	if stats.Failed != 0 {
+		fmt.Fprintf(bar.w, "  %d failed", stats.Failed)
+	}
+	if stats.Done < total {
+		fmt.Fprintf(bar.w, "  ETA %s", stats.Remaining.Round(time.Second))
+	} else {
+		fmt.Fprintln(bar.w)
+	}
+}
@@ -0,0 +1,15 @@
+package gosseract
+
+// TesseractBuild describes the build of tesseract linked, see BuildInfo.
+type TesseractBuild struct {
+
+	// Version of tesseract, empty without cgo.
+	Version string `json:"version"`
+
+	// OpenCL is whether tesseract is built with OpenCL and has selected an OpenCL device, such as a GPU,
+	// rather than the native CPU, which clients use unless Client.DisableOpenCL is set.
+	OpenCL bool `json:"opencl"`
+
+	// ThreadLimit is OMP_THREAD_LIMIT of the process, zero if not set, see Tuning.
+	ThreadLimit int `json:"thread_limit"`
+}
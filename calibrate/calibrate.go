@@ -0,0 +1,165 @@
+// Package calibrate maps raw confidences of tesseract to probabilities that words are correct.
+// Raw confidences are poorly calibrated, and differ from a model to another, then thresholds
+// such as "accept above 0.9" mean nothing until calibrated by an evaluation run:
+// collect Samples of recognized words checked against the ground truth, fit a Calibrator
+// by FitLogistic or FitIsotonic, keep it as JSON, and apply it to documents by Document.
+package calibrate
+
+import (
+	"math"
+	"sort"
+
+	"github.com/chennqqi/gosseract/v2/document"
+)
+
+// Calibrator maps a raw confidence, from 0 to 100, to the probability that the word is correct.
+type Calibrator interface {
+	Calibrate(confidence float64) float64
+}
+
+// Func is a user-supplied mapping as a Calibrator.
+type Func func(confidence float64) float64
+
+// Calibrate calls f.
+func (f Func) Calibrate(confidence float64) float64 {
+	return f(confidence)
+}
+
+// Sample is a recognized word of an evaluation run, with its raw confidence
+// and whether it matched the ground truth.
+type Sample struct {
+	Confidence float64 `json:"confidence"`
+	Correct    bool    `json:"correct"`
+}
+
+// Document sets the probability of each word of doc calibrated by c.
+func Document(doc *document.Document, c Calibrator) {
+	doc.EachWord(func(ref document.WordRef, word *document.Word) {
+		word.Probability = clamp(c.Calibrate(word.Confidence))
+	})
+}
+
+// Logistic is Platt scaling, i.e. the probability is 1 / (1 + exp(-(A*x + B))) for x = confidence / 100.
+type Logistic struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+}
+
+// Calibrate returns the probability for the confidence.
+func (l Logistic) Calibrate(confidence float64) float64 {
+	return 1 / (1 + math.Exp(-(l.A*confidence/100 + l.B)))
+}
+
+// FitLogistic fits Logistic to the samples by maximum likelihood, by Newton's method.
+// A slight regularization keeps it finite even if the samples are perfectly separable.
+func FitLogistic(samples []Sample) Logistic {
+	const lambda = 1e-3
+	l := Logistic{}
+	for iter := 0; iter < 100; iter++ {
+		// Gradient and Hessian of the negative log likelihood with respect to A and B.
+		ga, gb := lambda*l.A, lambda*l.B
+		haa, hab, hbb := lambda, 0.0, lambda
+		for _, s := range samples {
+			x := s.Confidence / 100
+			p := l.Calibrate(s.Confidence)
+			y := 0.0
+			if s.Correct {
+				y = 1
+			}
+			ga += (p - y) * x
+			gb += p - y
+			w := p * (1 - p)
+			haa += w * x * x
+			hab += w * x
+			hbb += w
+		}
+		det := haa*hbb - hab*hab
+		if det == 0 {
+			break
+		}
+		da, db := (hbb*ga-hab*gb)/det, (haa*gb-hab*ga)/det
+		l.A -= da
+		l.B -= db
+		if math.Abs(da) < 1e-9 && math.Abs(db) < 1e-9 {
+			break
+		}
+	}
+	return l
+}
+
+// Isotonic is a monotone piecewise linear mapping through the points,
+// which are sorted by confidence. Confidences out of the points are clamped to the ends.
+type Isotonic struct {
+	Points []Point `json:"points"`
+}
+
+// Point maps the confidence to the probability.
+type Point struct {
+	Confidence  float64 `json:"confidence"`
+	Probability float64 `json:"probability"`
+}
+
+// Calibrate returns the probability for the confidence, interpolating the points.
+func (iso Isotonic) Calibrate(confidence float64) float64 {
+	points := iso.Points
+	if len(points) == 0 {
+		return confidence / 100
+	}
+	i := sort.Search(len(points), func(i int) bool { return points[i].Confidence >= confidence })
+	switch {
+	case i == 0:
+		return points[0].Probability
+	case i == len(points):
+		return points[len(points)-1].Probability
+	}
+	lo, hi := points[i-1], points[i]
+	t := (confidence - lo.Confidence) / (hi.Confidence - lo.Confidence)
+	return lo.Probability + t*(hi.Probability-lo.Probability)
+}
+
+// FitIsotonic fits Isotonic to the samples by the pool adjacent violators algorithm,
+// which makes the probability never decrease as the confidence increases.
+func FitIsotonic(samples []Sample) Isotonic {
+	sorted := append([]Sample{}, samples...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Confidence < sorted[j].Confidence })
+	type pool struct {
+		confidence, correct, weight float64
+	}
+	// Samples of the same confidence start in the same pool.
+	initial := []pool{}
+	for _, s := range sorted {
+		if n := len(initial); n == 0 || initial[n-1].confidence != s.Confidence {
+			initial = append(initial, pool{confidence: s.Confidence})
+		}
+		last := &initial[len(initial)-1]
+		last.weight++
+		if s.Correct {
+			last.correct++
+		}
+	}
+	pools := []pool{}
+	for _, p := range initial {
+		pools = append(pools, p)
+		// Merge the last pools while they violate the monotonicity.
+		for len(pools) > 1 {
+			last, prev := pools[len(pools)-1], pools[len(pools)-2]
+			if prev.correct/prev.weight <= last.correct/last.weight {
+				break
+			}
+			pools = append(pools[:len(pools)-2], pool{
+				confidence: (prev.confidence*prev.weight + last.confidence*last.weight) / (prev.weight + last.weight),
+				correct:    prev.correct + last.correct,
+				weight:     prev.weight + last.weight,
+			})
+		}
+	}
+	iso := Isotonic{Points: make([]Point, 0, len(pools))}
+	for _, p := range pools {
+		iso.Points = append(iso.Points, Point{Confidence: p.confidence, Probability: p.correct / p.weight})
+	}
+	return iso
+}
+
+func clamp(p float64) float64 {
+	return math.Max(0, math.Min(1, p))
+}
@@ -0,0 +1,57 @@
+package calibrate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chennqqi/gosseract/v2/document"
+	. "github.com/otiai10/mint"
+)
+
+// samples are correct with the probability of confidence^2, i.e. raw confidences are overconfident.
+func testSamples() []Sample {
+	samples := []Sample{}
+	for conf := 0; conf <= 100; conf += 5 {
+		p := float64(conf) / 100 * float64(conf) / 100
+		for i := 0; i < 20; i++ {
+			samples = append(samples, Sample{Confidence: float64(conf), Correct: float64(i) < p*20})
+		}
+	}
+	return samples
+}
+
+func TestFitLogistic(t *testing.T) {
+	l := FitLogistic(testSamples())
+	Expect(t, l.A > 0).ToBe(true)
+	Expect(t, l.Calibrate(50) < 0.5).ToBe(true)
+	Expect(t, l.Calibrate(100) > 0.8).ToBe(true)
+
+	When(t, "samples are separable", func(t *testing.T) {
+		l := FitLogistic([]Sample{{10, false}, {90, true}})
+		Expect(t, math.IsNaN(l.A) || math.IsInf(l.A, 0)).ToBe(false)
+		Expect(t, l.Calibrate(90) > 0.5).ToBe(true)
+	})
+}
+
+func TestFitIsotonic(t *testing.T) {
+	iso := FitIsotonic(testSamples())
+	for i := 1; i < len(iso.Points); i++ {
+		Expect(t, iso.Points[i].Probability >= iso.Points[i-1].Probability).ToBe(true)
+	}
+	Expect(t, iso.Calibrate(50) > 0.2 && iso.Calibrate(50) < 0.3).ToBe(true)
+	Expect(t, iso.Calibrate(-10)).ToBe(iso.Points[0].Probability)
+
+	When(t, "samples of the same confidence disagree", func(t *testing.T) {
+		iso := FitIsotonic([]Sample{{50, false}, {50, true}})
+		Expect(t, len(iso.Points)).ToBe(1)
+		Expect(t, iso.Calibrate(50)).ToBe(0.5)
+	})
+}
+
+func TestDocument(t *testing.T) {
+	doc := &document.Document{Blocks: []document.Block{{Paragraphs: []document.Paragraph{{Lines: []document.Line{{
+		Words: []document.Word{{Text: "foo", Confidence: 90}},
+	}}}}}}}
+	Document(doc, Func(func(c float64) float64 { return c / 50 }))
+	Expect(t, doc.Words()[0].Probability).ToBe(1.0)
+}
@@ -4,10 +4,14 @@
 package gosseract
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"image"
 	"os"
+
+	"github.com/chennqqi/gosseract/v2/document"
+	"github.com/chennqqi/gosseract/v2/normalize"
 )
 
 var ErrNotImplementWithoutCGO = errors.New("Not implement when without cgo")
@@ -17,10 +21,29 @@ func Version() string {
 	return ""
 }
 
+// BuildInfo returns the build of tesseract linked, which is unknown without cgo.
+func BuildInfo() TesseractBuild {
+	return TesseractBuild{ThreadLimit: ThreadLimit()}
+}
+
 // ClearPersistentCache clears any library-level memory caches. There are a variety of expensive-to-load constant data structures (mostly language dictionaries) that are cached globally – surviving the Init() and End() of individual TessBaseAPI's. This function allows the clearing of these caches.
+// Languages preloaded by PreloadLanguages are released as well.
 func ClearPersistentCache() {
 }
 
+// PreloadLanguages loads dictionaries of given languages into the library-level caches of tesseract,
+// which survive the Init() and End() of individual TessBaseAPI's, so that initializing clients
+// afterwards gets faster. It loads each language once per process, from the default tessdata directory.
+// Use ClearPersistentCache to release them under memory pressure.
+func PreloadLanguages(langs ...string) error {
+	return ErrNotImplementWithoutCGO
+}
+
+// DetectLanguages ranks the candidate languages by how well each of them matches the image data, best first.
+func DetectLanguages(data []byte, candidates []string) ([]LanguageScore, error) {
+	return nil, ErrNotImplementWithoutCGO
+}
+
 // Client is argument builder for tesseract::TessBaseAPI.
 type Client struct {
 
@@ -46,9 +69,49 @@ type Client struct {
 	// TODO: Fix link to official page
 	ConfigFilePath string
 
+	// Preprocess specifies image preprocessing applied before OCR.
+	// See PreprocessOptions for available steps.
+	Preprocess PreprocessOptions
+
+	// Normalize specifies script-specific normalization applied to the text and documents recognized.
+	// Documents keep the text as recognized in Word.Original.
+	Normalize normalize.Options
+
+	// Grayscale converts color images into grayscale by Go before handing them to tesseract, which saves memory
+	// and time of Leptonica for large color scans where color carries no information.
+	// Only PNG, JPEG and GIF are converted, and their resolution is left for tesseract to estimate.
+	Grayscale bool
+
+	// MaxImagePixels limits width times height of images set, read from their headers before decoding them,
+	// so that decompression bombs of small files are rejected by ImageError rather than exhausting memory.
+	// DefaultMaxImagePixels if zero, or no limit if negative.
+	MaxImagePixels int
+
+	// DisableOpenCL keeps tesseract from using OpenCL for images of this client, by binarizing them by Leptonica
+	// before tesseract, since thresholding is what tesseract accelerates by OpenCL. Thresholding methods of tesseract
+	// are bypassed then. It has no effect unless tesseract uses OpenCL, see BuildInfo.
+	DisableOpenCL bool
+
+	// Policy admits or rejects images before recognition, see Policy. Every image is admitted if nil.
+	Policy Policy
+
+	// Recorder receives the bundles of recognitions of TextWithOptions and TextWithConfidence, such as
+	// RecordFailures, to reproduce them by Replay. Nothing is recorded if nil.
+	Recorder Recorder
+
+	// TempDir is the directory to create temporary files in, see CreateTemp.
+	// If empty, the default directory for temporary files of the OS is used.
+	TempDir string
+
+	// temporary directory private to this client, removed on Close
+	tempDir string
+
 	// internal flag to check if the instance should be initialized again
 	// i.e, we should create a new gosseract client when language or config file change
 	shouldInit bool
+
+	// internal flag to check if the instance is already closed
+	closed bool
 }
 
 // NewClient construct new Client. It's due to caller to Close this client.
@@ -63,10 +126,19 @@ func NewClient() *Client {
 }
 
 // Close frees allocated API. This MUST be called for ANY client constructed by "NewClient" function.
+// Any method requiring the API returns ErrClientClosed after Close, so does Close itself.
+// Temporary files created by CreateTemp are removed as well.
 func (client *Client) Close() (err error) {
+	client.removeTempDir()
+	client.closed = true
 	return ErrNotImplementWithoutCGO
 }
 
+// OpenCLActive reports whether tesseract uses OpenCL for images of this client.
+func (client *Client) OpenCLActive() bool {
+	return false
+}
+
 // Version provides the version of Tesseract used by this client.
 func (client *Client) Version() string {
 	return ""
@@ -83,6 +155,7 @@ func (client *Client) SetImageFromBytes(data []byte) error {
 }
 
 // SetLanguage sets languages to use. English as default.
+// Variables registered for the languages are applied on the next recognition, see LanguageVariables.
 func (client *Client) SetLanguage(langs ...string) error {
 	if len(langs) == 0 {
 		return fmt.Errorf("languages cannot be empty")
@@ -139,6 +212,13 @@ func (client *Client) SetPageSegMode(mode PageSegMode) error {
 	return ErrNotImplementWithoutCGO
 }
 
+// SetThresholdingMethod sets the algorithm to binarize images, representing "thresholding_method" of tesseract.
+// Tesseract earlier than 5.0 cannot select it, then THRESHOLD_SAUVOLA falls back on gosseract's own
+// Sauvola binarization (see PreprocessOptions.Sauvola), and THRESHOLD_LEPTONICA_OTSU is rejected.
+func (client *Client) SetThresholdingMethod(method ThresholdingMethod) error {
+	return ErrNotImplementWithoutCGO
+}
+
 // SetConfigFile sets the file path to config file.
 func (client *Client) SetConfigFile(fpath string) error {
 	return ErrNotImplementWithoutCGO
@@ -155,6 +235,14 @@ func (client *Client) init() error {
 	return ErrNotImplementWithoutCGO
 }
 
+// Warmup initializes TessBaseAPI and runs recognition once on a tiny built-in image,
+// so that loading models doesn't slow down the first real request, e.g. on cold starts of serverless.
+// The image set by SetImage or SetImageFromBytes is kept as it is.
+// Because a running step of tesseract cannot be interrupted, ctx is checked between the steps.
+func (client *Client) Warmup(ctx context.Context) error {
+	return ErrNotImplementWithoutCGO
+}
+
 // This method flag the current instance to be initialized again on the next call to a function that
 // requires a gosseract API initialized: when user change the config file or the languages
 // the instance needs to init a new gosseract api
@@ -185,12 +273,62 @@ func (client *Client) Text() (out string, err error) {
 
 }
 
+// TextWithOptions recognizes the image data with the configuration of this client overridden by opts,
+// and restores the configuration afterwards.
+func (client *Client) TextWithOptions(ctx context.Context, data []byte, opts Options) (string, error) {
+	return "", ErrNotImplementWithoutCGO
+}
+
+// TextWithConfidence recognizes the image data like TextWithOptions, along with the mean confidence of the words.
+func (client *Client) TextWithConfidence(ctx context.Context, data []byte, opts Options) (string, float64, error) {
+	return "", 0, ErrNotImplementWithoutCGO
+}
+
 // HOCRText finally initialize tesseract::TessBaseAPI, execute OCR and returns hOCR text.
 // See https://en.wikipedia.org/wiki/HOCR for more information of hOCR.
 func (client *Client) HOCRText() (out string, err error) {
 	return out, ErrNotImplementWithoutCGO
 }
 
+// TextOfZones recognizes the zones of the image set, thresholding the image once for all the zones.
+func (client *Client) TextOfZones(ctx context.Context, zones []Zone) ([]ZoneText, error) {
+	return nil, ErrNotImplementWithoutCGO
+}
+
+// Symbols recognizes the image and returns the symbols with their choices in reading order.
+func (client *Client) Symbols() ([]Symbol, error) {
+	return nil, ErrNotImplementWithoutCGO
+}
+
+// DecodeText recognizes the image and decodes the text of the symbols by the decoder, such as Constraint.
+func (client *Client) DecodeText(dec Decoder) (string, float64, error) {
+	return "", 0, ErrNotImplementWithoutCGO
+}
+
+// Document finally initialize tesseract::TessBaseAPI, execute OCR and returns the layout of the page.
+func (client *Client) Document() (*document.Document, error) {
+	return nil, ErrNotImplementWithoutCGO
+}
+
+// DocumentWithContext recognizes the image like Document, returning the words recognized so far when ctx is done.
+func (client *Client) DocumentWithContext(ctx context.Context) (*document.Document, error) {
+	return nil, ErrNotImplementWithoutCGO
+}
+
+// Reclaim frees memory cached by this client, i.e. the preprocessed image and the recognition results
+// held by TessBaseAPI, which are rebuilt by the next recognition.
+// The image set by SetImage or SetImageFromBytes is kept.
+func (client *Client) Reclaim() {
+}
+
+// ImageScale returns the factor by which the image was downscaled for OCR,
+// because of MaxDimension or MaxPixels of client.Preprocess.
+// Bounding boxes are mapped back to the original image by gosseract, but hOCR is not,
+// so divide coordinates in hOCR by this factor to get the original ones.
+func (client *Client) ImageScale() float64 {
+	return 1
+}
+
 // BoundingBox contains the position, confidence and UTF8 text of the recognized word
 type BoundingBox struct {
 	Box                                image.Rectangle
@@ -214,3 +352,41 @@ func GetAvailableLanguages() ([]string, error) {
 func (client *Client) GetBoundingBoxesVerbose() (out []BoundingBox, err error) {
 	return nil, ErrNotImplementWithoutCGO
 }
+
+// ResultIterator walks the results of the last recognition of Client element by element, see Client.Iterator.
+type ResultIterator struct{}
+
+// Iterator recognizes the image and returns ResultIterator walking the results at the level.
+func (client *Client) Iterator(level PageIteratorLevel) (*ResultIterator, error) {
+	return nil, ErrNotImplementWithoutCGO
+}
+
+// Next moves the iterator to the next element, and reports whether there is one.
+func (it *ResultIterator) Next() bool {
+	return false
+}
+
+// Err returns the error which stopped Next.
+func (it *ResultIterator) Err() error {
+	return ErrNotImplementWithoutCGO
+}
+
+// Text returns the text of the element.
+func (it *ResultIterator) Text() (string, error) {
+	return "", ErrNotImplementWithoutCGO
+}
+
+// Confidence returns the confidence of the element from 0 to 100.
+func (it *ResultIterator) Confidence() (float64, error) {
+	return 0, ErrNotImplementWithoutCGO
+}
+
+// BoundingBox returns the bounding box of the element.
+func (it *ResultIterator) BoundingBox() (image.Rectangle, error) {
+	return image.Rectangle{}, ErrNotImplementWithoutCGO
+}
+
+// Close deletes the iterator.
+func (it *ResultIterator) Close() error {
+	return nil
+}
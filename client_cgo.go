@@ -10,12 +10,21 @@ package gosseract
 
 import "C"
 import (
+	"context"
 	"fmt"
 	"image"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
+
+	"github.com/chennqqi/gosseract/v2/document"
+	"github.com/chennqqi/gosseract/v2/normalize"
 )
 
 // Version returns the version of Tesseract-OCR
@@ -26,11 +35,102 @@ func Version() string {
 	return C.GoString(version)
 }
 
+// BuildInfo returns the build of tesseract linked, such as whether it accelerates recognitions by OpenCL.
+func BuildInfo() TesseractBuild {
+	return TesseractBuild{Version: Version(), OpenCL: openCLAvailable(), ThreadLimit: ThreadLimit()}
+}
+
+var openCL struct {
+	once      sync.Once
+	available bool
+}
+
+// openCLAvailable reports whether tesseract has selected an OpenCL device, which it does once per process.
+func openCLAvailable() bool {
+	openCL.once.Do(func() {
+		openCL.available = bool(C.OpenCLAvailable())
+	})
+	return openCL.available
+}
+
 // ClearPersistentCache clears any library-level memory caches. There are a variety of expensive-to-load constant data structures (mostly language dictionaries) that are cached globally – surviving the Init() and End() of individual TessBaseAPI's. This function allows the clearing of these caches.
+// Languages preloaded by PreloadLanguages are released as well.
 func ClearPersistentCache() {
+	preloaded.Lock()
+	defer preloaded.Unlock()
 	api := C.Create()
 	defer C.Free(api)
 	C.ClearPersistentCache(api)
+	preloaded.languages = map[string]bool{}
+}
+
+// preloaded records languages already loaded into the library-level caches by PreloadLanguages.
+var preloaded = struct {
+	sync.Mutex
+	languages map[string]bool
+}{languages: map[string]bool{}}
+
+// PreloadLanguages loads dictionaries of given languages into the library-level caches of tesseract,
+// which survive the Init() and End() of individual TessBaseAPI's, so that initializing clients
+// afterwards gets faster. It loads each language once per process, from the default tessdata directory.
+// Use ClearPersistentCache to release them under memory pressure.
+func PreloadLanguages(langs ...string) error {
+	if len(langs) == 0 {
+		return fmt.Errorf("languages cannot be empty")
+	}
+	preloaded.Lock()
+	defer preloaded.Unlock()
+	for _, lang := range langs {
+		if preloaded.languages[lang] {
+			continue
+		}
+		client := NewClient()
+		client.SetLanguage(lang)
+		err := client.initAPI()
+		client.Close()
+		if err != nil {
+			return fmt.Errorf("failed to preload language %s: %v", lang, err)
+		}
+		preloaded.languages[lang] = true
+	}
+	return nil
+}
+
+// DetectLanguages ranks the candidate languages by how well each of them matches the image data, best first,
+// so that the right model can be picked before the expensive full recognition.
+// It recognizes a downscaled copy of the image once for each candidate, from the default tessdata directory.
+func DetectLanguages(data []byte, candidates []string) ([]LanguageScore, error) {
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("candidate languages cannot be empty")
+	}
+	scores := make([]LanguageScore, 0, len(candidates))
+	for _, lang := range candidates {
+		score, err := languageScore(data, lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to score language %s: %v", lang, err)
+		}
+		scores = append(scores, LanguageScore{Language: lang, Score: score})
+	}
+	sortLanguageScores(scores)
+	return scores, nil
+}
+
+// languageScore recognizes the image data downscaled in the language and returns the mean confidence.
+func languageScore(data []byte, lang string) (float64, error) {
+	client := NewClient()
+	defer client.Close()
+	client.Languages = []string{lang}
+	client.Preprocess.MaxDimension = detectionMaxDimension
+	if err := client.SetImageFromBytes(data); err != nil {
+		return 0, err
+	}
+	if err := client.init(); err != nil {
+		return 0, err
+	}
+	if err := client.recognize(context.Background()); err != nil {
+		return 0, err
+	}
+	return float64(C.MeanTextConf(client.api)), nil
 }
 
 // Client is argument builder for tesseract::TessBaseAPI.
@@ -41,6 +141,24 @@ type Client struct {
 	// or when a new image is set
 	pixImage C.PixImage
 
+	// Holds a reference to the pix image preprocessed from pixImage,
+	// which is reused until the image or the preprocess options change
+	preparedImage C.PixImage
+	preparedWith  PreprocessOptions
+	preparedOtsu  bool
+	preparedScale float64
+
+	// Holds a reference to the binary image tesseract thresholded from preparedImage for zones,
+	// by the values of thresholdingVariables of thresholdedWith, see TextOfZones
+	thresholded     C.PixImage
+	thresholdedWith string
+
+	// boxes of the rules erased from the image prepared, see PreprocessOptions.RemoveGridLines
+	gridLines []image.Rectangle
+
+	// whether Policy has admitted pixImage
+	admitted bool
+
 	// Trim specifies characters to trim, which would be trimed from result string.
 	// As results of OCR, text often contains unnecessary characters, such as newlines, on the head/foot of string.
 	// If `Trim` is set, this client will remove specified characters from the result.
@@ -63,15 +181,61 @@ type Client struct {
 	// TODO: Fix link to official page
 	ConfigFilePath string
 
+	// Preprocess specifies image preprocessing applied before OCR.
+	// See PreprocessOptions for available steps.
+	Preprocess PreprocessOptions
+
+	// Normalize specifies script-specific normalization applied to the text and documents recognized.
+	// Documents keep the text as recognized in Word.Original.
+	Normalize normalize.Options
+
+	// Grayscale converts color images into grayscale by Go before handing them to tesseract, which saves memory
+	// and time of Leptonica for large color scans where color carries no information.
+	// Only PNG, JPEG and GIF are converted, and their resolution is left for tesseract to estimate.
+	Grayscale bool
+
+	// MaxImagePixels limits width times height of images set, read from their headers before decoding them,
+	// so that decompression bombs of small files are rejected by ImageError rather than exhausting memory.
+	// DefaultMaxImagePixels if zero, or no limit if negative.
+	MaxImagePixels int
+
+	// DisableOpenCL keeps tesseract from using OpenCL for images of this client, by binarizing them by Leptonica
+	// before tesseract, since thresholding is what tesseract accelerates by OpenCL. Thresholding methods of tesseract
+	// are bypassed then. It has no effect unless tesseract uses OpenCL, see BuildInfo.
+	DisableOpenCL bool
+
+	// Policy admits or rejects images before recognition, see Policy. Every image is admitted if nil.
+	Policy Policy
+
+	// Recorder receives the bundles of recognitions of TextWithOptions and TextWithConfidence, such as
+	// RecordFailures, to reproduce them by Replay. Nothing is recorded if nil.
+	Recorder Recorder
+
+	// TempDir is the directory to create temporary files in, see CreateTemp.
+	// If empty, the default directory for temporary files of the OS is used.
+	TempDir string
+
+	// temporary directory private to this client, removed on Close
+	tempDir string
+
+	// result iterators not closed, released before TessBaseAPI frees the results they walk
+	iterators map[*ResultIterator]struct{}
+
 	// internal flag to check if the instance should be initialized again
 	// i.e, we should create a new gosseract client when language or config file change
 	shouldInit bool
+
+	// internal flag to check if the instance is already closed
+	closed bool
+
+	// serializes TextWithOptions on the same client
+	mu sync.Mutex
 }
 
 // NewClient construct new Client. It's due to caller to Close this client.
 func NewClient() *Client {
 	client := &Client{
-		api:        C.Create(),
+		api:        newAPI(),
 		Variables:  map[SettableVariable]string{},
 		Trim:       true,
 		shouldInit: true,
@@ -81,32 +245,66 @@ func NewClient() *Client {
 }
 
 // Close frees allocated API. This MUST be called for ANY client constructed by "NewClient" function.
+// Any method requiring the API returns ErrClientClosed after Close, so does Close itself.
+// Temporary files created by CreateTemp are removed as well.
 func (client *Client) Close() (err error) {
 	// defer func() {
 	// 	if e := recover(); e != nil {
 	// 		err = fmt.Errorf("%v", e)
 	// 	}
 	// }()
-	C.Clear(client.api)
-	C.Free(client.api)
+	if client.closed {
+		return ErrClientClosed
+	}
+	client.releaseIterators()
+	if client.api != nil {
+		C.Clear(client.api)
+		freeAPI(client.api)
+		client.api = nil
+	}
+	client.releasePreparedImage()
 	if client.pixImage != nil {
-		C.DestroyPixImage(client.pixImage)
+		destroyPixImage(client.pixImage)
 		client.pixImage = nil
 	}
-	return err
+	client.closed = true
+	client.shouldInit = true
+	return client.removeTempDir()
 }
 
-// Version provides the version of Tesseract used by this client.
+// checkAPI returns an error if TessBaseAPI is not available,
+// because the client is not constructed by NewClient, or already closed.
+func (client *Client) checkAPI() error {
+	if client.closed {
+		return ErrClientClosed
+	}
+	if client.api == nil {
+		return fmt.Errorf("TessBaseAPI is not constructed, please use `gosseract.NewClient`")
+	}
+	return nil
+}
+
+// OpenCLActive reports whether tesseract uses OpenCL for images of this client,
+// i.e. tesseract is built with OpenCL, has selected an OpenCL device, and DisableOpenCL isn't set.
+func (client *Client) OpenCLActive() bool {
+	return openCLAvailable() && !client.DisableOpenCL
+}
+
+// Version provides the version of Tesseract used by this client, or "" after Close.
 func (client *Client) Version() string {
+	if client.checkAPI() != nil {
+		return ""
+	}
 	version := C.Version(client.api)
 	return C.GoString(version)
 }
 
 // SetImage sets path to image file to be processed OCR.
+// It returns ImageError for files of unknown formats, malformed, or larger than MaxImagePixels.
 func (client *Client) SetImage(imagepath string) error {
 
-	if client.api == nil {
-		return fmt.Errorf("TessBaseAPI is not constructed, please use `gosseract.NewClient`")
+	if err := client.checkAPI(); err != nil {
+		return err
 	}
 	if imagepath == "" {
 		return fmt.Errorf("image path cannot be empty")
@@ -114,43 +312,86 @@ func (client *Client) SetImage(imagepath string) error {
 	if _, err := os.Stat(imagepath); err != nil {
 		return fmt.Errorf("cannot detect the stat of specified file: %v", err)
 	}
+	if client.Grayscale {
+		data, err := os.ReadFile(imagepath)
+		if err != nil {
+			return err
+		}
+		return client.SetImageFromBytes(data)
+	}
 
+	client.releasePreparedImage()
 	if client.pixImage != nil {
-		C.DestroyPixImage(client.pixImage)
+		destroyPixImage(client.pixImage)
 		client.pixImage = nil
 	}
+	client.admitted = false
 
 	p := C.CString(imagepath)
 	defer C.free(unsafe.Pointer(p))
 
-	img := C.CreatePixImageByFilePath(p)
+	var width, height C.int
+	if C.ReadPixImageHeaderByFilePath(p, &width, &height) != 0 {
+		return &ImageError{Reason: "unknown format or malformed header"}
+	}
+	if err := checkDimensions(int(width), int(height), client.MaxImagePixels); err != nil {
+		return err
+	}
+	img := trackPixImage(C.CreatePixImageByFilePath(p))
+	if img == nil {
+		return &ImageError{Reason: "failed to decode"}
+	}
 	client.pixImage = img
 
 	return nil
 }
 
 // SetImageFromBytes sets the image data to be processed OCR.
+// It returns ImageError for data of unknown formats, malformed, or larger than MaxImagePixels.
 func (client *Client) SetImageFromBytes(data []byte) error {
 
-	if client.api == nil {
-		return fmt.Errorf("TessBaseAPI is not constructed, please use `gosseract.NewClient`")
+	if err := client.checkAPI(); err != nil {
+		return err
 	}
 	if len(data) == 0 {
 		return fmt.Errorf("image data cannot be empty")
 	}
 
+	client.releasePreparedImage()
 	if client.pixImage != nil {
-		C.DestroyPixImage(client.pixImage)
+		destroyPixImage(client.pixImage)
 		client.pixImage = nil
 	}
+	client.admitted = false
 
-	img := C.CreatePixImageFromBytes((*C.uchar)(unsafe.Pointer(&data[0])), C.int(len(data)))
+	// Leptonica takes the size by int, and its decoders allocate the pixels as the header says,
+	// so the header is checked before decoding.
+	if len(data) > math.MaxInt32 {
+		return &ImageError{Reason: fmt.Sprintf("%d bytes of data exceeds the limit of %d", len(data), math.MaxInt32), TooLarge: true}
+	}
+	var width, height C.int
+	if C.ReadPixImageHeader((*C.uchar)(unsafe.Pointer(&data[0])), C.int(len(data)), &width, &height) != 0 {
+		return &ImageError{Reason: "unknown format or malformed header"}
+	}
+	if err := checkDimensions(int(width), int(height), client.MaxImagePixels); err != nil {
+		return err
+	}
+	if client.Grayscale {
+		if gray := grayscale(data, client.MaxImagePixels); gray != nil {
+			data = gray
+		}
+	}
+	img := trackPixImage(C.CreatePixImageFromBytes((*C.uchar)(unsafe.Pointer(&data[0])), C.int(len(data))))
+	if img == nil {
+		return &ImageError{Reason: "failed to decode"}
+	}
 	client.pixImage = img
 
 	return nil
 }
 
 // SetLanguage sets languages to use. English as default.
+// Variables registered for the languages are applied on the next recognition, see LanguageVariables.
 func (client *Client) SetLanguage(langs ...string) error {
 	if len(langs) == 0 {
 		return fmt.Errorf("languages cannot be empty")
@@ -197,6 +438,9 @@ func (client *Client) SetBlacklist(blacklist string) error {
 // Because `api->SetVariable` must be called after `api->Init`, this method cannot detect unexpected key for variables.
 // Check `client.setVariablesToInitializedAPI` for more information.
 func (client *Client) SetVariable(key SettableVariable, value string) error {
+	if client.closed {
+		return ErrClientClosed
+	}
 	client.Variables[key] = value
 
 	client.setVariablesToInitializedAPIIfNeeded()
@@ -208,10 +452,33 @@ func (client *Client) SetVariable(key SettableVariable, value string) error {
 // See official documentation for PSM here https://tesseract-ocr.github.io/tessdoc/ImproveQuality#page-segmentation-method
 // See https://github.com/otiai10/gosseract/issues/52 for more information.
 func (client *Client) SetPageSegMode(mode PageSegMode) error {
+	if err := client.checkAPI(); err != nil {
+		return err
+	}
 	C.SetPageSegMode(client.api, C.int(mode))
 	return nil
 }
 
+// SetThresholdingMethod sets the algorithm to binarize images, representing "thresholding_method" of tesseract.
+// Tesseract earlier than 5.0 cannot select it, then THRESHOLD_SAUVOLA falls back on gosseract's own
+// Sauvola binarization (see PreprocessOptions.Sauvola), and THRESHOLD_LEPTONICA_OTSU is rejected.
+func (client *Client) SetThresholdingMethod(method ThresholdingMethod) error {
+	if err := client.checkAPI(); err != nil {
+		return err
+	}
+	if method < THRESHOLD_OTSU || method > THRESHOLD_SAUVOLA {
+		return fmt.Errorf("unknown thresholding method: %d", method)
+	}
+	if majorVersion(client.Version()) >= 5 {
+		return client.SetVariable(THRESHOLDING_METHOD, strconv.Itoa(int(method)))
+	}
+	if method == THRESHOLD_LEPTONICA_OTSU {
+		return fmt.Errorf("thresholding method %d is not supported by tesseract %s", method, client.Version())
+	}
+	client.Preprocess.Sauvola = method == THRESHOLD_SAUVOLA
+	return nil
+}
+
 // SetConfigFile sets the file path to config file.
 func (client *Client) SetConfigFile(fpath string) error {
 	info, err := os.Stat(fpath)
@@ -241,11 +508,58 @@ func (client *Client) SetTessdataPrefix(prefix string) error {
 
 // Initialize tesseract::TessBaseAPI
 func (client *Client) init() error {
+	return client.prepare(context.Background())
+}
 
-	if !client.shouldInit {
-		C.SetPixImage(client.api, client.pixImage)
+// prepare is init admitting the image by Policy with ctx, which carries the metadata of the caller.
+func (client *Client) prepare(ctx context.Context) error {
+
+	if err := client.checkAPI(); err != nil {
+		return err
+	}
+
+	if client.shouldInit {
+		if err := client.initAPI(); err != nil {
+			return err
+		}
+	}
+
+	if client.pixImage == nil {
+		return fmt.Errorf("PixImage is not set, use SetImage or SetImageFromBytes before Text or HOCRText")
+	}
+
+	if err := client.admit(ctx); err != nil {
+		return err
+	}
+
+	client.releaseIterators()
+	C.SetPixImage(client.api, client.preparedPixImage())
+
+	return nil
+}
+
+// admit calls Policy for the image unless it's admitted already.
+func (client *Client) admit(ctx context.Context) error {
+	if client.Policy == nil || client.admitted {
 		return nil
 	}
+	admission := Admission{
+		Width:    int(C.PixImageWidth(client.pixImage)),
+		Height:   int(C.PixImageHeight(client.pixImage)),
+		Entropy:  float64(C.PixImageEntropy(client.pixImage)),
+		Metadata: MetadataFrom(ctx),
+	}
+	if err := client.Policy(ctx, admission); err != nil {
+		return err
+	}
+	client.admitted = true
+	return nil
+}
+
+// initAPI initializes TessBaseAPI with the languages, the config file and the variables of this client.
+func (client *Client) initAPI() error {
+
+	client.releaseIterators()
 
 	var languages *C.char
 	if len(client.Languages) != 0 {
@@ -265,7 +579,7 @@ func (client *Client) init() error {
 	}
 	defer C.free(unsafe.Pointer(tessdataPrefix))
 
-	errbuf := [512]C.char{}
+	errbuf := [C.ERRBUF_SIZE]C.char{}
 	res := C.Init(client.api, tessdataPrefix, languages, configfile, &errbuf[0])
 	msg := C.GoString(&errbuf[0])
 
@@ -273,19 +587,169 @@ func (client *Client) init() error {
 		return fmt.Errorf("failed to initialize TessBaseAPI with code %d: %s", res, msg)
 	}
 
+	// Variables of the languages unknown to the tesseract linked, such as of the legacy engine, are skipped.
+	for key, value := range LanguageVariables(client.Languages...) {
+		if _, ok := client.Variables[key]; !ok {
+			client.setAPIVariable(key, value)
+		}
+	}
 	if err := client.setVariablesToInitializedAPI(); err != nil {
 		return err
 	}
 
+	client.shouldInit = false
+
+	return nil
+}
+
+// Warmup initializes TessBaseAPI and runs recognition once on a tiny built-in image,
+// so that loading models doesn't slow down the first real request, e.g. on cold starts of serverless.
+// The image set by SetImage or SetImageFromBytes is kept as it is.
+// Because a running step of tesseract cannot be interrupted, ctx is checked between the steps.
+func (client *Client) Warmup(ctx context.Context) error {
+	if err := client.checkAPI(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if client.shouldInit {
+		if err := client.initAPI(); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data := warmupImage()
+	img := trackPixImage(C.CreatePixImageFromBytes((*C.uchar)(unsafe.Pointer(&data[0])), C.int(len(data))))
+	if img == nil {
+		return fmt.Errorf("failed to read the warm-up image")
+	}
+	defer destroyPixImage(img)
+	client.releaseIterators()
+	C.SetPixImage(client.api, img)
+	errbuf := [C.ERRBUF_SIZE]C.char{}
+	res := C.Recognize(client.api, &errbuf[0])
+	if err := bridgeError("Recognize", &errbuf[0]); err != nil {
+		return err
+	}
+	if res != 0 {
+		return fmt.Errorf("failed to recognize the warm-up image with code %d", res)
+	}
+	return nil
+}
+
+// preparedPixImage returns the image to be passed to TessBaseAPI,
+// applying client.Preprocess to the image set by SetImage or SetImageFromBytes.
+// The result is cached, so that preprocessing runs only once for each image.
+func (client *Client) preparedPixImage() C.PixImage {
 	if client.pixImage == nil {
-		return fmt.Errorf("PixImage is not set, use SetImage or SetImageFromBytes before Text or HOCRText")
+		return nil
+	}
+	otsu := client.DisableOpenCL && openCLAvailable()
+	if client.preparedImage != nil && client.preparedWith == client.Preprocess && client.preparedOtsu == otsu {
+		return client.preparedImage
+	}
+	client.releasePreparedImage()
+
+	img := client.pixImage
+	client.preparedScale = 1
+	w, h := int(C.PixImageWidth(img)), int(C.PixImageHeight(img))
+	if scale := client.Preprocess.downscaleFactor(w, h); scale < 1 {
+		img = client.applyPreprocess(img, C.ScalePixImage(img, C.float(scale)))
+		client.preparedScale = scale
+	}
+	if client.Preprocess.Rectify {
+		img = client.applyPreprocess(img, C.RectifyPixImage(img))
+	}
+	if client.Preprocess.NormalizeBackground {
+		img = client.applyPreprocess(img, C.NormalizeBackgroundPixImage(img))
+	}
+	if client.Preprocess.Contrast {
+		img = client.applyPreprocess(img, C.ContrastNormalizePixImage(img))
+	}
+	if client.Preprocess.RemoveGridLines {
+		img = client.applyPreprocess(img, client.removeGridLines(img))
+	}
+	if client.Preprocess.JoinDots {
+		img = client.applyPreprocess(img, C.JoinDotsPixImage(img))
+	}
+	if client.Preprocess.SevenSegment {
+		img = client.applyPreprocess(img, C.JoinSegmentsPixImage(img))
+	}
+	if client.Preprocess.Sauvola {
+		img = client.applyPreprocess(img, C.SauvolaBinarizePixImage(img))
+	}
+	if otsu {
+		img = client.applyPreprocess(img, C.OtsuBinarizePixImage(img))
 	}
 
-	C.SetPixImage(client.api, client.pixImage)
+	client.preparedImage = img
+	client.preparedWith = client.Preprocess
+	client.preparedOtsu = otsu
+	return img
+}
 
-	client.shouldInit = false
+// Reclaim frees memory cached by this client, i.e. the preprocessed image and the recognition results
+// held by TessBaseAPI, which are rebuilt by the next recognition.
+// The image set by SetImage or SetImageFromBytes is kept.
+func (client *Client) Reclaim() {
+	client.releasePreparedImage()
+	client.releaseIterators()
+	if client.api != nil {
+		C.Clear(client.api)
+	}
+}
 
-	return nil
+// ImageScale returns the factor by which the image was downscaled for OCR,
+// because of MaxDimension or MaxPixels of client.Preprocess.
+// Bounding boxes are mapped back to the original image by gosseract, but hOCR is not,
+// so divide coordinates in hOCR by this factor to get the original ones.
+func (client *Client) ImageScale() float64 {
+	if client.preparedScale == 0 {
+		return 1
+	}
+	return client.preparedScale
+}
+
+// applyPreprocess takes the result of a preprocess step applied to img.
+// Steps return NULL when they have nothing to do, then img itself is kept.
+func (client *Client) applyPreprocess(img, result C.PixImage) C.PixImage {
+	if result == nil {
+		return img
+	}
+	if img != client.pixImage {
+		destroyPixImage(img)
+	}
+	return trackPixImage(result)
+}
+
+// removeGridLines erases the rules of the image, keeping their boxes in the coordinates of the original image.
+func (client *Client) removeGridLines(img C.PixImage) C.PixImage {
+	var lines *C.int
+	var count C.int
+	result := C.RemoveGridLinesPixImage(img, &lines, &count)
+	if lines != nil {
+		defer C.free(unsafe.Pointer(lines))
+		coords := unsafe.Slice(lines, 4*int(count))
+		for i := 0; i < int(count); i++ {
+			box := image.Rect(int(coords[4*i]), int(coords[4*i+1]), int(coords[4*i+2]), int(coords[4*i+3]))
+			client.gridLines = append(client.gridLines, unscaleRect(box, client.preparedScale))
+		}
+	}
+	return result
+}
+
+// releasePreparedImage destroys the cached preprocessed image, if it's not the original one.
+func (client *Client) releasePreparedImage() {
+	if client.preparedImage != nil && client.preparedImage != client.pixImage {
+		destroyPixImage(client.preparedImage)
+	}
+	client.preparedImage = nil
+	destroyPixImage(client.thresholded)
+	client.thresholded = nil
+	client.gridLines = nil
 }
 
 // This method flag the current instance to be initialized again on the next call to a function that
@@ -329,7 +793,245 @@ func (client *Client) Text() (out string, err error) {
 	if err = client.init(); err != nil {
 		return
 	}
-	out = C.GoString(C.UTF8Text(client.api))
+	return client.utf8Text()
+}
+
+// TextWithOptions recognizes the image data with the configuration of this client overridden by opts,
+// and restores the configuration afterwards, so that requests needing different PSM, whitelist
+// or languages can share a client without mutating it. The image data replaces the image set before.
+// Calls of TextWithOptions on the same client are serialized, not to race each other.
+// Languages different from client.Languages are recognized by a scratch instance, which costs initialization.
+// Recognition is stopped when ctx is done, returning ctx.Err().
+func (client *Client) TextWithOptions(ctx context.Context, data []byte, opts Options) (string, error) {
+	text, _, err := client.TextWithConfidence(ctx, data, opts)
+	return text, err
+}
+
+// TextWithConfidence recognizes the image data like TextWithOptions, along with the mean confidence of the words
+// from 0 to 100, such as to accept results above a threshold, see Speculate.
+func (client *Client) TextWithConfidence(ctx context.Context, data []byte, opts Options) (string, float64, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	text, confidence, err := client.textWithConfidence(ctx, data, opts)
+	if client.Recorder != nil && client.checkAPI() == nil {
+		client.record(ctx, data, opts, text, confidence, err)
+	}
+	return text, confidence, err
+}
+
+// record passes the bundle of the recognition to Recorder.
+func (client *Client) record(ctx context.Context, data []byte, opts Options, text string, confidence float64, err error) {
+	bundle := &Bundle{
+		Recorded:       time.Now(),
+		Build:          TesseractBuild{Version: client.Version(), OpenCL: openCLAvailable(), ThreadLimit: ThreadLimit()},
+		Languages:      append([]string{}, client.Languages...),
+		TessdataPrefix: client.TessdataPrefix,
+		ConfigFilePath: client.ConfigFilePath,
+		PageSegMode:    PageSegMode(C.GetPageSegMode(client.api)),
+		Variables:      map[SettableVariable]string{},
+		Preprocess:     client.Preprocess,
+		Normalize:      client.Normalize,
+		Trim:           client.Trim,
+		Grayscale:      client.Grayscale,
+		DisableOpenCL:  client.DisableOpenCL,
+		Options:        opts,
+		Metadata:       MetadataFrom(ctx),
+		Image:          append([]byte{}, data...),
+		Text:           text,
+		Confidence:     confidence,
+	}
+	for key, value := range client.Variables {
+		bundle.Variables[key] = value
+	}
+	if err != nil {
+		bundle.Err = err.Error()
+	}
+	client.Recorder(ctx, bundle)
+}
+
+// textWithConfidence is TextWithConfidence with the lock held.
+func (client *Client) textWithConfidence(ctx context.Context, data []byte, opts Options) (string, float64, error) {
+	if err := client.checkAPI(); err != nil {
+		return "", 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", 0, err
+	}
+	var constraint *Constraint
+	if opts.Constraint != "" {
+		var err error
+		if constraint, err = cachedConstraint(opts.Constraint); err != nil {
+			return "", 0, err
+		}
+	}
+	target := client
+	if len(opts.Languages) != 0 && !sameLanguages(opts.Languages, client.Languages) {
+		scratch, err := client.scratch(opts.Languages)
+		if err != nil {
+			return "", 0, err
+		}
+		defer scratch.Close()
+		target = scratch
+	}
+	if err := target.SetImageFromBytes(data); err != nil {
+		return "", 0, err
+	}
+	if target.shouldInit {
+		if err := target.initAPI(); err != nil {
+			return "", 0, err
+		}
+	}
+	restore, err := target.override(opts)
+	defer restore()
+	if err != nil {
+		return "", 0, err
+	}
+	if err := target.prepare(ctx); err != nil {
+		return "", 0, err
+	}
+	if err := target.recognize(ctx); err != nil {
+		return "", 0, err
+	}
+	if constraint != nil {
+		return target.decode(constraint)
+	}
+	text, err := target.utf8Text()
+	if err != nil {
+		return "", 0, err
+	}
+	return text, float64(C.MeanTextConf(target.api)), nil
+}
+
+// scratch constructs and initializes a new client configured as same as this one, except for languages.
+func (client *Client) scratch(langs []string) (*Client, error) {
+	scratch := NewClient()
+	scratch.Trim = client.Trim
+	scratch.TessdataPrefix = client.TessdataPrefix
+	scratch.ConfigFilePath = client.ConfigFilePath
+	scratch.Preprocess = client.Preprocess
+	scratch.Normalize = client.Normalize
+	scratch.TempDir = client.TempDir
+	scratch.Policy = client.Policy
+	scratch.Languages = langs
+	for key, value := range client.Variables {
+		scratch.Variables[key] = value
+	}
+	if err := scratch.initAPI(); err != nil {
+		scratch.Close()
+		return nil, err
+	}
+	C.SetPageSegMode(scratch.api, C.GetPageSegMode(client.api))
+	return scratch, nil
+}
+
+// override applies opts to the initialized TessBaseAPI, without touching client.Variables,
+// and returns the function to restore the previous configuration, which MUST be called even on error.
+func (client *Client) override(opts Options) (restore func(), err error) {
+	previous := map[SettableVariable]string{}
+	mode := C.GetPageSegMode(client.api)
+	preprocess := client.Preprocess
+	restore = func() {
+		for key, value := range previous {
+			client.setAPIVariable(key, value)
+		}
+		C.SetPageSegMode(client.api, mode)
+		client.Preprocess = preprocess
+	}
+
+	for key, value := range opts.variables() {
+		current, ok := client.getAPIVariable(key)
+		if !ok {
+			return restore, fmt.Errorf("unknown variable: %v", key)
+		}
+		previous[key] = current
+		if err := client.setAPIVariable(key, value); err != nil {
+			return restore, err
+		}
+	}
+	if opts.PageSegMode != PSM_OSD_ONLY {
+		C.SetPageSegMode(client.api, C.int(opts.PageSegMode))
+	}
+	if opts.Preprocess != nil {
+		client.Preprocess = *opts.Preprocess
+	}
+	return restore, nil
+}
+
+// getAPIVariable returns the current value of the variable of the initialized TessBaseAPI.
+func (client *Client) getAPIVariable(key SettableVariable) (string, bool) {
+	k := C.CString(string(key))
+	defer C.free(unsafe.Pointer(k))
+	buf := [4096]C.char{}
+	if !bool(C.GetVariableAsString(client.api, k, &buf[0], C.int(len(buf)))) {
+		return "", false
+	}
+	return C.GoString(&buf[0]), true
+}
+
+// setAPIVariable sets the variable to the initialized TessBaseAPI directly, without touching client.Variables.
+func (client *Client) setAPIVariable(key SettableVariable, value string) error {
+	k, v := C.CString(string(key)), C.CString(value)
+	defer C.free(unsafe.Pointer(k))
+	defer C.free(unsafe.Pointer(v))
+	if !bool(C.SetVariable(client.api, k, v)) {
+		return fmt.Errorf("failed to set variable with key(%v) and value(%v)", key, value)
+	}
+	return nil
+}
+
+// recognize runs recognition on the image set to TessBaseAPI, which tesseract cancels when ctx is done,
+// polling the monitor between words. The deadline of ctx is passed to tesseract as well.
+func (client *Client) recognize(ctx context.Context) error {
+	deadline := 0
+	if d, ok := ctx.Deadline(); ok {
+		msecs := time.Until(d).Milliseconds()
+		if msecs <= 0 {
+			return context.DeadlineExceeded
+		}
+		deadline = int(msecs)
+	}
+	monitor := C.CreateMonitor(C.int(deadline))
+	defer C.DestroyMonitor(monitor)
+
+	if ctx.Done() != nil {
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case <-ctx.Done():
+				C.CancelMonitor(monitor)
+			case <-done:
+			}
+		}()
+		// The monitor MUST NOT be cancelled after destroyed.
+		defer wg.Wait()
+		defer close(done)
+	}
+
+	errbuf := [C.ERRBUF_SIZE]C.char{}
+	res := C.RecognizeWithMonitor(client.api, monitor, &errbuf[0])
+	if err := bridgeError("Recognize", &errbuf[0]); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if res != 0 {
+		return fmt.Errorf("failed to recognize with code %d", res)
+	}
+	return nil
+}
+
+// utf8Text returns the text recognized by TessBaseAPI, running recognition if not yet.
+func (client *Client) utf8Text() (out string, err error) {
+	errbuf := [C.ERRBUF_SIZE]C.char{}
+	text := C.UTF8Text(client.api, &errbuf[0])
+	if err = bridgeError("GetUTF8Text", &errbuf[0]); err != nil {
+		return
+	}
+	out = client.Normalize.String(C.GoString(text))
 	if client.Trim {
 		out = strings.Trim(out, "\n")
 	}
@@ -342,7 +1044,12 @@ func (client *Client) HOCRText() (out string, err error) {
 	if err = client.init(); err != nil {
 		return
 	}
-	out = C.GoString(C.HOCRText(client.api))
+	errbuf := [C.ERRBUF_SIZE]C.char{}
+	text := C.HOCRText(client.api, &errbuf[0])
+	if err = bridgeError("GetHOCRText", &errbuf[0]); err != nil {
+		return
+	}
+	out = C.GoString(text)
 	return
 }
 
@@ -356,22 +1063,23 @@ type BoundingBox struct {
 
 // GetBoundingBoxes returns bounding boxes for each matched word
 func (client *Client) GetBoundingBoxes(level PageIteratorLevel) (out []BoundingBox, err error) {
-	if client.api == nil {
-		return out, fmt.Errorf("TessBaseAPI is not constructed, please use `gosseract.NewClient`")
-	}
 	if err = client.init(); err != nil {
 		return
 	}
-	boxArray := C.GetBoundingBoxes(client.api, C.int(level))
+	errbuf := [C.ERRBUF_SIZE]C.char{}
+	boxArray := C.GetBoundingBoxes(client.api, C.int(level), &errbuf[0])
 	length := int(boxArray.length)
 	defer C.free(unsafe.Pointer(boxArray.boxes))
 	defer C.free(unsafe.Pointer(boxArray))
+	if err = bridgeError("GetBoundingBoxes", &errbuf[0]); err != nil {
+		return
+	}
 	out = make([]BoundingBox, 0, length)
 	for i := 0; i < length; i++ {
 		// cast to bounding_box: boxes + i*sizeof(box)
 		box := (*C.struct_bounding_box)(unsafe.Pointer(uintptr(unsafe.Pointer(boxArray.boxes)) + uintptr(i)*unsafe.Sizeof(C.struct_bounding_box{})))
 		out = append(out, BoundingBox{
-			Box:        image.Rect(int(box.x1), int(box.y1), int(box.x2), int(box.y2)),
+			Box:        unscaleRect(image.Rect(int(box.x1), int(box.y1), int(box.x2), int(box.y2)), client.ImageScale()),
 			Word:       C.GoString(box.word),
 			Confidence: float64(box.confidence),
 		})
@@ -397,22 +1105,23 @@ func GetAvailableLanguages() ([]string, error) {
 // GetBoundingBoxesVerbose returns bounding boxes at word level with block_num, par_num, line_num and word_num
 // according to the c++ api that returns a formatted TSV output. Reference: `TessBaseAPI::GetTSVText`.
 func (client *Client) GetBoundingBoxesVerbose() (out []BoundingBox, err error) {
-	if client.api == nil {
-		return out, fmt.Errorf("TessBaseAPI is not constructed, please use `gosseract.NewClient`")
-	}
 	if err = client.init(); err != nil {
 		return
 	}
-	boxArray := C.GetBoundingBoxesVerbose(client.api)
+	errbuf := [C.ERRBUF_SIZE]C.char{}
+	boxArray := C.GetBoundingBoxesVerbose(client.api, &errbuf[0])
 	length := int(boxArray.length)
 	defer C.free(unsafe.Pointer(boxArray.boxes))
 	defer C.free(unsafe.Pointer(boxArray))
+	if err = bridgeError("GetBoundingBoxesVerbose", &errbuf[0]); err != nil {
+		return
+	}
 	out = make([]BoundingBox, 0, length)
 	for i := 0; i < length; i++ {
 		// cast to bounding_box: boxes + i*sizeof(box)
 		box := (*C.struct_bounding_box)(unsafe.Pointer(uintptr(unsafe.Pointer(boxArray.boxes)) + uintptr(i)*unsafe.Sizeof(C.struct_bounding_box{})))
 		out = append(out, BoundingBox{
-			Box:        image.Rect(int(box.x1), int(box.y1), int(box.x2), int(box.y2)),
+			Box:        unscaleRect(image.Rect(int(box.x1), int(box.y1), int(box.x2), int(box.y2)), client.ImageScale()),
 			Word:       C.GoString(box.word),
 			Confidence: float64(box.confidence),
 			BlockNum:   int(box.block_num),
@@ -424,6 +1133,447 @@ func (client *Client) GetBoundingBoxesVerbose() (out []BoundingBox, err error) {
 	return
 }
 
+// ResultIterator walks the results of the last recognition of Client element by element at its level,
+// see Client.Iterator. It pins the results it walks: methods return ErrClientClosed after Close of the client,
+// and ErrIteratorReleased once the client recognizes again or clears its results, rather than touching
+// the results freed. Like Client, it's not safe for concurrent use. It's due to caller to Close the iterator.
+//
+//	it, err := client.Iterator(gosseract.RIL_WORD)
+//	if err != nil {
+//		return err
+//	}
+//	defer it.Close()
+//	for it.Next() {
+//		word, _ := it.Text()
+//		box, _ := it.BoundingBox()
+//	}
+//	return it.Err()
+type ResultIterator struct {
+	client *Client
+	it     C.Iterator
+	level  PageIteratorLevel
+
+	// whether Next is called once, and whether the iterator is at an element
+	started, ok bool
+
+	released bool
+	err      error
+}
+
+// Iterator finally initialize tesseract::TessBaseAPI, execute OCR and returns ResultIterator
+// walking the results at the level, such as RIL_WORD, in reading order.
+func (client *Client) Iterator(level PageIteratorLevel) (*ResultIterator, error) {
+	if err := client.init(); err != nil {
+		return nil, err
+	}
+	if err := client.recognize(context.Background()); err != nil {
+		return nil, err
+	}
+	errbuf := [C.ERRBUF_SIZE]C.char{}
+	it := &ResultIterator{client: client, it: C.GetResultIterator(client.api, &errbuf[0]), level: level}
+	if err := bridgeError("GetIterator", &errbuf[0]); err != nil {
+		return nil, err
+	}
+	if it.it != nil {
+		atomic.AddInt64(&nativeStats.iterators, 1)
+	}
+	if client.iterators == nil {
+		client.iterators = map[*ResultIterator]struct{}{}
+	}
+	client.iterators[it] = struct{}{}
+	return it, nil
+}
+
+// releaseIterators releases the result iterators not closed, before the results they walk are freed.
+func (client *Client) releaseIterators() {
+	for it := range client.iterators {
+		it.release()
+	}
+}
+
+// Next moves the iterator to the next element, or to the first one on the first call,
+// and reports whether there is one. It returns false at the end, or on the error returned by Err.
+func (it *ResultIterator) Next() bool {
+	if it.err = it.check(); it.err != nil {
+		it.ok = false
+		return false
+	}
+	switch {
+	case it.it == nil:
+		// Nothing is recognized.
+		it.ok = false
+	case !it.started:
+		it.ok = true
+	case it.ok:
+		it.ok = bool(C.ResultIteratorNext(it.it, C.int(it.level)))
+	}
+	it.started = true
+	return it.ok
+}
+
+// Err returns the error which stopped Next, or nil at the end of the results.
+func (it *ResultIterator) Err() error {
+	return it.err
+}
+
+// Text returns the text of the element.
+func (it *ResultIterator) Text() (string, error) {
+	if err := it.current(); err != nil {
+		return "", err
+	}
+	errbuf := [C.ERRBUF_SIZE]C.char{}
+	text := C.ResultIteratorText(it.it, C.int(it.level), &errbuf[0])
+	defer C.free(unsafe.Pointer(text))
+	if err := bridgeError("GetUTF8Text", &errbuf[0]); err != nil {
+		return "", err
+	}
+	return it.client.Normalize.String(C.GoString(text)), nil
+}
+
+// Confidence returns the confidence of the element from 0 to 100.
+func (it *ResultIterator) Confidence() (float64, error) {
+	if err := it.current(); err != nil {
+		return 0, err
+	}
+	return float64(C.ResultIteratorConfidence(it.it, C.int(it.level))), nil
+}
+
+// BoundingBox returns the bounding box of the element, in the coordinates of the image set.
+func (it *ResultIterator) BoundingBox() (image.Rectangle, error) {
+	if err := it.current(); err != nil {
+		return image.Rectangle{}, err
+	}
+	var x1, y1, x2, y2 C.int
+	if !C.ResultIteratorBoundingBox(it.it, C.int(it.level), &x1, &y1, &x2, &y2) {
+		return image.Rectangle{}, nil
+	}
+	return unscaleRect(image.Rect(int(x1), int(y1), int(x2), int(y2)), it.client.ImageScale()), nil
+}
+
+// Close deletes the iterator. The iterators released by the client are closed already.
+func (it *ResultIterator) Close() error {
+	it.release()
+	return nil
+}
+
+// check returns the error of the iterator which can't walk the results anymore.
+func (it *ResultIterator) check() error {
+	if it.client.closed {
+		return ErrClientClosed
+	}
+	if it.released {
+		return ErrIteratorReleased
+	}
+	return nil
+}
+
+// current returns an error if the iterator is not at an element.
+func (it *ResultIterator) current() error {
+	if err := it.check(); err != nil {
+		return err
+	}
+	if !it.ok {
+		return fmt.Errorf("ResultIterator is not at an element, call Next before")
+	}
+	return nil
+}
+
+func (it *ResultIterator) release() {
+	if it.released {
+		return
+	}
+	it.released, it.ok = true, false
+	if it.it != nil {
+		C.DeleteResultIterator(it.it)
+		it.it = nil
+		atomic.AddInt64(&nativeStats.iterators, -1)
+	}
+	delete(it.client.iterators, it)
+}
+
+// TextOfZones recognizes the zones of the image set, such as the fields of a template of forms,
+// thresholding the image once for all the zones, and caching it until the image, Preprocess or the variables
+// of thresholding change. The layout isn't cached, since tesseract analyzes it within the rectangle recognized,
+// so each zone costs only the analysis and the recognition of itself, not of the whole page.
+// Zones failed are reported by ZoneText.Err, and it returns an error only if none can be recognized,
+// or ctx is done, along with the texts of the zones recognized so far.
+func (client *Client) TextOfZones(ctx context.Context, zones []Zone) ([]ZoneText, error) {
+	if err := client.prepare(ctx); err != nil {
+		return nil, err
+	}
+	thresholded, err := client.thresholdedPixImage()
+	if err != nil {
+		return nil, err
+	}
+	// The image prepared is set again by prepare for the next recognition.
+	C.SetPixImage(client.api, thresholded)
+	scale := client.ImageScale()
+	bounds := image.Rect(0, 0, int(C.PixImageWidth(thresholded)), int(C.PixImageHeight(thresholded)))
+	texts := make([]ZoneText, 0, len(zones))
+	for _, zone := range zones {
+		if err := ctx.Err(); err != nil {
+			return texts, err
+		}
+		texts = append(texts, client.textOfZone(ctx, zone, scaleRect(zone.Rect, scale).Intersect(bounds)))
+	}
+	return texts, nil
+}
+
+// textOfZone recognizes the zone of the rectangle on the thresholded image set.
+func (client *Client) textOfZone(ctx context.Context, zone Zone, rect image.Rectangle) ZoneText {
+	text := ZoneText{Name: zone.Name}
+	if text.Err = zone.check(); text.Err != nil {
+		return text
+	}
+	if rect.Empty() {
+		text.Err = fmt.Errorf("zone %q is out of the image", zone.Name)
+		return text
+	}
+	opts := zone.Options
+	restore, err := client.override(opts)
+	defer restore()
+	if err != nil {
+		text.Err = err
+		return text
+	}
+	C.SetRectangle(client.api, C.int(rect.Min.X), C.int(rect.Min.Y), C.int(rect.Dx()), C.int(rect.Dy()))
+	if text.Err = client.recognize(ctx); text.Err != nil {
+		return text
+	}
+	if opts.Constraint != "" {
+		constraint, err := cachedConstraint(opts.Constraint)
+		if err != nil {
+			text.Err = err
+			return text
+		}
+		text.Text, text.Confidence, text.Err = client.decode(constraint)
+		return text
+	}
+	if text.Text, text.Err = client.utf8Text(); text.Err != nil {
+		return text
+	}
+	text.Confidence = float64(C.MeanTextConf(client.api))
+	return text
+}
+
+// thresholdedPixImage returns the binary image tesseract thresholds from the image set,
+// which is cached until the image prepared or any of thresholdingVariables changes.
+func (client *Client) thresholdedPixImage() (C.PixImage, error) {
+	values := make([]string, 0, len(thresholdingVariables))
+	for _, key := range thresholdingVariables {
+		// Variables unknown to tesseract of older versions don't change the image.
+		value, _ := client.getAPIVariable(key)
+		values = append(values, value)
+	}
+	with := strings.Join(values, "\n")
+	if client.thresholded != nil && client.thresholdedWith == with {
+		return client.thresholded, nil
+	}
+	destroyPixImage(client.thresholded)
+	client.thresholded = nil
+	errbuf := [C.ERRBUF_SIZE]C.char{}
+	img := trackPixImage(C.ThresholdedPixImage(client.api, &errbuf[0]))
+	if err := bridgeError("GetThresholdedImage", &errbuf[0]); err != nil {
+		destroyPixImage(img)
+		return nil, err
+	}
+	if img == nil {
+		return nil, fmt.Errorf("failed to threshold the image")
+	}
+	client.thresholded, client.thresholdedWith = img, with
+	return img, nil
+}
+
+// Symbols recognizes the image and returns the symbols with their choices in reading order,
+// for Decoders of structured codes, see DecodeText.
+func (client *Client) Symbols() ([]Symbol, error) {
+	if err := client.init(); err != nil {
+		return nil, err
+	}
+	restore, err := client.override(Options{Variables: map[SettableVariable]string{LSTM_CHOICE_MODE: "2"}})
+	defer restore()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.recognize(context.Background()); err != nil {
+		return nil, err
+	}
+	return client.symbols()
+}
+
+// DecodeText recognizes the image and decodes the text of the symbols by the decoder, such as Constraint,
+// along with the mean confidence of the choices taken. It returns ErrNoMatch if the decoder decodes none.
+func (client *Client) DecodeText(dec Decoder) (string, float64, error) {
+	symbols, err := client.Symbols()
+	if err != nil {
+		return "", 0, err
+	}
+	return decodeSymbols(dec, symbols)
+}
+
+// decode decodes the text of the symbols of the last recognition by the decoder.
+func (client *Client) decode(dec Decoder) (string, float64, error) {
+	symbols, err := client.symbols()
+	if err != nil {
+		return "", 0, err
+	}
+	return decodeSymbols(dec, symbols)
+}
+
+// symbols copies the symbols of the last recognition walked by the bridge into Go.
+func (client *Client) symbols() ([]Symbol, error) {
+	errbuf := [C.ERRBUF_SIZE]C.char{}
+	walked := C.GetSymbols(client.api, &errbuf[0])
+	defer C.FreeSymbols(walked)
+	if err := bridgeError("GetSymbols", &errbuf[0]); err != nil {
+		return nil, err
+	}
+	scale := client.ImageScale()
+	length := int(walked.length)
+	out := make([]Symbol, 0, length)
+	for i := 0; i < length; i++ {
+		// cast to symbol: symbols + i*sizeof(symbol)
+		s := (*C.struct_symbol)(unsafe.Pointer(uintptr(unsafe.Pointer(walked.symbols)) + uintptr(i)*unsafe.Sizeof(C.struct_symbol{})))
+		symbol := Symbol{
+			Box:     unscaleRect(image.Rect(int(s.x1), int(s.y1), int(s.x2), int(s.y2)), scale),
+			Word:    int(s.word),
+			Choices: make([]Choice, 0, int(s.length)),
+		}
+		for j := 0; j < int(s.length); j++ {
+			c := (*C.struct_symbol_choice)(unsafe.Pointer(uintptr(unsafe.Pointer(s.choices)) + uintptr(j)*unsafe.Sizeof(C.struct_symbol_choice{})))
+			symbol.Choices = append(symbol.Choices, Choice{Text: C.GoString(c.text), Confidence: float64(c.confidence)})
+		}
+		out = append(out, symbol)
+	}
+	return out, nil
+}
+
+// Document finally initialize tesseract::TessBaseAPI, execute OCR and returns the layout of the page,
+// i.e. blocks with their outline polygons, paragraphs, lines and words, as plain data.
+func (client *Client) Document() (*document.Document, error) {
+	return client.DocumentWithContext(context.Background())
+}
+
+// DocumentWithContext recognizes the image like Document, stopping when ctx is done.
+// Then it returns the words recognized so far, in the Document flagged Partial, along with ctx.Err(),
+// so that interactive UIs can still show something.
+func (client *Client) DocumentWithContext(ctx context.Context) (*document.Document, error) {
+	if err := client.prepare(ctx); err != nil {
+		return nil, err
+	}
+	stopped := client.recognize(ctx)
+	if stopped != nil && stopped != ctx.Err() {
+		return nil, stopped
+	}
+	errbuf := [C.ERRBUF_SIZE]C.char{}
+	layout := C.GetLayout(client.api, &errbuf[0])
+	defer C.FreeLayout(layout)
+	if err := bridgeError("GetLayout", &errbuf[0]); err != nil {
+		return nil, err
+	}
+	doc := buildDocument(client.layoutElements(layout))
+	doc.Width, doc.Height = int(C.PixImageWidth(client.pixImage)), int(C.PixImageHeight(client.pixImage))
+	doc.GridLines = append([]image.Rectangle(nil), client.gridLines...)
+	if stopped != nil {
+		dropUnrecognized(doc)
+		doc.Partial = true
+	}
+	normalize.Document(doc, client.Normalize)
+	return doc, stopped
+}
+
+// layoutElements copies the layout walked by the bridge into Go,
+// mapping coordinates back to the original image.
+func (client *Client) layoutElements(layout *C.struct_layout) []layoutElement {
+	scale := client.ImageScale()
+	length := int(layout.length)
+	elements := make([]layoutElement, 0, length)
+	for i := 0; i < length; i++ {
+		// cast to layout_element: elements + i*sizeof(element)
+		e := (*C.struct_layout_element)(unsafe.Pointer(uintptr(unsafe.Pointer(layout.elements)) + uintptr(i)*unsafe.Sizeof(C.struct_layout_element{})))
+		element := layoutElement{
+			level:       PageIteratorLevel(e.level),
+			box:         unscaleRect(image.Rect(int(e.x1), int(e.y1), int(e.x2), int(e.y2)), scale),
+			text:        C.GoString(e.text),
+			confidence:  float64(e.confidence),
+			bold:        bool(e.bold),
+			dictionary:  bool(e.from_dictionary),
+			numeric:     bool(e.numeric),
+			superscript: bool(e.superscript),
+			subscript:   bool(e.subscript),
+			dropcap:     bool(e.dropcap),
+			blockType:   document.BlockType(e.block_type),
+			orientation: document.Orientation(e.orientation),
+			deskew:      float64(e.deskew_angle),
+		}
+		if bool(e.has_baseline) {
+			element.baseline = []image.Point{
+				unscalePoint(image.Pt(int(e.bx1), int(e.by1)), scale),
+				unscalePoint(image.Pt(int(e.bx2), int(e.by2)), scale),
+			}
+		}
+		if e.polygon_length > 0 {
+			coords := unsafe.Slice((*C.int)(unsafe.Pointer(e.polygon)), 2*int(e.polygon_length))
+			element.polygon = make([]image.Point, 0, int(e.polygon_length))
+			for j := 0; j < len(coords); j += 2 {
+				element.polygon = append(element.polygon, unscalePoint(image.Pt(int(coords[j]), int(coords[j+1])), scale))
+			}
+		}
+		elements = append(elements, element)
+	}
+	return elements
+}
+
+// majorVersion parses the major version number of tesseract, such as 5 from "5.3.0" or "v5.0.0-alpha".
+func majorVersion(version string) int {
+	version = strings.TrimPrefix(version, "v")
+	if idx := strings.Index(version, "."); idx >= 0 {
+		version = version[:idx]
+	}
+	major, _ := strconv.Atoi(version)
+	return major
+}
+
+// bridgeError returns the C++ exception caught by the bridge and written to errbuf as an error,
+// or nil if nothing is thrown.
+func bridgeError(method string, errbuf *C.char) error {
+	if msg := C.GoString(errbuf); msg != "" {
+		return &TesseractError{Method: method, Message: msg}
+	}
+	return nil
+}
+
+// newAPI constructs TessBaseAPI, counting it as an outstanding native allocation.
+func newAPI() C.TessBaseAPI {
+	atomic.AddInt64(&nativeStats.clients, 1)
+	return C.Create()
+}
+
+// freeAPI frees TessBaseAPI constructed by newAPI.
+func freeAPI(api C.TessBaseAPI) {
+	C.Free(api)
+	atomic.AddInt64(&nativeStats.clients, -1)
+}
+
+// trackPixImage counts img as an outstanding native allocation, and returns it as it is.
+func trackPixImage(img C.PixImage) C.PixImage {
+	if img != nil {
+		atomic.AddInt64(&nativeStats.pixImages, 1)
+		atomic.AddInt64(&nativeStats.pixImagesBytes, int64(C.PixImageBytes(img)))
+	}
+	return img
+}
+
+// destroyPixImage destroys img counted by trackPixImage.
+func destroyPixImage(img C.PixImage) {
+	if img == nil {
+		return
+	}
+	atomic.AddInt64(&nativeStats.pixImages, -1)
+	atomic.AddInt64(&nativeStats.pixImagesBytes, -int64(C.PixImageBytes(img)))
+	C.DestroyPixImage(img)
+}
+
 // getDataPath is useful hepler to determine where current tesseract
 // installation stores trained models
 func getDataPath() string {
@@ -1,16 +1,249 @@
+// Command gosseract recognizes text of images, or of all the images in directories, printing the texts to stdout
+// separated by form feeds, or storing them as sidecars in the directory of -o. Progress of directories is drawn on stderr.
+// Texts are encoded by -encoding and -newline for legacy consumers, such as "-encoding utf-16 -newline crlf".
+//
+// Images are processed by the pipeline of -pipeline instead, see package pipeline, registered by the JSON file
+// of -pipelines, as the server processes them, printing the outputs of the pipeline to stdout.
+//
+// With -autotune, the images are the samples to benchmark the tunings of threads and clients on this machine by,
+// see gosseract.AutoTune, printing the pages per second of each tuning and the fastest one.
+//
+//	gosseract [-l eng] [-psm 3] [-o dir] [-encoding utf-8] [-newline lf] [-mask-pii] [-q] path...
+//	gosseract -pipelines pipelines.json -pipeline invoices path...
+//	gosseract -autotune [-l eng] [-psm 3] path...
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/chennqqi/gosseract/v2"
+	"github.com/chennqqi/gosseract/v2/batch"
+	"github.com/chennqqi/gosseract/v2/pipeline"
+	"github.com/chennqqi/gosseract/v2/store"
 )
 
 func main() {
+	gosseract.RunAutoTuneChild()
+
+	languages := flag.String("l", "eng", "languages joined by \"+\", such as \"eng+deu\"")
+	psm := flag.Int("psm", int(gosseract.PSM_AUTO), "page segmentation mode")
+	output := flag.String("o", "", "directory to store the texts as sidecars, such as \"scan-1.png.txt\" of \"scan-1.png\", rather than print them")
+	encoding := flag.String("encoding", "utf-8", "encoding of the texts: utf-8, utf-8-bom, utf-16 (little endian with BOM), utf-16le or utf-16be")
+	newline := flag.String("newline", "lf", "newline of the texts: lf or crlf")
+	maskPII := flag.Bool("mask-pii", false, "mask emails, phone numbers, national IDs and IBAN in the texts")
+	quiet := flag.Bool("q", false, "don't draw the progress of directories")
+	pipelines := flag.String("pipelines", "", "JSON file of pipelines to register")
+	name := flag.String("pipeline", "", "name of the pipeline to process the images by, which configures the recognition and the output")
+	autotune := flag.Bool("autotune", false, "benchmark the tunings of threads and clients by the images, printing the fastest")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: gosseract [-l eng] [-psm 3] [-o dir] [-encoding utf-8] [-newline lf] [-mask-pii] [-q] path...")
+		fmt.Fprintln(os.Stderr, "       gosseract [-pipelines file] -pipeline name path...")
+		fmt.Fprintln(os.Stderr, "       gosseract -autotune [-l eng] [-psm 3] path...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *autotune {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		cfg := gosseract.Config{Languages: strings.Split(*languages, "+"), PageSegMode: gosseract.PageSegMode(*psm)}
+		if !runAutoTune(ctx, cfg, flag.Args()) {
+			os.Exit(1)
+		}
+		return
+	}
+	if *pipelines != "" {
+		if err := loadPipelines(*pipelines); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	if *name != "" {
+		def, ok := pipeline.Lookup(*name)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown pipeline %q\n", *name)
+			os.Exit(2)
+		}
+		if *output != "" {
+			fmt.Fprintln(os.Stderr, "-o is not supported with -pipeline, whose outputs are printed to stdout")
+			os.Exit(2)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if !runPipeline(ctx, def, flag.Args()) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	enc, err := store.ParseEncoding(*encoding)
+	if err == nil {
+		enc.Newline, err = store.ParseNewline(*newline)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 	client := gosseract.NewClient()
 	defer client.Close()
-	client.SetImage("path/to/image.png")
-	text, _ := client.Text()
-	fmt.Println(text)
-	// Hello, World!
+	if err := client.SetLanguage(strings.Split(*languages, "+")...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	opts := batch.Options{Recognition: gosseract.Options{PageSegMode: gosseract.PageSegMode(*psm)}, Encoding: enc, MaskPII: *maskPII}
+	if *output != "" {
+		opts.Output = store.Dir(*output)
+	}
+
+	failed := false
+	first := true
+	for _, path := range flag.Args() {
+		results, err := process(ctx, client, path, opts, *quiet)
+		for _, result := range results {
+			if result.Err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", result.Name, result.Err)
+				failed = true
+				continue
+			}
+			if opts.Output != nil {
+				continue
+			}
+			text := result.Text + "\n"
+			if !first {
+				text = "\f" + text
+			}
+			os.Stdout.Write(enc.Encode(text))
+			// The BOM leads the output only.
+			first, enc.BOM = false, false
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			os.Exit(1)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func process(ctx context.Context, client *gosseract.Client, path string, opts batch.Options, quiet bool) ([]batch.Result, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return batch.ProcessPages(ctx, client, []batch.Page{{Name: path, Data: data}}, opts)
+	}
+	if !quiet {
+		opts.Progress = batch.NewProgressBar(os.Stderr)
+	}
+	return batch.ProcessDir(ctx, client, path, opts)
+}
+
+func loadPipelines(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return pipeline.Load(file)
+}
+
+// runPipeline processes the images of the paths by the pipeline, writing the results to stdout,
+// and reports whether all of them are processed.
+func runPipeline(ctx context.Context, def pipeline.Definition, paths []string) bool {
+	rec, err := def.Config.Build()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return false
+	}
+	defer rec.Close()
+	w := def.NewWriter(os.Stdout)
+	ok := true
+	for _, path := range paths {
+		files, err := imageFiles(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			ok = false
+			continue
+		}
+		for _, file := range files {
+			if ctx.Err() != nil {
+				return false
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+				ok = false
+				continue
+			}
+			result, err := def.Run(ctx, rec, data)
+			if err == nil {
+				err = w.Write(result)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+				ok = false
+			}
+		}
+	}
+	return ok
+}
+
+// runAutoTune benchmarks the tunings by the images of the paths, writing the results to stdout,
+// and reports whether they're benchmarked.
+func runAutoTune(ctx context.Context, cfg gosseract.Config, paths []string) bool {
+	samples := [][]byte{}
+	for _, path := range paths {
+		files, err := imageFiles(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			return false
+		}
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", file, err)
+				return false
+			}
+			samples = append(samples, data)
+		}
+	}
+	best, results, err := gosseract.AutoTune(ctx, cfg, samples, nil)
+	for _, result := range results {
+		fmt.Printf("%v: %.2f pages/s\n", result.Tuning, result.PagesPerSecond)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return false
+	}
+	fmt.Printf("fastest: %v\n", best)
+	return true
+}
+
+// imageFiles returns the path of a file, or the images in the directory of the path, see batch.ImageFiles.
+func imageFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	return batch.ImageFiles(path)
 }
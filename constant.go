@@ -57,6 +57,20 @@ const (
 	RIL_SYMBOL
 )
 
+// ThresholdingMethod represents tesseract::ThresholdMethod, the algorithm to binarize images.
+// It is configurable only since tesseract 5.0.
+// See https://github.com/tesseract-ocr/tesseract/blob/5.0.0/include/tesseract/publictypes.h#L280-L287
+type ThresholdingMethod int
+
+const (
+	// THRESHOLD_OTSU - (DEFAULT) Tesseract's legacy Otsu thresholding.
+	THRESHOLD_OTSU ThresholdingMethod = iota
+	// THRESHOLD_LEPTONICA_OTSU - Leptonica's tiled Otsu thresholding.
+	THRESHOLD_LEPTONICA_OTSU
+	// THRESHOLD_SAUVOLA - Sauvola's local thresholding, good at stained or low-contrast documents.
+	THRESHOLD_SAUVOLA
+)
+
 // SettableVariable represents available strings for TessBaseAPI::SetVariable.
 // See https://groups.google.com/forum/#!topic/tesseract-ocr/eHTBzrBiwvQ
 // and https://github.com/tesseract-ocr/tesseract/blob/master/src/ccmain/tesseractclass.h
@@ -75,4 +89,8 @@ const (
 	// There is a known issue in 4.00 with LSTM
 	// https://github.com/tesseract-ocr/tesseract/issues/751
 	TESSEDIT_CHAR_BLACKLIST SettableVariable = "tessedit_char_blacklist"
+	// THRESHOLDING_METHOD - Thresholding method, see ThresholdingMethod (since 5.0)
+	THRESHOLDING_METHOD SettableVariable = "thresholding_method"
+	// LSTM_CHOICE_MODE - Choices of symbols LSTM reports: 0 none, 1 of timesteps, 2 of characters (since 4.1)
+	LSTM_CHOICE_MODE SettableVariable = "lstm_choice_mode"
 )
@@ -0,0 +1,234 @@
+package gosseract
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"regexp/syntax"
+	"sort"
+	"sync"
+)
+
+// Choice is a text a symbol may be, with the confidence from 0 to 100.
+type Choice struct {
+	Text       string
+	Confidence float64
+}
+
+// Symbol is a symbol recognized, with the choices of its text, the best first, see Client.Symbols.
+type Symbol struct {
+	Box image.Rectangle
+	// Word is the index of the word of the symbol in the page, from 0.
+	Word    int
+	Choices []Choice
+}
+
+// Decoder decodes the text of symbols from their choices, such as Constraint, returning the text,
+// the mean confidence of the choices taken, and whether any text is decoded, see Client.DecodeText.
+type Decoder interface {
+	Decode(symbols []Symbol) (text string, confidence float64, ok bool)
+}
+
+// decodeSymbols decodes the text of the symbols by the decoder, or fails by ErrNoMatch.
+func decodeSymbols(dec Decoder, symbols []Symbol) (string, float64, error) {
+	text, confidence, ok := dec.Decode(symbols)
+	if !ok {
+		return "", 0, ErrNoMatch
+	}
+	return text, confidence, nil
+}
+
+// Constraint is a Decoder of the most confident text of the choices matching a regular expression,
+// such as `[A-Z]{2}\d{6}` of serial numbers, which goes beyond whitelists for structured codes:
+// a symbol read as "O" where a digit must be is decoded "0" if tesseract ever considered it.
+// The whole text must match, and empty-width assertions such as ^ and \b are ignored.
+// Words are joined with a space where the expression allows one, or without it.
+type Constraint struct {
+	pattern string
+	prog    *syntax.Prog
+}
+
+// NewConstraint compiles the regular expression of the syntax of the regexp package into Constraint.
+func NewConstraint(pattern string) (*Constraint, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraint %q: %v", pattern, err)
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraint %q: %v", pattern, err)
+	}
+	return &Constraint{pattern: pattern, prog: prog}, nil
+}
+
+// MustConstraint is NewConstraint panicking for invalid patterns, for patterns of constants.
+func MustConstraint(pattern string) *Constraint {
+	c, err := NewConstraint(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// constraints caches Constraints of Options.Constraint by the patterns.
+var constraints sync.Map
+
+func cachedConstraint(pattern string) (*Constraint, error) {
+	if c, ok := constraints.Load(pattern); ok {
+		return c.(*Constraint), nil
+	}
+	c, err := NewConstraint(pattern)
+	if err != nil {
+		return nil, err
+	}
+	constraints.Store(pattern, c)
+	return c, nil
+}
+
+// String returns the pattern of the constraint.
+func (c *Constraint) String() string {
+	return c.pattern
+}
+
+// decoding is the best decoding of the symbols so far reaching a state of the program.
+type decoding struct {
+	// score is the sum of the log probabilities of the choices taken
+	score      float64
+	confidence float64
+	choices    int
+	text       string
+}
+
+// Decode decodes the most confident text matching the constraint, by the product of the confidences
+// of the choices taken for each symbol. It returns false if no text of the choices matches.
+func (c *Constraint) Decode(symbols []Symbol) (string, float64, bool) {
+	states := map[uint32]decoding{}
+	for _, pc := range c.closure(uint32(c.prog.Start)) {
+		states[pc] = decoding{}
+	}
+	for i, symbol := range symbols {
+		if len(symbol.Choices) == 0 {
+			continue
+		}
+		if i != 0 && symbol.Word != symbols[i-1].Word {
+			// Either joined by a space or without it.
+			for pc, d := range c.advance(states, " ", -1) {
+				if current, ok := states[pc]; !ok || d.score > current.score {
+					states[pc] = d
+				}
+			}
+		}
+		next := map[uint32]decoding{}
+		for _, choice := range symbol.Choices {
+			for pc, d := range c.advance(states, choice.Text, choice.Confidence) {
+				if current, ok := next[pc]; !ok || d.score > current.score {
+					next[pc] = d
+				}
+			}
+		}
+		if states = next; len(states) == 0 {
+			return "", 0, false
+		}
+	}
+	best, matched := decoding{}, false
+	for _, pc := range sortedStates(states) {
+		if d := states[pc]; c.prog.Inst[pc].Op == syntax.InstMatch && (!matched || d.score > best.score) {
+			best, matched = d, true
+		}
+	}
+	if !matched {
+		return "", 0, false
+	}
+	if best.choices != 0 {
+		best.confidence /= float64(best.choices)
+	}
+	return best.text, best.confidence, true
+}
+
+// advance returns the best decodings reaching each state from the states by the text of the confidence,
+// or by the text of no choice if the confidence is negative.
+func (c *Constraint) advance(states map[uint32]decoding, text string, confidence float64) map[uint32]decoding {
+	reached := map[uint32]decoding{}
+	for _, start := range sortedStates(states) {
+		current := []uint32{start}
+		for _, r := range text {
+			next := []uint32{}
+			seen := map[uint32]bool{}
+			for _, pc := range current {
+				if !c.matchRune(pc, r) {
+					continue
+				}
+				for _, to := range c.closure(c.prog.Inst[pc].Out) {
+					if !seen[to] {
+						seen[to] = true
+						next = append(next, to)
+					}
+				}
+			}
+			if current = next; len(current) == 0 {
+				break
+			}
+		}
+		d := states[start]
+		d.text += text
+		if confidence >= 0 {
+			d.score += math.Log(math.Max(confidence, 0.1) / 100)
+			d.confidence += confidence
+			d.choices++
+		}
+		for _, pc := range current {
+			if r, ok := reached[pc]; !ok || d.score > r.score {
+				reached[pc] = d
+			}
+		}
+	}
+	return reached
+}
+
+func (c *Constraint) matchRune(pc uint32, r rune) bool {
+	inst := &c.prog.Inst[pc]
+	switch inst.Op {
+	case syntax.InstRune, syntax.InstRune1:
+		return inst.MatchRune(r)
+	case syntax.InstRuneAny:
+		return true
+	case syntax.InstRuneAnyNotNL:
+		return r != '\n'
+	}
+	return false
+}
+
+// closure returns the states consuming runes, or matching, reachable from pc without consuming any.
+func (c *Constraint) closure(pc uint32) []uint32 {
+	states := []uint32{}
+	seen := map[uint32]bool{}
+	var walk func(pc uint32)
+	walk = func(pc uint32) {
+		if seen[pc] {
+			return
+		}
+		seen[pc] = true
+		inst := &c.prog.Inst[pc]
+		switch inst.Op {
+		case syntax.InstAlt, syntax.InstAltMatch:
+			walk(inst.Out)
+			walk(inst.Arg)
+		case syntax.InstCapture, syntax.InstNop, syntax.InstEmptyWidth:
+			walk(inst.Out)
+		case syntax.InstRune, syntax.InstRune1, syntax.InstRuneAny, syntax.InstRuneAnyNotNL, syntax.InstMatch:
+			states = append(states, pc)
+		}
+	}
+	walk(pc)
+	return states
+}
+
+// sortedStates returns the states in order, for decodings of the same score to be deterministic.
+func sortedStates(states map[uint32]decoding) []uint32 {
+	pcs := make([]uint32, 0, len(states))
+	for pc := range states {
+		pcs = append(pcs, pc)
+	}
+	sort.Slice(pcs, func(i, j int) bool { return pcs[i] < pcs[j] })
+	return pcs
+}
@@ -0,0 +1,259 @@
+// Package daemon runs a gosseract.Pool for long-running processes, such as servers and workers,
+// configured by a file which is reloaded when it changes, without dropping requests in flight
+// or restarting the process. Profiles of package server configured by files are served by Runner,
+// see server.Profile.ConfigFile.
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2"
+)
+
+// LoadConfig reads the JSON configuration file of the path, whose keys are the fields of gosseract.Config, e.g.
+//
+//	{"languages": ["eng", "deu"], "pageSegMode": 3, "variables": {"preserve_interword_spaces": "1"}, "tuning": {"clients": 4}}
+//
+// Policy can't be configured by files, see Runner.Configure.
+func LoadConfig(path string) (gosseract.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return gosseract.Config{}, err
+	}
+	return parseConfig(path, data)
+}
+
+func parseConfig(path string, data []byte) (gosseract.Config, error) {
+	cfg := gosseract.Config{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// Pool is what Runner needs of gosseract.Pool, see NewWith.
+type Pool interface {
+	TextWithOptions(ctx context.Context, data []byte, opts gosseract.Options) (string, error)
+	TextWithConfidence(ctx context.Context, data []byte, opts gosseract.Options) (string, float64, error)
+	Close() error
+}
+
+// generation is a pool built from a version of the configuration, with the requests in flight on it.
+type generation struct {
+	cfg      gosseract.Config
+	data     []byte
+	pool     Pool
+	inflight sync.WaitGroup
+}
+
+// Runner recognizes images by the pool of the configuration file, rebuilding the pool when the file changes,
+// such as languages, variables or Tuning.Clients. Requests in flight finish on the old pool, which is closed after them,
+// while new requests go to the new pool. It's safe to share among goroutines.
+type Runner struct {
+	path string
+
+	// Configure is applied to configurations loaded, before pools are built,
+	// to set what files can't, such as Policy. Set it before Watch.
+	Configure func(cfg *gosseract.Config)
+
+	// OnReload is notified of reloads of the file, with the error if the reload failed,
+	// in which case the previous pool keeps serving. Set it before Watch.
+	OnReload func(cfg gosseract.Config, err error)
+
+	// build builds pools, Config.BuildPool but for tests.
+	build func(cfg gosseract.Config) (Pool, error)
+
+	// the content of the file failed to reload, not to build it again until changed
+	rejected    []byte
+	rejectedErr error
+
+	reloading sync.Mutex
+	mu        sync.RWMutex
+	current   *generation
+	closed    bool
+	// the old generations draining in the background
+	draining sync.WaitGroup
+}
+
+// New loads the configuration file of the path and builds its pool. It's due to caller to Close the Runner.
+func New(path string, configure func(cfg *gosseract.Config)) (*Runner, error) {
+	return NewWith(path, configure, func(cfg gosseract.Config) (Pool, error) {
+		return cfg.BuildPool()
+	})
+}
+
+// NewWith is New building the pools by build rather than gosseract.Config.BuildPool, such as pools of
+// those of package server.
+func NewWith(path string, configure func(cfg *gosseract.Config), build func(cfg gosseract.Config) (Pool, error)) (*Runner, error) {
+	runner := &Runner{path: path, Configure: configure, build: build}
+	if err := runner.Reload(); err != nil {
+		return nil, err
+	}
+	return runner, nil
+}
+
+// Config returns the configuration of the pool serving now.
+func (runner *Runner) Config() gosseract.Config {
+	runner.mu.RLock()
+	defer runner.mu.RUnlock()
+	if runner.current == nil {
+		return gosseract.Config{}
+	}
+	return runner.current.cfg
+}
+
+// TextWithOptions recognizes the image data by the pool serving now, see gosseract.Pool.TextWithOptions.
+// It returns gosseract.ErrClientClosed after Close.
+func (runner *Runner) TextWithOptions(ctx context.Context, data []byte, opts gosseract.Options) (string, error) {
+	gen, err := runner.acquire()
+	if err != nil {
+		return "", err
+	}
+	defer gen.inflight.Done()
+	return gen.pool.TextWithOptions(ctx, data, opts)
+}
+
+// TextWithConfidence recognizes the image data by the pool serving now, see gosseract.Pool.TextWithConfidence.
+// It returns gosseract.ErrClientClosed after Close.
+func (runner *Runner) TextWithConfidence(ctx context.Context, data []byte, opts gosseract.Options) (string, float64, error) {
+	gen, err := runner.acquire()
+	if err != nil {
+		return "", 0, err
+	}
+	defer gen.inflight.Done()
+	return gen.pool.TextWithConfidence(ctx, data, opts)
+}
+
+// acquire returns the generation serving now, counting the request in flight on it.
+func (runner *Runner) acquire() (*generation, error) {
+	runner.mu.RLock()
+	defer runner.mu.RUnlock()
+	if runner.closed || runner.current == nil {
+		return nil, gosseract.ErrClientClosed
+	}
+	runner.current.inflight.Add(1)
+	return runner.current, nil
+}
+
+// Reload loads the configuration file and, if it's changed, builds the new pool and swaps it for the old one,
+// which is drained and closed in the background. The old pool keeps serving if the file is invalid
+// or the pool fails to build, e.g. for unknown languages. It's called by Watch, or on SIGHUP for instance.
+func (runner *Runner) Reload() error {
+	runner.reloading.Lock()
+	defer runner.reloading.Unlock()
+	data, err := os.ReadFile(runner.path)
+	if err != nil {
+		return err
+	}
+	runner.mu.RLock()
+	unchanged := runner.current != nil && bytes.Equal(runner.current.data, data)
+	runner.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+	if runner.rejected != nil && bytes.Equal(runner.rejected, data) {
+		return runner.rejectedErr
+	}
+	cfg, p, err := runner.load(data)
+	if err != nil {
+		runner.rejected, runner.rejectedErr = data, err
+		return err
+	}
+	runner.rejected, runner.rejectedErr = nil, nil
+
+	runner.mu.Lock()
+	if runner.closed {
+		runner.mu.Unlock()
+		return p.Close()
+	}
+	old := runner.current
+	runner.current = &generation{cfg: cfg, data: data, pool: p}
+	runner.mu.Unlock()
+	if old != nil {
+		runner.draining.Add(1)
+		go func() {
+			defer runner.draining.Done()
+			old.drain()
+		}()
+	}
+	return nil
+}
+
+// load parses and configures the configuration, and builds its pool.
+func (runner *Runner) load(data []byte) (gosseract.Config, Pool, error) {
+	cfg, err := parseConfig(runner.path, data)
+	if err != nil {
+		return cfg, nil, err
+	}
+	if runner.Configure != nil {
+		runner.Configure(&cfg)
+	}
+	p, err := runner.build(cfg)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("failed to build the pool of %s: %v", runner.path, err)
+	}
+	return cfg, p, nil
+}
+
+// drain closes the pool after the requests in flight on it. No request is added once it's swapped out.
+func (gen *generation) drain() error {
+	gen.inflight.Wait()
+	return gen.pool.Close()
+}
+
+// Watch polls the configuration file by the interval and reloads it when it's changed, until ctx is done.
+// It returns ctx.Err(). Failures of reloads are notified to OnReload once for each content of the file,
+// and don't stop watching.
+func (runner *Runner) Watch(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	failed := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		before := runner.generation()
+		err := runner.Reload()
+		after := runner.generation()
+		if runner.OnReload != nil && (after != before || err != nil && err.Error() != failed) {
+			runner.OnReload(after.cfg, err)
+		}
+		failed = ""
+		if err != nil {
+			failed = err.Error()
+		}
+	}
+}
+
+func (runner *Runner) generation() *generation {
+	runner.mu.RLock()
+	defer runner.mu.RUnlock()
+	return runner.current
+}
+
+// Close waits for the requests in flight, and closes the pool, along with the old pools still draining.
+func (runner *Runner) Close() error {
+	runner.reloading.Lock()
+	defer runner.reloading.Unlock()
+	runner.mu.Lock()
+	if runner.closed {
+		runner.mu.Unlock()
+		return gosseract.ErrClientClosed
+	}
+	runner.closed = true
+	gen := runner.current
+	runner.mu.Unlock()
+	err := gen.drain()
+	runner.draining.Wait()
+	return err
+}
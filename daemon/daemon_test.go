@@ -0,0 +1,183 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2"
+	. "github.com/otiai10/mint"
+)
+
+// fakePool answers the languages of its configuration, blocking recognitions until released.
+type fakePool struct {
+	text    string
+	release chan struct{}
+	mu      sync.Mutex
+	closed  bool
+}
+
+func (p *fakePool) TextWithOptions(ctx context.Context, data []byte, opts gosseract.Options) (string, error) {
+	if p.release != nil {
+		<-p.release
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return "", gosseract.ErrClientClosed
+	}
+	return p.text, nil
+}
+
+func (p *fakePool) TextWithConfidence(ctx context.Context, data []byte, opts gosseract.Options) (string, float64, error) {
+	text, err := p.TextWithOptions(ctx, data, opts)
+	return text, 90, err
+}
+
+func (p *fakePool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+func (p *fakePool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+func newRunner(t *testing.T, config string, pools *[]*fakePool) (*Runner, string) {
+	path := filepath.Join(t.TempDir(), "gosseract.json")
+	Expect(t, os.WriteFile(path, []byte(config), 0o644)).ToBe(nil)
+	runner := &Runner{path: path, build: func(cfg gosseract.Config) (Pool, error) {
+		if len(cfg.Languages) == 0 {
+			return nil, errors.New("no languages")
+		}
+		p := &fakePool{text: strings.Join(cfg.Languages, "+")}
+		if len(*pools) == 0 {
+			p.release = make(chan struct{})
+		}
+		*pools = append(*pools, p)
+		return p, nil
+	}}
+	Expect(t, runner.Reload()).ToBe(nil)
+	return runner, path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gosseract.json")
+	Expect(t, os.WriteFile(path, []byte(`{"languages": ["eng", "deu"], "pageSegMode": 6, "variables": {"preserve_interword_spaces": "1"}, "tuning": {"clients": 4}}`), 0o644)).ToBe(nil)
+	cfg, err := LoadConfig(path)
+	Expect(t, err).ToBe(nil)
+	Expect(t, cfg.Languages).ToBe([]string{"eng", "deu"})
+	Expect(t, cfg.PageSegMode).ToBe(gosseract.PSM_SINGLE_BLOCK)
+	Expect(t, cfg.Variables["preserve_interword_spaces"]).ToBe("1")
+	Expect(t, cfg.Tuning.Clients).ToBe(4)
+
+	Expect(t, os.WriteFile(path, []byte(`{"langauges": ["eng"]}`), 0o644)).ToBe(nil)
+	_, err = LoadConfig(path)
+	Expect(t, err).Not().ToBe(nil)
+}
+
+func TestRunner_Reload(t *testing.T) {
+	pools := []*fakePool{}
+	runner, path := newRunner(t, `{"languages": ["eng"]}`, &pools)
+	defer runner.Close()
+
+	// A request in flight on the first pool.
+	done := make(chan string)
+	go func() {
+		text, _ := runner.TextWithOptions(context.Background(), nil, gosseract.Options{})
+		done <- text
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	Expect(t, os.WriteFile(path, []byte(`{"languages": ["eng", "deu"]}`), 0o644)).ToBe(nil)
+	Expect(t, runner.Reload()).ToBe(nil)
+	Expect(t, len(pools)).ToBe(2)
+	Expect(t, runner.Config().Languages).ToBe([]string{"eng", "deu"})
+	text, err := runner.TextWithOptions(context.Background(), nil, gosseract.Options{})
+	Expect(t, err).ToBe(nil)
+	Expect(t, text).ToBe("eng+deu")
+	Expect(t, pools[0].isClosed()).ToBe(false)
+
+	close(pools[0].release)
+	Expect(t, <-done).ToBe("eng")
+	for i := 0; i < 100 && !pools[0].isClosed(); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	Expect(t, pools[0].isClosed()).ToBe(true)
+
+	When(t, "the file is unchanged", func(t *testing.T) {
+		Expect(t, runner.Reload()).ToBe(nil)
+		Expect(t, len(pools)).ToBe(2)
+	})
+
+	When(t, "the pool fails to build", func(t *testing.T) {
+		Expect(t, os.WriteFile(path, []byte(`{"languages": []}`), 0o644)).ToBe(nil)
+		Expect(t, runner.Reload()).Not().ToBe(nil)
+		Expect(t, runner.Reload()).Not().ToBe(nil)
+		Expect(t, len(pools)).ToBe(2)
+		text, err := runner.TextWithOptions(context.Background(), nil, gosseract.Options{})
+		Expect(t, err).ToBe(nil)
+		Expect(t, text).ToBe("eng+deu")
+	})
+}
+
+func TestRunner_Close(t *testing.T) {
+	pools := []*fakePool{}
+	runner, path := newRunner(t, `{"languages": ["eng"]}`, &pools)
+
+	// A request in flight on the first pool, swapped out.
+	done := make(chan string)
+	go func() {
+		text, _ := runner.TextWithOptions(context.Background(), nil, gosseract.Options{})
+		done <- text
+	}()
+	time.Sleep(10 * time.Millisecond)
+	Expect(t, os.WriteFile(path, []byte(`{"languages": ["eng", "deu"]}`), 0o644)).ToBe(nil)
+	Expect(t, runner.Reload()).ToBe(nil)
+
+	closed := make(chan error)
+	go func() { closed <- runner.Close() }()
+	select {
+	case <-closed:
+		t.Fatal("closed before the old pool is drained")
+	case <-time.After(10 * time.Millisecond):
+	}
+	Expect(t, pools[1].isClosed()).ToBe(true)
+
+	close(pools[0].release)
+	Expect(t, <-done).ToBe("eng")
+	Expect(t, <-closed).ToBe(nil)
+	Expect(t, pools[0].isClosed()).ToBe(true)
+}
+
+func TestRunner_Watch(t *testing.T) {
+	pools := []*fakePool{}
+	runner, path := newRunner(t, `{"languages": ["eng"]}`, &pools)
+	close(pools[0].release)
+	reloads := make(chan error, 10)
+	runner.OnReload = func(cfg gosseract.Config, err error) { reloads <- err }
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runner.Watch(ctx, time.Millisecond)
+
+	Expect(t, os.WriteFile(path, []byte(`{"languages": ["jpn"]}`), 0o644)).ToBe(nil)
+	Expect(t, <-reloads).ToBe(nil)
+	Expect(t, runner.Config().Languages).ToBe([]string{"jpn"})
+	Expect(t, os.WriteFile(path, []byte(`{`), 0o644)).ToBe(nil)
+	Expect(t, <-reloads).Not().ToBe(nil)
+	cancel()
+
+	Expect(t, runner.Close()).ToBe(nil)
+	_, err := runner.TextWithOptions(context.Background(), nil, gosseract.Options{})
+	Expect(t, err).ToBe(gosseract.ErrClientClosed)
+	Expect(t, runner.Close()).ToBe(gosseract.ErrClientClosed)
+}
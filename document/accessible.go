@@ -0,0 +1,188 @@
+package document
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WriteAccessibleText renders the document as plain text in reading order for screen readers and speech synthesis,
+// which read scanned pages poorly from hOCR or flat text. Lines of each paragraph are reflowed into one,
+// joining words hyphenated at line ends, and paragraphs are separated by empty lines.
+// Headings, inferred from font sizes, are announced as "Heading level 1: Introduction",
+// and pictures as "[image at the top left, 300 by 200 pixels at 40, 60]".
+func (doc *Document) WriteAccessibleText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	body := doc.bodyStyle()
+	first := true
+	for _, block := range doc.Blocks {
+		if block.Type.IsImage() {
+			writeSeparator(bw, &first)
+			fmt.Fprintf(bw, "[image%s, %d by %d pixels at %d, %d]\n",
+				doc.location(block), block.Box.Dx(), block.Box.Dy(), block.Box.Min.X, block.Box.Min.Y)
+			continue
+		}
+		for _, para := range block.Paragraphs {
+			text := reflow(para.Lines)
+			if text == "" {
+				continue
+			}
+			writeSeparator(bw, &first)
+			if level := headingLevel(block, para, body); level != 0 {
+				fmt.Fprintf(bw, "Heading level %d: ", level)
+			}
+			fmt.Fprintln(bw, text)
+		}
+	}
+	return bw.Flush()
+}
+
+func writeSeparator(w io.Writer, first *bool) {
+	if !*first {
+		fmt.Fprintln(w)
+	}
+	*first = false
+}
+
+// location describes where the block is on the page in words, such as " at the top left",
+// or returns "" if the size of the page is unknown.
+func (doc *Document) location(block Block) string {
+	if doc.Width <= 0 || doc.Height <= 0 {
+		return ""
+	}
+	center := block.Box.Min.Add(block.Box.Max).Div(2)
+	vertical := [3]string{"top", "middle", "bottom"}[thirds(center.Y, doc.Height)]
+	horizontal := [3]string{"left", "center", "right"}[thirds(center.X, doc.Width)]
+	switch {
+	case vertical == "middle" && horizontal == "center":
+		return " at the center"
+	case vertical == "middle":
+		return " at the middle " + horizontal
+	case horizontal == "center":
+		return " at the " + vertical
+	}
+	return " at the " + vertical + " " + horizontal
+}
+
+func thirds(v, size int) int {
+	switch i := 3 * v / size; {
+	case i < 0:
+		return 0
+	case i > 2:
+		return 2
+	default:
+		return i
+	}
+}
+
+// reflow joins the lines by spaces into a line of text, joining words hyphenated at line ends,
+// i.e. a line ending with "-" followed by a lowercase letter.
+func reflow(lines []Line) string {
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		texts = append(texts, line.Text())
+	}
+	return reflowTexts(texts)
+}
+
+func reflowTexts(texts []string) string {
+	var b strings.Builder
+	for _, text := range texts {
+		if text == "" {
+			continue
+		}
+		if b.Len() != 0 {
+			next, _ := utf8.DecodeRuneInString(text)
+			if joined := b.String(); strings.HasSuffix(joined, "-") && unicode.IsLower(next) {
+				b.Reset()
+				b.WriteString(strings.TrimSuffix(joined, "-"))
+			} else {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// fontSize estimates the font size of the words in pixels, as the median height of their boxes,
+// or returns 0 if there's no word.
+func fontSize(words []Word) float64 {
+	heights := make([]int, 0, len(words))
+	for _, word := range words {
+		if h := word.Box.Dy(); h > 0 {
+			heights = append(heights, h)
+		}
+	}
+	if len(heights) == 0 {
+		return 0
+	}
+	sort.Ints(heights)
+	if n := len(heights); n%2 == 0 {
+		return float64(heights[n/2-1]+heights[n/2]) / 2
+	}
+	return float64(heights[len(heights)/2])
+}
+
+// bodyStyle is the style of the body text of documents, against which headings and footnotes are told apart.
+type bodyStyle struct {
+	// size is the font size most words are set in.
+	size float64
+	// bold is whether most words are bold, when bold words aren't headings.
+	bold bool
+}
+
+func styleOf(words []Word) bodyStyle {
+	bold := 0
+	for _, word := range words {
+		if word.Bold {
+			bold++
+		}
+	}
+	return bodyStyle{size: fontSize(words), bold: 2*bold > len(words)}
+}
+
+// bodyStyle is the style of the body text of the document.
+func (doc *Document) bodyStyle() bodyStyle {
+	return styleOf(doc.Words())
+}
+
+// maxHeadingLines is the most lines of a paragraph taken as a heading.
+const maxHeadingLines = 3
+
+// headingLevel returns the level of the heading the paragraph is, from 1 for the largest,
+// or 0 if it's body text. Short paragraphs set larger than the body text by a quarter are headings,
+// and so are those in blocks of headings detected by tesseract, and lines in bold amid regular text.
+func headingLevel(block Block, para Paragraph, body bodyStyle) int {
+	if len(para.Lines) == 0 || len(para.Lines) > maxHeadingLines {
+		return 0
+	}
+	words := []Word{}
+	for _, line := range para.Lines {
+		words = append(words, line.Words...)
+	}
+	if len(words) == 0 {
+		return 0
+	}
+	ratio := 0.0
+	if body.size > 0 {
+		ratio = fontSize(words) / body.size
+	}
+	bold := len(para.Lines) == 1 && !body.bold && ratio >= 0.9
+	for _, word := range words {
+		bold = bold && word.Bold
+	}
+	switch {
+	case ratio >= 2:
+		return 1
+	case ratio >= 1.5:
+		return 2
+	case ratio >= 1.25, block.Type == BlockHeadingText, bold:
+		return 3
+	}
+	return 0
+}
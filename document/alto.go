@@ -0,0 +1,140 @@
+package document
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"image"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/chennqqi/gosseract/v2/geometry"
+)
+
+// ParseALTO loads a Document from ALTO XML, such as generated by tesseract or WriteALTO.
+// ComposedBlock and TextBlock are read as blocks and paragraphs, a TextBlock out of any ComposedBlock
+// making a block by itself, with TextLine and String as lines and words. Only the first page is read.
+func ParseALTO(r io.Reader) (*Document, error) {
+	decoder := xml.NewDecoder(r)
+	builder := &Builder{}
+	pages, composed := 0, 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ALTO: %v", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			attrs := altoAttrs(t)
+			box := attrs.box()
+			switch t.Name.Local {
+			case "Page":
+				pages++
+				if pages > 1 {
+					return builder.Document(), nil
+				}
+				builder.SetSize(attrs.int("WIDTH"), attrs.int("HEIGHT"))
+			case "ComposedBlock":
+				composed++
+				builder.Block(Block{Box: box})
+			case "TextBlock":
+				if composed == 0 {
+					builder.Block(Block{Box: box})
+				}
+				builder.Paragraph(Paragraph{Box: box})
+			case "TextLine":
+				builder.Line(Line{Box: box})
+			case "String":
+				builder.Word(Word{
+					Box:        box,
+					Text:       attrs["CONTENT"],
+					Confidence: attrs.float("WC") * 100,
+					Quad:       geometry.QuadOf(box),
+				})
+			}
+		case xml.EndElement:
+			if t.Name.Local == "ComposedBlock" {
+				composed--
+			}
+		}
+	}
+	return builder.Document(), nil
+}
+
+type altoAttributes map[string]string
+
+func altoAttrs(t xml.StartElement) altoAttributes {
+	attrs := altoAttributes{}
+	for _, attr := range t.Attr {
+		attrs[attr.Name.Local] = attr.Value
+	}
+	return attrs
+}
+
+// float returns the attribute as a number, which ALTO allows to be fractional even for pixels.
+func (attrs altoAttributes) float(name string) float64 {
+	v, _ := strconv.ParseFloat(attrs[name], 64)
+	return v
+}
+
+func (attrs altoAttributes) int(name string) int {
+	return int(math.Round(attrs.float(name)))
+}
+
+func (attrs altoAttributes) box() image.Rectangle {
+	x, y := attrs.int("HPOS"), attrs.int("VPOS")
+	return image.Rect(x, y, x+attrs.int("WIDTH"), y+attrs.int("HEIGHT"))
+}
+
+// WriteALTO renders the document as ALTO XML v3 in the same structure as tesseract generates,
+// i.e. a ComposedBlock for each block and a TextBlock for each paragraph.
+func (doc *Document) WriteALTO(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, `<?xml version="1.0" encoding="UTF-8"?>
+<alto xmlns="http://www.loc.gov/standards/alto/ns-v3#" xmlns:xlink="http://www.w3.org/1999/xlink" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.loc.gov/standards/alto/ns-v3# http://www.loc.gov/alto/v3/alto-3-0.xsd">
+ <Description>
+  <MeasurementUnit>pixel</MeasurementUnit>
+  <OCRProcessing ID="OCR_0">
+   <ocrProcessingStep>
+    <processingSoftware>
+     <softwareName>gosseract</softwareName>
+    </processingSoftware>
+   </ocrProcessingStep>
+  </OCRProcessing>
+ </Description>
+ <Layout>
+`)
+	fmt.Fprintf(bw, "  <Page WIDTH=\"%d\" HEIGHT=\"%d\" PHYSICAL_IMG_NR=\"0\" ID=\"page_0\">\n", doc.Width, doc.Height)
+	fmt.Fprintf(bw, "   <PrintSpace %s>\n", altoPosition(image.Rect(0, 0, doc.Width, doc.Height)))
+	for b, block := range doc.Blocks {
+		fmt.Fprintf(bw, "    <ComposedBlock ID=\"cblock_%d\" %s>\n", b, altoPosition(block.Box))
+		for p, para := range block.Paragraphs {
+			fmt.Fprintf(bw, "     <TextBlock ID=\"block_%d_%d\" %s>\n", b, p, altoPosition(para.Box))
+			for l, line := range para.Lines {
+				fmt.Fprintf(bw, "      <TextLine ID=\"line_%d_%d_%d\" %s>\n", b, p, l, altoPosition(line.Box))
+				for i, word := range line.Words {
+					if i > 0 {
+						fmt.Fprintf(bw, "       <SP WIDTH=\"%d\" VPOS=\"%d\" HPOS=\"%d\"/>\n",
+							word.Box.Min.X-line.Words[i-1].Box.Max.X, word.Box.Min.Y, line.Words[i-1].Box.Max.X)
+					}
+					fmt.Fprintf(bw, "       <String ID=\"string_%d_%d_%d_%d\" %s WC=\"%.2f\" CONTENT=\"%s\"/>\n",
+						b, p, l, i, altoPosition(word.Box), word.Confidence/100, html.EscapeString(word.Text))
+				}
+				fmt.Fprint(bw, "      </TextLine>\n")
+			}
+			fmt.Fprint(bw, "     </TextBlock>\n")
+		}
+		fmt.Fprint(bw, "    </ComposedBlock>\n")
+	}
+	fmt.Fprint(bw, "   </PrintSpace>\n  </Page>\n </Layout>\n</alto>\n")
+	return bw.Flush()
+}
+
+func altoPosition(box image.Rectangle) string {
+	return fmt.Sprintf("HPOS=\"%d\" VPOS=\"%d\" WIDTH=\"%d\" HEIGHT=\"%d\"", box.Min.X, box.Min.Y, box.Dx(), box.Dy())
+}
@@ -0,0 +1,69 @@
+package document
+
+// Builder assembles a Document from its elements in reading order,
+// each block, paragraph and line followed by its children.
+// Elements missing their parents, e.g. words out of any line, get empty ones.
+// The zero value is ready to use.
+type Builder struct {
+	doc Document
+}
+
+// Block appends a block to the document.
+func (b *Builder) Block(block Block) {
+	b.doc.Blocks = append(b.doc.Blocks, block)
+}
+
+// Paragraph appends a paragraph to the last block.
+func (b *Builder) Paragraph(para Paragraph) {
+	block := b.lastBlock()
+	block.Paragraphs = append(block.Paragraphs, para)
+}
+
+// Line appends a line to the last paragraph.
+func (b *Builder) Line(line Line) {
+	para := b.lastParagraph()
+	para.Lines = append(para.Lines, line)
+}
+
+// Word appends a word to the last line.
+func (b *Builder) Word(word Word) {
+	line := b.lastLine()
+	line.Words = append(line.Words, word)
+}
+
+// Document returns the document built so far.
+func (b *Builder) Document() *Document {
+	doc := b.doc
+	if doc.Blocks == nil {
+		doc.Blocks = []Block{}
+	}
+	return &doc
+}
+
+// SetSize sets the size of the page.
+func (b *Builder) SetSize(width, height int) {
+	b.doc.Width, b.doc.Height = width, height
+}
+
+func (b *Builder) lastBlock() *Block {
+	if len(b.doc.Blocks) == 0 {
+		b.doc.Blocks = append(b.doc.Blocks, Block{})
+	}
+	return &b.doc.Blocks[len(b.doc.Blocks)-1]
+}
+
+func (b *Builder) lastParagraph() *Paragraph {
+	block := b.lastBlock()
+	if len(block.Paragraphs) == 0 {
+		block.Paragraphs = append(block.Paragraphs, Paragraph{})
+	}
+	return &block.Paragraphs[len(block.Paragraphs)-1]
+}
+
+func (b *Builder) lastLine() *Line {
+	para := b.lastParagraph()
+	if len(para.Lines) == 0 {
+		para.Lines = append(para.Lines, Line{})
+	}
+	return &para.Lines[len(para.Lines)-1]
+}
@@ -0,0 +1,69 @@
+package document
+
+import "fmt"
+
+// WordRef addresses a word in a Document by the indices of its block, paragraph and line, and of the word itself.
+type WordRef struct {
+	Block     int `json:"block"`
+	Paragraph int `json:"paragraph"`
+	Line      int `json:"line"`
+	Word      int `json:"word"`
+}
+
+// Correction replaces the text of the word addressed by Ref.
+type Correction struct {
+	Ref  WordRef `json:"ref"`
+	Text string  `json:"text"`
+}
+
+// Word returns the word addressed by ref, or nil if there is no such word.
+func (doc *Document) Word(ref WordRef) *Word {
+	if ref.Block < 0 || ref.Block >= len(doc.Blocks) {
+		return nil
+	}
+	block := &doc.Blocks[ref.Block]
+	if ref.Paragraph < 0 || ref.Paragraph >= len(block.Paragraphs) {
+		return nil
+	}
+	para := &block.Paragraphs[ref.Paragraph]
+	if ref.Line < 0 || ref.Line >= len(para.Lines) {
+		return nil
+	}
+	line := &para.Lines[ref.Line]
+	if ref.Word < 0 || ref.Word >= len(line.Words) {
+		return nil
+	}
+	return &line.Words[ref.Word]
+}
+
+// EachWord calls f for each word of the document in reading order, which can modify the word.
+func (doc *Document) EachWord(f func(ref WordRef, word *Word)) {
+	for b := range doc.Blocks {
+		for p := range doc.Blocks[b].Paragraphs {
+			for l := range doc.Blocks[b].Paragraphs[p].Lines {
+				line := &doc.Blocks[b].Paragraphs[p].Lines[l]
+				for w := range line.Words {
+					f(WordRef{Block: b, Paragraph: p, Line: l, Word: w}, &line.Words[w])
+				}
+			}
+		}
+	}
+}
+
+// Correct replaces the text of words by the corrections, e.g. made by human reviewers,
+// marking them as Corrected with the full confidence, so that the document can be rendered
+// again without running OCR. Nothing is applied if any of the corrections addresses no word.
+func (doc *Document) Correct(corrections ...Correction) error {
+	for _, c := range corrections {
+		if doc.Word(c.Ref) == nil {
+			return fmt.Errorf("no word to correct at %+v", c.Ref)
+		}
+	}
+	for _, c := range corrections {
+		word := doc.Word(c.Ref)
+		word.Text = c.Text
+		word.Confidence = 100
+		word.Corrected = true
+	}
+	return nil
+}
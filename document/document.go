@@ -0,0 +1,204 @@
+// Package document represents a page recognized by gosseract as plain data,
+// i.e. blocks, paragraphs, lines and words with their geometry,
+// which can be inspected and rendered without tesseract.
+package document
+
+import (
+	"image"
+	"strings"
+
+	"github.com/chennqqi/gosseract/v2/geometry"
+)
+
+// Document is the layout of a recognized page, in reading order.
+type Document struct {
+	// Width and Height of the page image in pixels, zero if unknown.
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	Blocks []Block `json:"blocks"`
+
+	// Partial is whether the recognition was cancelled or timed out on the way,
+	// so that the document has only the words recognized so far.
+	Partial bool `json:"partial,omitempty"`
+
+	// Handwriting is the regions of the page which look handwritten, see Document.MarkHandwriting.
+	Handwriting []HandwritingRegion `json:"handwriting,omitempty"`
+
+	// Graphics is the stamps and logos of the page, see Document.MarkGraphics.
+	Graphics []GraphicRegion `json:"graphics,omitempty"`
+
+	// GridLines is the boxes of the rules of tables and forms erased before recognition,
+	// see gosseract.PreprocessOptions.RemoveGridLines.
+	GridLines []image.Rectangle `json:"grid_lines,omitempty"`
+}
+
+// Block is a region of the page, such as a column of text.
+type Block struct {
+	Box image.Rectangle `json:"box"`
+
+	// Type of the block as tesseract detects it. Blocks of pictures have no paragraphs.
+	Type BlockType `json:"type,omitempty"`
+
+	// Polygon is the outline of the block, which is not always rectangular,
+	// e.g. rotated text on skewed scans, or text flowing around pictures.
+	// It's nil if tesseract reports no outline.
+	Polygon geometry.Polygon `json:"polygon,omitempty"`
+
+	Paragraphs []Paragraph `json:"paragraphs"`
+}
+
+// Paragraph is a paragraph of text in a block.
+type Paragraph struct {
+	Box   image.Rectangle `json:"box"`
+	Lines []Line          `json:"lines"`
+}
+
+// Line is a line of text in a paragraph.
+type Line struct {
+	Box   image.Rectangle `json:"box"`
+	Words []Word          `json:"words"`
+}
+
+// Word is a word recognized in a line.
+type Word struct {
+	Box        image.Rectangle `json:"box"`
+	Text       string          `json:"text"`
+	Confidence float64         `json:"confidence"`
+
+	// Original is the text as recognized, if Text is normalized afterwards, e.g. by the normalize package.
+	Original string `json:"original,omitempty"`
+
+	// Orientation of the block the word belongs to.
+	Orientation Orientation `json:"orientation"`
+
+	// Angle is the rotation of the word in degrees, counterclockwise from the horizontal,
+	// combining the orientation and the skew of its baseline, e.g. 90 for text read bottom to top.
+	Angle float64 `json:"angle"`
+
+	// Quad is the corners of the rotated rectangle enclosing the word, clockwise from the top-left
+	// corner as the text reads, which is tighter than Box for rotated text.
+	Quad geometry.Quad `json:"quad"`
+
+	// Probability that the text is correct, calibrated from Confidence by the calibrate package.
+	// It's zero unless calibrated.
+	Probability float64 `json:"probability,omitempty"`
+
+	// Bold is whether the word is set in bold, as far as tesseract tells.
+	Bold bool `json:"bold,omitempty"`
+
+	// FromDictionary is whether the word is found in the dictionaries of the language, and Numeric whether
+	// it's matched by the numeric patterns instead. Words of neither are guesses of characters, to be trusted less.
+	FromDictionary bool `json:"from_dictionary,omitempty"`
+	Numeric        bool `json:"numeric,omitempty"`
+
+	// Superscript and Subscript are whether the word is raised or lowered from the line in a smaller size,
+	// such as "2" of "x²" and footnote markers, and Dropcap whether it's the large initial opening a paragraph.
+	Superscript bool `json:"superscript,omitempty"`
+	Subscript   bool `json:"subscript,omitempty"`
+	Dropcap     bool `json:"dropcap,omitempty"`
+
+	// Underlined and StruckThrough are whether rules run under or across the word, see Document.MarkRules.
+	Underlined    bool `json:"underlined,omitempty"`
+	StruckThrough bool `json:"struck_through,omitempty"`
+
+	// Corrected is true if the text is corrected by Document.Correct, rather than recognized.
+	Corrected bool `json:"corrected,omitempty"`
+}
+
+// BlockType is the type of a block, representing tesseract::PolyBlockType.
+type BlockType int
+
+const (
+	// BlockUnknown is a block of unknown type, such as blocks of documents parsed from other formats.
+	BlockUnknown BlockType = iota
+	BlockFlowingText
+	BlockHeadingText
+	BlockPulloutText
+	BlockEquation
+	BlockInlineEquation
+	BlockTable
+	BlockVerticalText
+	BlockCaptionText
+	BlockFlowingImage
+	BlockHeadingImage
+	BlockPulloutImage
+	BlockHorizontalLine
+	BlockVerticalLine
+	BlockNoise
+)
+
+// IsImage reports whether the block is a picture, rather than text.
+func (t BlockType) IsImage() bool {
+	return t == BlockFlowingImage || t == BlockHeadingImage || t == BlockPulloutImage
+}
+
+// Orientation is the direction the top of the text faces, representing tesseract::Orientation.
+type Orientation int
+
+const (
+	// OrientationPageUp is upright text.
+	OrientationPageUp Orientation = iota
+	// OrientationPageRight is text rotated 90 degrees clockwise, with its top facing right.
+	OrientationPageRight
+	// OrientationPageDown is text upside down.
+	OrientationPageDown
+	// OrientationPageLeft is text rotated 90 degrees counterclockwise, with its top facing left.
+	OrientationPageLeft
+)
+
+// Angle returns the rotation of text in this orientation in degrees, counterclockwise.
+func (o Orientation) Angle() float64 {
+	switch o {
+	case OrientationPageRight:
+		return -90
+	case OrientationPageDown:
+		return 180
+	case OrientationPageLeft:
+		return 90
+	}
+	return 0
+}
+
+// Text returns the text of the document, separating words by spaces,
+// lines by newlines and paragraphs by empty lines.
+func (doc *Document) Text() string {
+	paragraphs := []string{}
+	for _, block := range doc.Blocks {
+		for _, para := range block.Paragraphs {
+			paragraphs = append(paragraphs, para.Text())
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// Words returns all the words of the document in reading order.
+func (doc *Document) Words() []Word {
+	words := []Word{}
+	for _, block := range doc.Blocks {
+		for _, para := range block.Paragraphs {
+			for _, line := range para.Lines {
+				words = append(words, line.Words...)
+			}
+		}
+	}
+	return words
+}
+
+// Text returns the text of the paragraph, separating lines by newlines.
+func (para Paragraph) Text() string {
+	lines := make([]string, 0, len(para.Lines))
+	for _, line := range para.Lines {
+		lines = append(lines, line.Text())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Text returns the text of the line, separating words by spaces.
+func (line Line) Text() string {
+	words := make([]string, 0, len(line.Words))
+	for _, word := range line.Words {
+		words = append(words, word.Text)
+	}
+	return strings.Join(words, " ")
+}
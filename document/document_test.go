@@ -0,0 +1,676 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2/geometry"
+	. "github.com/otiai10/mint"
+)
+
+func testDocument() *Document {
+	return &Document{Blocks: []Block{
+		{
+			Box:     image.Rect(0, 0, 100, 40),
+			Polygon: []image.Point{{0, 0}, {100, 5}, {100, 40}, {0, 35}},
+			Paragraphs: []Paragraph{
+				{Lines: []Line{
+					{Words: []Word{{Text: "Hello,"}, {Text: "World!"}}},
+					{Words: []Word{{Text: "Second"}, {Text: "line"}}},
+				}},
+				{Lines: []Line{
+					{Words: []Word{{Text: "Next"}}},
+				}},
+			},
+		},
+	}}
+}
+
+func TestDocument_Text(t *testing.T) {
+	Expect(t, testDocument().Text()).ToBe("Hello, World!\nSecond line\n\nNext")
+	Expect(t, (&Document{}).Text()).ToBe("")
+}
+
+func TestDocument_Words(t *testing.T) {
+	words := testDocument().Words()
+	Expect(t, len(words)).ToBe(5)
+	Expect(t, words[4].Text).ToBe("Next")
+}
+
+func TestOrientation_Angle(t *testing.T) {
+	Expect(t, OrientationPageUp.Angle()).ToBe(0.0)
+	Expect(t, OrientationPageRight.Angle()).ToBe(-90.0)
+	Expect(t, OrientationPageLeft.Angle()).ToBe(90.0)
+}
+
+func TestDocument_Correct(t *testing.T) {
+	doc := testDocument()
+	err := doc.Correct(Correction{Ref: WordRef{Paragraph: 0, Line: 1, Word: 1}, Text: "lines"})
+	Expect(t, err).ToBe(nil)
+	Expect(t, doc.Text()).ToBe("Hello, World!\nSecond lines\n\nNext")
+	word := doc.Word(WordRef{Line: 1, Word: 1})
+	Expect(t, word.Corrected).ToBe(true)
+	Expect(t, word.Confidence).ToBe(100.0)
+
+	When(t, "any correction addresses no word", func(t *testing.T) {
+		err := doc.Correct(
+			Correction{Ref: WordRef{}, Text: "Hi,"},
+			Correction{Ref: WordRef{Block: 1}, Text: "foo"},
+		)
+		Expect(t, err).Not().ToBe(nil)
+		Expect(t, doc.Word(WordRef{}).Text).ToBe("Hello,")
+	})
+
+	Because(t, "EachWord visits words with their refs", func(t *testing.T) {
+		refs := []WordRef{}
+		doc.EachWord(func(ref WordRef, word *Word) {
+			refs = append(refs, ref)
+		})
+		Expect(t, len(refs)).ToBe(5)
+		Expect(t, refs[4]).ToBe(WordRef{Paragraph: 1})
+	})
+}
+
+func TestParseHOCR(t *testing.T) {
+	doc := testDocument()
+	doc.Width, doc.Height = 120, 50
+	doc.Blocks[0].Paragraphs[0].Lines[0].Words[0].Text = "<Hello>&"
+	buf := bytes.NewBuffer(nil)
+	Expect(t, doc.WriteHOCR(buf)).ToBe(nil)
+
+	loaded, err := ParseHOCR(buf)
+	Expect(t, err).ToBe(nil)
+	Expect(t, loaded.Text()).ToBe(doc.Text())
+	Expect(t, loaded.Width).ToBe(120)
+
+	When(t, "hOCR is generated by tesseract", func(t *testing.T) {
+		doc, err := ParseHOCR(strings.NewReader(`<div class='ocr_page' id='page_1' title='image ""; bbox 0 0 640 480; ppageno 0'>
+   <div class='ocr_carea' id='block_1_1' title="bbox 36 92 618 184">
+    <p class='ocr_par' id='par_1_1' lang='eng' title="bbox 36 92 618 184">
+     <span class='ocr_line' id='line_1_1' title="bbox 36 92 618 184; baseline 0 -24; x_size 92; x_descenders 22; x_ascenders 22">
+      <span class='ocrx_word' id='word_1_1' title='bbox 36 92 322 184; x_wconf 96'><strong>Hello,</strong></span>
+      <span class='ocrx_word' id='word_1_2' title='bbox 376 92 618 184; x_wconf 95'>World&#39;s</span>
+     </span>
+    </p>
+   </div>
+  </div>`))
+		Expect(t, err).ToBe(nil)
+		Expect(t, doc.Text()).ToBe("Hello, World's")
+		Expect(t, doc.Words()[0].Box).ToBe(image.Rect(36, 92, 322, 184))
+		Expect(t, doc.Words()[1].Confidence).ToBe(95.0)
+	})
+}
+
+func TestParseALTO(t *testing.T) {
+	doc := testDocument()
+	doc.Blocks[0].Paragraphs[0].Lines[0].Words[0].Box = image.Rect(10, 20, 50, 40)
+	doc.Blocks[0].Paragraphs[0].Lines[0].Words[0].Confidence = 91
+	buf := bytes.NewBuffer(nil)
+	Expect(t, doc.WriteALTO(buf)).ToBe(nil)
+
+	loaded, err := ParseALTO(buf)
+	Expect(t, err).ToBe(nil)
+	Expect(t, loaded.Text()).ToBe(doc.Text())
+	Expect(t, loaded.Words()[0].Box).ToBe(image.Rect(10, 20, 50, 40))
+	Expect(t, loaded.Words()[0].Confidence).ToBe(91.0)
+}
+
+func TestParseJSON(t *testing.T) {
+	doc := testDocument()
+	buf := bytes.NewBuffer(nil)
+	Expect(t, doc.WriteJSON(buf)).ToBe(nil)
+	loaded, err := ParseJSON(buf)
+	Expect(t, err).ToBe(nil)
+	Expect(t, loaded).Deeply().ToBe(doc)
+}
+
+func TestDocument_ExportReview(t *testing.T) {
+	doc := testDocument()
+	doc.EachWord(func(ref WordRef, word *Word) {
+		word.Confidence = 95
+		word.Box = image.Rect(10*ref.Word, 10*ref.Line, 10*ref.Word+8, 10*ref.Line+8)
+	})
+	doc.Word(WordRef{Line: 1, Word: 1}).Confidence = 40
+	page := image.NewGray(image.Rect(0, 0, 100, 40))
+
+	buf := bytes.NewBuffer(nil)
+	err := doc.ExportReview(buf, page, 60)
+	Expect(t, err).ToBe(nil)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	Expect(t, len(lines)).ToBe(1)
+
+	item := ReviewItem{}
+	Expect(t, json.Unmarshal([]byte(lines[0]), &item)).ToBe(nil)
+	Expect(t, item.Text).ToBe("line")
+	crop, err := png.Decode(bytes.NewReader(item.Crop))
+	Expect(t, err).ToBe(nil)
+	Expect(t, crop.Bounds().Size()).ToBe(image.Pt(16, 16))
+
+	When(t, "reviewer corrects the word", func(t *testing.T) {
+		correction := "lines"
+		item.Correction = &correction
+		b, _ := json.Marshal(item)
+		err := doc.ImportReview(bytes.NewReader(append(b, '\n')))
+		Expect(t, err).ToBe(nil)
+		Expect(t, doc.Word(item.Ref).Text).ToBe("lines")
+
+		Because(t, "the review is stale now", func(t *testing.T) {
+			err := doc.ImportReview(bytes.NewReader(b))
+			Expect(t, err).Not().ToBe(nil)
+		})
+	})
+}
+
+func TestDocument_Rows(t *testing.T) {
+	doc := &Document{Blocks: []Block{
+		{Paragraphs: []Paragraph{{Lines: []Line{
+			{Box: image.Rect(0, 0, 50, 10), Words: []Word{{Text: "Coffee", Box: image.Rect(0, 0, 50, 10)}}},
+			{Box: image.Rect(0, 20, 50, 30), Words: []Word{{Text: "Tea", Box: image.Rect(0, 20, 50, 30)}}},
+		}}}},
+		{Paragraphs: []Paragraph{{Lines: []Line{
+			{Box: image.Rect(100, 2, 130, 12), Words: []Word{{Text: "3.50", Box: image.Rect(100, 2, 130, 12)}}},
+			{Box: image.Rect(100, 22, 130, 32), Words: []Word{{Text: "2.00", Box: image.Rect(100, 22, 130, 32)}}},
+		}}}},
+	}}
+	rows := doc.Rows()
+	Expect(t, len(rows)).ToBe(2)
+	Expect(t, rows[0].Text()).ToBe("Coffee 3.50")
+	Expect(t, rows[0].Box).ToBe(image.Rect(0, 0, 130, 12))
+	Expect(t, rows[1].Text()).ToBe("Tea 2.00")
+}
+
+func TestDocument_WriteAccessibleText(t *testing.T) {
+	word := func(text string, x, y, h int) Word {
+		return Word{Text: text, Box: image.Rect(x, y, x+10*len(text), y+h)}
+	}
+	doc := &Document{Width: 300, Height: 300, Blocks: []Block{
+		{Paragraphs: []Paragraph{
+			{Lines: []Line{{Words: []Word{word("Report", 0, 0, 30)}}}},
+			{Lines: []Line{
+				{Words: []Word{word("An", 0, 40, 12), word("exam-", 30, 40, 12)}},
+				{Words: []Word{word("ple", 0, 60, 12), word("of", 40, 60, 12), word("text.", 70, 60, 12)}},
+			}},
+		}},
+		{Box: image.Rect(200, 0, 300, 80), Type: BlockFlowingImage},
+		{Type: BlockHeadingText, Paragraphs: []Paragraph{
+			{Lines: []Line{{Words: []Word{word("Notes", 0, 100, 12)}}}},
+		}},
+	}}
+	buf := bytes.NewBuffer(nil)
+	err := doc.WriteAccessibleText(buf)
+	Expect(t, err).ToBe(nil)
+	Expect(t, buf.String()).ToBe("Heading level 1: Report\n\n" +
+		"An example of text.\n\n" +
+		"[image at the top right, 100 by 80 pixels at 200, 0]\n\n" +
+		"Heading level 3: Notes\n")
+
+	When(t, "the size of the page is unknown", func(t *testing.T) {
+		doc.Width, doc.Height = 0, 0
+		buf.Reset()
+		doc.WriteAccessibleText(buf)
+		Expect(t, strings.Contains(buf.String(), "[image, 100 by 80 pixels at 200, 0]\n")).ToBe(true)
+	})
+}
+
+func TestParseHOCR_Photo(t *testing.T) {
+	doc := &Document{Width: 100, Height: 100, Blocks: []Block{
+		{Box: image.Rect(10, 10, 50, 50), Type: BlockFlowingImage},
+		{Box: image.Rect(0, 60, 100, 80), Paragraphs: []Paragraph{{Lines: []Line{{Words: []Word{{Text: "Caption"}}}}}}},
+	}}
+	buf := bytes.NewBuffer(nil)
+	Expect(t, doc.WriteHOCR(buf)).ToBe(nil)
+	parsed, err := ParseHOCR(buf)
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(parsed.Blocks)).ToBe(2)
+	Expect(t, parsed.Blocks[0].Type.IsImage()).ToBe(true)
+	Expect(t, parsed.Blocks[0].Box).ToBe(image.Rect(10, 10, 50, 50))
+	Expect(t, parsed.Blocks[1].Paragraphs[0].Text()).ToBe("Caption")
+}
+
+func testPages() Pages {
+	word := func(text string, x, y, h int) Word {
+		return Word{Text: text, Box: image.Rect(x, y, x+10*len(text), y+h)}
+	}
+	line := func(y, h int, texts ...string) Line {
+		line, x := Line{}, 0
+		for _, text := range texts {
+			line.Words = append(line.Words, word(text, x, y, h))
+			x += 10*len(text) + 10
+		}
+		return line
+	}
+	return Pages{
+		{Blocks: []Block{{Paragraphs: []Paragraph{
+			{Lines: []Line{line(0, 30, "Groceries")}},
+			{Lines: []Line{line(40, 12, "Buy", "these", "*today*:")}},
+			{Lines: []Line{
+				line(60, 12, "•", "Apples"),
+				line(80, 12, "•", "Pears", "and"),
+				line(100, 12, "plums"),
+			}},
+		}}}},
+		{Blocks: []Block{
+			{Paragraphs: []Paragraph{{Lines: []Line{
+				line(0, 12, "1.", "Wash"),
+				line(20, 12, "2.", "Cut"),
+			}}}},
+			{Box: image.Rect(0, 50, 40, 80), Type: BlockFlowingImage},
+			{Paragraphs: []Paragraph{{Lines: []Line{line(90, 12, "#1", "in", "town")}}}},
+		}},
+	}
+}
+
+func TestPages_WriteMarkdown(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	err := testPages().WriteMarkdown(buf)
+	Expect(t, err).ToBe(nil)
+	Expect(t, buf.String()).ToBe("<!-- page 1 -->\n\n" +
+		"# Groceries\n\n" +
+		"Buy these \\*today\\*:\n\n" +
+		"- Apples\n- Pears and plums\n\n" +
+		"<!-- page 2 -->\n\n" +
+		"1. Wash\n2. Cut\n\n" +
+		"<!-- image, 40 by 30 pixels at 0, 50 -->\n\n" +
+		"\\#1 in town\n")
+}
+
+func TestPages_WriteEPUB(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	err := testPages().WriteEPUB(buf, EPUBMetadata{Title: "Shopping", Modified: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)})
+	Expect(t, err).ToBe(nil)
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	Expect(t, err).ToBe(nil)
+	Expect(t, zr.File[0].Name).ToBe("mimetype")
+	Expect(t, zr.File[0].Method).ToBe(zip.Store)
+	files := unzip(t, buf.Bytes())
+	Expect(t, files["mimetype"]).ToBe("application/epub+zip")
+	Expect(t, strings.Contains(files["OEBPS/content.opf"], "<dc:title>Shopping</dc:title>")).ToBe(true)
+	Expect(t, strings.Contains(files["OEBPS/content.opf"], "2020-01-02T03:04:05Z")).ToBe(true)
+	Expect(t, strings.Contains(files["OEBPS/content.opf"], `<itemref idref="page-2"/>`)).ToBe(true)
+	Expect(t, strings.Contains(files["OEBPS/page-1.xhtml"], "<h1 id=\"heading-1-1\">Groceries</h1>\n  <p>Buy these *today*:</p>\n  <ul>\n   <li>Apples</li>\n")).ToBe(true)
+	Expect(t, strings.Contains(files["OEBPS/page-2.xhtml"], "<ol>\n   <li value=\"1\">Wash</li>\n   <li value=\"2\">Cut</li>\n  </ol>\n")).ToBe(true)
+	Expect(t, strings.Contains(files["OEBPS/nav.xhtml"], `<a href="page-1.xhtml#heading-1-1">Groceries</a>`)).ToBe(true)
+}
+
+func unzip(t *testing.T, b []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	Expect(t, err).ToBe(nil)
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		Expect(t, err).ToBe(nil)
+		content, _ := io.ReadAll(r)
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestPages_WriteDOCX(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	err := testPages().WriteDOCX(buf, DOCXOptions{})
+	Expect(t, err).ToBe(nil)
+	files := unzip(t, buf.Bytes())
+	Expect(t, strings.Contains(files["[Content_Types].xml"], `PartName="/word/document.xml"`)).ToBe(true)
+	doc := files["word/document.xml"]
+	Expect(t, strings.Contains(doc, `<w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t xml:space="preserve">Groceries</w:t></w:r>`)).ToBe(true)
+	Expect(t, strings.Contains(doc, `<w:t xml:space="preserve">•</w:t><w:tab/><w:t xml:space="preserve">Pears and plums</w:t>`)).ToBe(true)
+	Expect(t, strings.Contains(doc, `<w:t xml:space="preserve">2.</w:t><w:tab/><w:t xml:space="preserve">Cut</w:t>`)).ToBe(true)
+	Expect(t, strings.Count(doc, `<w:br w:type="page"/>`)).ToBe(1)
+
+	When(t, "paragraphs are positioned", func(t *testing.T) {
+		pages := testPages()
+		for _, page := range pages {
+			page.Width, page.Height = 200, 300
+		}
+		pages[0].Blocks[0].Paragraphs[0].Box = image.Rect(10, 0, 100, 30)
+		buf.Reset()
+		err := pages.WriteDOCX(buf, DOCXOptions{Positioned: true, DPI: 144})
+		Expect(t, err).ToBe(nil)
+		doc := unzip(t, buf.Bytes())["word/document.xml"]
+		Expect(t, strings.Contains(doc, `<w:framePr w:w="900" w:h="300" w:hRule="atLeast" w:x="100" w:y="0"`)).ToBe(true)
+		Expect(t, strings.Contains(doc, `<w:sz w:val="30"/>`)).ToBe(true)
+		Expect(t, strings.Contains(doc, `<w:t xml:space="preserve">• Pears and</w:t></w:r><w:r><w:br/></w:r>`)).ToBe(true)
+		Expect(t, strings.Count(doc, `<w:pgSz w:w="2000" w:h="3000"/>`)).ToBe(2)
+		Expect(t, strings.Contains(doc, "image")).ToBe(false)
+	})
+}
+
+func TestPages_WritePDF(t *testing.T) {
+	page := &Document{Width: 600, Height: 300, Blocks: []Block{{Paragraphs: []Paragraph{{Lines: []Line{{Words: []Word{
+		{Text: "Hello", Box: image.Rect(100, 100, 300, 150)},
+	}}}}}}}}
+	buf := bytes.NewBuffer(nil)
+	err := Pages{page, page}.WritePDF(buf, PDFOptions{Images: []image.Image{image.NewGray(image.Rect(0, 0, 600, 300))}, DPI: 72})
+	Expect(t, err).ToBe(nil)
+	pdf := buf.Bytes()
+	Expect(t, bytes.HasPrefix(pdf, []byte("%PDF-1.5\n"))).ToBe(true)
+	Expect(t, bytes.HasSuffix(pdf, []byte("%%EOF\n"))).ToBe(true)
+	Expect(t, bytes.Contains(pdf, []byte("/Subtype /Image /Width 600 /Height 300 /ColorSpace /DeviceGray"))).ToBe(true)
+	Expect(t, bytes.Count(pdf, []byte("/Subtype /Image"))).ToBe(1)
+	Expect(t, bytes.Contains(pdf, []byte("/MediaBox [ 0 0 600.00 300.00 ]"))).ToBe(true)
+
+	Because(t, "the cross-reference table points at the objects", func(t *testing.T) {
+		start := bytes.LastIndex(pdf, []byte("startxref\n"))
+		xref := 0
+		fmt.Sscanf(string(pdf[start+len("startxref\n"):]), "%d", &xref)
+		table := strings.Split(string(pdf[xref:]), "\n")
+		Expect(t, table[0]).ToBe("xref")
+		Expect(t, table[1]).ToBe("0 13")
+		for i := 1; i < 13; i++ {
+			offset := 0
+			fmt.Sscanf(table[2+i], "%d", &offset)
+			Expect(t, strings.HasPrefix(string(pdf[offset:]), fmt.Sprintf("%d 0 obj\n", i))).ToBe(true)
+		}
+	})
+
+	Because(t, "the words are drawn invisibly over their boxes", func(t *testing.T) {
+		start := bytes.Index(pdf, []byte("8 0 obj\n"))
+		stream := pdf[bytes.Index(pdf[start:], []byte("stream\n"))+start+len("stream\n"):]
+		zr, err := zlib.NewReader(bytes.NewReader(stream))
+		Expect(t, err).ToBe(nil)
+		content, _ := io.ReadAll(zr)
+		Expect(t, strings.Contains(string(content), "q 600.00 0 0 300.00 0 0 cm /Im1 Do Q")).ToBe(true)
+		Expect(t, strings.Contains(string(content), "3 Tr")).ToBe(true)
+		Expect(t, strings.Contains(string(content),
+			"/F1 50.00 Tf 160.00 Tz 1.0000 0.0000 0.0000 1.0000 100.00 150.00 Tm <00480065006C006C006F> Tj")).ToBe(true)
+	})
+}
+
+func TestPages_Furniture(t *testing.T) {
+	line := func(y int, text string) Line {
+		words := []Word{}
+		for _, w := range strings.Fields(text) {
+			words = append(words, Word{Text: w, Box: image.Rect(0, y, 50, y+20)})
+		}
+		return Line{Box: image.Rect(0, y, 500, y+20), Words: words}
+	}
+	page := func(lines ...Line) *Document {
+		return &Document{Width: 600, Height: 1000, Blocks: []Block{{Paragraphs: []Paragraph{{Lines: lines}}}}}
+	}
+	pages := Pages{
+		page(line(10, "The Book | 1"), line(500, "Once upon a time")),
+		page(line(10, "The B0ok | 2"), line(500, "there was"), line(950, "- 2 -")),
+		page(line(10, "Epilogue"), line(500, "a page."), line(950, "Page 3")),
+	}
+	found := pages.Furniture(FurnitureOptions{})
+	Expect(t, found).Deeply().ToBe([]Furniture{
+		{Kind: FurnitureHeader, Text: "The Book | 1", Page: 0},
+		{Kind: FurnitureHeader, Text: "The B0ok | 2", Page: 1},
+		{Kind: FurniturePageNumber, Text: "- 2 -", Page: 1, Line: 2},
+		{Kind: FurniturePageNumber, Text: "Page 3", Page: 2, Line: 2},
+	})
+	Expect(t, pages.StripFurniture(FurnitureOptions{}).Text()).ToBe("Once upon a time\n\fthere was\n\fEpilogue\na page.")
+	Expect(t, pages[0].Text()).ToBe("The Book | 1\nOnce upon a time")
+
+	When(t, "the height of pages is unknown", func(t *testing.T) {
+		for _, page := range pages {
+			page.Height = 0
+		}
+		found := pages.Furniture(FurnitureOptions{})
+		Expect(t, len(found)).ToBe(4)
+		Expect(t, found[0].Kind).ToBe(FurnitureHeader)
+	})
+}
+
+func TestDocument_Captions(t *testing.T) {
+	para := func(y int, text string) Paragraph {
+		words := []Word{}
+		for _, w := range strings.Fields(text) {
+			words = append(words, Word{Text: w, Box: image.Rect(0, y, 40, y+12)})
+		}
+		return Paragraph{Box: image.Rect(0, y, 200, y+12), Lines: []Line{{Words: words}}}
+	}
+	doc := &Document{Blocks: []Block{
+		{Paragraphs: []Paragraph{para(0, "Some text above.")}},
+		{Box: image.Rect(0, 20, 200, 120), Type: BlockFlowingImage},
+		{Paragraphs: []Paragraph{para(125, "Figure 1. A cat."), para(140, "More text.")}},
+		{Box: image.Rect(300, 20, 500, 120), Type: BlockFlowingImage},
+	}}
+	Expect(t, doc.Captions()).Deeply().ToBe([]Caption{{Image: 1, Block: 2, Paragraph: 0, Text: "Figure 1. A cat."}})
+}
+
+func TestDocument_Footnotes(t *testing.T) {
+	word := func(text string, x, y, h int) Word {
+		return Word{Text: text, Box: image.Rect(x, y, x+10, y+h)}
+	}
+	doc := &Document{Width: 300, Height: 400, Blocks: []Block{
+		{Paragraphs: []Paragraph{{Lines: []Line{
+			{Box: image.Rect(0, 0, 300, 20), Words: []Word{word("In", 0, 0, 20), word("1990", 20, 0, 20), word("it", 40, 0, 20), word("rained¹", 60, 0, 20)}},
+			{Box: image.Rect(0, 30, 300, 50), Words: []Word{word("and", 0, 30, 20), word("snowed", 20, 30, 20), word("2", 40, 30, 8), word("a", 60, 30, 20), word("lot*.", 80, 30, 20)}},
+		}}}},
+		{Paragraphs: []Paragraph{{Lines: []Line{
+			{Words: []Word{word("1", 0, 350, 12), word("Heavily.", 20, 350, 12)}},
+			{Words: []Word{word("2", 0, 365, 12), word("Lightly,", 20, 365, 12)}},
+			{Words: []Word{word("at", 0, 380, 12), word("night.", 20, 380, 12)}},
+			{Words: []Word{word("*", 0, 395, 12), word("Citation", 20, 395, 12), word("needed.", 40, 395, 12)}},
+		}}}},
+	}}
+	Expect(t, doc.Footnotes()).Deeply().ToBe([]Footnote{
+		{Marker: "1", Ref: &WordRef{Word: 3}, Block: 1, Text: "Heavily."},
+		{Marker: "2", Ref: &WordRef{Line: 1, Word: 2}, Block: 1, Line: 1, Text: "Lightly, at night."},
+		{Marker: "*", Ref: &WordRef{Line: 1, Word: 4}, Block: 1, Line: 3, Text: "Citation needed."},
+	})
+}
+
+func TestPages_Outline(t *testing.T) {
+	para := func(y, h int, bold bool, text string) Paragraph {
+		words := []Word{}
+		for _, w := range strings.Fields(text) {
+			words = append(words, Word{Text: w, Box: image.Rect(0, y, 40, y+h), Bold: bold})
+		}
+		return Paragraph{Box: image.Rect(0, y, 200, y+h), Lines: []Line{{Words: words}}}
+	}
+	body := "Lorem ipsum dolor sit amet consectetur"
+	pages := Pages{
+		{Blocks: []Block{{Paragraphs: []Paragraph{para(0, 30, false, "Chapter One"), para(40, 12, false, body), para(60, 12, true, "Details"), para(80, 12, false, body)}}}},
+		{Blocks: []Block{{Paragraphs: []Paragraph{para(0, 20, false, "Section"), para(30, 12, false, body)}}}},
+		{Blocks: []Block{{Paragraphs: []Paragraph{para(0, 30, false, "Chapter Two")}}}},
+	}
+	Expect(t, pages.Outline()).Deeply().ToBe([]Heading{
+		{Level: 1, Text: "Chapter One", Box: image.Rect(0, 0, 200, 30), Children: []Heading{
+			{Level: 3, Text: "Details", Paragraph: 2, Box: image.Rect(0, 60, 200, 72)},
+			{Level: 2, Text: "Section", Page: 1, Box: image.Rect(0, 0, 200, 20)},
+		}},
+		{Level: 1, Text: "Chapter Two", Page: 2, Box: image.Rect(0, 0, 200, 30)},
+	})
+	Expect(t, len(pages[0].Outline())).ToBe(1)
+	Expect(t, len(pages[0].Outline()[0].Children)).ToBe(1)
+}
+
+func TestParseHOCR_Bold(t *testing.T) {
+	doc := &Document{Blocks: []Block{{Paragraphs: []Paragraph{{Lines: []Line{{Words: []Word{{Text: "Bold", Bold: true}, {Text: "plain"}}}}}}}}}
+	buf := bytes.NewBuffer(nil)
+	Expect(t, doc.WriteHOCR(buf)).ToBe(nil)
+	parsed, err := ParseHOCR(buf)
+	Expect(t, err).ToBe(nil)
+	words := parsed.Words()
+	Expect(t, words[0].Bold).ToBe(true)
+	Expect(t, words[0].Text).ToBe("Bold")
+	Expect(t, words[1].Bold).ToBe(false)
+
+	When(t, "words are superscripts or subscripts", func(t *testing.T) {
+		doc := &Document{Blocks: []Block{{Paragraphs: []Paragraph{{Lines: []Line{{Words: []Word{
+			{Text: "x"}, {Text: "2", Superscript: true, Bold: true}, {Text: "i", Subscript: true},
+		}}}}}}}}
+		buf := bytes.NewBuffer(nil)
+		Expect(t, doc.WriteHOCR(buf)).ToBe(nil)
+		Expect(t, strings.Contains(buf.String(), "<strong><sup>2</sup></strong>")).ToBe(true)
+		parsed, err := ParseHOCR(buf)
+		Expect(t, err).ToBe(nil)
+		words := parsed.Words()
+		Expect(t, words[1].Superscript).ToBe(true)
+		Expect(t, words[1].Bold).ToBe(true)
+		Expect(t, words[2].Subscript).ToBe(true)
+		Expect(t, words[2].Superscript).ToBe(false)
+	})
+}
+
+func TestDocument_MarkRules(t *testing.T) {
+	page := image.NewGray(image.Rect(0, 0, 200, 40))
+	draw.Draw(page, page.Bounds(), image.White, image.Point{}, draw.Src)
+	ink := func(r image.Rectangle) { draw.Draw(page, r, image.Black, image.Point{}, draw.Src) }
+	// Strokes of letters, i.e. vertical bars, in each word.
+	for x := 0; x < 200; x += 8 {
+		ink(image.Rect(x, 10, x+2, 30))
+	}
+	ink(image.Rect(0, 31, 50, 33))    // under "underlined", below its box
+	ink(image.Rect(60, 19, 110, 21))  // across "struck"
+	ink(image.Rect(170, 19, 180, 21)) // across the dash, as the dash itself
+	doc := &Document{Blocks: []Block{{Paragraphs: []Paragraph{{Lines: []Line{{Words: []Word{
+		{Text: "underlined", Box: image.Rect(0, 10, 50, 30)},
+		{Text: "struck", Box: image.Rect(60, 10, 110, 30)},
+		{Text: "plain", Box: image.Rect(120, 10, 160, 30)},
+		{Text: "—", Box: image.Rect(170, 10, 180, 30)},
+	}}}}}}}}
+	Expect(t, doc.MarkRules(page)).ToBe(2)
+	words := doc.Words()
+	Expect(t, words[0].Underlined).ToBe(true)
+	Expect(t, words[0].StruckThrough).ToBe(false)
+	Expect(t, words[1].StruckThrough).ToBe(true)
+	Expect(t, words[1].Underlined).ToBe(false)
+	Expect(t, words[2].Underlined || words[2].StruckThrough).ToBe(false)
+	Expect(t, words[3].StruckThrough).ToBe(false)
+}
+
+func TestDocument_MarkHandwriting(t *testing.T) {
+	page := image.NewGray(image.Rect(0, 0, 400, 100))
+	draw.Draw(page, page.Bounds(), image.White, image.Point{}, draw.Src)
+	ink := func(r image.Rectangle) { draw.Draw(page, r, image.Black, image.Point{}, draw.Src) }
+	// Bars of printed text not recognized, such as a barcode.
+	for x := 10; x < 100; x += 8 {
+		ink(image.Rect(x, 20, x+3, 60))
+	}
+	// A signature, i.e. a slanted scrawl.
+	scrawl := func(x0 int) {
+		for x := x0; x < x0+120; x++ {
+			y := 50 + int(25*math.Sin(float64(x)/7)) + (x-x0)/6
+			ink(image.Rect(x, y, x+2, y+2))
+		}
+	}
+	scrawl(150)
+	// Another one under a word recognized well, which is masked.
+	scrawl(300)
+	doc := &Document{Blocks: []Block{{Paragraphs: []Paragraph{{Lines: []Line{{Words: []Word{
+		{Text: "Jane", Box: image.Rect(290, 0, 400, 100), Confidence: 95},
+	}}}}}}}}
+	Expect(t, doc.MarkHandwriting(page)).ToBe(1)
+	Expect(t, doc.Handwriting[0].Box.Min.X).ToBe(150)
+	Expect(t, doc.Handwriting[0].Irregularity >= handwritingMinIrregularity).ToBe(true)
+
+	When(t, "the word is recognized poorly", func(t *testing.T) {
+		doc.Blocks[0].Paragraphs[0].Lines[0].Words[0].Confidence = 20
+		Expect(t, doc.MarkHandwriting(page)).ToBe(2)
+	})
+}
+
+func TestDocument_MarkGraphics(t *testing.T) {
+	page := image.NewGray(image.Rect(0, 0, 400, 120))
+	draw.Draw(page, page.Bounds(), image.White, image.Point{}, draw.Src)
+	// A round stamp with a mark in the middle.
+	for a := 0.0; a < 2*math.Pi; a += 0.01 {
+		x, y := 60+int(45*math.Cos(a)), 60+int(45*math.Sin(a))
+		draw.Draw(page, image.Rect(x-2, y-2, x+2, y+2), image.Black, image.Point{}, draw.Src)
+	}
+	draw.Draw(page, image.Rect(50, 55, 70, 65), image.Black, image.Point{}, draw.Src)
+	// A solid logo.
+	draw.Draw(page, image.Rect(200, 30, 260, 90), image.Black, image.Point{}, draw.Src)
+	// A rule, which is neither.
+	draw.Draw(page, image.Rect(300, 60, 390, 63), image.Black, image.Point{}, draw.Src)
+
+	doc := &Document{}
+	Expect(t, doc.MarkGraphics(page)).ToBe(2)
+	Expect(t, doc.Graphics[0].Kind).ToBe(GraphicStamp)
+	Expect(t, doc.Graphics[0].Box).ToBe(image.Rect(14, 14, 107, 106))
+	Expect(t, doc.Graphics[1].Kind).ToBe(GraphicLogo)
+	Expect(t, doc.Graphics[1].Box).ToBe(image.Rect(200, 30, 260, 90))
+	Expect(t, doc.MarkHandwriting(page)).ToBe(0)
+}
+
+func TestLine_Phrases(t *testing.T) {
+	line := Line{Box: image.Rect(0, 0, 300, 20), Words: []Word{
+		{Text: "Total", Box: image.Rect(200, 0, 250, 20), Confidence: 90},
+		{Text: "Unit", Box: image.Rect(0, 0, 40, 20), Confidence: 80, Bold: true},
+		{Text: "price", Box: image.Rect(48, 0, 98, 20), Confidence: 90, Bold: true},
+		{Text: "due", Box: image.Rect(260, 0, 290, 20), Confidence: 60},
+	}}
+	phrases := line.Phrases(1)
+	Expect(t, len(phrases)).ToBe(2)
+	Expect(t, phrases[0].Text).ToBe("Unit price")
+	Expect(t, phrases[0].Box).ToBe(image.Rect(0, 0, 98, 20))
+	Expect(t, phrases[0].Quad).ToBe(geometry.QuadOf(image.Rect(0, 0, 98, 20)))
+	Expect(t, math.Abs(phrases[0].Confidence-(80*4+90*5)/9.0) < 1e-9).ToBe(true)
+	Expect(t, phrases[0].Bold).ToBe(true)
+	Expect(t, phrases[1].Text).ToBe("Total due")
+	Expect(t, phrases[1].Bold).ToBe(false)
+	Expect(t, len(line.Phrases(0))).ToBe(4)
+	// The words of the line are left as they are.
+	Expect(t, line.Words[0].Text).ToBe("Total")
+}
+
+func TestWord_Split(t *testing.T) {
+	word := Word{Text: "ab  cd", Box: image.Rect(10, 5, 70, 25), Confidence: 80}
+	words := word.Split(1)
+	Expect(t, len(words)).ToBe(2)
+	Expect(t, words[0].Text).ToBe("ab")
+	Expect(t, words[0].Box).ToBe(image.Rect(10, 5, 30, 25))
+	Expect(t, words[1].Text).ToBe("cd")
+	Expect(t, words[1].Box).ToBe(image.Rect(50, 5, 70, 25))
+	Expect(t, words[1].Confidence).ToBe(80.0)
+
+	words = Word{Text: "ab cd", Box: image.Rect(0, 0, 45, 10)}.Split(0)
+	Expect(t, words[0].Box).ToBe(image.Rect(0, 0, 20, 10))
+	Expect(t, words[1].Box).ToBe(image.Rect(25, 0, 45, 10))
+
+	Expect(t, len(Word{Text: "single"}.Split(0))).ToBe(1)
+	phrase := Line{Words: []Word{{Text: "a", Box: image.Rect(0, 0, 10, 10)}, {Text: "b", Box: image.Rect(15, 0, 25, 10)}}}.Phrases(1)
+	Expect(t, len(phrase[0].Split(0))).ToBe(2)
+}
+
+func TestLine_SnapToBaseline(t *testing.T) {
+	line := Line{Box: image.Rect(0, 0, 200, 30), Words: []Word{
+		{Text: "The", Box: image.Rect(0, 2, 30, 21)},
+		{Text: "quick", Box: image.Rect(35, 3, 80, 27)},
+		{Text: "brown", Box: image.Rect(85, 2, 130, 20)},
+		{Text: "fox", Box: image.Rect(135, 4, 160, 22)},
+		{Text: "2", Box: image.Rect(161, 0, 166, 8), Superscript: true},
+	}}
+	Expect(t, line.Baseline()).ToBe(21)
+	snapped := line.SnapToBaseline()
+	Expect(t, snapped.Words[0].Box.Max.Y).ToBe(21)
+	Expect(t, snapped.Words[1].Box.Max.Y).ToBe(27)
+	Expect(t, snapped.Words[2].Box.Max.Y).ToBe(21)
+	Expect(t, snapped.Words[2].Quad).ToBe(geometry.QuadOf(image.Rect(85, 2, 130, 21)))
+	Expect(t, snapped.Words[3].Box.Max.Y).ToBe(21)
+	Expect(t, snapped.Words[4].Box.Max.Y).ToBe(8)
+	Expect(t, line.Words[2].Box.Max.Y).ToBe(20)
+
+	Expect(t, Line{Box: image.Rect(0, 0, 10, 12)}.Baseline()).ToBe(12)
+	Expect(t, Line{Words: []Word{{Text: "jpg", Box: image.Rect(0, 0, 10, 14)}}}.Baseline()).ToBe(14)
+
+	doc := &Document{Blocks: []Block{{Paragraphs: []Paragraph{{Lines: []Line{line}}}}}}
+	doc.SnapToBaselines()
+	Expect(t, doc.Blocks[0].Paragraphs[0].Lines[0].Words[2].Box.Max.Y).ToBe(21)
+}
+
+func TestWord_Region(t *testing.T) {
+	word := Word{Text: "total", Box: image.Rect(0, 0, 50, 12), Confidence: 88}
+	region := word.Region()
+	Expect(t, region.Label).ToBe("total")
+	Expect(t, region.Polygon).ToBe(geometry.QuadOf(word.Box).Polygon())
+	Expect(t, region.Confidence).ToBe(88.0)
+
+	block := Block{Box: image.Rect(0, 0, 10, 10), Type: BlockFlowingImage, Polygon: geometry.Polygon{{0, 0}, {10, 0}, {5, 10}}}
+	Expect(t, block.Region().Label).ToBe("image")
+	Expect(t, block.Region().Polygon).ToBe(block.Polygon)
+}
@@ -0,0 +1,185 @@
+package document
+
+import (
+	"archive/zip"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+)
+
+// DOCXOptions specifies how Pages.WriteDOCX lays out documents.
+type DOCXOptions struct {
+	// Positioned places each paragraph in a text frame at its position on the page, sized as the page image,
+	// keeping the layout of the original rather than reflowing the text.
+	Positioned bool
+
+	// DPI is the resolution of the page images, to convert pixels into the size of pages and fonts
+	// of positioned frames. Zero means 300.
+	DPI int
+}
+
+// WriteDOCX renders the pages as a DOCX document, editable in word processors.
+// By default, paragraphs are reflowed into headings, lists and body text as WriteMarkdown does,
+// with page breaks between pages. With DOCXOptions.Positioned, each page is a section of the size of its image,
+// and each paragraph a text frame keeping its lines, position and font size. Pictures are left out of positioned pages.
+func (pages Pages) WriteDOCX(w io.Writer, opts DOCXOptions) error {
+	if opts.DPI <= 0 {
+		opts.DPI = 300
+	}
+	zw := zip.NewWriter(w)
+	for _, part := range []struct{ name, content string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRels},
+		{"word/_rels/document.xml.rels", docxDocumentRels},
+		{"word/styles.xml", docxStyles},
+	} {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		io.WriteString(f, part.content)
+	}
+	f, err := zw.Create("word/document.xml")
+	if err != nil {
+		return err
+	}
+	io.WriteString(f, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>
+`)
+	body := pages.bodyStyle()
+	for i, page := range pages {
+		last := i == len(pages)-1
+		if opts.Positioned {
+			writeDOCXFrames(f, page, opts.DPI)
+			// Each page is a section of its own size, the last of which is the body's own.
+			if !last {
+				fmt.Fprintf(f, "<w:p><w:pPr>%s</w:pPr></w:p>\n", docxSection(page, opts.DPI))
+			} else {
+				fmt.Fprintln(f, docxSection(page, opts.DPI))
+			}
+			continue
+		}
+		writeDOCXFlow(f, page.elements(body))
+		if !last {
+			fmt.Fprintln(f, `<w:p><w:r><w:br w:type="page"/></w:r></w:p>`)
+		}
+	}
+	io.WriteString(f, "</w:body>\n</w:document>\n")
+	return zw.Close()
+}
+
+func writeDOCXFlow(w io.Writer, elements []element) {
+	for _, e := range elements {
+		switch e.kind {
+		case elementHeading:
+			fmt.Fprintf(w, "<w:p><w:pPr><w:pStyle w:val=\"Heading%d\"/></w:pPr>%s</w:p>\n", e.level, docxRun(e.text, ""))
+		case elementItem:
+			marker := "•"
+			if e.number != 0 {
+				marker = fmt.Sprintf("%d.", e.number)
+			}
+			fmt.Fprintf(w, "<w:p><w:pPr><w:pStyle w:val=\"ListParagraph\"/><w:ind w:left=\"720\" w:hanging=\"360\"/></w:pPr>%s</w:p>\n",
+				docxRun(marker+"\t"+e.text, ""))
+		case elementImage:
+			fmt.Fprintf(w, "<w:p>%s</w:p>\n", docxRun(fmt.Sprintf("[image, %d by %d pixels]", e.box.Dx(), e.box.Dy()), "<w:i/>"))
+		default:
+			fmt.Fprintf(w, "<w:p>%s</w:p>\n", docxRun(e.text, ""))
+		}
+	}
+}
+
+// writeDOCXFrames writes the paragraphs of the page as frames anchored to the page, with their lines broken as recognized.
+func writeDOCXFrames(w io.Writer, page *Document, dpi int) {
+	for _, block := range page.Blocks {
+		for _, para := range block.Paragraphs {
+			words := []Word{}
+			lines := []string{}
+			for _, line := range para.Lines {
+				if text := line.Text(); text != "" {
+					lines = append(lines, text)
+					words = append(words, line.Words...)
+				}
+			}
+			if len(lines) == 0 {
+				continue
+			}
+			box := para.Box
+			if box.Empty() {
+				for _, word := range words {
+					box = box.Union(word.Box)
+				}
+			}
+			fmt.Fprintf(w, "<w:p><w:pPr><w:framePr w:w=\"%d\" w:h=\"%d\" w:hRule=\"atLeast\" w:x=\"%d\" w:y=\"%d\" w:hAnchor=\"page\" w:vAnchor=\"page\" w:wrap=\"around\"/>"+
+				"<w:spacing w:before=\"0\" w:after=\"0\"/></w:pPr>",
+				twips(box.Dx(), dpi), twips(box.Dy(), dpi), twips(box.Min.X, dpi), twips(box.Min.Y, dpi))
+			// Font sizes are in half points.
+			size := ""
+			if px := fontSize(words); px > 0 {
+				size = fmt.Sprintf("<w:sz w:val=\"%d\"/>", int(math.Round(px*144/float64(dpi))))
+			}
+			for i, line := range lines {
+				if i != 0 {
+					io.WriteString(w, "<w:r><w:br/></w:r>")
+				}
+				io.WriteString(w, docxRun(line, size))
+			}
+			io.WriteString(w, "</w:p>\n")
+		}
+	}
+}
+
+// docxSection returns the properties of the section of the page, sized as its image without margins.
+func docxSection(page *Document, dpi int) string {
+	size := ""
+	if page.Width > 0 && page.Height > 0 {
+		size = fmt.Sprintf("<w:pgSz w:w=\"%d\" w:h=\"%d\"/>", twips(page.Width, dpi), twips(page.Height, dpi))
+	}
+	return "<w:sectPr>" + size + `<w:pgMar w:top="0" w:right="0" w:bottom="0" w:left="0" w:header="0" w:footer="0" w:gutter="0"/></w:sectPr>`
+}
+
+// twips converts pixels into twentieths of points.
+func twips(px, dpi int) int {
+	return px * 1440 / dpi
+}
+
+func docxRun(text, props string) string {
+	if props != "" {
+		props = "<w:rPr>" + props + "</w:rPr>"
+	}
+	parts := strings.Split(html.EscapeString(text), "\t")
+	return "<w:r>" + props + "<w:t xml:space=\"preserve\">" + strings.Join(parts, "</w:t><w:tab/><w:t xml:space=\"preserve\">") + "</w:t></w:r>"
+}
+
+const docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+ <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+ <Default Extension="xml" ContentType="application/xml"/>
+ <Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+ <Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+</Types>
+`
+
+const docxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+ <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>
+`
+
+const docxDocumentRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+ <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>
+`
+
+const docxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+ <w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:pPr><w:spacing w:after="160"/></w:pPr></w:style>
+ <w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:keepNext/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:sz w:val="36"/></w:rPr></w:style>
+ <w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:keepNext/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:sz w:val="30"/></w:rPr></w:style>
+ <w:style w:type="paragraph" w:styleId="Heading3"><w:name w:val="heading 3"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:pPr><w:keepNext/><w:outlineLvl w:val="2"/></w:pPr><w:rPr><w:b/><w:sz w:val="26"/></w:rPr></w:style>
+ <w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:after="0"/></w:pPr></w:style>
+</w:styles>
+`
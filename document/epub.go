@@ -0,0 +1,164 @@
+package document
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"fmt"
+	"html"
+	"io"
+	"time"
+)
+
+// EPUBMetadata is the metadata of books written by Pages.WriteEPUB.
+type EPUBMetadata struct {
+	Title  string
+	Author string
+
+	// Language of the book as a BCP 47 tag, "en" if empty.
+	Language string
+
+	// Identifier of the book, such as "urn:isbn:9780000000000", derived from the title and the text if empty.
+	Identifier string
+
+	// Modified is the time of the last modification, now if zero.
+	Modified time.Time
+}
+
+// WriteEPUB renders the pages as an EPUB 3 book of a chapter by page, structured as WriteMarkdown does,
+// whose table of contents lists the headings, or the pages if there's no heading.
+func (pages Pages) WriteEPUB(w io.Writer, meta EPUBMetadata) error {
+	if meta.Language == "" {
+		meta.Language = "en"
+	}
+	if meta.Identifier == "" {
+		sum := sha1.Sum([]byte(meta.Title + "\x00" + pages.Text()))
+		meta.Identifier = fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+	}
+	if meta.Modified.IsZero() {
+		meta.Modified = time.Now()
+	}
+
+	zw := zip.NewWriter(w)
+	// The mimetype comes first, uncompressed, to identify the file as EPUB.
+	f, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	io.WriteString(f, "application/epub+zip")
+	if f, err = zw.Create("META-INF/container.xml"); err != nil {
+		return err
+	}
+	io.WriteString(f, `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+ <rootfiles>
+  <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+ </rootfiles>
+</container>
+`)
+
+	body := pages.bodyStyle()
+	toc := []string{}
+	for i, page := range pages {
+		if f, err = zw.Create(fmt.Sprintf("OEBPS/page-%d.xhtml", i+1)); err != nil {
+			return err
+		}
+		toc = append(toc, writeEPUBPage(f, meta, i+1, page.elements(body))...)
+	}
+	if len(toc) == 0 {
+		for i := range pages {
+			toc = append(toc, fmt.Sprintf("<a href=\"page-%d.xhtml\">Page %d</a>", i+1, i+1))
+		}
+	}
+
+	if f, err = zw.Create("OEBPS/nav.xhtml"); err != nil {
+		return err
+	}
+	fmt.Fprint(f, epubHead(meta, meta.Title))
+	fmt.Fprint(f, "  <nav epub:type=\"toc\" id=\"toc\">\n   <ol>\n")
+	for _, entry := range toc {
+		fmt.Fprintf(f, "    <li>%s</li>\n", entry)
+	}
+	fmt.Fprint(f, "   </ol>\n  </nav>\n </body>\n</html>\n")
+
+	if f, err = zw.Create("OEBPS/content.opf"); err != nil {
+		return err
+	}
+	fmt.Fprintf(f, `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="id">
+ <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <dc:identifier id="id">%s</dc:identifier>
+  <dc:title>%s</dc:title>
+  <dc:language>%s</dc:language>
+`, html.EscapeString(meta.Identifier), html.EscapeString(meta.Title), html.EscapeString(meta.Language))
+	if meta.Author != "" {
+		fmt.Fprintf(f, "  <dc:creator>%s</dc:creator>\n", html.EscapeString(meta.Author))
+	}
+	fmt.Fprintf(f, "  <meta property=\"dcterms:modified\">%s</meta>\n </metadata>\n <manifest>\n", meta.Modified.UTC().Format("2006-01-02T15:04:05Z"))
+	fmt.Fprint(f, "  <item id=\"nav\" href=\"nav.xhtml\" media-type=\"application/xhtml+xml\" properties=\"nav\"/>\n")
+	for i := range pages {
+		fmt.Fprintf(f, "  <item id=\"page-%d\" href=\"page-%d.xhtml\" media-type=\"application/xhtml+xml\"/>\n", i+1, i+1)
+	}
+	fmt.Fprint(f, " </manifest>\n <spine>\n")
+	for i := range pages {
+		fmt.Fprintf(f, "  <itemref idref=\"page-%d\"/>\n", i+1)
+	}
+	fmt.Fprint(f, " </spine>\n</package>\n")
+	return zw.Close()
+}
+
+func epubHead(meta EPUBMetadata, title string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="%s" lang="%s">
+ <head>
+  <title>%s</title>
+ </head>
+ <body>
+`, html.EscapeString(meta.Language), html.EscapeString(meta.Language), html.EscapeString(title))
+}
+
+// writeEPUBPage writes the XHTML of the page, returning the entries of the table of contents for its headings.
+func writeEPUBPage(w io.Writer, meta EPUBMetadata, number int, elements []element) []string {
+	fmt.Fprint(w, epubHead(meta, fmt.Sprintf("Page %d", number)))
+	toc := []string{}
+	list := ""
+	closeList := func() {
+		if list != "" {
+			fmt.Fprintf(w, "  </%s>\n", list)
+			list = ""
+		}
+	}
+	for _, e := range elements {
+		if e.kind == elementItem {
+			tag := "ul"
+			if e.number != 0 {
+				tag = "ol"
+			}
+			if tag != list {
+				closeList()
+				fmt.Fprintf(w, "  <%s>\n", tag)
+				list = tag
+			}
+			if e.number != 0 {
+				fmt.Fprintf(w, "   <li value=\"%d\">%s</li>\n", e.number, html.EscapeString(e.text))
+			} else {
+				fmt.Fprintf(w, "   <li>%s</li>\n", html.EscapeString(e.text))
+			}
+			continue
+		}
+		closeList()
+		switch e.kind {
+		case elementHeading:
+			id := fmt.Sprintf("heading-%d-%d", number, len(toc)+1)
+			fmt.Fprintf(w, "  <h%d id=\"%s\">%s</h%d>\n", e.level, id, html.EscapeString(e.text), e.level)
+			toc = append(toc, fmt.Sprintf("<a href=\"page-%d.xhtml#%s\">%s</a>", number, id, html.EscapeString(e.text)))
+		case elementImage:
+			fmt.Fprintf(w, "  <p class=\"image\">[image, %d by %d pixels]</p>\n", e.box.Dx(), e.box.Dy())
+		default:
+			fmt.Fprintf(w, "  <p>%s</p>\n", html.EscapeString(e.text))
+		}
+	}
+	closeList()
+	fmt.Fprint(w, " </body>\n</html>\n")
+	return toc
+}
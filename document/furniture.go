@@ -0,0 +1,238 @@
+package document
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FurnitureKind is the kind of lines in the margins of pages, which aren't the content of pages.
+type FurnitureKind int
+
+const (
+	// FurnitureHeader is a running header, repeated at the top of pages, such as the title of a book.
+	FurnitureHeader FurnitureKind = iota + 1
+	// FurnitureFooter is a running footer, repeated at the bottom of pages.
+	FurnitureFooter
+	// FurniturePageNumber is a line of a page number alone, such as "12", "- 12 -", "Page 12 of 30" or "xii".
+	FurniturePageNumber
+)
+
+// Furniture is a line of the margins of a page, i.e. a running header or footer, or a page number.
+type Furniture struct {
+	Kind FurnitureKind `json:"kind"`
+	Text string        `json:"text"`
+
+	// Page is the index of the page in Pages, and the others of the line in the page.
+	Page      int `json:"page"`
+	Block     int `json:"block"`
+	Paragraph int `json:"paragraph"`
+	Line      int `json:"line"`
+}
+
+// FurnitureOptions specifies how Pages.Furniture detects headers, footers and page numbers.
+type FurnitureOptions struct {
+	// Margin is the height of the margins at the top and bottom of pages where furniture is,
+	// as a fraction of the height. Zero means 0.1. For pages of unknown height,
+	// the top and bottom lines of each page are taken as in the margins.
+	Margin float64
+
+	// MinPages is the number of pages a line must be repeated on to be a header or footer. Zero means 2.
+	// Page numbers are detected on a page alone.
+	MinPages int
+}
+
+var pageNumber = regexp.MustCompile(`(?i)^(?:page\s*|p\.\s*|[-–—]\s*)?(?:\d{1,4}|[ivxlcdm]{1,7})(?:\s*(?:/|of)\s*\d{1,4})?(?:\s*[-–—])?$`)
+
+type furnitureLine struct {
+	Furniture
+	key string
+	top bool
+}
+
+// Furniture returns the running headers, footers and page numbers of the pages, in order of pages.
+// Lines in the margins repeated on several pages are taken as headers or footers. They're compared ignoring digits,
+// so that headers of page numbers or chapters, such as "Chapter 3 | 45", are detected as repeated,
+// and up to a fifth of their characters may differ, as OCR of the same line often does.
+func (pages Pages) Furniture(opts FurnitureOptions) []Furniture {
+	if opts.Margin <= 0 || opts.Margin >= 0.5 {
+		opts.Margin = 0.1
+	}
+	if opts.MinPages <= 0 {
+		opts.MinPages = 2
+	}
+	candidates := []furnitureLine{}
+	for i, page := range pages {
+		candidates = append(candidates, page.marginLines(i, opts.Margin)...)
+	}
+
+	// Cluster similar lines at the same side of pages, counting the pages each cluster appears on.
+	type cluster struct {
+		key   string
+		top   bool
+		pages map[int]bool
+	}
+	clusters := []*cluster{}
+	assigned := make([]*cluster, len(candidates))
+	for i, c := range candidates {
+		if c.key == "" {
+			continue
+		}
+		for _, cl := range clusters {
+			if cl.top == c.top && similarText(cl.key, c.key) {
+				assigned[i] = cl
+				break
+			}
+		}
+		if assigned[i] == nil {
+			assigned[i] = &cluster{key: c.key, top: c.top, pages: map[int]bool{}}
+			clusters = append(clusters, assigned[i])
+		}
+		assigned[i].pages[c.Page] = true
+	}
+
+	found := []Furniture{}
+	for i, c := range candidates {
+		switch {
+		case pageNumber.MatchString(c.Text):
+			c.Kind = FurniturePageNumber
+		case assigned[i] != nil && len(assigned[i].pages) >= opts.MinPages && c.top:
+			c.Kind = FurnitureHeader
+		case assigned[i] != nil && len(assigned[i].pages) >= opts.MinPages:
+			c.Kind = FurnitureFooter
+		default:
+			continue
+		}
+		found = append(found, c.Furniture)
+	}
+	return found
+}
+
+// marginLines returns the lines in the margins of the page, or its top and bottom lines if its height is unknown.
+func (doc *Document) marginLines(page int, margin float64) []furnitureLine {
+	lines := []furnitureLine{}
+	for b, block := range doc.Blocks {
+		for p, para := range block.Paragraphs {
+			for l, line := range para.Lines {
+				if text := line.Text(); strings.TrimSpace(text) != "" {
+					lines = append(lines, furnitureLine{
+						Furniture: Furniture{Text: text, Page: page, Block: b, Paragraph: p, Line: l},
+						key:       furnitureKey(text),
+					})
+				}
+			}
+		}
+	}
+	boxes := func(i int) Line {
+		f := lines[i].Furniture
+		return doc.Blocks[f.Block].Paragraphs[f.Paragraph].Lines[f.Line]
+	}
+	if doc.Height <= 0 {
+		if len(lines) == 0 {
+			return lines
+		}
+		sort.SliceStable(lines, func(i, j int) bool { return boxes(i).Box.Min.Y < boxes(j).Box.Min.Y })
+		top, bottom := lines[0], lines[len(lines)-1]
+		top.top = true
+		if len(lines) == 1 {
+			return []furnitureLine{top}
+		}
+		return []furnitureLine{top, bottom}
+	}
+	inMargins := []furnitureLine{}
+	for i, line := range lines {
+		box := boxes(i).Box
+		switch {
+		case float64(box.Max.Y) <= margin*float64(doc.Height):
+			line.top = true
+		case float64(box.Min.Y) >= (1-margin)*float64(doc.Height):
+		default:
+			continue
+		}
+		inMargins = append(inMargins, line)
+	}
+	return inMargins
+}
+
+// furnitureKey normalizes the text of lines for comparison, lowering letters and dropping digits, spaces and punctuation.
+func furnitureKey(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, text)
+}
+
+// similarText reports whether the texts differ by at most a fifth of the longer one.
+func similarText(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	limit := len(ra)
+	if len(rb) > limit {
+		limit = len(rb)
+	}
+	return levenshtein(ra, rb) <= limit/5
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j] + 1
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+			if prev[j-1]+cost < cur[j] {
+				cur[j] = prev[j-1] + cost
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// StripFurniture returns copies of the pages without the furniture detected by Pages.Furniture,
+// dropping paragraphs left empty, so that the text of the pages flows without interruptions.
+func (pages Pages) StripFurniture(opts FurnitureOptions) Pages {
+	type lineRef struct{ page, block, paragraph, line int }
+	strip := map[lineRef]bool{}
+	for _, f := range pages.Furniture(opts) {
+		strip[lineRef{f.Page, f.Block, f.Paragraph, f.Line}] = true
+	}
+	stripped := make(Pages, 0, len(pages))
+	for i, page := range pages {
+		doc := &Document{Width: page.Width, Height: page.Height, Blocks: make([]Block, 0, len(page.Blocks))}
+		for b, block := range page.Blocks {
+			paragraphs := []Paragraph{}
+			for p, para := range block.Paragraphs {
+				lines := []Line{}
+				for l, line := range para.Lines {
+					if !strip[lineRef{i, b, p, l}] {
+						lines = append(lines, line)
+					}
+				}
+				if len(lines) != 0 {
+					para.Lines = lines
+					paragraphs = append(paragraphs, para)
+				}
+			}
+			if len(paragraphs) == 0 && len(block.Paragraphs) != 0 {
+				continue
+			}
+			block.Paragraphs = paragraphs
+			doc.Blocks = append(doc.Blocks, block)
+		}
+		stripped = append(stripped, doc)
+	}
+	return stripped
+}
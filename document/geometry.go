@@ -0,0 +1,193 @@
+package document
+
+import (
+	"image"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chennqqi/gosseract/v2/geometry"
+)
+
+// SpaceRatio is the width of a space relative to the mean width of characters, which Word.Split assumes
+// for zero. It's about that of proportional fonts of body text.
+const SpaceRatio = 0.5
+
+// Phrases joins the adjacent words of the line into phrases, while the horizontal gap between them is gap times
+// the height of the line at most, such as 1 to join words of the same phrase but not across columns of tables.
+// Phrases are words of the texts joined by spaces, the union of the boxes and the mean confidence by characters,
+// marked Bold only if all of the words are. It's meaningful only for upright text.
+func (line Line) Phrases(gap float64) []Word {
+	words := append([]Word{}, line.Words...)
+	sort.SliceStable(words, func(i, j int) bool { return words[i].Box.Min.X < words[j].Box.Min.X })
+	height := line.Box.Dy()
+	phrases := []Word{}
+	weights := []int{}
+	for _, word := range words {
+		n := utf8.RuneCountInString(word.Text)
+		if i := len(phrases) - 1; i >= 0 && float64(word.Box.Min.X-phrases[i].Box.Max.X) <= gap*float64(lineHeight(height, phrases[i], word)) {
+			phrase := &phrases[i]
+			phrase.Text += " " + word.Text
+			if word.Original != "" || phrase.Original != "" {
+				phrase.Original = strings.TrimSpace(originalOf(*phrase) + " " + originalOf(word))
+			}
+			if weights[i]+n != 0 {
+				phrase.Confidence = (phrase.Confidence*float64(weights[i]) + word.Confidence*float64(n)) / float64(weights[i]+n)
+			}
+			weights[i] += n
+			phrase.Box = phrase.Box.Union(word.Box)
+			phrase.Quad = geometry.QuadOf(phrase.Box)
+			phrase.Bold = phrase.Bold && word.Bold
+			phrase.FromDictionary = phrase.FromDictionary && word.FromDictionary
+			phrase.Numeric = phrase.Numeric && word.Numeric
+			phrase.Underlined = phrase.Underlined && word.Underlined
+			phrase.StruckThrough = phrase.StruckThrough && word.StruckThrough
+			phrase.Corrected = phrase.Corrected || word.Corrected
+			phrase.Probability = 0
+			continue
+		}
+		phrases = append(phrases, word)
+		weights = append(weights, n)
+	}
+	return phrases
+}
+
+// lineHeight returns the height of the line of the words, or their taller one for lines without boxes.
+func lineHeight(height int, a, b Word) int {
+	if height > 0 {
+		return height
+	}
+	if a.Box.Dy() > b.Box.Dy() {
+		return a.Box.Dy()
+	}
+	return b.Box.Dy()
+}
+
+func originalOf(word Word) string {
+	if word.Original != "" {
+		return word.Original
+	}
+	return word.Text
+}
+
+// Split splits the word of multiple words, such as of Phrases or of texts tesseract recognizes with spaces,
+// into the words separated by whitespace, dividing the box horizontally in proportion to the characters,
+// each space of which is spaceRatio of the mean width of the others, or SpaceRatio if zero.
+// Words of a single word are returned as they are. It's meaningful only for upright text.
+func (word Word) Split(spaceRatio float64) []Word {
+	fields := strings.Fields(word.Text)
+	if len(fields) <= 1 {
+		return []Word{word}
+	}
+	if spaceRatio <= 0 {
+		spaceRatio = SpaceRatio
+	}
+	rest := strings.TrimSpace(word.Text)
+	chars, spaces := 0, 0
+	for _, r := range rest {
+		if unicode.IsSpace(r) {
+			spaces++
+		} else {
+			chars++
+		}
+	}
+	unit := float64(word.Box.Dx()) / (float64(chars) + spaceRatio*float64(spaces))
+
+	words := make([]Word, 0, len(fields))
+	x := float64(word.Box.Min.X)
+	for i, field := range fields {
+		// Runs of spaces between the fields count as many spaces.
+		gap := len(rest) - len(strings.TrimLeftFunc(rest, unicode.IsSpace))
+		x += spaceRatio * unit * float64(utf8.RuneCountInString(rest[:gap]))
+		rest = rest[gap+len(field):]
+		end := x + unit*float64(utf8.RuneCountInString(field))
+		box := image.Rect(int(x+0.5), word.Box.Min.Y, int(end+0.5), word.Box.Max.Y)
+		if i == len(fields)-1 {
+			box.Max.X = word.Box.Max.X
+		}
+		split := word
+		split.Text, split.Original, split.Box, split.Quad = field, "", box, geometry.QuadOf(box)
+		words = append(words, split)
+		x = end
+	}
+	return words
+}
+
+// descenders are characters reaching below the baseline in most fonts.
+const descenders = "gjpqyQ,;()[]{}|/$@µ"
+
+// Baseline returns the y coordinate of the baseline of the line, the median of the bottoms of the words
+// sitting on it, i.e. of neither descenders nor subscripts and superscripts. It falls back to the median
+// of the bottoms of all the words, and to the bottom of the line without words.
+func (line Line) Baseline() int {
+	bottoms, all := []int{}, []int{}
+	for _, word := range line.Words {
+		if word.Box.Empty() {
+			continue
+		}
+		all = append(all, word.Box.Max.Y)
+		if !word.Superscript && !word.Subscript && !strings.ContainsAny(word.Text, descenders) {
+			bottoms = append(bottoms, word.Box.Max.Y)
+		}
+	}
+	if len(bottoms) == 0 {
+		bottoms = all
+	}
+	if len(bottoms) == 0 {
+		return line.Box.Max.Y
+	}
+	sort.Ints(bottoms)
+	return bottoms[len(bottoms)/2]
+}
+
+// SnapToBaseline returns the line with the bottoms of the words sitting on the baseline moved to it,
+// see Baseline, removing the jitter of boxes of words so that underlines and highlights of the line are level.
+// Words of descenders, subscripts and superscripts are kept as they are. It's meaningful only for upright text.
+func (line Line) SnapToBaseline() Line {
+	baseline := line.Baseline()
+	snapped := Line{Box: line.Box, Words: make([]Word, len(line.Words))}
+	for i, word := range line.Words {
+		if !word.Box.Empty() && !word.Superscript && !word.Subscript && !strings.ContainsAny(word.Text, descenders) && word.Box.Min.Y < baseline {
+			word.Box.Max.Y = baseline
+			word.Quad = geometry.QuadOf(word.Box)
+		}
+		snapped.Words[i] = word
+	}
+	return snapped
+}
+
+// SnapToBaselines snaps the words of all the lines of the document to their baselines, see Line.SnapToBaseline.
+func (doc *Document) SnapToBaselines() {
+	for b := range doc.Blocks {
+		for p := range doc.Blocks[b].Paragraphs {
+			for l, line := range doc.Blocks[b].Paragraphs[p].Lines {
+				doc.Blocks[b].Paragraphs[p].Lines[l] = line.SnapToBaseline()
+			}
+		}
+	}
+}
+
+// Region returns the word as the region of its quad labeled by its text, with its confidence,
+// such as for external layout tools and redaction, see geometry.LabeledRegion.
+func (word Word) Region() geometry.LabeledRegion {
+	quad := word.Quad
+	if quad == (geometry.Quad{}) {
+		quad = geometry.QuadOf(word.Box)
+	}
+	return geometry.LabeledRegion{Label: word.Text, Polygon: quad.Polygon(), Confidence: word.Confidence}
+}
+
+// Region returns the block as the region of its outline, or of its box without one, labeled "image" for pictures
+// and "text" for the others.
+func (block Block) Region() geometry.LabeledRegion {
+	polygon := block.Polygon
+	if len(polygon) == 0 {
+		polygon = geometry.QuadOf(block.Box).Polygon()
+	}
+	label := "text"
+	if block.Type.IsImage() {
+		label = "image"
+	}
+	return geometry.LabeledRegion{Label: label, Polygon: polygon}
+}
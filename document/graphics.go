@@ -0,0 +1,64 @@
+package document
+
+import "image"
+
+// GraphicKind is the kind of graphics, which aren't text to be recognized.
+type GraphicKind int
+
+const (
+	// GraphicStamp is a round stamp or seal, whose ink runs along its round border.
+	GraphicStamp GraphicKind = iota + 1
+	// GraphicLogo is a blob of dense ink, such as a logo. Other solid graphics, such as barcodes, are of this kind too.
+	GraphicLogo
+)
+
+// GraphicRegion is an area of ink not recognized as text, which is a stamp or a logo,
+// to be cropped out for image matching rather than misread as text.
+type GraphicRegion struct {
+	Kind GraphicKind     `json:"kind"`
+	Box  image.Rectangle `json:"box"`
+
+	// Density is the fraction of ink in Box.
+	Density float64 `json:"density"`
+}
+
+const (
+	// graphicMinSize is the width and height of graphics at least, in pixels, unlike rules and specks.
+	graphicMinSize = 2 * regionCell
+	// stampMinSize is the width and height of stamps at least, and stampMaxAspect the ratio of their sides at most.
+	stampMinSize   = 3 * regionCell
+	stampMaxAspect = 1.4
+	// stampMinRing is the fraction of the ink of stamps in the ring of their border at least.
+	stampMinRing = 0.5
+	// logoMinDensity is the density of logos at least.
+	logoMinDensity = handwritingMaxDensity
+)
+
+// MarkGraphics finds the regions of ink in page, the image the document is recognized from,
+// which aren't covered by words recognized and are stamps or logos, sets them to Graphics,
+// and returns the number of them.
+func (doc *Document) MarkGraphics(page image.Image) int {
+	doc.Graphics = nil
+	for _, region := range doc.inkRegions(page) {
+		kind := GraphicKind(0)
+		switch {
+		case region.isStamp():
+			kind = GraphicStamp
+		case region.box.Dx() >= graphicMinSize && region.box.Dy() >= graphicMinSize && region.density() >= logoMinDensity:
+			kind = GraphicLogo
+		default:
+			continue
+		}
+		doc.Graphics = append(doc.Graphics, GraphicRegion{Kind: kind, Box: region.box, Density: region.density()})
+	}
+	return len(doc.Graphics)
+}
+
+// isStamp reports whether the region is roughly a circle or an ellipse, with ink along its border.
+func (r inkRegion) isStamp() bool {
+	w, h := float64(r.box.Dx()), float64(r.box.Dy())
+	if r.box.Dx() < stampMinSize || r.box.Dy() < stampMinSize || w > stampMaxAspect*h || h > stampMaxAspect*w {
+		return false
+	}
+	return float64(r.ring) >= stampMinRing*float64(r.ink)
+}
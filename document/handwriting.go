@@ -0,0 +1,41 @@
+package document
+
+import "image"
+
+// HandwritingRegion is an area of ink not recognized as text which looks handwritten, such as a signature,
+// to be routed to a handwriting model rather than trusting tesseract, which returns garbage for it.
+type HandwritingRegion struct {
+	Box image.Rectangle `json:"box"`
+
+	// Density is the fraction of ink in Box, and Irregularity the fraction of the edges of strokes running
+	// diagonally or curving, rather than horizontally or vertically as strokes of printed text mostly do.
+	Density      float64 `json:"density"`
+	Irregularity float64 `json:"irregularity"`
+}
+
+// Regions of handwriting are sparse strokes, unlike photos and noise, and irregular, unlike printed text.
+const (
+	handwritingMaxDensity      = 0.35
+	handwritingMinIrregularity = 0.4
+)
+
+// MarkHandwriting finds the regions of ink in page, the image the document is recognized from,
+// which aren't covered by words recognized and look handwritten by their strokes, sets them to Handwriting,
+// and returns the number of them. Round stamps, see MarkGraphics, aren't taken as handwriting.
+func (doc *Document) MarkHandwriting(page image.Image) int {
+	doc.Handwriting = nil
+	for _, region := range doc.inkRegions(page) {
+		if region.isHandwriting() {
+			doc.Handwriting = append(doc.Handwriting, HandwritingRegion{
+				Box:          region.box,
+				Density:      region.density(),
+				Irregularity: region.irregularity(),
+			})
+		}
+	}
+	return len(doc.Handwriting)
+}
+
+func (r inkRegion) isHandwriting() bool {
+	return r.density() <= handwritingMaxDensity && r.irregularity() >= handwritingMinIrregularity && !r.isStamp()
+}
@@ -0,0 +1,189 @@
+package document
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/chennqqi/gosseract/v2/geometry"
+)
+
+// ParseHOCR loads a Document from hOCR, such as generated by Client.HOCRText or WriteHOCR.
+// Blocks (ocr_carea, and ocr_photo for pictures), paragraphs (ocr_par), lines (ocr_line and its variants, e.g. ocr_caption)
+// and words (ocrx_word) are read with their bbox and x_wconf, and other elements are ignored.
+// Only the first page is read.
+func ParseHOCR(r io.Reader) (*Document, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	builder := &Builder{}
+	pages := 0
+	// Depth of the word being read, to collect its text from nested elements, such as <strong>.
+	depth, wordDepth := 0, 0
+	var word *Word
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse hOCR: %v", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			if word != nil {
+				// tesseract marks bold words by <strong>, and superscripts and subscripts by <sup> and <sub>.
+				switch t.Name.Local {
+				case "strong", "b":
+					word.Bold = true
+				case "sup":
+					word.Superscript = true
+				case "sub":
+					word.Subscript = true
+				}
+				continue
+			}
+			class, title := hocrAttrs(t)
+			box, conf := parseHOCRTitle(title)
+			switch class {
+			case "ocr_page":
+				pages++
+				if pages > 1 {
+					return builder.Document(), nil
+				}
+				builder.SetSize(box.Max.X, box.Max.Y)
+			case "ocr_carea", "ocrx_block":
+				builder.Block(Block{Box: box})
+			case "ocr_photo", "ocr_image":
+				builder.Block(Block{Box: box, Type: BlockFlowingImage})
+			case "ocr_par":
+				builder.Paragraph(Paragraph{Box: box})
+			case "ocr_line", "ocrx_line", "ocr_caption", "ocr_header", "ocr_textfloat":
+				builder.Line(Line{Box: box})
+			case "ocrx_word":
+				word = &Word{Box: box, Confidence: conf, Quad: geometry.QuadOf(box)}
+				wordDepth = depth
+				text.Reset()
+			}
+		case xml.CharData:
+			if word != nil {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if word != nil && depth == wordDepth {
+				word.Text = strings.TrimSpace(text.String())
+				builder.Word(*word)
+				word = nil
+			}
+			depth--
+		}
+	}
+	return builder.Document(), nil
+}
+
+// hocrAttrs returns the hOCR class, i.e. the one starting with "ocr", and the title of the element.
+func hocrAttrs(t xml.StartElement) (class, title string) {
+	for _, attr := range t.Attr {
+		switch attr.Name.Local {
+		case "class":
+			for _, c := range strings.Fields(attr.Value) {
+				if strings.HasPrefix(c, "ocr") {
+					class = c
+				}
+			}
+		case "title":
+			title = attr.Value
+		}
+	}
+	return class, title
+}
+
+// parseHOCRTitle parses the properties "bbox" and "x_wconf" of hOCR title,
+// e.g. "bbox 36 92 618 184; x_wconf 95".
+func parseHOCRTitle(title string) (box image.Rectangle, conf float64) {
+	for _, prop := range strings.Split(title, ";") {
+		fields := strings.Fields(prop)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "bbox":
+			if len(fields) != 5 {
+				continue
+			}
+			coords := [4]int{}
+			for i := range coords {
+				coords[i], _ = strconv.Atoi(fields[i+1])
+			}
+			box = image.Rect(coords[0], coords[1], coords[2], coords[3])
+		case "x_wconf":
+			if len(fields) == 2 {
+				conf, _ = strconv.ParseFloat(fields[1], 64)
+			}
+		}
+	}
+	return box, conf
+}
+
+// WriteHOCR renders the document as hOCR in the same structure as tesseract generates.
+func (doc *Document) WriteHOCR(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN"
+    "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+<html xmlns="http://www.w3.org/1999/xhtml" xml:lang="en" lang="en">
+ <head>
+  <title></title>
+  <meta http-equiv="Content-Type" content="text/html;charset=utf-8"/>
+  <meta name="ocr-system" content="gosseract"/>
+  <meta name="ocr-capabilities" content="ocr_page ocr_carea ocr_photo ocr_par ocr_line ocrx_word ocrp_wconf"/>
+ </head>
+ <body>
+`)
+	fmt.Fprintf(bw, "  <div class=\"ocr_page\" id=\"page_1\" title=\"bbox 0 0 %d %d\">\n", doc.Width, doc.Height)
+	for b, block := range doc.Blocks {
+		if block.Type.IsImage() {
+			fmt.Fprintf(bw, "   <div class=\"ocr_photo\" id=\"block_1_%d\" title=\"%s\"></div>\n", b+1, hocrBBox(block.Box))
+			continue
+		}
+		fmt.Fprintf(bw, "   <div class=\"ocr_carea\" id=\"block_1_%d\" title=\"%s\">\n", b+1, hocrBBox(block.Box))
+		for p, para := range block.Paragraphs {
+			fmt.Fprintf(bw, "    <p class=\"ocr_par\" id=\"par_1_%d_%d\" title=\"%s\">\n", b+1, p+1, hocrBBox(para.Box))
+			for l, line := range para.Lines {
+				fmt.Fprintf(bw, "     <span class=\"ocr_line\" id=\"line_1_%d_%d_%d\" title=\"%s\">\n", b+1, p+1, l+1, hocrBBox(line.Box))
+				for i, word := range line.Words {
+					text := html.EscapeString(word.Text)
+					switch {
+					case word.Superscript:
+						text = "<sup>" + text + "</sup>"
+					case word.Subscript:
+						text = "<sub>" + text + "</sub>"
+					}
+					if word.Bold {
+						text = "<strong>" + text + "</strong>"
+					}
+					fmt.Fprintf(bw, "      <span class=\"ocrx_word\" id=\"word_1_%d_%d_%d_%d\" title=\"%s; x_wconf %d\">%s</span>\n",
+						b+1, p+1, l+1, i+1, hocrBBox(word.Box), int(word.Confidence), text)
+				}
+				fmt.Fprint(bw, "     </span>\n")
+			}
+			fmt.Fprint(bw, "    </p>\n")
+		}
+		fmt.Fprint(bw, "   </div>\n")
+	}
+	fmt.Fprint(bw, "  </div>\n </body>\n</html>\n")
+	return bw.Flush()
+}
+
+func hocrBBox(box image.Rectangle) string {
+	return fmt.Sprintf("bbox %d %d %d %d", box.Min.X, box.Min.Y, box.Max.X, box.Max.Y)
+}
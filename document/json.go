@@ -0,0 +1,21 @@
+package document
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ParseJSON loads a Document from JSON, such as generated by WriteJSON.
+func ParseJSON(r io.Reader) (*Document, error) {
+	doc := &Document{}
+	if err := json.NewDecoder(r).Decode(doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %v", err)
+	}
+	return doc, nil
+}
+
+// WriteJSON renders the document as JSON, which keeps everything of the document, unlike hOCR and ALTO.
+func (doc *Document) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(doc)
+}
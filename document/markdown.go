@@ -0,0 +1,148 @@
+package document
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type elementKind int
+
+const (
+	elementParagraph elementKind = iota
+	elementHeading
+	elementItem
+	elementImage
+)
+
+// element is a heading, paragraph, list item or picture of a page, as exporters of reflowed text write them.
+type element struct {
+	kind elementKind
+	text string
+
+	// level of headings from 1
+	level int
+	// number of items of ordered lists, 0 for bullets
+	number int
+	// box of pictures
+	box image.Rectangle
+}
+
+var (
+	bulletMarker = regexp.MustCompile(`^(?:[•·▪‣◦●○■□]\s*|[-–*]\s+)`)
+	numberMarker = regexp.MustCompile(`^\(?(\d{1,3})[.)]\s+`)
+	letterMarker = regexp.MustCompile(`^\(?[a-z]\)\s+`)
+)
+
+// listItem returns the number of the list item the line starts, 0 for bullets, and the text after its marker.
+// Items lettered as "a)" or "(a)" are taken as bullets keeping their letters.
+func listItem(text string) (number int, rest string, ok bool) {
+	if loc := bulletMarker.FindStringIndex(text); loc != nil {
+		return 0, text[loc[1]:], true
+	}
+	if sub := numberMarker.FindStringSubmatch(text); sub != nil {
+		number, _ := strconv.Atoi(sub[1])
+		return number, text[len(sub[0]):], true
+	}
+	if letterMarker.MatchString(text) {
+		return 0, text, true
+	}
+	return 0, "", false
+}
+
+// elements splits the page into elements in reading order, with headings inferred by the font size of the body text.
+// Paragraphs are reflowed, and lines starting with bullets or numbers start list items,
+// which the following lines without markers continue.
+func (doc *Document) elements(body bodyStyle) []element {
+	elements := []element{}
+	for _, block := range doc.Blocks {
+		if block.Type.IsImage() {
+			elements = append(elements, element{kind: elementImage, box: block.Box})
+			continue
+		}
+		for _, para := range block.Paragraphs {
+			if level := headingLevel(block, para, body); level != 0 {
+				if text := reflow(para.Lines); text != "" {
+					elements = append(elements, element{kind: elementHeading, level: level, text: text})
+				}
+				continue
+			}
+			current := &element{kind: elementParagraph}
+			texts := []string{}
+			flush := func() {
+				if current.text = reflowTexts(texts); current.text != "" {
+					elements = append(elements, *current)
+				}
+				texts = nil
+			}
+			for _, line := range para.Lines {
+				text := line.Text()
+				if number, rest, ok := listItem(text); ok {
+					flush()
+					current = &element{kind: elementItem, number: number}
+					text = rest
+				}
+				texts = append(texts, text)
+			}
+			flush()
+		}
+	}
+	return elements
+}
+
+var (
+	markdownEscaper    = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`)
+	markdownBlockStart = regexp.MustCompile(`^(?:[#>+-]|\d+[.)])`)
+)
+
+// escapeMarkdown escapes the text not to be taken as Markdown, such as "*" of emphasis,
+// or "1." and "#" starting lists and headings.
+func escapeMarkdown(text string) string {
+	text = markdownEscaper.Replace(text)
+	if loc := markdownBlockStart.FindStringIndex(text); loc != nil {
+		text = text[:loc[1]-1] + `\` + text[loc[1]-1:]
+	}
+	return text
+}
+
+// WriteMarkdown renders the pages as Markdown, reflowing paragraphs and joining words hyphenated at line ends.
+// Headings are inferred from font sizes relative to the body text, see Document.WriteAccessibleText,
+// and lists are preserved as bulleted and numbered lists. Pictures and the starts of pages, if more than one,
+// are noted by HTML comments, such as "<!-- page 2 -->".
+func (pages Pages) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	body := pages.bodyStyle()
+	first, inList := true, false
+	for i, page := range pages {
+		if len(pages) > 1 {
+			writeSeparator(bw, &first)
+			fmt.Fprintf(bw, "<!-- page %d -->\n", i+1)
+		}
+		for _, e := range page.elements(body) {
+			if e.kind != elementItem || !inList {
+				writeSeparator(bw, &first)
+			}
+			inList = e.kind == elementItem
+			switch e.kind {
+			case elementHeading:
+				fmt.Fprintf(bw, "%s %s\n", strings.Repeat("#", e.level), escapeMarkdown(e.text))
+			case elementItem:
+				if e.number != 0 {
+					fmt.Fprintf(bw, "%d. %s\n", e.number, escapeMarkdown(e.text))
+				} else {
+					fmt.Fprintf(bw, "- %s\n", escapeMarkdown(e.text))
+				}
+			case elementImage:
+				fmt.Fprintf(bw, "<!-- image, %d by %d pixels at %d, %d -->\n", e.box.Dx(), e.box.Dy(), e.box.Min.X, e.box.Min.Y)
+			default:
+				fmt.Fprintln(bw, escapeMarkdown(e.text))
+			}
+		}
+		inList = false
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,247 @@
+package document
+
+import (
+	"image"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Caption links a caption to the picture it describes.
+type Caption struct {
+	// Image is the index of the block of the picture.
+	Image int `json:"image"`
+
+	// Block and Paragraph are the indices of the paragraph of the caption.
+	Block     int    `json:"block"`
+	Paragraph int    `json:"paragraph"`
+	Text      string `json:"text"`
+}
+
+// Footnote links a footnote to its marker in the text.
+type Footnote struct {
+	// Marker of the footnote as digits or symbols, such as "1" or "*", with superscript digits as digits.
+	Marker string `json:"marker"`
+
+	// Ref is the word of the marker in the text, nil if no marker is found.
+	Ref *WordRef `json:"ref,omitempty"`
+
+	// Block, Paragraph and Line are the indices of the first line of the footnote.
+	Block     int    `json:"block"`
+	Paragraph int    `json:"paragraph"`
+	Line      int    `json:"line"`
+	Text      string `json:"text"`
+}
+
+// captionLabel matches the labels captions usually start with.
+var captionLabel = regexp.MustCompile(`(?i)^(?:fig(?:ure)?|abb(?:ildung)?|tab(?:le|elle)?|image|photo|plate|chart|map|illustration)\b\.?`)
+
+// Captions returns the captions of pictures, i.e. the paragraphs just below or above pictures, overlapping
+// them horizontally, within twice the font size of the body text. Paragraphs labeled as "Figure 3" and the like,
+// or detected as captions by tesseract, are preferred, then those below pictures.
+func (doc *Document) Captions() []Caption {
+	gap := 2 * doc.bodyStyle().size
+	used := map[[2]int]bool{}
+	captions := []Caption{}
+	for i, picture := range doc.Blocks {
+		if !picture.Type.IsImage() {
+			continue
+		}
+		best, bestScore := Caption{}, -1
+		for b, block := range doc.Blocks {
+			if block.Type.IsImage() {
+				continue
+			}
+			for p, para := range block.Paragraphs {
+				box := paragraphBox(para)
+				if used[[2]int{b, p}] || !overlapsHorizontally(box, picture.Box) {
+					continue
+				}
+				score := 0
+				switch {
+				case box.Min.Y >= picture.Box.Max.Y && float64(box.Min.Y-picture.Box.Max.Y) <= gap:
+					score = 1
+				case box.Max.Y <= picture.Box.Min.Y && float64(picture.Box.Min.Y-box.Max.Y) <= gap:
+				default:
+					continue
+				}
+				text := reflow(para.Lines)
+				if captionLabel.MatchString(text) || block.Type == BlockCaptionText {
+					score += 2
+				}
+				if score > bestScore {
+					best, bestScore = Caption{Image: i, Block: b, Paragraph: p, Text: text}, score
+				}
+			}
+		}
+		if bestScore >= 0 {
+			used[[2]int{best.Block, best.Paragraph}] = true
+			captions = append(captions, best)
+		}
+	}
+	return captions
+}
+
+// overlapsHorizontally reports whether the box overlaps the other horizontally by at least half of the narrower one.
+func overlapsHorizontally(box, other image.Rectangle) bool {
+	left, right := box.Min.X, box.Max.X
+	if other.Min.X > left {
+		left = other.Min.X
+	}
+	if other.Max.X < right {
+		right = other.Max.X
+	}
+	narrower := box.Dx()
+	if other.Dx() < narrower {
+		narrower = other.Dx()
+	}
+	return right > left && 2*(right-left) >= narrower
+}
+
+// paragraphBox returns the box of the paragraph, or the union of its lines if it's unknown.
+func paragraphBox(para Paragraph) image.Rectangle {
+	if !para.Box.Empty() {
+		return para.Box
+	}
+	box := image.Rectangle{}
+	for _, line := range para.Lines {
+		lineBox := line.Box
+		for _, word := range line.Words {
+			lineBox = lineBox.Union(word.Box)
+		}
+		box = box.Union(lineBox)
+	}
+	return box
+}
+
+var (
+	footnoteStart  = regexp.MustCompile(`^([0-9]{1,3}|[*†‡§¶]+|[⁰¹²³⁴⁵⁶⁷⁸⁹]+)[.)]?(?:\s+|$)`)
+	superscripts   = strings.NewReplacer("⁰", "0", "¹", "1", "²", "2", "³", "3", "⁴", "4", "⁵", "5", "⁶", "6", "⁷", "7", "⁸", "8", "⁹", "9")
+	trailingMarker = regexp.MustCompile(`([*†‡§¶]+|[⁰¹²³⁴⁵⁶⁷⁸⁹]+|[0-9]{1,3})[.,;:!?)]*$`)
+)
+
+// footnoteMaxSize is the largest font size of footnotes relative to the body text.
+const footnoteMaxSize = 0.9
+
+// Footnotes returns the footnotes of the page, linked to their markers in the text.
+// Footnotes are lines starting with markers, such as "1" or "*", in paragraphs set smaller than the body text,
+// or in the bottom quarter of pages if their markers are found in the text. Markers in the text are
+// superscript digits or symbols ending words, such as "text¹" or "text*", small words of markers raised
+// above their lines, or digits of footnote markers ending words of letters, such as "text1".
+// Each footnote is linked to the first marker of its own.
+func (doc *Document) Footnotes() []Footnote {
+	body := doc.bodyStyle().size
+	footnotes := []Footnote{}
+	// whether footnotes are set small, rather than only at the bottom
+	smallNotes := []bool{}
+	inFootnotes := map[[2]int]bool{}
+	for b, block := range doc.Blocks {
+		for p, para := range block.Paragraphs {
+			box := paragraphBox(para)
+			words := []Word{}
+			for _, line := range para.Lines {
+				words = append(words, line.Words...)
+			}
+			small := body > 0 && fontSize(words) <= footnoteMaxSize*body
+			bottom := doc.Height > 0 && 4*box.Min.Y >= 3*doc.Height
+			if !small && !bottom {
+				continue
+			}
+			var current *Footnote
+			texts := []string{}
+			flush := func() {
+				if current != nil {
+					current.Text = reflowTexts(texts)
+					footnotes = append(footnotes, *current)
+					smallNotes = append(smallNotes, small)
+				}
+				current, texts = nil, nil
+			}
+			for l, line := range para.Lines {
+				text := line.Text()
+				if sub := footnoteStart.FindStringSubmatch(text); sub != nil {
+					flush()
+					current = &Footnote{Marker: superscripts.Replace(sub[1]), Block: b, Paragraph: p, Line: l}
+					text = text[len(sub[0]):]
+				}
+				if current != nil {
+					texts = append(texts, text)
+				}
+			}
+			flush()
+			for _, f := range footnotes {
+				if f.Block == b && f.Paragraph == p {
+					inFootnotes[[2]int{b, p}] = true
+				}
+			}
+		}
+	}
+
+	markers := map[string]bool{}
+	for _, f := range footnotes {
+		markers[f.Marker] = true
+	}
+	linked := map[WordRef]bool{}
+	for i := range footnotes {
+		f := &footnotes[i]
+		doc.EachWord(func(ref WordRef, word *Word) {
+			if f.Ref != nil || linked[ref] || inFootnotes[[2]int{ref.Block, ref.Paragraph}] {
+				return
+			}
+			if doc.footnoteMarker(ref, *word, markers) == f.Marker {
+				r := ref
+				f.Ref = &r
+				linked[ref] = true
+			}
+		})
+	}
+	found := []Footnote{}
+	for i, f := range footnotes {
+		if smallNotes[i] || f.Ref != nil {
+			found = append(found, f)
+		}
+	}
+	return found
+}
+
+// footnoteMarker returns the marker of footnotes the word is or ends with, or "" if none.
+func (doc *Document) footnoteMarker(ref WordRef, word Word, markers map[string]bool) string {
+	loc := trailingMarker.FindStringSubmatchIndex(word.Text)
+	if loc == nil {
+		return ""
+	}
+	raw := word.Text[loc[2]:loc[3]]
+	marker := superscripts.Replace(raw)
+	if !markers[marker] {
+		return ""
+	}
+	before := word.Text[:loc[2]]
+	switch {
+	case raw != marker, word.Superscript, strings.Trim(raw, "*†‡§¶") == "":
+		// Superscripts and symbols are markers wherever they are.
+		return marker
+	case before == "":
+		// A word of digits alone is a marker if it's small and raised above the line.
+		line := doc.Blocks[ref.Block].Paragraphs[ref.Paragraph].Lines[ref.Line]
+		size := fontSize(line.Words)
+		middle := (line.Box.Min.Y + line.Box.Max.Y) / 2
+		if line.Box.Empty() {
+			middle = (word.Box.Min.Y + word.Box.Max.Y) / 2
+		}
+		if float64(word.Box.Dy()) <= 0.7*size && word.Box.Max.Y <= middle {
+			return marker
+		}
+	case isLetters(before):
+		return marker
+	}
+	return ""
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,63 @@
+package document
+
+import "image"
+
+// Heading is a heading of the outline of documents, with the headings of its sections.
+type Heading struct {
+	// Level of the heading from 1 for the largest, see Document.WriteAccessibleText.
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+
+	// Page is the index of the page in Pages, and Block and Paragraph the indices of the paragraph of the heading,
+	// which anchor entries of tables of contents.
+	Page      int `json:"page"`
+	Block     int `json:"block"`
+	Paragraph int `json:"paragraph"`
+
+	// Box of the heading on its page.
+	Box image.Rectangle `json:"box"`
+
+	Children []Heading `json:"children,omitempty"`
+}
+
+// Outline returns the tree of the headings of the document, see Pages.Outline.
+func (doc *Document) Outline() []Heading {
+	return Pages{doc}.Outline()
+}
+
+// Outline returns the tree of the headings of the pages, from which tables of contents can be generated.
+// Headings are inferred from font sizes relative to the body text of all the pages, and from bold lines,
+// and each heading has those of lower levels following it until the next heading of its level or higher.
+func (pages Pages) Outline() []Heading {
+	body := pages.bodyStyle()
+	flat := []Heading{}
+	for i, page := range pages {
+		for b, block := range page.Blocks {
+			for p, para := range block.Paragraphs {
+				level := headingLevel(block, para, body)
+				if level == 0 {
+					continue
+				}
+				if text := reflow(para.Lines); text != "" {
+					flat = append(flat, Heading{Level: level, Text: text, Page: i, Block: b, Paragraph: p, Box: paragraphBox(para)})
+				}
+			}
+		}
+	}
+	outline, _ := nestHeadings(flat, 0)
+	return outline
+}
+
+// nestHeadings nests the headings deeper than the level, returning them and the rest of the headings.
+func nestHeadings(flat []Heading, level int) ([]Heading, []Heading) {
+	nested := []Heading{}
+	for len(flat) != 0 && flat[0].Level > level {
+		heading := flat[0]
+		heading.Children, flat = nestHeadings(flat[1:], heading.Level)
+		if len(heading.Children) == 0 {
+			heading.Children = nil
+		}
+		nested = append(nested, heading)
+	}
+	return nested, flat
+}
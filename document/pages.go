@@ -0,0 +1,30 @@
+package document
+
+import "strings"
+
+// Pages are the documents of the pages of a multi-page scan, such as a book or a report, in order.
+type Pages []*Document
+
+// Text returns the text of the pages, separating pages by form feeds as tesseract does.
+func (pages Pages) Text() string {
+	texts := make([]string, 0, len(pages))
+	for _, page := range pages {
+		texts = append(texts, page.Text())
+	}
+	return strings.Join(texts, "\n\f")
+}
+
+// Words returns all the words of the pages in reading order.
+func (pages Pages) Words() []Word {
+	words := []Word{}
+	for _, page := range pages {
+		words = append(words, page.Words()...)
+	}
+	return words
+}
+
+// bodyStyle is the style of the body text of the pages, measured over all the pages,
+// since some pages, such as the titles of chapters, have little body text.
+func (pages Pages) bodyStyle() bodyStyle {
+	return styleOf(pages.Words())
+}
@@ -0,0 +1,205 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"math"
+	"unicode/utf16"
+
+	"github.com/chennqqi/gosseract/v2/geometry"
+)
+
+// PDFOptions specifies how Pages.WritePDF renders searchable PDFs.
+type PDFOptions struct {
+	// Images are the page images in order of pages, drawn under the text. Pages without images, nil or
+	// beyond Images, are of the text layer only, which is searchable but blank.
+	Images []image.Image
+
+	// DPI is the resolution of the page images, to convert pixels into points. Zero means 300.
+	DPI int
+
+	// Quality of the page images encoded in JPEG from 1 to 100, jpeg.DefaultQuality if zero.
+	Quality int
+}
+
+// glyphlessWidth is the width of every glyph of the text layer in thousandths of the font size.
+const glyphlessWidth = 500
+
+// WritePDF renders the pages as a searchable PDF, of the page images with the words drawn invisibly
+// over them, as tesseract's PDF renderer does, so documents corrected by Document.Correct or loaded
+// by ParseHOCR and ParseALTO are rendered again without running OCR. Each word is scaled and rotated
+// to its Quad, or Box if it has none, so that selecting text highlights the words on the image.
+// Pages are of the size of Width and Height, or of their images if unknown, at PDFOptions.DPI.
+// The font of the text is not embedded, since it has no visible glyphs, so the PDF is not PDF/A.
+func (pages Pages) WritePDF(w io.Writer, opts PDFOptions) error {
+	if opts.DPI <= 0 {
+		opts.DPI = 300
+	}
+	if opts.Quality <= 0 {
+		opts.Quality = jpeg.DefaultQuality
+	}
+	pw := &pdfWriter{w: w}
+	pw.printf("%%PDF-1.5\n%%\xe2\xe3\xcf\xd3\n")
+
+	// The objects of the document come first, followed by the page, its content and its image of each page.
+	const pagesStart = 7
+	kids := &bytes.Buffer{}
+	for i := range pages {
+		fmt.Fprintf(kids, "%d 0 R ", pagesStart+3*i)
+	}
+	pw.object("<< /Type /Catalog /Pages 2 0 R >>")
+	pw.object(fmt.Sprintf("<< /Type /Pages /Kids [ %s] /Count %d >>", kids, len(pages)))
+	pw.object("<< /Type /Font /Subtype /Type0 /BaseFont /GlyphLessFont /Encoding /Identity-H" +
+		" /DescendantFonts [ 4 0 R ] /ToUnicode 6 0 R >>")
+	pw.object(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /GlyphLessFont"+
+		" /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >>"+
+		" /FontDescriptor 5 0 R /DW %d /CIDToGIDMap /Identity >>", glyphlessWidth))
+	pw.object(fmt.Sprintf("<< /Type /FontDescriptor /FontName /GlyphLessFont /Flags 5"+
+		" /FontBBox [ 0 0 %d 1000 ] /ItalicAngle 0 /Ascent 1000 /Descent 0 /CapHeight 1000 /StemV 80 >>", glyphlessWidth))
+	pw.stream("", []byte(pdfToUnicode))
+
+	for i, page := range pages {
+		var img image.Image
+		if i < len(opts.Images) {
+			img = opts.Images[i]
+		}
+		width, height := page.Width, page.Height
+		if (width == 0 || height == 0) && img != nil {
+			width, height = img.Bounds().Dx(), img.Bounds().Dy()
+		}
+		scale := 72 / float64(opts.DPI)
+		pageWidth, pageHeight := float64(width)*scale, float64(height)*scale
+
+		content := &bytes.Buffer{}
+		resources := "/Font << /F1 3 0 R >>"
+		if img != nil {
+			fmt.Fprintf(content, "q %.2f 0 0 %.2f 0 0 cm /Im1 Do Q\n", pageWidth, pageHeight)
+			resources += fmt.Sprintf(" /XObject << /Im1 %d 0 R >>", pagesStart+3*i+2)
+		}
+		writePDFText(content, page, scale, pageHeight)
+
+		pw.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [ 0 0 %.2f %.2f ] /Contents %d 0 R /Resources << %s >> >>",
+			pageWidth, pageHeight, pagesStart+3*i+1, resources))
+		compressed := &bytes.Buffer{}
+		zw := zlib.NewWriter(compressed)
+		zw.Write(content.Bytes())
+		zw.Close()
+		pw.stream("/Filter /FlateDecode", compressed.Bytes())
+		if img == nil {
+			// Keep the numbers of the objects of the following pages.
+			pw.object("null")
+			continue
+		}
+		encoded := &bytes.Buffer{}
+		if err := jpeg.Encode(encoded, img, &jpeg.Options{Quality: opts.Quality}); err != nil {
+			return fmt.Errorf("failed to encode the image of page %d: %v", i+1, err)
+		}
+		colorSpace := "/DeviceRGB"
+		if _, ok := img.(*image.Gray); ok {
+			colorSpace = "/DeviceGray"
+		}
+		pw.stream(fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /DCTDecode",
+			img.Bounds().Dx(), img.Bounds().Dy(), colorSpace), encoded.Bytes())
+	}
+	return pw.close()
+}
+
+// writePDFText draws the words of the page invisibly, i.e. in the rendering mode 3, each fit to its quad.
+func writePDFText(w io.Writer, page *Document, scale, pageHeight float64) {
+	io.WriteString(w, "BT\n3 Tr\n")
+	for _, word := range page.Words() {
+		codes := utf16.Encode([]rune(word.Text))
+		q := word.Quad
+		if q == (geometry.Quad{}) {
+			q = geometry.QuadOf(word.Box)
+		}
+		// The baseline runs from the bottom-left to the bottom-right corner as the text reads.
+		// Flip the y axis of the image into that of PDF.
+		dx, dy := float64(q[2].X-q[3].X), float64(q[3].Y-q[2].Y)
+		length := math.Hypot(dx, dy) * scale
+		size := math.Hypot(float64(q[0].X-q[3].X), float64(q[0].Y-q[3].Y)) * scale
+		if len(codes) == 0 || length == 0 || size == 0 {
+			continue
+		}
+		cos, sin := dx*scale/length, dy*scale/length
+		stretch := 100 * length / (float64(len(codes)) * glyphlessWidth / 1000 * size)
+		fmt.Fprintf(w, "/F1 %.2f Tf %.2f Tz %.4f %.4f %.4f %.4f %.2f %.2f Tm <",
+			size, stretch, cos, sin, 0-sin, cos, float64(q[3].X)*scale, pageHeight-float64(q[3].Y)*scale)
+		for _, code := range codes {
+			fmt.Fprintf(w, "%04X", code)
+		}
+		io.WriteString(w, "> Tj\n")
+	}
+	io.WriteString(w, "ET\n")
+}
+
+// pdfToUnicode maps the codes of the text layer, which are of UTF-16, to themselves for text extraction.
+const pdfToUnicode = `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def
+/CMapName /Adobe-Identity-UCS def
+/CMapType 2 def
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+1 beginbfrange
+<0000> <FFFF> <0000>
+endbfrange
+endcmap
+CMapName currentdict /CMap defineresource pop
+end
+end
+`
+
+// pdfWriter writes objects numbered in order, recording their offsets for the cross-reference table.
+type pdfWriter struct {
+	w       io.Writer
+	offset  int
+	offsets []int
+	err     error
+}
+
+func (pw *pdfWriter) printf(format string, args ...interface{}) {
+	if pw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(pw.w, format, args...)
+	pw.offset += n
+	pw.err = err
+}
+
+func (pw *pdfWriter) write(b []byte) {
+	if pw.err != nil {
+		return
+	}
+	n, err := pw.w.Write(b)
+	pw.offset += n
+	pw.err = err
+}
+
+func (pw *pdfWriter) object(body string) {
+	pw.offsets = append(pw.offsets, pw.offset)
+	pw.printf("%d 0 obj\n%s\nendobj\n", len(pw.offsets), body)
+}
+
+func (pw *pdfWriter) stream(dict string, data []byte) {
+	pw.offsets = append(pw.offsets, pw.offset)
+	pw.printf("%d 0 obj\n<< %s /Length %d >>\nstream\n", len(pw.offsets), dict, len(data))
+	pw.write(data)
+	pw.printf("\nendstream\nendobj\n")
+}
+
+func (pw *pdfWriter) close() error {
+	xref := pw.offset
+	pw.printf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1)
+	for _, offset := range pw.offsets {
+		pw.printf("%010d 00000 n \n", offset)
+	}
+	pw.printf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(pw.offsets)+1, xref)
+	return pw.err
+}
@@ -0,0 +1,147 @@
+package document
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// regionCell is the size of the cells of the page, in pixels, which strokes of regions are connected by.
+const regionCell = 16
+
+// Words of confidence lower than regionMinConfidence are taken as not recognized,
+// since tesseract makes garbage words of low confidence for handwriting, stamps and logos.
+const regionMinConfidence = 60
+
+// regionMinInk is the number of ink pixels of regions at least, ignoring specks.
+const regionMinInk = 64
+
+// inkRegion is a connected region of ink of the page not covered by words recognized.
+type inkRegion struct {
+	box image.Rectangle
+	// number of ink pixels, of those on the edges of strokes, and of the edges running diagonally or curving
+	ink, edges, irregular int
+	// number of ink pixels in the outer ring of the ellipse inscribed in box
+	ring int
+}
+
+func (r inkRegion) density() float64 {
+	return float64(r.ink) / float64(r.box.Dx()*r.box.Dy())
+}
+
+func (r inkRegion) irregularity() float64 {
+	if r.edges == 0 {
+		return 0
+	}
+	return float64(r.irregular) / float64(r.edges)
+}
+
+// inkRegions finds the regions of ink in page, the image the document is recognized from,
+// excluding the words recognized, in coordinates of the page.
+func (doc *Document) inkRegions(page image.Image) []inkRegion {
+	bounds := page.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	ink := make([]bool, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			ink[y*w+x] = color.GrayModel.Convert(page.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray).Y < ruleDarkness
+		}
+	}
+	doc.EachWord(func(ref WordRef, word *Word) {
+		if word.Confidence < regionMinConfidence {
+			return
+		}
+		box := word.Box.Sub(bounds.Min).Intersect(image.Rect(0, 0, w, h))
+		for y := box.Min.Y; y < box.Max.Y; y++ {
+			for x := box.Min.X; x < box.Max.X; x++ {
+				ink[y*w+x] = false
+			}
+		}
+	})
+	inkAt := func(x, y int) bool {
+		return x >= 0 && y >= 0 && x < w && y < h && ink[y*w+x]
+	}
+
+	// Cells of ink are connected into regions.
+	cw, ch := (w+regionCell-1)/regionCell, (h+regionCell-1)/regionCell
+	cells := make([]bool, cw*ch)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if ink[y*w+x] {
+				cells[(y/regionCell)*cw+x/regionCell] = true
+			}
+		}
+	}
+	regions := []inkRegion{}
+	visited := make([]bool, cw*ch)
+	for start := range cells {
+		if !cells[start] || visited[start] {
+			continue
+		}
+		visited[start] = true
+		queue := []int{start}
+		members := []int{}
+		region := inkRegion{}
+		for len(queue) > 0 {
+			cell := queue[0]
+			queue = queue[1:]
+			members = append(members, cell)
+			cx, cy := cell%cw, cell/cw
+			for y := cy * regionCell; y < (cy+1)*regionCell && y < h; y++ {
+				for x := cx * regionCell; x < (cx+1)*regionCell && x < w; x++ {
+					if !ink[y*w+x] {
+						continue
+					}
+					region.ink++
+					region.box = region.box.Union(image.Rect(x, y, x+1, y+1))
+					left, right, up, down := inkAt(x-1, y), inkAt(x+1, y), inkAt(x, y-1), inkAt(x, y+1)
+					if left && right && up && down {
+						continue
+					}
+					region.edges++
+					// Edges of printed strokes run along rows or columns.
+					if !(left && right && (!up || !down)) && !(up && down && (!left || !right)) {
+						region.irregular++
+					}
+				}
+			}
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := cx+dx, cy+dy
+					if nx < 0 || ny < 0 || nx >= cw || ny >= ch {
+						continue
+					}
+					if next := ny*cw + nx; cells[next] && !visited[next] {
+						visited[next] = true
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+		if region.ink < regionMinInk {
+			continue
+		}
+		// Count the ink in the outer ring of the inscribed ellipse, where borders of round stamps run.
+		rx, ry := float64(region.box.Dx())/2, float64(region.box.Dy())/2
+		mx, my := float64(region.box.Min.X)+rx, float64(region.box.Min.Y)+ry
+		for _, cell := range members {
+			cx, cy := cell%cw, cell/cw
+			for y := cy * regionCell; y < (cy+1)*regionCell && y < h; y++ {
+				for x := cx * regionCell; x < (cx+1)*regionCell && x < w; x++ {
+					if !ink[y*w+x] {
+						continue
+					}
+					if r := math.Hypot((float64(x)+0.5-mx)/rx, (float64(y)+0.5-my)/ry); r >= ringInner && r <= ringOuter {
+						region.ring++
+					}
+				}
+			}
+		}
+		region.box = region.box.Add(bounds.Min)
+		regions = append(regions, region)
+	}
+	return regions
+}
+
+// The outer ring of ellipses, relative to their radii.
+const ringInner, ringOuter = 0.75, 1.1
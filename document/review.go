@@ -0,0 +1,107 @@
+package document
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+// ReviewItem is a word to be reviewed by humans, which makes a line of the review format,
+// i.e. JSON Lines exported by ExportReview and imported back by ImportReview.
+type ReviewItem struct {
+	Ref        WordRef         `json:"ref"`
+	Text       string          `json:"text"`
+	Confidence float64         `json:"confidence"`
+	Box        image.Rectangle `json:"box"`
+
+	// Crop is the PNG image of the word cut out of the page, to be shown to reviewers.
+	Crop []byte `json:"crop,omitempty"`
+
+	// Correction is the text filled in by the reviewer, nil to accept Text as it is.
+	Correction *string `json:"correction,omitempty"`
+}
+
+// reviewCropPadding is the margin around words in crops, to show reviewers a bit of the context.
+const reviewCropPadding = 4
+
+// ExportReview writes the words with confidence lower than threshold, in reading order,
+// as the review format. Crops are cut out of page, if not nil, which must be the image recognized.
+func (doc *Document) ExportReview(w io.Writer, page image.Image, threshold float64) error {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	var err error
+	doc.EachWord(func(ref WordRef, word *Word) {
+		if err != nil || word.Confidence >= threshold {
+			return
+		}
+		item := ReviewItem{Ref: ref, Text: word.Text, Confidence: word.Confidence, Box: word.Box}
+		if page != nil {
+			if item.Crop, err = cropPNG(page, word.Box.Inset(-reviewCropPadding)); err != nil {
+				return
+			}
+		}
+		err = encoder.Encode(item)
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ImportReview applies the corrections in the review format to the document, see Correct.
+// Items whose Text doesn't match the word anymore are rejected, not to apply a stale review
+// to a document recognized or corrected again since exported.
+func (doc *Document) ImportReview(r io.Reader) error {
+	corrections := []Correction{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		item := ReviewItem{}
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			return fmt.Errorf("failed to parse review at line %d: %v", n, err)
+		}
+		if item.Correction == nil {
+			continue
+		}
+		word := doc.Word(item.Ref)
+		if word == nil || word.Text != item.Text {
+			return fmt.Errorf("review at line %d doesn't match the word at %+v", n, item.Ref)
+		}
+		corrections = append(corrections, Correction{Ref: item.Ref, Text: *item.Correction})
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return doc.Correct(corrections...)
+}
+
+// cropPNG encodes the part of img within r as PNG.
+func cropPNG(img image.Image, r image.Rectangle) ([]byte, error) {
+	r = r.Intersect(img.Bounds())
+	if r.Empty() {
+		return nil, nil
+	}
+	var crop image.Image
+	if sub, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		crop = sub.SubImage(r)
+	} else {
+		rgba := image.NewRGBA(r)
+		draw.Draw(rgba, r, img, r.Min, draw.Src)
+		crop = rgba
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := png.Encode(buf, crop); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
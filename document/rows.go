@@ -0,0 +1,49 @@
+package document
+
+import "sort"
+
+// Rows returns the visual rows of the document from top to bottom, each of which joins the lines
+// overlapping vertically by at least half the height of the lower one, with words ordered from left to right.
+// Tesseract splits rows of separated columns into lines of different blocks, such as items and prices
+// of receipts or cells of tables, which can be read together by rows.
+// It's meaningful only for upright text.
+func (doc *Document) Rows() []Line {
+	lines := []Line{}
+	for _, block := range doc.Blocks {
+		for _, para := range block.Paragraphs {
+			for _, line := range para.Lines {
+				if len(line.Words) != 0 {
+					lines = append(lines, line)
+				}
+			}
+		}
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Box.Min.Y+lines[i].Box.Max.Y < lines[j].Box.Min.Y+lines[j].Box.Max.Y
+	})
+	rows := []Line{}
+	for _, line := range lines {
+		if n := len(rows); n != 0 && overlapsRow(rows[n-1], line) {
+			rows[n-1].Box = rows[n-1].Box.Union(line.Box)
+			rows[n-1].Words = append(rows[n-1].Words, line.Words...)
+			continue
+		}
+		rows = append(rows, Line{Box: line.Box, Words: append([]Word{}, line.Words...)})
+	}
+	for _, row := range rows {
+		words := row.Words
+		sort.SliceStable(words, func(i, j int) bool { return words[i].Box.Min.X < words[j].Box.Min.X })
+	}
+	return rows
+}
+
+func overlapsRow(row, line Line) bool {
+	top, bottom := row.Box.Min.Y, row.Box.Max.Y
+	if line.Box.Min.Y > top {
+		top = line.Box.Min.Y
+	}
+	if line.Box.Max.Y < bottom {
+		bottom = line.Box.Max.Y
+	}
+	return bottom > top && 2*(bottom-top) >= line.Box.Dy()
+}
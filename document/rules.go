@@ -0,0 +1,77 @@
+package document
+
+import (
+	"image"
+	"image/color"
+	"unicode"
+)
+
+// Rules detected by MarkRules must run across this fraction of the width of words at least,
+// which strokes of letters hardly do.
+const ruleMinCoverage = 0.8
+
+// ruleDarkness is the gray level below which pixels are ink.
+const ruleDarkness = 128
+
+// MarkRules detects horizontal rules over words in page, the image the document is recognized from,
+// and flags the words Underlined by rules along or just below their bottom, or StruckThrough by rules across
+// their middle, which tesseract doesn't tell. It returns the number of words flagged.
+// Words of a single character, or without letters or digits such as dashes, are too short to tell rules from strokes.
+func (doc *Document) MarkRules(page image.Image) int {
+	marked := 0
+	doc.EachWord(func(ref WordRef, word *Word) {
+		if !ruleable(word.Text) {
+			return
+		}
+		box, h := word.Box, word.Box.Dy()
+		if h <= 0 {
+			return
+		}
+		// Underlines are often left out of the boxes of words, so the band reaches below them.
+		for y := box.Min.Y; y < box.Max.Y+h*3/10; y++ {
+			if !isRule(page, box.Min.X, box.Max.X, y) {
+				continue
+			}
+			switch offset := y - box.Min.Y; {
+			case offset >= h*35/100 && offset <= h*75/100:
+				word.StruckThrough = true
+			case offset >= h*85/100:
+				word.Underlined = true
+			}
+		}
+		if word.StruckThrough || word.Underlined {
+			marked++
+		}
+	})
+	return marked
+}
+
+// isRule reports whether the row y of page has a run of ink from x0 to x1 covering ruleMinCoverage of it.
+func isRule(page image.Image, x0, x1, y int) bool {
+	bounds := page.Bounds()
+	if y < bounds.Min.Y || y >= bounds.Max.Y {
+		return false
+	}
+	run, longest := 0, 0
+	for x := x0; x < x1; x++ {
+		if x >= bounds.Min.X && x < bounds.Max.X && color.GrayModel.Convert(page.At(x, y)).(color.Gray).Y < ruleDarkness {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	return float64(longest) >= ruleMinCoverage*float64(x1-x0)
+}
+
+// ruleable reports whether rules can be told from strokes of the text.
+func ruleable(text string) bool {
+	count, alnum := 0, false
+	for _, r := range text {
+		count++
+		alnum = alnum || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	return count >= 2 && alnum
+}
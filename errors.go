@@ -0,0 +1,43 @@
+package gosseract
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrClientClosed is returned by methods of Client called after Close.
+var ErrClientClosed = errors.New("client is already closed")
+
+// ErrIteratorReleased is returned by methods of ResultIterator called after its Close, or after the client
+// recognizes again or clears its results, which frees the results walked.
+var ErrIteratorReleased = errors.New("result iterator is already released")
+
+// ErrNoMatch is returned for texts decoded by Options.Constraint or Client.DecodeText of which no choice matches.
+var ErrNoMatch = errors.New("no text of the choices matches")
+
+// TesseractError represents a C++ exception thrown by tesseract::TessBaseAPI.
+// Exceptions are caught at the boundary of cgo and returned as this error,
+// instead of terminating the whole process.
+type TesseractError struct {
+	// Method is the name of TessBaseAPI method which has thrown.
+	Method string
+	// Message is what the exception says, i.e. std::exception::what.
+	Message string
+}
+
+func (err *TesseractError) Error() string {
+	return fmt.Sprintf("tesseract threw an exception in %s: %s", err.Method, err.Message)
+}
+
+// ImageError is returned by SetImage and SetImageFromBytes for images which are not set,
+// such as of unknown formats, malformed or truncated, or too large, see Client.MaxImagePixels.
+type ImageError struct {
+	// Reason is why the image is not set.
+	Reason string
+	// TooLarge is whether the image exceeds the limit of pixels, such as of decompression bombs.
+	TooLarge bool
+}
+
+func (err *ImageError) Error() string {
+	return fmt.Sprintf("invalid image: %s", err.Reason)
+}
@@ -0,0 +1,232 @@
+// Package export emits recognized text in chunks, per page, block or paragraph with their metadata,
+// to a Sink provided by users, such as to feed vector databases and search indexes from OCR pipelines.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/chennqqi/gosseract/v2/document"
+)
+
+// Level is the unit of chunks.
+type Level int
+
+const (
+	// LevelPage makes a chunk of each page, which is the default.
+	LevelPage Level = iota
+	// LevelBlock makes a chunk of each block of text, such as a column.
+	LevelBlock
+	// LevelParagraph makes a chunk of each paragraph.
+	LevelParagraph
+)
+
+// Chunk is a piece of text recognized, with where it's from.
+type Chunk struct {
+	// ID identifies the chunk by the source, the page and the index of the chunk in the page,
+	// such as "scans/a.pdf#3/2", to upsert chunks of pages recognized again.
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	// Page is the number of the page in the source, from 1, or 0 if the source isn't paged.
+	Page int    `json:"page,omitempty"`
+	Text string `json:"text"`
+
+	// Box is the region of the chunk in the page, and Confidence the mean confidence of its words,
+	// which are zero for chunks of plain texts.
+	Box        image.Rectangle `json:"box"`
+	Confidence float64         `json:"confidence,omitempty"`
+
+	// Metadata is of Options.Metadata.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Sink receives chunks, such as to embed and index them. Chunks of the same page are written together.
+type Sink interface {
+	Write(ctx context.Context, chunks []Chunk) error
+}
+
+// SinkFunc is a function as a Sink.
+type SinkFunc func(ctx context.Context, chunks []Chunk) error
+
+// Write calls f.
+func (f SinkFunc) Write(ctx context.Context, chunks []Chunk) error {
+	return f(ctx, chunks)
+}
+
+// Options specifies how pages are chunked.
+type Options struct {
+	Level Level
+
+	// MaxChars splits chunks longer than this, in characters, at lines, or at words of lines longer than this,
+	// to fit the input of embedding models. Zero for no limit.
+	MaxChars int
+
+	// Metadata is attached to all the chunks, such as the collection or the owner of the sources.
+	Metadata map[string]string
+}
+
+// Document writes the chunks of the page of the source to the sink, if any. Empty chunks are skipped.
+func Document(ctx context.Context, sink Sink, source string, page int, doc *document.Document, opts Options) error {
+	chunks := Chunks(source, page, doc, opts)
+	if len(chunks) == 0 {
+		return nil
+	}
+	return sink.Write(ctx, chunks)
+}
+
+// Text writes the chunks of the plain text of the page of the source to the sink, if any,
+// such as of gosseract.Client.Text, which is chunked by MaxChars only.
+func Text(ctx context.Context, sink Sink, source string, page int, text string, opts Options) error {
+	doc := &document.Document{}
+	para := document.Paragraph{}
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		l := document.Line{}
+		for _, word := range strings.Fields(line) {
+			l.Words = append(l.Words, document.Word{Text: word})
+		}
+		para.Lines = append(para.Lines, l)
+	}
+	doc.Blocks = []document.Block{{Paragraphs: []document.Paragraph{para}}}
+	opts.Level = LevelPage
+	return Document(ctx, sink, source, page, doc, opts)
+}
+
+// Chunks returns the chunks of the page of the source without writing them.
+func Chunks(source string, page int, doc *document.Document, opts Options) []Chunk {
+	groups := [][]document.Line{}
+	for _, block := range doc.Blocks {
+		if opts.Level == LevelBlock || (opts.Level == LevelPage && len(groups) == 0) {
+			groups = append(groups, nil)
+		}
+		for _, para := range block.Paragraphs {
+			if opts.Level == LevelParagraph {
+				groups = append(groups, nil)
+			}
+			groups[len(groups)-1] = append(groups[len(groups)-1], para.Lines...)
+		}
+	}
+	chunks := []Chunk{}
+	for _, lines := range groups {
+		for _, piece := range split(lines, opts.MaxChars) {
+			chunk := chunkOf(piece)
+			if chunk.Text == "" {
+				continue
+			}
+			chunk.ID = fmt.Sprintf("%s#%d/%d", source, page, len(chunks))
+			chunk.Source, chunk.Page, chunk.Metadata = source, page, opts.Metadata
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks
+}
+
+// split splits the lines into pieces of maxChars at most, at lines, or at words of lines too long.
+func split(lines []document.Line, maxChars int) [][]document.Line {
+	if maxChars <= 0 {
+		return [][]document.Line{lines}
+	}
+	pieces := [][]document.Line{}
+	current, size := []document.Line{}, 0
+	flush := func() {
+		if len(current) != 0 {
+			pieces = append(pieces, current)
+		}
+		current, size = []document.Line{}, 0
+	}
+	for _, line := range lines {
+		for _, part := range splitLine(line, maxChars) {
+			n := utf8.RuneCountInString(part.Text())
+			if size != 0 && size+1+n > maxChars {
+				flush()
+			}
+			if size != 0 {
+				size++
+			}
+			current, size = append(current, part), size+n
+		}
+	}
+	flush()
+	return pieces
+}
+
+// splitLine splits the line into lines of maxChars at most at words. Words longer than maxChars are kept whole.
+func splitLine(line document.Line, maxChars int) []document.Line {
+	if utf8.RuneCountInString(line.Text()) <= maxChars {
+		return []document.Line{line}
+	}
+	parts := []document.Line{}
+	current, size := document.Line{}, 0
+	for _, word := range line.Words {
+		n := utf8.RuneCountInString(word.Text)
+		if size != 0 && size+1+n > maxChars {
+			parts = append(parts, current)
+			current, size = document.Line{}, 0
+		}
+		if size != 0 {
+			size++
+		}
+		current.Words, size = append(current.Words, word), size+n
+	}
+	if len(current.Words) != 0 {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// chunkOf joins the lines into the chunk, with the union of the boxes and the mean confidence of the words.
+func chunkOf(lines []document.Line) Chunk {
+	chunk := Chunk{}
+	texts := []string{}
+	words := 0
+	for _, line := range lines {
+		if text := line.Text(); strings.TrimSpace(text) != "" {
+			texts = append(texts, text)
+		}
+		for _, word := range line.Words {
+			if !word.Box.Empty() {
+				chunk.Box = chunk.Box.Union(word.Box)
+			}
+			chunk.Confidence += word.Confidence
+			words++
+		}
+	}
+	if words != 0 {
+		chunk.Confidence /= float64(words)
+	}
+	chunk.Text = strings.Join(texts, "\n")
+	return chunk
+}
+
+// JSONLines is a Sink writing chunks to w as JSON lines, such as for bulk loaders of search indexes.
+// It's safe to share among goroutines.
+type JSONLines struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLines creates JSONLines writing to w.
+func NewJSONLines(w io.Writer) *JSONLines {
+	return &JSONLines{w: w}
+}
+
+// Write writes the chunks, a line each.
+func (sink *JSONLines) Write(ctx context.Context, chunks []Chunk) error {
+	buf := []byte{}
+	for _, chunk := range chunks {
+		b, err := json.Marshal(chunk)
+		if err != nil {
+			return err
+		}
+		buf = append(append(buf, b...), '\n')
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	_, err := sink.w.Write(buf)
+	return err
+}
@@ -0,0 +1,78 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/chennqqi/gosseract/v2/document"
+	. "github.com/otiai10/mint"
+)
+
+// line builds a line of the words, 10 pixels wide each, at the row y.
+func line(y int, text string) document.Line {
+	l := document.Line{}
+	for i, word := range strings.Fields(text) {
+		l.Words = append(l.Words, document.Word{Text: word, Confidence: 80, Box: image.Rect(10*i, y, 10*i+8, y+8)})
+	}
+	return l
+}
+
+func sample() *document.Document {
+	return &document.Document{Blocks: []document.Block{
+		{Paragraphs: []document.Paragraph{
+			{Lines: []document.Line{line(0, "Annual report"), line(10, "of the year")}},
+			{Lines: []document.Line{line(30, "Revenue grew")}},
+		}},
+		{Type: document.BlockFlowingImage},
+		{Paragraphs: []document.Paragraph{{Lines: []document.Line{line(60, "Page 1")}}}},
+	}}
+}
+
+func TestChunks(t *testing.T) {
+	chunks := Chunks("report.pdf", 3, sample(), Options{Metadata: map[string]string{"owner": "finance"}})
+	Expect(t, len(chunks)).ToBe(1)
+	Expect(t, chunks[0].ID).ToBe("report.pdf#3/0")
+	Expect(t, chunks[0].Text).ToBe("Annual report\nof the year\nRevenue grew\nPage 1")
+	Expect(t, chunks[0].Box).ToBe(image.Rect(0, 0, 28, 68))
+	Expect(t, chunks[0].Confidence).ToBe(80.0)
+	Expect(t, chunks[0].Metadata["owner"]).ToBe("finance")
+
+	chunks = Chunks("report.pdf", 3, sample(), Options{Level: LevelBlock})
+	Expect(t, len(chunks)).ToBe(2)
+	Expect(t, chunks[1].ID).ToBe("report.pdf#3/1")
+	Expect(t, chunks[1].Text).ToBe("Page 1")
+
+	chunks = Chunks("report.pdf", 3, sample(), Options{Level: LevelParagraph})
+	Expect(t, len(chunks)).ToBe(3)
+	Expect(t, chunks[1].Text).ToBe("Revenue grew")
+
+	When(t, "chunks are longer than MaxChars", func(t *testing.T) {
+		chunks := Chunks("report.pdf", 3, sample(), Options{Level: LevelParagraph, MaxChars: 13})
+		texts := []string{}
+		for _, chunk := range chunks {
+			texts = append(texts, chunk.Text)
+		}
+		Expect(t, texts).ToBe([]string{"Annual report", "of the year", "Revenue grew", "Page 1"})
+
+		chunks = Chunks("report.pdf", 3, sample(), Options{Level: LevelParagraph, MaxChars: 8})
+		Expect(t, chunks[0].Text).ToBe("Annual")
+		Expect(t, chunks[1].Text).ToBe("report")
+	})
+}
+
+func TestJSONLines(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	sink := NewJSONLines(buf)
+	Expect(t, Text(context.Background(), sink, "note.png", 0, "hello world\nagain\n", Options{})).ToBe(nil)
+	Expect(t, Text(context.Background(), sink, "blank.png", 0, " \n", Options{})).ToBe(nil)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	Expect(t, len(lines)).ToBe(1)
+	chunk := Chunk{}
+	Expect(t, json.Unmarshal([]byte(lines[0]), &chunk)).ToBe(nil)
+	Expect(t, chunk.ID).ToBe("note.png#0/0")
+	Expect(t, chunk.Text).ToBe("hello world\nagain")
+}
@@ -0,0 +1,116 @@
+package extract
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoDate is returned by ParseDate if the text has no date.
+var ErrNoDate = errors.New("no date found")
+
+// monthNames maps names of months and their abbreviations, in lower case, to months.
+var monthNames = map[string]time.Month{
+	"january": 1, "february": 2, "march": 3, "april": 4, "may": 5, "june": 6, "july": 7, "august": 8,
+	"september": 9, "october": 10, "november": 11, "december": 12,
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "sept": 9, "oct": 10, "nov": 11, "dec": 12,
+	"januar": 1, "februar": 2, "märz": 3, "mai": 5, "juni": 6, "juli": 7, "oktober": 10, "dezember": 12, "okt": 10, "dez": 12,
+	"janvier": 1, "février": 2, "mars": 3, "avril": 4, "juin": 6, "juillet": 7, "août": 8, "septembre": 9,
+	"octobre": 10, "novembre": 11, "décembre": 12,
+	"enero": 1, "febrero": 2, "marzo": 3, "abril": 4, "mayo": 5, "junio": 6, "julio": 7, "agosto": 8,
+	"septiembre": 9, "octubre": 10, "noviembre": 11, "diciembre": 12,
+}
+
+var monthPattern = func() string {
+	names := make([]string, 0, len(monthNames))
+	for name := range monthNames {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	// Longer names first, not to match "mar" of "march".
+	sort.Slice(names, func(i, j int) bool {
+		return len(names[i]) > len(names[j]) || (len(names[i]) == len(names[j]) && names[i] < names[j])
+	})
+	return "(" + strings.Join(names, "|") + ")"
+}()
+
+var (
+	isoDate      = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	numericDate  = regexp.MustCompile(`\b(\d{1,2})[./-](\d{1,2})[./-](\d{4}|\d{2})\b`)
+	dayMonthDate = regexp.MustCompile(`(?i)\b(\d{1,2})\.? ` + monthPattern + `\.?,? (\d{4})\b`)
+	monthDayDate = regexp.MustCompile(`(?i)\b` + monthPattern + `\.? (\d{1,2}),? (\d{4})\b`)
+)
+
+// monthFirstRegions are the regions writing numeric dates month first, such as 01/15/2024.
+var monthFirstRegions = map[string]bool{"US": true, "PH": true}
+
+// ParseDate finds the first date in text, written in ISO 8601 such as 2024-01-15, with numbers such as
+// 15.01.2024 or 15/01/24, or with names of months in English, German, French or Spanish such as "15 Jan 2024"
+// or "January 15, 2024". Numeric dates are read day first unless the region of locale is the United States,
+// or the first number can't be a month. Years of two digits are in the 2000s.
+func ParseDate(text string, locale string) (time.Time, error) {
+	date, _, ok := findDate(text, locale)
+	if !ok {
+		return time.Time{}, ErrNoDate
+	}
+	return date, nil
+}
+
+// findDate returns the first date in text and the text the date is parsed from.
+func findDate(text string, locale string) (time.Time, string, bool) {
+	monthFirst := false
+	if parts := strings.Split(strings.ReplaceAll(locale, "_", "-"), "-"); len(parts) > 1 {
+		monthFirst = monthFirstRegions[strings.ToUpper(parts[1])]
+	}
+	found, first, start := time.Time{}, "", len(text)+1
+	try := func(pattern *regexp.Regexp, parse func(m []string) (int, int, int)) {
+		for _, loc := range pattern.FindAllStringSubmatchIndex(text, -1) {
+			if loc[0] >= start {
+				return
+			}
+			m := make([]string, len(loc)/2)
+			for i := range m {
+				m[i] = text[loc[2*i]:loc[2*i+1]]
+			}
+			y, mo, d := parse(m)
+			if date, ok := validDate(y, mo, d); ok {
+				found, first, start = date, m[0], loc[0]
+				return
+			}
+		}
+	}
+	try(isoDate, func(m []string) (int, int, int) { return atoi(m[1]), atoi(m[2]), atoi(m[3]) })
+	try(numericDate, func(m []string) (int, int, int) {
+		d, mo := atoi(m[1]), atoi(m[2])
+		if (monthFirst && d <= 12) || mo > 12 {
+			d, mo = mo, d
+		}
+		return year(m[3]), mo, d
+	})
+	try(dayMonthDate, func(m []string) (int, int, int) {
+		return atoi(m[3]), int(monthNames[strings.ToLower(m[2])]), atoi(m[1])
+	})
+	try(monthDayDate, func(m []string) (int, int, int) {
+		return atoi(m[3]), int(monthNames[strings.ToLower(m[1])]), atoi(m[2])
+	})
+	return found, first, first != ""
+}
+
+func validDate(y, m, d int) (time.Time, bool) {
+	date := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	return date, m >= 1 && m <= 12 && date.Day() == d && date.Month() == time.Month(m)
+}
+
+func year(s string) int {
+	if len(s) == 2 {
+		return 2000 + atoi(s)
+	}
+	return atoi(s)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
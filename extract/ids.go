@@ -0,0 +1,107 @@
+package extract
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ibanLengths are the lengths of IBAN of countries, to reject IBAN joined with following text.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AT": 20, "BE": 16, "BG": 22, "BH": 22, "BR": 29, "CH": 21, "CY": 28, "CZ": 24,
+	"DE": 22, "DK": 18, "EE": 20, "ES": 24, "FI": 18, "FR": 27, "GB": 22, "GI": 23, "GR": 27, "HR": 21,
+	"HU": 28, "IE": 22, "IL": 23, "IS": 26, "IT": 27, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27,
+	"MT": 31, "NL": 18, "NO": 15, "PL": 28, "PT": 25, "RO": 24, "SA": 24, "SE": 24, "SI": 19, "SK": 24,
+	"SM": 27, "TR": 26,
+}
+
+var ibanPattern = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[0-9A-Z]{1,4}){3,8}\b`)
+
+// ValidIBAN reports whether s, with or without spaces, is an IBAN of the right length for its country
+// passing the check digits of ISO 13616, i.e. mod 97.
+func ValidIBAN(s string) bool {
+	s = strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	if len(s) < 15 || len(s) > 34 || strings.Trim(s, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") != "" {
+		return false
+	}
+	if length, ok := ibanLengths[s[:2]]; ok && len(s) != length {
+		return false
+	}
+	remainder := 0
+	for _, c := range s[4:] + s[:4] {
+		if c >= 'A' {
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		}
+	}
+	return remainder == 1
+}
+
+// vatFormats are the formats of VAT identification numbers after the country prefix.
+var vatFormats = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^U\d{8}$`),
+	"BE": regexp.MustCompile(`^[01]\d{9}$`),
+	"BG": regexp.MustCompile(`^\d{9,10}$`),
+	"CY": regexp.MustCompile(`^\d{8}[A-Z]$`),
+	"CZ": regexp.MustCompile(`^\d{8,10}$`),
+	"DE": regexp.MustCompile(`^\d{9}$`),
+	"DK": regexp.MustCompile(`^\d{8}$`),
+	"EE": regexp.MustCompile(`^\d{9}$`),
+	"EL": regexp.MustCompile(`^\d{9}$`),
+	"ES": regexp.MustCompile(`^[0-9A-Z]\d{7}[0-9A-Z]$`),
+	"FI": regexp.MustCompile(`^\d{8}$`),
+	"FR": regexp.MustCompile(`^[0-9A-Z]{2}\d{9}$`),
+	"GB": regexp.MustCompile(`^(?:\d{9}|\d{12}|GD\d{3}|HA\d{3})$`),
+	"HR": regexp.MustCompile(`^\d{11}$`),
+	"HU": regexp.MustCompile(`^\d{8}$`),
+	"IE": regexp.MustCompile(`^(?:\d{7}[A-W][A-I]?|\d[A-Z+*]\d{5}[A-W])$`),
+	"IT": regexp.MustCompile(`^\d{11}$`),
+	"LT": regexp.MustCompile(`^(?:\d{9}|\d{12})$`),
+	"LU": regexp.MustCompile(`^\d{8}$`),
+	"LV": regexp.MustCompile(`^\d{11}$`),
+	"MT": regexp.MustCompile(`^\d{8}$`),
+	"NL": regexp.MustCompile(`^\d{9}B\d{2}$`),
+	"PL": regexp.MustCompile(`^\d{10}$`),
+	"PT": regexp.MustCompile(`^\d{9}$`),
+	"RO": regexp.MustCompile(`^\d{2,10}$`),
+	"SE": regexp.MustCompile(`^\d{12}$`),
+	"SI": regexp.MustCompile(`^\d{8}$`),
+	"SK": regexp.MustCompile(`^\d{10}$`),
+	"XI": regexp.MustCompile(`^(?:\d{9}|\d{12}|GD\d{3}|HA\d{3})$`),
+}
+
+var vatPattern = func() *regexp.Regexp {
+	countries := make([]string, 0, len(vatFormats))
+	for country := range vatFormats {
+		countries = append(countries, country)
+	}
+	return regexp.MustCompile(`\b(?:` + strings.Join(countries, "|") + `) ?[0-9A-Z][0-9A-Z ]{3,16}[0-9A-Z]\b`)
+}()
+
+// ValidVATID reports whether s, with or without spaces, is in the format of VAT identification numbers
+// of its country prefix, such as DE123456789, for the member states of the EU and the United Kingdom.
+// Check digits are not validated, which are specific to countries.
+func ValidVATID(s string) bool {
+	s = strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	if len(s) < 4 {
+		return false
+	}
+	format, ok := vatFormats[s[:2]]
+	return ok && format.MatchString(s[2:])
+}
+
+// findIDs returns the substrings matching pattern, shortened by the groups separated by spaces at the end
+// until valid, such as an IBAN followed by a code. Invalid ones are returned as they match, with false.
+func findIDs(text string, pattern *regexp.Regexp, valid func(string) bool) (ids []string, validities []bool) {
+	for _, match := range pattern.FindAllString(text, -1) {
+		groups := strings.Split(match, " ")
+		id, ok := match, false
+		for n := len(groups); n > 0 && !ok; n-- {
+			if candidate := strings.Join(groups[:n], " "); valid(candidate) {
+				id, ok = candidate, true
+			}
+		}
+		ids, validities = append(ids, id), append(validities, ok)
+	}
+	return ids, validities
+}
@@ -0,0 +1,366 @@
+package extract
+
+import (
+	"image"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/chennqqi/gosseract/v2/document"
+)
+
+// Field is a value extracted from a document, with where it's found.
+// Text is empty if the field is not found.
+type Field struct {
+	Text string          `json:"text"`
+	Box  image.Rectangle `json:"box"`
+
+	// Confidence is the lowest confidence of the words of the value.
+	Confidence float64 `json:"confidence"`
+
+	// Valid is whether the value passes its validation, such as the check digits of IBAN,
+	// which is true for values without validation if parsed.
+	Valid bool `json:"valid"`
+}
+
+// DateField is a Field of a date.
+type DateField struct {
+	Field
+	Time time.Time `json:"time"`
+}
+
+// MoneyField is a Field of an amount of money.
+type MoneyField struct {
+	Field
+	Money Money `json:"money"`
+}
+
+// Invoice is the fields of an invoice.
+type Invoice struct {
+	// Template is the name of the template applied, empty if none.
+	Template string `json:"template,omitempty"`
+
+	Number  Field      `json:"number"`
+	Date    DateField  `json:"date"`
+	DueDate DateField  `json:"due_date"`
+	Total   MoneyField `json:"total"`
+
+	// VATIDs are the VAT identification numbers on the invoice, usually of the vendor and the customer,
+	// valid in their formats, see ValidVATID.
+	VATIDs []Field `json:"vat_ids"`
+
+	// IBAN is the first valid IBAN, or the one labeled as such with Valid false if none is valid.
+	IBAN Field `json:"iban"`
+}
+
+// InvoiceAnchors are the keywords labeling the fields of invoices, such as "Invoice No.",
+// followed by the values on the same rows or under them on the next rows. They are matched case-insensitively.
+type InvoiceAnchors struct {
+	Number  []string `json:"number,omitempty"`
+	Date    []string `json:"date,omitempty"`
+	DueDate []string `json:"due_date,omitempty"`
+	Total   []string `json:"total,omitempty"`
+}
+
+// DefaultInvoiceAnchors are the anchors used for the fields without anchors in templates.
+var DefaultInvoiceAnchors = InvoiceAnchors{
+	Number: []string{
+		"invoice number", "invoice no", "invoice nr", "invoice #", "invoice id", "inv no", "inv #",
+		"rechnungsnummer", "rechnungs-nr", "rechnung nr", "numéro de facture", "n° de facture", "facture n°",
+		"número de factura", "factura n", "fattura n", "factuurnummer",
+	},
+	Date: []string{
+		"invoice date", "date of issue", "issue date", "date", "rechnungsdatum", "datum", "date de facture",
+		"fecha", "data", "factuurdatum",
+	},
+	DueDate: []string{
+		"due date", "payment due", "due", "fälligkeitsdatum", "fällig", "zahlbar bis", "échéance",
+		"date d'échéance", "vencimiento", "scadenza", "vervaldatum",
+	},
+	Total: []string{
+		"total", "amount due", "balance due", "grand total", "total due", "gesamtbetrag", "rechnungsbetrag",
+		"summe", "gesamt", "montant total", "total ttc", "importe total", "totale", "totaal",
+	},
+}
+
+// InvoiceTemplate customizes the extraction for invoices of a vendor.
+type InvoiceTemplate struct {
+	Name string `json:"name"`
+
+	// Keywords identify invoices of the vendor, such as its name or VAT ID,
+	// all of which must appear in the document case-insensitively for the template to apply.
+	Keywords []string `json:"keywords"`
+
+	// Locale of amounts and dates, overriding the locale given to ParseInvoice if not empty.
+	Locale string `json:"locale,omitempty"`
+
+	// Anchors of the fields, replacing DefaultInvoiceAnchors of the fields specified.
+	Anchors InvoiceAnchors `json:"anchors"`
+}
+
+// ParseInvoice extracts the fields of the document of an invoice, applying the first of templates matching it.
+// Fields labeled by anchors, see InvoiceAnchors, are parsed from the text after the anchors on the same rows,
+// or under them on the next rows, see document.Document.Rows. The total is the largest amount labeled as a total,
+// while IBAN and VAT IDs are found anywhere by their formats. Amounts and dates are parsed in locale.
+func ParseInvoice(doc *document.Document, locale string, templates ...InvoiceTemplate) *Invoice {
+	invoice := &Invoice{VATIDs: []Field{}}
+	rows := doc.Rows()
+	anchors := DefaultInvoiceAnchors
+	if template, ok := matchTemplate(rows, templates); ok {
+		invoice.Template = template.Name
+		if template.Locale != "" {
+			locale = template.Locale
+		}
+		anchors = mergeAnchors(template.Anchors, anchors)
+	}
+	fields := map[string][]string{
+		"number": anchors.Number, "date": anchors.Date, "due_date": anchors.DueDate, "total": anchors.Total,
+	}
+	x := newAnchoredRows(rows, fields)
+
+	for _, f := range x.find("number", invoiceNumber) {
+		invoice.Number = f
+		break
+	}
+	dates := func(text string) (string, bool) {
+		_, found, ok := findDate(text, locale)
+		return found, ok
+	}
+	for _, date := range []struct {
+		field string
+		date  *DateField
+	}{{"date", &invoice.Date}, {"due_date", &invoice.DueDate}} {
+		for _, f := range x.find(date.field, dates) {
+			t, _, _ := findDate(f.Text, locale)
+			*date.date = DateField{Field: f, Time: t}
+			break
+		}
+	}
+	for _, f := range x.find("total", func(text string) (string, bool) {
+		amounts := FindMoney(text, locale)
+		if len(amounts) == 0 {
+			return "", false
+		}
+		return amounts[len(amounts)-1].Text, true
+	}) {
+		m, err := ParseMoney(f.Text, locale)
+		if err != nil {
+			continue
+		}
+		if invoice.Total.Text == "" || m.Float64() > invoice.Total.Money.Float64() {
+			invoice.Total = MoneyField{Field: f, Money: m}
+		}
+	}
+
+	labeledIBAN := Field{}
+	for _, row := range x.rows {
+		text := row.text
+		ids, valid := findIDs(text, ibanPattern, ValidIBAN)
+		for i, id := range ids {
+			start := strings.Index(text, id)
+			if valid[i] {
+				if invoice.IBAN.Text == "" {
+					invoice.IBAN = row.field(start, start+len(id), true)
+				}
+				// Not to find VAT IDs in IBAN.
+				text = text[:start] + strings.Repeat(" ", len(id)) + text[start+len(id):]
+			} else if labeledIBAN.Text == "" && strings.Contains(strings.ToUpper(text[:start]), "IBAN") {
+				labeledIBAN = row.field(start, start+len(id), false)
+			}
+		}
+		ids, valid = findIDs(text, vatPattern, ValidVATID)
+		for i, id := range ids {
+			if valid[i] {
+				start := strings.Index(text, id)
+				invoice.VATIDs = append(invoice.VATIDs, row.field(start, start+len(id), true))
+			}
+		}
+	}
+	if invoice.IBAN.Text == "" {
+		invoice.IBAN = labeledIBAN
+	}
+	return invoice
+}
+
+func matchTemplate(rows []document.Line, templates []InvoiceTemplate) (InvoiceTemplate, bool) {
+	if len(templates) == 0 {
+		return InvoiceTemplate{}, false
+	}
+	texts := make([]string, 0, len(rows))
+	for _, row := range rows {
+		texts = append(texts, row.Text())
+	}
+	text := strings.ToLower(strings.Join(texts, "\n"))
+	for _, template := range templates {
+		matched := len(template.Keywords) != 0
+		for _, keyword := range template.Keywords {
+			matched = matched && strings.Contains(text, strings.ToLower(keyword))
+		}
+		if matched {
+			return template, true
+		}
+	}
+	return InvoiceTemplate{}, false
+}
+
+func mergeAnchors(anchors, defaults InvoiceAnchors) InvoiceAnchors {
+	if len(anchors.Number) == 0 {
+		anchors.Number = defaults.Number
+	}
+	if len(anchors.Date) == 0 {
+		anchors.Date = defaults.Date
+	}
+	if len(anchors.DueDate) == 0 {
+		anchors.DueDate = defaults.DueDate
+	}
+	if len(anchors.Total) == 0 {
+		anchors.Total = defaults.Total
+	}
+	return anchors
+}
+
+// invoiceNumber returns the first token with digits, such as "INV-2024-001".
+func invoiceNumber(text string) (string, bool) {
+	for _, token := range strings.Fields(text) {
+		token = strings.TrimFunc(token, func(r rune) bool { return unicode.IsPunct(r) && r != '/' })
+		if strings.ContainsAny(token, "0123456789") && len(token) <= 32 {
+			return token, true
+		}
+	}
+	return "", false
+}
+
+// anchoredRow is a row with the positions of its words in its text.
+type anchoredRow struct {
+	document.Line
+	text   string
+	starts []int
+
+	// anchors found in the row, not overlapping each other.
+	anchors []anchor
+}
+
+type anchor struct {
+	field      string
+	start, end int
+}
+
+type anchoredRows struct {
+	rows []anchoredRow
+}
+
+var anchorSeparators = regexp.MustCompile(`^[\s:#.\-–]*`)
+
+func newAnchoredRows(rows []document.Line, fields map[string][]string) *anchoredRows {
+	type keyword struct {
+		field   string
+		pattern *regexp.Regexp
+	}
+	keywords := []keyword{}
+	for field, anchors := range fields {
+		for _, a := range anchors {
+			keywords = append(keywords, keyword{field, regexp.MustCompile(`(?i)` + regexp.QuoteMeta(a))})
+		}
+	}
+	x := &anchoredRows{}
+	for _, line := range rows {
+		row := anchoredRow{Line: line}
+		for i, word := range line.Words {
+			if i > 0 {
+				row.text += " "
+			}
+			row.starts = append(row.starts, len(row.text))
+			row.text += word.Text
+		}
+		found := []anchor{}
+		for _, k := range keywords {
+			for _, loc := range k.pattern.FindAllStringIndex(row.text, -1) {
+				if isWordBoundary(row.text, loc[0], loc[1]-loc[0]) {
+					found = append(found, anchor{field: k.field, start: loc[0], end: loc[1]})
+				}
+			}
+		}
+		// Anchors in longer ones are dropped, e.g. "date" in "due date".
+		for _, a := range found {
+			contained := false
+			for _, b := range found {
+				if b.start <= a.start && a.end <= b.end && b.end-b.start > a.end-a.start {
+					contained = true
+				}
+			}
+			if !contained {
+				row.anchors = append(row.anchors, a)
+			}
+		}
+		x.rows = append(x.rows, row)
+	}
+	return x
+}
+
+// find returns the values of the field found by extract, which returns the substring of the value
+// in the text after its anchor, from the top of the document.
+func (x *anchoredRows) find(field string, extract func(text string) (string, bool)) []Field {
+	fields := []Field{}
+	for i, row := range x.rows {
+		for _, a := range row.anchors {
+			if a.field != field {
+				continue
+			}
+			offset := a.end + len(anchorSeparators.FindString(row.text[a.end:]))
+			// The value ends at the next anchor, e.g. "Invoice No: 123 Date: 2024-01-15".
+			end := len(row.text)
+			for _, b := range row.anchors {
+				if b.start >= offset && b.start < end {
+					end = b.start
+				}
+			}
+			rest := row.text[offset:end]
+			if value, ok := extract(rest); ok {
+				start := offset + strings.Index(rest, value)
+				fields = append(fields, row.field(start, start+len(value), true))
+				continue
+			}
+			if i+1 < len(x.rows) {
+				if f, ok := x.rows[i+1].under(row.anchorBox(a), extract); ok {
+					fields = append(fields, f)
+				}
+			}
+		}
+	}
+	return fields
+}
+
+// field returns the Field of the text between start and end, with the words overlapping it.
+func (row anchoredRow) field(start, end int, valid bool) Field {
+	f := Field{Text: row.text[start:end], Confidence: 100, Valid: valid}
+	for i, word := range row.Words {
+		if row.starts[i] < end && row.starts[i]+len(word.Text) > start {
+			f.Box = f.Box.Union(word.Box)
+			if word.Confidence < f.Confidence {
+				f.Confidence = word.Confidence
+			}
+		}
+	}
+	return f
+}
+
+func (row anchoredRow) anchorBox(a anchor) image.Rectangle {
+	return row.field(a.start, a.end, true).Box
+}
+
+// under extracts the value from the words starting under or right of the left edge of box.
+func (row anchoredRow) under(box image.Rectangle, extract func(text string) (string, bool)) (Field, bool) {
+	for i, word := range row.Words {
+		if word.Box.Max.X <= box.Min.X {
+			continue
+		}
+		rest := row.text[row.starts[i]:]
+		if value, ok := extract(rest); ok {
+			start := row.starts[i] + strings.Index(rest, value)
+			return row.field(start, start+len(value), true), true
+		}
+		break
+	}
+	return Field{}, false
+}
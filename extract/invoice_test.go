@@ -0,0 +1,115 @@
+package extract
+
+import (
+	"image"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2/document"
+	. "github.com/otiai10/mint"
+)
+
+// invoiceDocument builds a document of rows, each of which is words with confidence 90 but "?"-suffixed ones with 40.
+func invoiceDocument(rows ...string) *document.Document {
+	b := &document.Builder{}
+	for i, row := range rows {
+		b.Line(document.Line{Box: image.Rect(0, i*20, 500, i*20+16)})
+		x := 0
+		for _, text := range strings.Fields(row) {
+			conf := 90.0
+			if len(text) > 1 && text[len(text)-1] == '?' {
+				text, conf = text[:len(text)-1], 40
+			}
+			b.Word(document.Word{Box: image.Rect(x, i*20, x+10*len(text), i*20+16), Text: text, Confidence: conf})
+			x += 10*len(text) + 10
+		}
+	}
+	return b.Document()
+}
+
+func TestParseInvoice(t *testing.T) {
+	doc := invoiceDocument(
+		"ACME GmbH VAT ID: DE123456789",
+		"Invoice No.: INV-2024-0042? Date: 15.01.2024",
+		"Due Date",
+		"14.02.2024",
+		"Net amount 100,00 €",
+		"VAT 19% 19,00 €",
+		"Total 119,00 €",
+		"IBAN: DE89 3704 0044 0532 0130 00",
+		"Customer VAT: FR 12 345678901",
+	)
+	invoice := ParseInvoice(doc, "de-DE")
+	Expect(t, invoice.Template).ToBe("")
+	Expect(t, invoice.Number.Text).ToBe("INV-2024-0042")
+	Expect(t, invoice.Number.Confidence).ToBe(40.0)
+	Expect(t, invoice.Date.Time).ToBe(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	Expect(t, invoice.DueDate.Time).ToBe(time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC))
+	Expect(t, invoice.DueDate.Box).ToBe(image.Rect(0, 60, 100, 76))
+	Expect(t, invoice.Total.Money.String()).ToBe("119.00 EUR")
+	Expect(t, invoice.IBAN.Text).ToBe("DE89 3704 0044 0532 0130 00")
+	Expect(t, invoice.IBAN.Valid).ToBe(true)
+	Expect(t, len(invoice.VATIDs)).ToBe(2)
+	Expect(t, invoice.VATIDs[0].Text).ToBe("DE123456789")
+	Expect(t, invoice.VATIDs[1].Text).ToBe("FR 12 345678901")
+
+	When(t, "a template of the vendor matches", func(t *testing.T) {
+		doc := invoiceDocument(
+			"Example Corp",
+			"Ref 2024/77 Issued 01/15/2024",
+			"Please pay $1,250.00",
+			"IBAN GB82 WEST 1234 5698 7654 33",
+		)
+		invoice := ParseInvoice(doc, "de-DE",
+			InvoiceTemplate{Name: "other", Keywords: []string{"Other Corp"}},
+			InvoiceTemplate{Name: "example", Keywords: []string{"example corp"}, Locale: "en-US", Anchors: InvoiceAnchors{
+				Number: []string{"ref"}, Date: []string{"issued"}, Total: []string{"please pay"},
+			}},
+		)
+		Expect(t, invoice.Template).ToBe("example")
+		Expect(t, invoice.Number.Text).ToBe("2024/77")
+		Expect(t, invoice.Date.Time).ToBe(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+		Expect(t, invoice.Total.Money.String()).ToBe("1250.00 USD")
+		Expect(t, invoice.IBAN.Valid).ToBe(false)
+		Expect(t, invoice.IBAN.Text).ToBe("GB82 WEST 1234 5698 7654 33")
+	})
+}
+
+func TestValidIBAN(t *testing.T) {
+	Expect(t, ValidIBAN("DE89 3704 0044 0532 0130 00")).ToBe(true)
+	Expect(t, ValidIBAN("GB29NWBK60161331926819")).ToBe(true)
+	Expect(t, ValidIBAN("GB29NWBK60161331926818")).ToBe(false)
+	Expect(t, ValidIBAN("DE89370400440532013")).ToBe(false)
+}
+
+func TestValidVATID(t *testing.T) {
+	Expect(t, ValidVATID("DE123456789")).ToBe(true)
+	Expect(t, ValidVATID("ATU12345678")).ToBe(true)
+	Expect(t, ValidVATID("NL123456789B01")).ToBe(true)
+	Expect(t, ValidVATID("DE12345678")).ToBe(false)
+	Expect(t, ValidVATID("US123456789")).ToBe(false)
+}
+
+func TestParseDate(t *testing.T) {
+	for text, want := range map[string]time.Time{
+		"on 2024-01-15":          time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		"15.01.2024":             time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		"03/04/24":               time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC),
+		"01/15/2024":             time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		"15 Jan 2024":            time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		"January 15, 2024":       time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		"3. März 2024":           time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC),
+		"le 1 août 2024":         time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+		"31.02.2024 or 1.3.2024": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		date, err := ParseDate(text, "de-DE")
+		Expect(t, err).ToBe(nil)
+		Expect(t, date).ToBe(want)
+	}
+	date, err := ParseDate("03/04/24", "en-US")
+	Expect(t, err).ToBe(nil)
+	Expect(t, date).ToBe(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC))
+	_, err = ParseDate("no date", "")
+	Expect(t, err).ToBe(ErrNoDate)
+}
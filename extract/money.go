@@ -0,0 +1,299 @@
+// Package extract extracts typed values, such as amounts of money, from recognized text.
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount of money found in text.
+type Money struct {
+	// Units is the amount multiplied by 10^Scale, e.g. 123456 with Scale 2 for 1234.56,
+	// to keep the amount exact.
+	Units int64 `json:"units"`
+	Scale int   `json:"scale"`
+
+	// Currency is the ISO 4217 code detected from the symbol or code around the amount,
+	// empty if there's none.
+	Currency string `json:"currency,omitempty"`
+
+	// Text is the text the amount is parsed from.
+	Text string `json:"text"`
+
+	// Ambiguous is true if the separators are ambiguous without locale, such as "1,234"
+	// meaning either 1234 or 1.234. Such amounts are parsed taking the separator as the grouping.
+	Ambiguous bool `json:"ambiguous,omitempty"`
+}
+
+// Float64 returns the amount as a float, which may lose the exactness.
+func (m Money) Float64() float64 {
+	return float64(m.Units) / math.Pow10(m.Scale)
+}
+
+// String returns the amount with the decimal point and the currency, such as "1234.56 EUR".
+func (m Money) String() string {
+	sign, units := "", m.Units
+	if units < 0 {
+		sign, units = "-", -units
+	}
+	s := strconv.FormatInt(units, 10)
+	if m.Scale > 0 {
+		if len(s) <= m.Scale {
+			s = strings.Repeat("0", m.Scale-len(s)+1) + s
+		}
+		s = s[:len(s)-m.Scale] + "." + s[len(s)-m.Scale:]
+	}
+	if m.Currency != "" {
+		return sign + s + " " + m.Currency
+	}
+	return sign + s
+}
+
+// NumberFormat is the decimal separator and the grouping separator of numbers in a locale.
+type NumberFormat struct {
+	Decimal rune
+	Group   rune
+}
+
+var (
+	// FormatPoint is 1,234.56, e.g. of English, Chinese and Japanese.
+	FormatPoint = NumberFormat{Decimal: '.', Group: ','}
+	// FormatComma is 1.234,56, e.g. of German, Spanish and Italian.
+	FormatComma = NumberFormat{Decimal: ',', Group: '.'}
+	// FormatSpace is 1 234,56, e.g. of French, Polish and Russian.
+	FormatSpace = NumberFormat{Decimal: ',', Group: ' '}
+	// FormatApostrophe is 1'234.56, of Switzerland.
+	FormatApostrophe = NumberFormat{Decimal: '.', Group: '\''}
+)
+
+// localeFormats maps locales, or their languages, to their number formats.
+var localeFormats = map[string]NumberFormat{
+	"en": FormatPoint, "ja": FormatPoint, "zh": FormatPoint, "ko": FormatPoint, "he": FormatPoint, "th": FormatPoint,
+	"de": FormatComma, "es": FormatComma, "it": FormatComma, "nl": FormatComma, "pt": FormatComma, "id": FormatComma,
+	"tr": FormatComma, "da": FormatComma, "el": FormatComma, "ro": FormatComma, "hr": FormatComma, "sl": FormatComma,
+	"fr": FormatSpace, "ru": FormatSpace, "pl": FormatSpace, "cs": FormatSpace, "sk": FormatSpace, "sv": FormatSpace,
+	"nb": FormatSpace, "no": FormatSpace, "fi": FormatSpace, "uk": FormatSpace, "hu": FormatSpace, "bg": FormatSpace,
+	"pt-PT": FormatSpace, "de-CH": FormatApostrophe, "fr-CH": FormatApostrophe, "it-CH": FormatApostrophe,
+}
+
+// LocaleFormat returns the number format of the locale, such as "de-DE" or "fr", and whether it's known.
+func LocaleFormat(locale string) (NumberFormat, bool) {
+	locale = strings.ReplaceAll(locale, "_", "-")
+	if format, ok := localeFormats[locale]; ok {
+		return format, true
+	}
+	lang := strings.SplitN(locale, "-", 2)[0]
+	format, ok := localeFormats[strings.ToLower(lang)]
+	return format, ok
+}
+
+// currencySymbols maps symbols of currencies to ISO 4217 codes, longer ones first to match greedily.
+var currencySymbols = []struct{ symbol, code string }{
+	{"US$", "USD"}, {"CA$", "CAD"}, {"AU$", "AUD"}, {"NZ$", "NZD"}, {"HK$", "HKD"}, {"R$", "BRL"},
+	{"C$", "CAD"}, {"A$", "AUD"}, {"zł", "PLN"}, {"Kč", "CZK"}, {"kr", "SEK"}, {"Fr.", "CHF"},
+	{"$", "USD"}, {"€", "EUR"}, {"£", "GBP"}, {"¥", "JPY"}, {"￥", "JPY"}, {"₹", "INR"}, {"₩", "KRW"},
+	{"₽", "RUB"}, {"₺", "TRY"}, {"₪", "ILS"}, {"₫", "VND"}, {"฿", "THB"}, {"₱", "PHP"},
+}
+
+// currencyCodes are ISO 4217 codes of currencies commonly written in documents.
+var currencyCodes = []string{
+	"AED", "ARS", "AUD", "BGN", "BRL", "CAD", "CHF", "CLP", "CNY", "COP", "CZK", "DKK", "EGP", "EUR", "GBP",
+	"HKD", "HUF", "IDR", "ILS", "INR", "ISK", "JPY", "KRW", "MXN", "MYR", "NGN", "NOK", "NZD", "PHP", "PKR",
+	"PLN", "RON", "RUB", "SAR", "SEK", "SGD", "THB", "TRY", "TWD", "UAH", "USD", "VND", "ZAR",
+}
+
+var currencyPattern = func() string {
+	alternatives := []string{`\b(?:` + strings.Join(currencyCodes, "|") + `)\b`}
+	for _, c := range currencySymbols {
+		alternatives = append(alternatives, regexp.QuoteMeta(c.symbol))
+	}
+	return "(?:" + strings.Join(alternatives, "|") + ")"
+}()
+
+// spaces are the space characters used as the grouping separator, i.e. space, no-break space and narrow no-break space.
+const spaces = "\u0020\u00a0\u202f"
+
+// ErrNoAmount is returned by ParseMoney if the text has no amount.
+var ErrNoAmount = errors.New("no amount found")
+
+// moneyPattern returns the pattern of amounts with optional currencies around, for the format.
+// Spaces are allowed in numbers only if the format groups digits by spaces,
+// not to join separate numbers, such as quantities and prices in a line.
+func moneyPattern(format *NumberFormat) *regexp.Regexp {
+	separators := `.,'\x{00a0}\x{202f}`
+	if format != nil && format.Group == ' ' {
+		separators += ` `
+	}
+	number := `[-+]?\d(?:[\d` + separators + `]*\d)?`
+	return regexp.MustCompile(`(` + currencyPattern + `)?[ \x{00a0}]?(` + number + `)(?:[ \x{00a0}]?(` + currencyPattern + `))?`)
+}
+
+var (
+	inferringPattern = moneyPattern(nil)
+	spacedPattern    = moneyPattern(&FormatSpace)
+)
+
+// FindMoney finds all the amounts in text, written in the number format of locale,
+// or inferred from the separators if locale is empty or unknown, see Money.Ambiguous.
+// Numbers not parsable as amounts, such as dates with dots, are skipped.
+func FindMoney(text string, locale string) []Money {
+	format, pattern := findFormat(locale)
+	found := []Money{}
+	for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+		number := text[match[4]:match[5]]
+		// A sign right after a digit or letter is a hyphen, such as of dates and codes.
+		if match[4] > 0 && strings.ContainsAny(number[:1], "+-") && isAlnum(text[match[4]-1]) {
+			continue
+		}
+		m, err := parseAmount(number, format)
+		if err != nil {
+			continue
+		}
+		m.Text = strings.TrimSpace(text[match[0]:match[1]])
+		if match[2] >= 0 {
+			m.Currency = currencyCode(text[match[2]:match[3]])
+		}
+		if m.Currency == "" && match[6] >= 0 {
+			m.Currency = currencyCode(text[match[6]:match[7]])
+		}
+		found = append(found, m)
+	}
+	return found
+}
+
+func isAlnum(b byte) bool {
+	return ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+// ParseMoney parses text holding a single amount, such as "€1.234,56" or "1,234.56 USD", see FindMoney.
+func ParseMoney(text string, locale string) (Money, error) {
+	amounts := FindMoney(text, locale)
+	switch len(amounts) {
+	case 0:
+		return Money{}, ErrNoAmount
+	case 1:
+		return amounts[0], nil
+	}
+	return Money{}, fmt.Errorf("%d amounts found in %q", len(amounts), text)
+}
+
+func findFormat(locale string) (*NumberFormat, *regexp.Regexp) {
+	format, ok := LocaleFormat(locale)
+	if !ok {
+		return nil, inferringPattern
+	}
+	if format.Group == ' ' {
+		return &format, spacedPattern
+	}
+	return &format, inferringPattern
+}
+
+func currencyCode(s string) string {
+	for _, c := range currencySymbols {
+		if s == c.symbol {
+			return c.code
+		}
+	}
+	for _, code := range currencyCodes {
+		if s == code {
+			return s
+		}
+	}
+	return ""
+}
+
+// parseAmount parses the number in the format, or inferring the format if nil.
+func parseAmount(number string, format *NumberFormat) (Money, error) {
+	m := Money{}
+	negative := strings.HasPrefix(number, "-")
+	number = strings.TrimLeft(number, "+-")
+	if format == nil {
+		var err error
+		if format, m.Ambiguous, err = inferFormat(number); err != nil {
+			return m, err
+		}
+	}
+	integer, fraction := number, ""
+	if i := strings.LastIndexFunc(number, func(r rune) bool { return r == format.Decimal }); i >= 0 {
+		integer, fraction = number[:i], number[i+1:]
+	}
+	if strings.ContainsAny(fraction, ".,'"+spaces) {
+		return m, fmt.Errorf("invalid fraction: %s", number)
+	}
+	digits, err := ungroup(integer, format.Group)
+	if err != nil {
+		return m, err
+	}
+	if len(digits)+len(fraction) > 18 {
+		return m, fmt.Errorf("too many digits: %s", number)
+	}
+	m.Units, err = strconv.ParseInt(digits+fraction, 10, 64)
+	if err != nil {
+		return m, err
+	}
+	m.Scale = len(fraction)
+	if negative {
+		m.Units = -m.Units
+	}
+	return m, nil
+}
+
+// ungroup removes the grouping separators from the integer part, which must group digits by three.
+func ungroup(integer string, group rune) (string, error) {
+	isGroup := func(r rune) bool {
+		return r == group || r == '\u00a0' || r == '\u202f' || (group == ' ' && r == ' ')
+	}
+	groups := strings.FieldsFunc(integer, isGroup)
+	if len(groups) == 0 {
+		return "", fmt.Errorf("invalid number: %s", integer)
+	}
+	for i, g := range groups {
+		if strings.Trim(g, "0123456789") != "" || (i > 0 && len(g) != 3) || (i == 0 && len(groups) > 1 && len(g) > 3) {
+			return "", fmt.Errorf("invalid grouping: %s", integer)
+		}
+	}
+	return strings.Join(groups, ""), nil
+}
+
+// inferFormat infers the number format from the separators of the number.
+// The last one of points and commas is the decimal separator if both are used, and
+// the one used more than once, or followed by other than three digits, is the grouping or the decimal separator.
+func inferFormat(number string) (*NumberFormat, bool, error) {
+	if strings.Contains(number, "'") {
+		return &FormatApostrophe, false, nil
+	}
+	points, commas := strings.Count(number, "."), strings.Count(number, ",")
+	switch {
+	case points > 0 && commas > 0:
+		if strings.LastIndex(number, ".") > strings.LastIndex(number, ",") {
+			return &FormatPoint, false, nil
+		}
+		return &FormatComma, false, nil
+	case points == 0 && commas == 0:
+		return &FormatPoint, false, nil
+	}
+	separator, count := ".", points
+	if commas > 0 {
+		separator, count = ",", commas
+	}
+	decimal := &FormatPoint
+	if separator == "," {
+		decimal = &FormatComma
+	}
+	grouping := &FormatComma
+	if separator == "," {
+		grouping = &FormatPoint
+	}
+	if count > 1 {
+		return grouping, false, nil
+	}
+	if digits := len(number) - strings.LastIndex(number, separator) - 1; digits != 3 {
+		return decimal, false, nil
+	}
+	// e.g. "1,234" or "1.234"
+	return grouping, true, nil
+}
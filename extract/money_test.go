@@ -0,0 +1,75 @@
+package extract
+
+import (
+	"testing"
+
+	. "github.com/otiai10/mint"
+)
+
+func TestParseMoney(t *testing.T) {
+	m, err := ParseMoney("Total: €1.234,56", "")
+	Expect(t, err).ToBe(nil)
+	Expect(t, m.String()).ToBe("1234.56 EUR")
+	Expect(t, m.Units).ToBe(int64(123456))
+	Expect(t, m.Scale).ToBe(2)
+	Expect(t, m.Ambiguous).ToBe(false)
+
+	m, err = ParseMoney("1,234.56 USD", "")
+	Expect(t, err).ToBe(nil)
+	Expect(t, m.String()).ToBe("1234.56 USD")
+
+	m, err = ParseMoney("CHF 1'234.50", "")
+	Expect(t, err).ToBe(nil)
+	Expect(t, m.String()).ToBe("1234.50 CHF")
+
+	m, err = ParseMoney("-12,5 zł", "")
+	Expect(t, err).ToBe(nil)
+	Expect(t, m.String()).ToBe("-12.5 PLN")
+
+	_, err = ParseMoney("no amount", "")
+	Expect(t, err).ToBe(ErrNoAmount)
+
+	When(t, "separator is ambiguous", func(t *testing.T) {
+		m, err := ParseMoney("$1,234", "")
+		Expect(t, err).ToBe(nil)
+		Expect(t, m.Ambiguous).ToBe(true)
+		Expect(t, m.String()).ToBe("1234 USD")
+
+		Because(t, "locale resolves it", func(t *testing.T) {
+			m, err := ParseMoney("1,234 €", "de-DE")
+			Expect(t, err).ToBe(nil)
+			Expect(t, m.Ambiguous).ToBe(false)
+			Expect(t, m.String()).ToBe("1.234 EUR")
+		})
+	})
+
+	When(t, "locale groups digits by spaces", func(t *testing.T) {
+		m, err := ParseMoney("1 234,56 €", "fr_FR")
+		Expect(t, err).ToBe(nil)
+		Expect(t, m.String()).ToBe("1234.56 EUR")
+	})
+}
+
+func TestFindMoney(t *testing.T) {
+	amounts := FindMoney("2 x Coffee 3.50\nVAT 20% 1.17\nTOTAL USD 7.00\n2024-01-05", "en-US")
+	Expect(t, len(amounts)).ToBe(6)
+	Expect(t, amounts[1].String()).ToBe("3.50")
+	Expect(t, amounts[4].String()).ToBe("7.00 USD")
+	Expect(t, amounts[4].Text).ToBe("USD 7.00")
+	Expect(t, amounts[5].String()).ToBe("2024")
+
+	Because(t, "invalid grouping is not an amount", func(t *testing.T) {
+		Expect(t, len(FindMoney("12,34,567.00", "en"))).ToBe(0)
+	})
+}
+
+func TestLocaleFormat(t *testing.T) {
+	format, ok := LocaleFormat("de-CH")
+	Expect(t, ok).ToBe(true)
+	Expect(t, format).ToBe(FormatApostrophe)
+	format, ok = LocaleFormat("pt-BR")
+	Expect(t, ok).ToBe(true)
+	Expect(t, format).ToBe(FormatComma)
+	_, ok = LocaleFormat("xx")
+	Expect(t, ok).ToBe(false)
+}
@@ -0,0 +1,183 @@
+package extract
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// PIIKind is the kind of personally identifiable information.
+type PIIKind int
+
+const (
+	// PIIEmail is an email address.
+	PIIEmail PIIKind = iota + 1
+	// PIIPhone is a phone number of 8 to 15 digits, or 7 with the country code or the area code in parentheses.
+	PIIPhone
+	// PIINationalID is a national identification number: a Social Security number of the US,
+	// a National Insurance number of the UK or a Resident Identity Card number of China.
+	PIINationalID
+	// PIIIBAN is a valid IBAN, see ValidIBAN.
+	PIIIBAN
+)
+
+func (kind PIIKind) String() string {
+	switch kind {
+	case PIIEmail:
+		return "email"
+	case PIIPhone:
+		return "phone"
+	case PIINationalID:
+		return "national_id"
+	case PIIIBAN:
+		return "iban"
+	}
+	return "unknown"
+}
+
+// PIISpan is where personally identifiable information is in a text, by byte offsets from Start to End.
+type PIISpan struct {
+	Kind  PIIKind `json:"kind"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[\p{L}\p{N}._%+-]+@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)*\.\p{L}{2,}`)
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?)?\d{2,4}(?:[ .-]?\d{2,4}){1,4}`)
+	ssnPattern   = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+	ninoPattern  = regexp.MustCompile(`\b[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z] ?\d{2} ?\d{2} ?\d{2} ?[A-D]\b`)
+	ricPattern   = regexp.MustCompile(`\b\d{17}[0-9Xx]\b`)
+)
+
+// FindPII returns the spans of personally identifiable information in the text, in order of Start.
+// Spans don't overlap: national IDs and IBAN take precedence over phone numbers of the same digits.
+func FindPII(text string) []PIISpan {
+	spans := []PIISpan{}
+	add := func(kind PIIKind, start, end int) {
+		for _, span := range spans {
+			if start < span.End && span.Start < end {
+				return
+			}
+		}
+		spans = append(spans, PIISpan{Kind: kind, Start: start, End: end})
+	}
+	for _, loc := range ssnPattern.FindAllStringIndex(text, -1) {
+		if validSSN(text[loc[0]:loc[1]]) {
+			add(PIINationalID, loc[0], loc[1])
+		}
+	}
+	for _, loc := range ninoPattern.FindAllStringIndex(text, -1) {
+		if validNINO(text[loc[0]:loc[1]]) {
+			add(PIINationalID, loc[0], loc[1])
+		}
+	}
+	for _, loc := range ricPattern.FindAllStringIndex(text, -1) {
+		if validRIC(text[loc[0]:loc[1]]) {
+			add(PIINationalID, loc[0], loc[1])
+		}
+	}
+	for _, loc := range ibanPattern.FindAllStringIndex(text, -1) {
+		ids, validities := findIDs(text[loc[0]:loc[1]], ibanPattern, ValidIBAN)
+		if len(ids) == 1 && validities[0] {
+			add(PIIIBAN, loc[0], loc[0]+len(ids[0]))
+		}
+	}
+	for _, loc := range emailPattern.FindAllStringIndex(text, -1) {
+		add(PIIEmail, loc[0], loc[1])
+	}
+	for _, loc := range phonePattern.FindAllStringIndex(text, -1) {
+		if validPhone(text, loc[0], loc[1]) {
+			add(PIIPhone, loc[0], loc[1])
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	return spans
+}
+
+// MaskPII replaces the letters and digits of personally identifiable information in the text by "*",
+// keeping separators such as "@", "-" and spaces, and returns the masked text along with the spans masked,
+// so that texts can be logged and stored under privacy constraints. The spans are of the masked text,
+// which are the same as of the original for ASCII.
+func MaskPII(text string) (string, []PIISpan) {
+	spans := FindPII(text)
+	if len(spans) == 0 {
+		return text, spans
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	last, shift := 0, 0
+	for i, span := range spans {
+		b.WriteString(text[last:span.Start])
+		spans[i].Start += shift
+		for _, r := range text[span.Start:span.End] {
+			if r == '@' || r == '.' || r == '-' || r == '+' || r == '(' || r == ')' || r == ' ' {
+				b.WriteRune(r)
+				continue
+			}
+			b.WriteByte('*')
+			shift += 1 - utf8.RuneLen(r)
+		}
+		spans[i].End += shift
+		last = span.End
+	}
+	b.WriteString(text[last:])
+	return b.String(), spans
+}
+
+// validSSN rejects numbers the Social Security Administration never issues:
+// area 000, 666 or 900-999, group 00 and serial 0000.
+func validSSN(s string) bool {
+	area, group, serial := s[0:3], s[4:6], s[7:11]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+// validNINO rejects prefixes of National Insurance numbers never allocated.
+func validNINO(s string) bool {
+	switch strings.ToUpper(s[:2]) {
+	case "BG", "GB", "KN", "NK", "NT", "TN", "ZZ":
+		return false
+	}
+	return true
+}
+
+// validRIC validates the check digit of Resident Identity Card numbers of China by ISO 7064 MOD 11-2.
+func validRIC(s string) bool {
+	sum := 0
+	for i := 0; i < 17; i++ {
+		sum = (sum + int(s[i]-'0')) * 2 % 11
+	}
+	check := (12 - sum%11) % 11
+	last := s[17]
+	if check == 10 {
+		return last == 'X' || last == 'x'
+	}
+	return int(last-'0') == check
+}
+
+// validPhone reports whether the match of phonePattern is a phone number, rather than part of a longer number,
+// a date or an amount, by its digits, the characters around it and dates.
+func validPhone(text string, start, end int) bool {
+	if start > 0 && (isAlnum(text[start-1]) || strings.IndexByte(".,/:-", text[start-1]) >= 0) {
+		return false
+	}
+	if end < len(text) && (isAlnum(text[end]) || text[end] == '/' || text[end] == ',' && end+1 < len(text) && isAlnum(text[end+1])) {
+		return false
+	}
+	match := text[start:end]
+	digits := 0
+	for i := 0; i < len(match); i++ {
+		if '0' <= match[i] && match[i] <= '9' {
+			digits++
+		}
+	}
+	if digits > 15 {
+		return false
+	}
+	if _, date, ok := findDate(match, ""); ok && date == match {
+		return false
+	}
+	marked := match[0] == '+' || strings.IndexByte(match, '(') >= 0
+	return digits >= 8 || marked && digits >= 7
+}
@@ -0,0 +1,46 @@
+package extract
+
+import (
+	"testing"
+
+	. "github.com/otiai10/mint"
+)
+
+func TestFindPII(t *testing.T) {
+	text := "Contact jane.doe@example.com or +1 (555) 123-4567. SSN 123-45-6789, NI AB 12 34 56 C, ID 11010519491231002X."
+	spans := FindPII(text)
+	Expect(t, len(spans)).ToBe(5)
+	kinds := []PIIKind{PIIEmail, PIIPhone, PIINationalID, PIINationalID, PIINationalID}
+	values := []string{"jane.doe@example.com", "+1 (555) 123-4567", "123-45-6789", "AB 12 34 56 C", "11010519491231002X"}
+	for i, span := range spans {
+		Expect(t, span.Kind).ToBe(kinds[i])
+		Expect(t, text[span.Start:span.End]).ToBe(values[i])
+	}
+
+	When(t, "numbers aren't PII", func(t *testing.T) {
+		Expect(t, len(FindPII("Invoice 2024-05-12, total 1,234.56 EUR, qty 12 34"))).ToBe(0)
+		Expect(t, len(FindPII("ID 110105194912310021, NI QQ 12 34 56 C"))).ToBe(0)
+	})
+
+	When(t, "IBAN is valid", func(t *testing.T) {
+		spans := FindPII("Pay to DE89 3704 0044 0532 0130 00 by Friday")
+		Expect(t, len(spans)).ToBe(1)
+		Expect(t, spans[0].Kind).ToBe(PIIIBAN)
+	})
+}
+
+func TestMaskPII(t *testing.T) {
+	masked, spans := MaskPII("Mail jane@example.com, call 030 1234 5678.")
+	Expect(t, masked).ToBe("Mail ****@*******.***, call *** **** ****.")
+	Expect(t, len(spans)).ToBe(2)
+	Expect(t, masked[spans[1].Start:spans[1].End]).ToBe("*** **** ****")
+
+	// Spans are of the masked text for non-ASCII as well.
+	masked, spans = MaskPII("Mél: rené@exemple.fr!")
+	Expect(t, masked).ToBe("Mél: ****@*******.**!")
+	Expect(t, masked[spans[0].Start:spans[0].End]).ToBe("****@*******.**")
+
+	masked, spans = MaskPII("nothing to mask")
+	Expect(t, masked).ToBe("nothing to mask")
+	Expect(t, len(spans)).ToBe(0)
+}
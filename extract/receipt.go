@@ -0,0 +1,202 @@
+package extract
+
+import (
+	"errors"
+	"image"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chennqqi/gosseract/v2/document"
+)
+
+// Receipt is the content of a receipt, segmented into the header of the merchant, the items and the totals.
+type Receipt struct {
+	// Merchant is the first row of the header, usually the name of the store.
+	Merchant string `json:"merchant"`
+
+	// Header is the rows above the first item, such as the address and the date.
+	Header []string `json:"header"`
+
+	Items []ReceiptItem `json:"items"`
+
+	Subtotal *Money `json:"subtotal,omitempty"`
+	// Taxes are the amounts of taxes, one for each rate.
+	Taxes []Money `json:"taxes,omitempty"`
+	Total *Money  `json:"total,omitempty"`
+
+	// Footer is the rows below the total other than taxes, such as payments and greetings.
+	Footer []string `json:"footer"`
+}
+
+// ReceiptItem is a line item of a receipt.
+type ReceiptItem struct {
+	Description string `json:"description"`
+
+	// Quantity is 1 unless the row has a quantity, such as "2 x Coffee" or "Coffee 2 @ 3.50".
+	Quantity float64 `json:"quantity"`
+
+	// UnitPrice is the price of each, nil unless the row has one.
+	UnitPrice *Money `json:"unit_price,omitempty"`
+
+	Price Money `json:"price"`
+
+	Box image.Rectangle `json:"box"`
+
+	// Confidence is the lowest confidence of the words of the row.
+	Confidence float64 `json:"confidence"`
+}
+
+// ErrNoItems is returned by ParseReceipt if the document has no row with a price.
+var ErrNoItems = errors.New("no items found")
+
+// totalKeywords are the words of rows of totals, by the kinds of totals, compared in lower case.
+var totalKeywords = []struct {
+	kind     string
+	keywords []string
+}{
+	{"subtotal", []string{"subtotal", "sub total", "sub-total", "zwischensumme", "sous-total", "subtotale", "小計"}},
+	{"tax", []string{"tax", "vat", "gst", "hst", "mwst", "ust", "tva", "iva", "btw", "消費税", "税"}},
+	{"total", []string{"total", "amount due", "balance due", "grand total", "summe", "gesamt", "totale", "importe", "合計", "合计"}},
+}
+
+var (
+	leadingQuantity  = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)\s*(?:[xX×*]\s*|\s+)(\D.*)$`)
+	trailingQuantity = regexp.MustCompile(`^(.*?)\s*(\d+(?:[.,]\d+)?)\s*[xX×@*]\s*$`)
+)
+
+// ParseReceipt segments the document of a receipt by its rows, see document.Document.Rows.
+// Rows ending with a price, i.e. an amount with decimals or a currency, optionally followed by a tax code
+// such as "A", are items unless they have keywords of totals, such as "Subtotal", "VAT" and "Total".
+// Rows above the first item make the header, and rows between items without prices continue the
+// description of the item above, as wrapped descriptions. Amounts are parsed in locale, see FindMoney.
+func ParseReceipt(doc *document.Document, locale string) (*Receipt, error) {
+	receipt := &Receipt{Header: []string{}, Items: []ReceiptItem{}, Footer: []string{}}
+	for _, row := range doc.Rows() {
+		text := row.Text()
+		description, price, ok := rowPrice(text, locale)
+		if kind := totalKind(description); ok && kind != "" {
+			switch kind {
+			case "subtotal":
+				receipt.Subtotal = &price
+			case "tax":
+				receipt.Taxes = append(receipt.Taxes, price)
+			case "total":
+				if receipt.Total == nil {
+					receipt.Total = &price
+				} else {
+					receipt.Footer = append(receipt.Footer, text)
+				}
+			}
+			continue
+		}
+		switch {
+		case receipt.Total != nil:
+			receipt.Footer = append(receipt.Footer, text)
+		case ok:
+			receipt.Items = append(receipt.Items, receiptItem(row, description, price, locale))
+		case len(receipt.Items) == 0:
+			receipt.Header = append(receipt.Header, text)
+		default:
+			item := &receipt.Items[len(receipt.Items)-1]
+			item.Description = strings.TrimSpace(item.Description + " " + text)
+			item.Box = item.Box.Union(row.Box)
+			item.Confidence = minConfidence(item.Confidence, row)
+		}
+	}
+	if len(receipt.Items) == 0 {
+		return receipt, ErrNoItems
+	}
+	if len(receipt.Header) != 0 {
+		receipt.Merchant = receipt.Header[0]
+	}
+	return receipt, nil
+}
+
+// rowPrice splits the text of a row into the description and the price at its end.
+func rowPrice(text string, locale string) (string, Money, bool) {
+	amounts := FindMoney(text, locale)
+	if len(amounts) == 0 {
+		return text, Money{}, false
+	}
+	price := amounts[len(amounts)-1]
+	if price.Scale == 0 && price.Currency == "" {
+		return text, Money{}, false
+	}
+	i := strings.LastIndex(text, price.Text)
+	suffix := strings.TrimSpace(text[i+len(price.Text):])
+	if len([]rune(suffix)) > 2 || strings.ContainsAny(suffix, "0123456789") {
+		return text, Money{}, false
+	}
+	return strings.TrimSpace(text[:i]), price, true
+}
+
+func totalKind(description string) string {
+	description = strings.ToLower(description)
+	kind, first, length := "", len(description)+1, 0
+	for _, k := range totalKeywords {
+		for _, keyword := range k.keywords {
+			i := strings.Index(description, keyword)
+			if i < 0 || !isWordBoundary(description, i, len(keyword)) {
+				continue
+			}
+			// The first keyword wins, e.g. "Total incl. VAT" is the total, with the longest one at the same place,
+			// e.g. "Subtotal" is not the total.
+			if i < first || (i == first && len(keyword) > length) {
+				kind, first, length = k.kind, i, len(keyword)
+			}
+		}
+	}
+	return kind
+}
+
+// isWordBoundary reports whether s[i:i+n] is not a part of a longer word.
+// Keywords in scripts without spaces, such as Japanese, match anywhere.
+func isWordBoundary(s string, i, n int) bool {
+	if s[i] >= utf8.RuneSelf {
+		return true
+	}
+	before, _ := utf8.DecodeLastRuneInString(s[:i])
+	after, _ := utf8.DecodeRuneInString(s[i+n:])
+	return !unicode.IsLetter(before) && !unicode.IsLetter(after)
+}
+
+func receiptItem(row document.Line, description string, price Money, locale string) ReceiptItem {
+	item := ReceiptItem{Description: description, Quantity: 1, Price: price, Box: row.Box, Confidence: minConfidence(101, row)}
+	if m := leadingQuantity.FindStringSubmatch(description); m != nil {
+		item.Quantity, item.Description = parseQuantity(m[1]), strings.TrimSpace(m[2])
+	}
+	// A unit price at the end of the description, such as "Coffee 2 x 3.50" or "Coffee @ 3.50".
+	if amounts := FindMoney(item.Description, locale); len(amounts) != 0 {
+		unit := amounts[len(amounts)-1]
+		i := strings.LastIndex(item.Description, unit.Text)
+		if strings.TrimSpace(item.Description[i+len(unit.Text):]) == "" {
+			rest := strings.TrimSpace(item.Description[:i])
+			if m := trailingQuantity.FindStringSubmatch(rest); m != nil {
+				item.Description, item.Quantity, item.UnitPrice = strings.TrimSpace(m[1]), parseQuantity(m[2]), &unit
+			} else if strings.HasSuffix(rest, "@") {
+				item.Description, item.UnitPrice = strings.TrimSpace(strings.TrimSuffix(rest, "@")), &unit
+			}
+		}
+	}
+	return item
+}
+
+func parseQuantity(s string) float64 {
+	q, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	if err != nil || q == 0 {
+		return 1
+	}
+	return q
+}
+
+func minConfidence(confidence float64, row document.Line) float64 {
+	for _, word := range row.Words {
+		if word.Confidence < confidence {
+			confidence = word.Confidence
+		}
+	}
+	return confidence
+}
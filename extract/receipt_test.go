@@ -0,0 +1,87 @@
+package extract
+
+import (
+	"image"
+	"testing"
+
+	"github.com/chennqqi/gosseract/v2/document"
+	. "github.com/otiai10/mint"
+)
+
+// receiptDocument builds a document of rows, splitting the descriptions and the prices into separate blocks
+// as tesseract does for receipts with wide columns.
+func receiptDocument(rows ...[2]string) *document.Document {
+	b := &document.Builder{}
+	b.Block(document.Block{})
+	for i, row := range rows {
+		if row[0] == "" {
+			continue
+		}
+		b.Line(document.Line{Box: image.Rect(0, i*20, 200, i*20+16)})
+		b.Word(document.Word{Box: image.Rect(0, i*20, 200, i*20+16), Text: row[0], Confidence: 90})
+	}
+	b.Block(document.Block{})
+	for i, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		b.Line(document.Line{Box: image.Rect(300, i*20+2, 360, i*20+18)})
+		b.Word(document.Word{Box: image.Rect(300, i*20+2, 360, i*20+18), Text: row[1], Confidence: 80})
+	}
+	return b.Document()
+}
+
+func TestParseReceipt(t *testing.T) {
+	doc := receiptDocument(
+		[2]string{"CORNER CAFE", ""},
+		[2]string{"12 Main St.", ""},
+		[2]string{"2 x Latte", "7.00"},
+		[2]string{"Bagel with cream", "3.25 A"},
+		[2]string{"cheese", ""},
+		[2]string{"Cookie 3 @ 1.50", "4.50"},
+		[2]string{"Subtotal", "14.75"},
+		[2]string{"Tax 8%", "1.18"},
+		[2]string{"TOTAL", "$15.93"},
+		[2]string{"Cash", "20.00"},
+		[2]string{"Thank you!", ""},
+	)
+	receipt, err := ParseReceipt(doc, "en-US")
+	Expect(t, err).ToBe(nil)
+	Expect(t, receipt.Merchant).ToBe("CORNER CAFE")
+	Expect(t, receipt.Header).ToBe([]string{"CORNER CAFE", "12 Main St."})
+	Expect(t, len(receipt.Items)).ToBe(3)
+
+	Expect(t, receipt.Items[0].Description).ToBe("Latte")
+	Expect(t, receipt.Items[0].Quantity).ToBe(2.0)
+	Expect(t, receipt.Items[0].Price.String()).ToBe("7.00")
+	Expect(t, receipt.Items[0].Confidence).ToBe(80.0)
+
+	Expect(t, receipt.Items[1].Description).ToBe("Bagel with cream cheese")
+	Expect(t, receipt.Items[1].Quantity).ToBe(1.0)
+	Expect(t, receipt.Items[1].Price.String()).ToBe("3.25")
+	Expect(t, receipt.Items[1].Box).ToBe(image.Rect(0, 60, 360, 96))
+
+	Expect(t, receipt.Items[2].Description).ToBe("Cookie")
+	Expect(t, receipt.Items[2].Quantity).ToBe(3.0)
+	Expect(t, receipt.Items[2].UnitPrice.String()).ToBe("1.50")
+
+	Expect(t, receipt.Subtotal.String()).ToBe("14.75")
+	Expect(t, len(receipt.Taxes)).ToBe(1)
+	Expect(t, receipt.Taxes[0].String()).ToBe("1.18")
+	Expect(t, receipt.Total.String()).ToBe("15.93 USD")
+	Expect(t, receipt.Footer).ToBe([]string{"Cash 20.00", "Thank you!"})
+
+	When(t, "the receipt has no items", func(t *testing.T) {
+		_, err := ParseReceipt(receiptDocument([2]string{"Hello", ""}), "")
+		Expect(t, err).ToBe(ErrNoItems)
+	})
+}
+
+func TestTotalKind(t *testing.T) {
+	Expect(t, totalKind("TOTAL")).ToBe("total")
+	Expect(t, totalKind("Sub Total")).ToBe("subtotal")
+	Expect(t, totalKind("Total incl. VAT")).ToBe("total")
+	Expect(t, totalKind("MwSt 19%")).ToBe("tax")
+	Expect(t, totalKind("Mustard")).ToBe("")
+	Expect(t, totalKind("合計")).ToBe("total")
+}
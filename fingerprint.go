@@ -0,0 +1,91 @@
+package gosseract
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"math/bits"
+
+	// Register decoders for perceptual hashing.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// Fingerprint identifies contents of an image, see ImageFingerprint.
+type Fingerprint struct {
+	// Content is the hex-encoded SHA-256 of the image data, identical only for identical files.
+	Content string `json:"content"`
+	// Perceptual is the 64-bit difference hash (dHash) of the decoded pixels,
+	// which stays close for re-encoded, resized or slightly noisy scans of the same page.
+	// It's available only if HasPerceptual, i.e. the format is decodable by Go: PNG, JPEG or GIF,
+	// of DefaultMaxImagePixels at most.
+	Perceptual    uint64 `json:"perceptual,omitempty"`
+	HasPerceptual bool   `json:"has_perceptual"`
+}
+
+// ImageFingerprint calculates the fingerprint of image data, such as given to SetImageFromBytes,
+// so that ingestion pipelines can dedupe identical or near-identical scans before spending OCR time.
+func ImageFingerprint(data []byte) (Fingerprint, error) {
+	if len(data) == 0 {
+		return Fingerprint{}, fmt.Errorf("image data cannot be empty")
+	}
+	sum := sha256.Sum256(data)
+	fp := Fingerprint{Content: hex.EncodeToString(sum[:])}
+	if img, _, err := decodeLimited(data, DefaultMaxImagePixels); err == nil {
+		fp.Perceptual = differenceHash(img)
+		fp.HasPerceptual = true
+	}
+	return fp, nil
+}
+
+// Distance returns the number of different bits between perceptual hashes, from 0 to 64,
+// or -1 if either of them doesn't have one. Scans of the same page are usually within 10.
+func (fp Fingerprint) Distance(other Fingerprint) int {
+	if !fp.HasPerceptual || !other.HasPerceptual {
+		return -1
+	}
+	return bits.OnesCount64(fp.Perceptual ^ other.Perceptual)
+}
+
+// differenceHash shrinks the image to 9x8 grayscale cells,
+// and sets a bit for each cell brighter than its right neighbor.
+func differenceHash(img image.Image) uint64 {
+	const w, h = 9, 8
+	// Sampling up to 16x16 pixels per cell is enough for averages, and keeps large photos fast.
+	const samples = 16
+	b := img.Bounds()
+	cells := [h][w]float64{}
+	for cy := 0; cy < h; cy++ {
+		y0, y1 := b.Min.Y+cy*b.Dy()/h, b.Min.Y+(cy+1)*b.Dy()/h
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for cx := 0; cx < w; cx++ {
+			x0, x1 := b.Min.X+cx*b.Dx()/w, b.Min.X+(cx+1)*b.Dx()/w
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			sum, n := 0.0, 0
+			for y := y0; y < y1; y += (y1-y0)/samples + 1 {
+				for x := x0; x < x1; x += (x1-x0)/samples + 1 {
+					r, g, b, _ := img.At(x, y).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+					n++
+				}
+			}
+			cells[cy][cx] = sum / float64(n)
+		}
+	}
+	var hash uint64
+	for cy := 0; cy < h; cy++ {
+		for cx := 0; cx < w-1; cx++ {
+			hash <<= 1
+			if cells[cy][cx] > cells[cy][cx+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
@@ -0,0 +1,125 @@
+// Package forms reads the marks of checkboxes and radio buttons at the zones declared by templates of forms,
+// which OCR alone can't answer, since there is no text in them.
+package forms
+
+import (
+	"image"
+	"image/color"
+)
+
+// Template declares the zones of a form, as they are on a page of Width x Height pixels.
+// Zones are scaled to pages of other sizes, such as scans at other resolutions.
+// Zero Width and Height take zones in the coordinates of the pages as they are.
+type Template struct {
+	Name       string     `json:"name"`
+	Width      int        `json:"width"`
+	Height     int        `json:"height"`
+	Checkboxes []Checkbox `json:"checkboxes"`
+}
+
+// Checkbox is the zone of a checkbox, or of a radio button of Group, which is the box of its printed border.
+// At most one radio button of the same Group is checked.
+type Checkbox struct {
+	Name  string          `json:"name"`
+	Box   image.Rectangle `json:"box"`
+	Group string          `json:"group,omitempty"`
+}
+
+// Mark is the state of a checkbox, found by Template.Marks.
+type Mark struct {
+	Name    string `json:"name"`
+	Checked bool   `json:"checked"`
+
+	// Confidence of Checked, from 0.5 for marks as faint as the threshold up to 1 for clear ones.
+	Confidence float64 `json:"confidence"`
+
+	// Fill is the fraction of the inside of the zone covered by ink.
+	Fill float64 `json:"fill"`
+
+	// Box is the zone on the page.
+	Box image.Rectangle `json:"box"`
+}
+
+const (
+	// checkboxInset is the fraction of the sides of zones left out from each side, not to count printed borders,
+	// nor borders of zones slightly misaligned on scans.
+	checkboxInset = 0.2
+	// checkedFill is the fill of checked boxes at least, such as crosses, ticks and fills.
+	checkedFill = 0.08
+	// darkness is the gray level below which pixels are ink.
+	darkness = 128
+)
+
+// Marks reads the checkboxes of the template on page, in order of Checkboxes.
+func (tmpl Template) Marks(page image.Image) []Mark {
+	bounds := page.Bounds()
+	marks := make([]Mark, 0, len(tmpl.Checkboxes))
+	best := map[string]int{}
+	for i, checkbox := range tmpl.Checkboxes {
+		box := tmpl.scale(checkbox.Box, bounds)
+		fill := inkFill(page, box)
+		mark := Mark{Name: checkbox.Name, Checked: fill >= checkedFill, Fill: fill, Box: box}
+		if mark.Checked && checkbox.Group != "" {
+			// Only the fullest radio button of the group is checked.
+			if j, ok := best[checkbox.Group]; !ok || marks[j].Fill < fill {
+				if ok {
+					marks[j].Checked = false
+				}
+				best[checkbox.Group] = i
+			} else {
+				mark.Checked = false
+			}
+		}
+		marks = append(marks, mark)
+	}
+	for i := range marks {
+		marks[i].Confidence = confidence(marks[i].Fill)
+		if marks[i].Checked != (marks[i].Fill >= checkedFill) {
+			// Marked, but less than another of the group, which is ambiguous.
+			marks[i].Confidence = 0.5
+		}
+	}
+	return marks
+}
+
+// scale maps the zone of the template to the page.
+func (tmpl Template) scale(box image.Rectangle, bounds image.Rectangle) image.Rectangle {
+	if tmpl.Width <= 0 || tmpl.Height <= 0 {
+		return box
+	}
+	sx, sy := float64(bounds.Dx())/float64(tmpl.Width), float64(bounds.Dy())/float64(tmpl.Height)
+	return image.Rect(
+		bounds.Min.X+int(float64(box.Min.X)*sx), bounds.Min.Y+int(float64(box.Min.Y)*sy),
+		bounds.Min.X+int(float64(box.Max.X)*sx), bounds.Min.Y+int(float64(box.Max.Y)*sy),
+	)
+}
+
+// inkFill returns the fraction of ink inside the zone, leaving out its borders.
+func inkFill(page image.Image, box image.Rectangle) float64 {
+	dx, dy := int(float64(box.Dx())*checkboxInset), int(float64(box.Dy())*checkboxInset)
+	inside := image.Rect(box.Min.X+dx, box.Min.Y+dy, box.Max.X-dx, box.Max.Y-dy).Intersect(page.Bounds())
+	if inside.Empty() {
+		return 0
+	}
+	ink := 0
+	for y := inside.Min.Y; y < inside.Max.Y; y++ {
+		for x := inside.Min.X; x < inside.Max.X; x++ {
+			if color.GrayModel.Convert(page.At(x, y)).(color.Gray).Y < darkness {
+				ink++
+			}
+		}
+	}
+	return float64(ink) / float64(inside.Dx()*inside.Dy())
+}
+
+// confidence is how far the fill is from the threshold, either way.
+func confidence(fill float64) float64 {
+	distance := (fill - checkedFill) / checkedFill
+	if distance < 0 {
+		distance = -distance
+	}
+	if distance > 1 {
+		distance = 1
+	}
+	return 0.5 + distance/2
+}
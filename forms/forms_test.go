@@ -0,0 +1,69 @@
+package forms
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	. "github.com/otiai10/mint"
+)
+
+// form draws a page of boxes of 20 x 20 at x of 10, 50, 90 and 130,
+// crossing the second, filling the third and scribbling a dot in the fourth.
+func form() *image.Gray {
+	page := image.NewGray(image.Rect(0, 0, 200, 40))
+	draw.Draw(page, page.Bounds(), image.White, image.Point{}, draw.Src)
+	for _, x := range []int{10, 50, 90, 130} {
+		for i := 0; i < 20; i++ {
+			page.SetGray(x+i, 10, color.Gray{})
+			page.SetGray(x+i, 29, color.Gray{})
+			page.SetGray(x, 10+i, color.Gray{})
+			page.SetGray(x+19, 10+i, color.Gray{})
+		}
+	}
+	for i := 2; i < 18; i++ {
+		page.SetGray(50+i, 10+i, color.Gray{})
+		page.SetGray(51+i, 10+i, color.Gray{})
+		page.SetGray(69-i, 10+i, color.Gray{})
+		page.SetGray(68-i, 10+i, color.Gray{})
+	}
+	draw.Draw(page, image.Rect(93, 13, 107, 27), image.Black, image.Point{}, draw.Src)
+	page.SetGray(140, 20, color.Gray{})
+	return page
+}
+
+func TestTemplate_Marks(t *testing.T) {
+	tmpl := Template{Checkboxes: []Checkbox{
+		{Name: "empty", Box: image.Rect(10, 10, 30, 30)},
+		{Name: "crossed", Box: image.Rect(50, 10, 70, 30)},
+		{Name: "filled", Box: image.Rect(90, 10, 110, 30)},
+		{Name: "dot", Box: image.Rect(130, 10, 150, 30)},
+	}}
+	marks := tmpl.Marks(form())
+	Expect(t, len(marks)).ToBe(4)
+	Expect(t, marks[0].Checked).ToBe(false)
+	Expect(t, marks[0].Confidence).ToBe(1.0)
+	Expect(t, marks[1].Checked).ToBe(true)
+	Expect(t, marks[2].Checked).ToBe(true)
+	Expect(t, marks[2].Confidence).ToBe(1.0)
+	Expect(t, marks[3].Checked).ToBe(false)
+	Expect(t, marks[3].Confidence < 1).ToBe(true)
+
+	When(t, "checkboxes are radio buttons of a group", func(t *testing.T) {
+		for i := range tmpl.Checkboxes {
+			tmpl.Checkboxes[i].Group = "choice"
+		}
+		marks := tmpl.Marks(form())
+		Expect(t, marks[1].Checked).ToBe(false)
+		Expect(t, marks[1].Confidence).ToBe(0.5)
+		Expect(t, marks[2].Checked).ToBe(true)
+	})
+
+	When(t, "the template is declared on a page of another size", func(t *testing.T) {
+		tmpl := Template{Width: 100, Height: 20, Checkboxes: []Checkbox{{Name: "filled", Box: image.Rect(45, 5, 55, 15)}}}
+		marks := tmpl.Marks(form())
+		Expect(t, marks[0].Box).ToBe(image.Rect(90, 10, 110, 30))
+		Expect(t, marks[0].Checked).ToBe(true)
+	})
+}
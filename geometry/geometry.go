@@ -0,0 +1,188 @@
+// Package geometry is the shapes of regions of images shared by the packages of gosseract, beyond image.Rectangle:
+// Quad of rotated rectangles, such as of words of document.Word, Polygon of outlines, such as of blocks of
+// document.Block, and LabeledRegion of regions annotated, such as zones, detections and regions to redact,
+// in JSON for external layout tools, along with drawing them on images to visualize and redact them.
+package geometry
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// Polygon is the vertices of a simple polygon in order, in pixels of the image.
+type Polygon []image.Point
+
+// Bounds returns the smallest rectangle containing the polygon, whose Max is exclusive as of image.Rectangle.
+func (p Polygon) Bounds() image.Rectangle {
+	if len(p) == 0 {
+		return image.Rectangle{}
+	}
+	r := image.Rectangle{Min: p[0], Max: p[0]}
+	for _, pt := range p[1:] {
+		if pt.X < r.Min.X {
+			r.Min.X = pt.X
+		}
+		if pt.Y < r.Min.Y {
+			r.Min.Y = pt.Y
+		}
+		if pt.X > r.Max.X {
+			r.Max.X = pt.X
+		}
+		if pt.Y > r.Max.Y {
+			r.Max.Y = pt.Y
+		}
+	}
+	return r
+}
+
+// Area returns the area of the polygon in pixels, by the shoelace formula.
+func (p Polygon) Area() float64 {
+	sum := 0
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return math.Abs(float64(sum)) / 2
+}
+
+// Contains reports whether the center of the pixel of pt is inside the polygon.
+func (p Polygon) Contains(pt image.Point) bool {
+	x, y := float64(pt.X)+0.5, float64(pt.Y)+0.5
+	inside := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if (float64(a.Y) > y) != (float64(b.Y) > y) &&
+			x < float64(b.X-a.X)*(y-float64(a.Y))/float64(b.Y-a.Y)+float64(a.X) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Add returns the polygon translated by d, such as from a crop into the page.
+func (p Polygon) Add(d image.Point) Polygon {
+	moved := make(Polygon, len(p))
+	for i, pt := range p {
+		moved[i] = pt.Add(d)
+	}
+	return moved
+}
+
+// Scale returns the polygon scaled by the factor, such as into images resized.
+func (p Polygon) Scale(factor float64) Polygon {
+	scaled := make(Polygon, len(p))
+	for i, pt := range p {
+		scaled[i] = image.Pt(int(math.Round(float64(pt.X)*factor)), int(math.Round(float64(pt.Y)*factor)))
+	}
+	return scaled
+}
+
+// Quad is the corners of a rotated rectangle, clockwise from the top-left of the text it encloses,
+// which are the corners of the rectangle for upright text, see QuadOf.
+type Quad [4]image.Point
+
+// QuadOf returns the quad of the rectangle, clockwise from the top-left.
+func QuadOf(r image.Rectangle) Quad {
+	return Quad{r.Min, image.Pt(r.Max.X, r.Min.Y), r.Max, image.Pt(r.Min.X, r.Max.Y)}
+}
+
+// Polygon returns the quad as a polygon.
+func (q Quad) Polygon() Polygon {
+	return Polygon(q[:])
+}
+
+// Bounds returns the smallest rectangle containing the quad.
+func (q Quad) Bounds() image.Rectangle {
+	return q.Polygon().Bounds()
+}
+
+// Angle returns the angle of the top edge of the quad in degrees, counterclockwise from the horizontal,
+// from -180 to 180, e.g. 90 for text rotated to read from bottom to top.
+func (q Quad) Angle() float64 {
+	return math.Atan2(float64(q[0].Y-q[1].Y), float64(q[1].X-q[0].X)) * 180 / math.Pi
+}
+
+// LabeledRegion is a region of an image annotated, such as a zone to recognize, a detection of a license plate,
+// a field of a form or personally identifiable information to redact.
+type LabeledRegion struct {
+	Label   string  `json:"label"`
+	Polygon Polygon `json:"polygon"`
+
+	// Confidence of detections from 0 to 100, zero if not detected.
+	Confidence float64 `json:"confidence,omitempty"`
+
+	// Attributes are the annotations of the region other than the label, such as the text recognized.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// RectRegion returns the region of the rectangle.
+func RectRegion(label string, r image.Rectangle) LabeledRegion {
+	return LabeledRegion{Label: label, Polygon: QuadOf(r).Polygon()}
+}
+
+// QuadRegion returns the region of the quad.
+func QuadRegion(label string, q Quad) LabeledRegion {
+	return LabeledRegion{Label: label, Polygon: q.Polygon()}
+}
+
+// Bounds returns the smallest rectangle containing the region.
+func (region LabeledRegion) Bounds() image.Rectangle {
+	return region.Polygon.Bounds()
+}
+
+// Fill paints the polygon on the image in the color, such as black to redact the region.
+func Fill(dst draw.Image, p Polygon, c color.Color) {
+	r := p.Bounds().Intersect(dst.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if p.Contains(image.Pt(x, y)) {
+				dst.Set(x, y, c)
+			}
+		}
+	}
+}
+
+// Outline draws the edges of the polygon on the image in the color, such as to visualize the regions recognized.
+func Outline(dst draw.Image, p Polygon, c color.Color) {
+	for i := range p {
+		line(dst, p[i], p[(i+1)%len(p)], c)
+	}
+}
+
+// line draws the line from a to b by Bresenham's algorithm.
+func line(dst draw.Image, a, b image.Point, c color.Color) {
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := 1, 1
+	if a.X > b.X {
+		sx = -1
+	}
+	if a.Y > b.Y {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		if a.In(dst.Bounds()) {
+			dst.Set(a.X, a.Y, c)
+		}
+		if a == b {
+			return
+		}
+		if 2*e >= dy {
+			e += dy
+			a.X += sx
+		}
+		if 2*e <= dx {
+			e += dx
+			a.Y += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
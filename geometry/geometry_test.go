@@ -0,0 +1,62 @@
+package geometry
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"testing"
+
+	. "github.com/otiai10/mint"
+)
+
+func TestPolygon(t *testing.T) {
+	// A triangle of the left half of the square of 10.
+	p := Polygon{{0, 0}, {10, 10}, {0, 10}}
+	Expect(t, p.Bounds()).ToBe(image.Rect(0, 0, 10, 10))
+	Expect(t, p.Area()).ToBe(50.0)
+	Expect(t, p.Contains(image.Pt(1, 8))).ToBe(true)
+	Expect(t, p.Contains(image.Pt(8, 1))).ToBe(false)
+	Expect(t, p.Contains(image.Pt(11, 8))).ToBe(false)
+	Expect(t, p.Add(image.Pt(5, 5))).ToBe(Polygon{{5, 5}, {15, 15}, {5, 15}})
+	Expect(t, p.Scale(0.5)).ToBe(Polygon{{0, 0}, {5, 5}, {0, 5}})
+	Expect(t, Polygon{}.Bounds()).ToBe(image.Rectangle{})
+}
+
+func TestQuad(t *testing.T) {
+	r := image.Rect(10, 20, 110, 40)
+	q := QuadOf(r)
+	Expect(t, q).ToBe(Quad{{10, 20}, {110, 20}, {110, 40}, {10, 40}})
+	Expect(t, q.Bounds()).ToBe(r)
+	Expect(t, q.Angle()).ToBe(0.0)
+	// Read from bottom to top.
+	Expect(t, Quad{{0, 100}, {0, 0}, {20, 0}, {20, 100}}.Angle()).ToBe(90.0)
+}
+
+func TestLabeledRegion(t *testing.T) {
+	region := RectRegion("plate", image.Rect(0, 0, 4, 2))
+	region.Confidence = 91
+	b, err := json.Marshal(region)
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(b)).ToBe(`{"label":"plate","polygon":[{"X":0,"Y":0},{"X":4,"Y":0},{"X":4,"Y":2},{"X":0,"Y":2}],"confidence":91}`)
+	decoded := LabeledRegion{}
+	Expect(t, json.Unmarshal(b, &decoded)).ToBe(nil)
+	Expect(t, decoded.Label).ToBe("plate")
+	Expect(t, decoded.Polygon).ToBe(region.Polygon)
+	Expect(t, decoded.Confidence).ToBe(91.0)
+	Expect(t, QuadRegion("word", QuadOf(image.Rect(0, 0, 4, 2))).Bounds()).ToBe(image.Rect(0, 0, 4, 2))
+}
+
+func TestFill(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 10))
+	Fill(img, Polygon{{0, 0}, {10, 10}, {0, 10}}, color.White)
+	Expect(t, img.GrayAt(1, 8).Y).ToBe(uint8(255))
+	Expect(t, img.GrayAt(8, 1).Y).ToBe(uint8(0))
+
+	When(t, "outlines are drawn", func(t *testing.T) {
+		img := image.NewGray(image.Rect(0, 0, 10, 10))
+		Outline(img, QuadOf(image.Rect(2, 2, 7, 7)).Polygon(), color.White)
+		Expect(t, img.GrayAt(2, 2).Y).ToBe(uint8(255))
+		Expect(t, img.GrayAt(7, 4).Y).ToBe(uint8(255))
+		Expect(t, img.GrayAt(4, 4).Y).ToBe(uint8(0))
+	})
+}
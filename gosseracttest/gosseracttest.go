@@ -0,0 +1,92 @@
+// Package gosseracttest provides utilities for tests of packages using gosseract: tesseract is initialized once
+// for the whole test binary, rather than by each test, and a shared Pool serves parallel tests.
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(gosseracttest.Main(m, gosseract.Config{Languages: []string{"eng"}}))
+//	}
+//
+//	func TestInvoice(t *testing.T) {
+//		t.Parallel()
+//		text := gosseracttest.Text(t, data, gosseract.Options{PageSegMode: gosseract.PSM_SINGLE_BLOCK})
+//		...
+//	}
+package gosseracttest
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/chennqqi/gosseract/v2"
+)
+
+var shared struct {
+	once sync.Once
+	cfg  gosseract.Config
+	pool *gosseract.Pool
+	err  error
+}
+
+// Main runs the tests of m with tesseract initialized once by cfg, and tears it down afterwards,
+// returning the exit code for os.Exit. Languages of cfg are preloaded, and the shared Pool is built
+// and warmed up before any test, as many Recognizers as runtime.GOMAXPROCS unless cfg.Tuning.Clients is set,
+// which is the parallelism of tests by default. Failures of the initialization fail the tests using Pool.
+func Main(m *testing.M, cfg gosseract.Config) int {
+	setup(cfg)
+	code := m.Run()
+	teardown()
+	return code
+}
+
+func setup(cfg gosseract.Config) {
+	shared.once.Do(func() {
+		if cfg.Tuning.Clients == 0 {
+			cfg.Tuning.Clients = runtime.GOMAXPROCS(0)
+		}
+		shared.cfg = cfg
+		languages := cfg.Languages
+		if len(languages) == 0 {
+			languages = []string{"eng"}
+		}
+		if shared.err = gosseract.PreloadLanguages(languages...); shared.err != nil {
+			return
+		}
+		shared.pool, shared.err = cfg.BuildPool()
+	})
+}
+
+func teardown() {
+	if shared.pool != nil {
+		shared.pool.Close()
+	}
+	gosseract.ClearPersistentCache()
+}
+
+// Pool returns the shared Pool, which is safe for parallel tests. It fails the test if tesseract can't be initialized.
+// Without Main, it's initialized by the default Config at the first call, and never torn down.
+// Tests MUST NOT Close the Pool.
+func Pool(t testing.TB) *gosseract.Pool {
+	t.Helper()
+	setup(gosseract.Config{})
+	if shared.err != nil {
+		t.Fatalf("failed to initialize tesseract: %v", shared.err)
+	}
+	return shared.pool
+}
+
+// Text recognizes the image data by the shared Pool, failing the test on errors.
+func Text(t testing.TB, data []byte, opts gosseract.Options) string {
+	t.Helper()
+	pool := Pool(t)
+	text, err := pool.TextWithOptions(context.Background(), data, opts)
+	if err != nil {
+		t.Fatalf("failed to recognize: %v", err)
+	}
+	return text
+}
+
+// Config returns the configuration of the shared Pool.
+func Config() gosseract.Config {
+	return shared.cfg
+}
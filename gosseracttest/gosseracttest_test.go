@@ -0,0 +1,47 @@
+package gosseracttest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/chennqqi/gosseract/v2"
+	. "github.com/otiai10/mint"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(Main(m, gosseract.Config{Languages: []string{"eng"}, Trim: true}))
+}
+
+// recorder is a testing.TB recording failures, rather than failing.
+type recorder struct {
+	testing.TB
+	failure string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failure = fmt.Sprintf(format, args...)
+}
+
+func TestPool(t *testing.T) {
+	Expect(t, Config().Languages).ToBe([]string{"eng"})
+	Expect(t, Config().Tuning.Clients).Not().ToBe(0)
+	if shared.err != nil {
+		// Tesseract isn't available, such as built without cgo.
+		r := &recorder{TB: t}
+		Expect(t, Pool(r) == nil).ToBe(true)
+		Expect(t, r.failure).Not().ToBe("")
+		return
+	}
+	data, err := os.ReadFile("../test/data/001-helloworld.png")
+	Expect(t, err).ToBe(nil)
+	for i := 0; i < 4; i++ {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Parallel()
+			Expect(t, Text(t, data, gosseract.Options{})).ToBe("Hello, World!")
+		})
+	}
+	Expect(t, Pool(t) == Pool(t)).ToBe(true)
+}
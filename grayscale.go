@@ -0,0 +1,42 @@
+package gosseract
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// grayscale converts color image data into the grayscale PGM of the same pixels, which Leptonica reads as 8 bpp,
+// rather than 32 bpp of color images, see Client.Grayscale.
+// It returns nil for images already in grayscale, larger than the limit of pixels as of Client.MaxImagePixels,
+// or in formats Go can't decode: PNG, JPEG and GIF only.
+func grayscale(data []byte, limit int) []byte {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.ColorModel == color.GrayModel || cfg.ColorModel == color.Gray16Model {
+		return nil
+	}
+	img, _, err := decodeLimited(data, limit)
+	if err != nil {
+		return nil
+	}
+	b := img.Bounds()
+	header := fmt.Sprintf("P5\n%d %d\n255\n", b.Dx(), b.Dy())
+	pgm := make([]byte, len(header), len(header)+b.Dx()*b.Dy())
+	copy(pgm, header)
+	switch img := img.(type) {
+	case *image.YCbCr:
+		// Y of JPEG is the luma already.
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			i := img.YOffset(b.Min.X, y)
+			pgm = append(pgm, img.Y[i:i+b.Dx()]...)
+		}
+	default:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				pgm = append(pgm, color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+			}
+		}
+	}
+	return pgm
+}
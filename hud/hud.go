@@ -0,0 +1,149 @@
+// Package hud reads fixed regions of screens, such as HUDs of games and overlays of streams, by polling,
+// reporting only the regions whose text changes, so that bots and accessibility tools can poll frequently.
+package hud
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"github.com/chennqqi/gosseract/v2"
+)
+
+// Recognizer recognizes text of image data with options, which gosseract.Client implements.
+type Recognizer interface {
+	TextWithOptions(ctx context.Context, data []byte, opts gosseract.Options) (string, error)
+}
+
+// Parser parses the text of a region into a value, such as a number of a health bar.
+type Parser func(text string) (interface{}, error)
+
+// Int parses the digits of the text as an integer, ignoring other characters such as "HP" or "/",
+// with the minus sign if the text starts with one.
+func Int(text string) (interface{}, error) {
+	digits := strings.Map(func(r rune) rune {
+		if '0' <= r && r <= '9' {
+			return r
+		}
+		return -1
+	}, text)
+	if digits == "" {
+		return nil, fmt.Errorf("no digits in %q", text)
+	}
+	n, err := strconv.Atoi(digits)
+	if strings.HasPrefix(strings.TrimSpace(text), "-") {
+		n = -n
+	}
+	return n, err
+}
+
+// Region is a part of the screen to be read.
+type Region struct {
+	Name string
+	Rect image.Rectangle
+
+	// Options to recognize the region, such as a whitelist of digits and PSM_SINGLE_LINE for counters.
+	Options gosseract.Options
+
+	// Parse parses the text, nil to report the text as it is.
+	Parse Parser
+}
+
+// Change is a region whose text has changed.
+type Change struct {
+	Region string
+	Text   string
+
+	// Value parsed from Text by Region.Parse, nil if the region has no parser or Err is not nil.
+	Value interface{}
+	Err   error
+}
+
+// Reader reads the regions of frames, remembering the last ones to detect changes.
+// It's not safe for concurrent use.
+type Reader struct {
+	recognizer Recognizer
+	regions    []Region
+	pixels     map[string]uint64
+	texts      map[string]string
+}
+
+// NewReader creates a Reader of the regions, by the recognizer such as gosseract.Client.
+func NewReader(recognizer Recognizer, regions ...Region) *Reader {
+	return &Reader{
+		recognizer: recognizer,
+		regions:    regions,
+		pixels:     map[string]uint64{},
+		texts:      map[string]string{},
+	}
+}
+
+// Read reads the regions of the frame and returns the changes since the last frame, in order of regions.
+// Regions of the same pixels as the last frame are not recognized again, and regions recognized
+// as the same text are not reported, so all the regions are reported only for the first frame.
+func (r *Reader) Read(ctx context.Context, frame image.Image) ([]Change, error) {
+	changes := []Change{}
+	for _, region := range r.regions {
+		crop := cropRegion(frame, region.Rect)
+		sum := pixelHash(crop)
+		if last, ok := r.pixels[region.Name]; ok && last == sum {
+			continue
+		}
+		text, err := r.recognize(ctx, crop, region.Options)
+		if err != nil {
+			return changes, err
+		}
+		r.pixels[region.Name] = sum
+		if last, ok := r.texts[region.Name]; ok && last == text {
+			continue
+		}
+		r.texts[region.Name] = text
+		change := Change{Region: region.Name, Text: text}
+		if region.Parse != nil {
+			change.Value, change.Err = region.Parse(text)
+			if change.Err != nil {
+				change.Value = nil
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// recognize returns the text of the crop, which is empty for regions out of the frame.
+func (r *Reader) recognize(ctx context.Context, crop *image.RGBA, opts gosseract.Options) (string, error) {
+	if crop.Rect.Empty() {
+		return "", nil
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := png.Encode(buf, crop); err != nil {
+		return "", err
+	}
+	text, err := r.recognizer.TextWithOptions(ctx, buf.Bytes(), opts)
+	return strings.TrimSpace(text), err
+}
+
+// Reset forgets the last frame, so that the next Read reports all the regions.
+func (r *Reader) Reset() {
+	r.pixels, r.texts = map[string]uint64{}, map[string]string{}
+}
+
+func cropRegion(img image.Image, rect image.Rectangle) *image.RGBA {
+	rect = rect.Intersect(img.Bounds())
+	crop := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(crop, crop.Bounds(), img, rect.Min, draw.Src)
+	return crop
+}
+
+func pixelHash(img *image.RGBA) uint64 {
+	h := fnv.New64a()
+	fmt.Fprint(h, img.Rect.Dx(), img.Rect.Dy())
+	h.Write(img.Pix)
+	return h.Sum64()
+}
@@ -0,0 +1,78 @@
+package hud
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/chennqqi/gosseract/v2"
+	. "github.com/otiai10/mint"
+)
+
+// fakeRecognizer returns the texts in order, counting the recognitions.
+type fakeRecognizer struct {
+	texts []string
+	calls int
+}
+
+func (f *fakeRecognizer) TextWithOptions(ctx context.Context, data []byte, opts gosseract.Options) (string, error) {
+	if f.calls >= len(f.texts) {
+		return "", errors.New("unexpected recognition")
+	}
+	f.calls++
+	return f.texts[f.calls-1], nil
+}
+
+func TestReader_Read(t *testing.T) {
+	frame := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	rec := &fakeRecognizer{texts: []string{"HP 100", "Score 10", "HP 90 ", "HP 90"}}
+	reader := NewReader(rec,
+		Region{Name: "hp", Rect: image.Rect(0, 0, 50, 20), Parse: Int},
+		Region{Name: "score", Rect: image.Rect(50, 0, 100, 20)},
+		Region{Name: "outside", Rect: image.Rect(200, 200, 300, 300)},
+	)
+
+	changes, err := reader.Read(context.Background(), frame)
+	Expect(t, err).ToBe(nil)
+	Expect(t, changes).Deeply().ToBe([]Change{
+		{Region: "hp", Text: "HP 100", Value: 100},
+		{Region: "score", Text: "Score 10"},
+		{Region: "outside", Text: ""},
+	})
+
+	When(t, "the frame is the same", func(t *testing.T) {
+		changes, err := reader.Read(context.Background(), frame)
+		Expect(t, err).ToBe(nil)
+		Expect(t, len(changes)).ToBe(0)
+		Expect(t, rec.calls).ToBe(2)
+	})
+
+	When(t, "pixels of a region change", func(t *testing.T) {
+		frame.Set(10, 10, color.White)
+		changes, err := reader.Read(context.Background(), frame)
+		Expect(t, err).ToBe(nil)
+		Expect(t, changes).Deeply().ToBe([]Change{{Region: "hp", Text: "HP 90", Value: 90}})
+		Expect(t, rec.calls).ToBe(3)
+	})
+
+	When(t, "pixels change but the text doesn't", func(t *testing.T) {
+		frame.Set(11, 10, color.White)
+		changes, err := reader.Read(context.Background(), frame)
+		Expect(t, err).ToBe(nil)
+		Expect(t, len(changes)).ToBe(0)
+		Expect(t, rec.calls).ToBe(4)
+	})
+}
+
+func TestInt(t *testing.T) {
+	v, err := Int("HP 1,250")
+	Expect(t, err).ToBe(nil)
+	Expect(t, v).ToBe(1250)
+	v, err = Int(" -30")
+	Expect(t, err).ToBe(nil)
+	Expect(t, v).ToBe(-30)
+	_, err = Int("HP")
+	Expect(t, err).Not().ToBe(nil)
+}
@@ -0,0 +1,40 @@
+package gosseract
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+)
+
+// DefaultMaxImagePixels is the limit of width times height of images of clients without Client.MaxImagePixels,
+// 1 GiB of 32 bpp pixels, which is far beyond pages scanned at 600 DPI.
+const DefaultMaxImagePixels = 1 << 28
+
+// checkDimensions returns ImageError if the dimensions read from the header of an image are invalid,
+// or exceed the limit of pixels: DefaultMaxImagePixels if zero, or no limit if negative.
+func checkDimensions(width, height, limit int) error {
+	if width <= 0 || height <= 0 {
+		return &ImageError{Reason: fmt.Sprintf("invalid dimensions %dx%d", width, height)}
+	}
+	if limit == 0 {
+		limit = DefaultMaxImagePixels
+	}
+	if limit > 0 && int64(width)*int64(height) > int64(limit) {
+		return &ImageError{Reason: fmt.Sprintf("%dx%d exceeds the limit of %d pixels", width, height, limit), TooLarge: true}
+	}
+	return nil
+}
+
+// decodeLimited decodes the image data by Go, checking the dimensions of the header before allocating the pixels,
+// not to be exhausted by decompression bombs. The limit is of checkDimensions, such as Client.MaxImagePixels.
+func decodeLimited(data []byte, limit int) (image.Image, image.Config, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, cfg, err
+	}
+	if err := checkDimensions(cfg.Width, cfg.Height, limit); err != nil {
+		return nil, cfg, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, cfg, err
+}
@@ -0,0 +1,75 @@
+package gosseract
+
+import (
+	"sort"
+	"sync"
+)
+
+// LanguageScore is how well a language matches an image, see DetectLanguages.
+type LanguageScore struct {
+	Language string `json:"language"`
+	// Score is the mean confidence of words recognized in the language, from 0 to 100.
+	Score float64 `json:"score"`
+}
+
+// detectionMaxDimension caps the size of images recognized by DetectLanguages,
+// which are large enough for the confidence, and small enough to recognize quickly.
+const detectionMaxDimension = 800
+
+// sortLanguageScores sorts scores from the best, keeping the order of candidates for ties.
+func sortLanguageScores(scores []LanguageScore) {
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Score > scores[j].Score })
+}
+
+// builtinLanguageVariables are the variables known to suit the languages better than the defaults of tesseract.
+var builtinLanguageVariables = map[string]map[SettableVariable]string{
+	// Tesseract puts spaces between ideographs, which Chinese and Japanese don't separate words by,
+	// and its legacy engine chops ideographs of multiple components apart.
+	"chi_sim":      {"preserve_interword_spaces": "1", "chop_enable": "0"},
+	"chi_sim_vert": {"preserve_interword_spaces": "1", "chop_enable": "0"},
+	"chi_tra":      {"preserve_interword_spaces": "1", "chop_enable": "0"},
+	"chi_tra_vert": {"preserve_interword_spaces": "1", "chop_enable": "0"},
+	"jpn":          {"preserve_interword_spaces": "1"},
+	"jpn_vert":     {"preserve_interword_spaces": "1"},
+}
+
+var languageVariables = struct {
+	sync.RWMutex
+	registered map[string]map[SettableVariable]string
+}{registered: map[string]map[SettableVariable]string{}}
+
+// RegisterLanguageVariables registers the variables applied to clients initialized with the language,
+// such as "deu", overriding the built-in ones of the same keys, if any. Registering the language again
+// merges the variables into the registered ones. Clients initialized already apply them on the next SetLanguage.
+func RegisterLanguageVariables(lang string, vars map[SettableVariable]string) {
+	languageVariables.Lock()
+	defer languageVariables.Unlock()
+	registered, ok := languageVariables.registered[lang]
+	if !ok {
+		registered = map[SettableVariable]string{}
+		languageVariables.registered[lang] = registered
+	}
+	for key, value := range vars {
+		registered[key] = value
+	}
+}
+
+// LanguageVariables returns the variables applied to clients of the languages, registered by
+// RegisterLanguageVariables over the built-in ones. For variables of multiple languages, the first language wins.
+// Client.Variables override them all.
+func LanguageVariables(langs ...string) map[SettableVariable]string {
+	languageVariables.RLock()
+	defer languageVariables.RUnlock()
+	vars := map[SettableVariable]string{}
+	for i := len(langs) - 1; i >= 0; i-- {
+		for key, value := range builtinLanguageVariables[langs[i]] {
+			if _, ok := languageVariables.registered[langs[i]][key]; !ok {
+				vars[key] = value
+			}
+		}
+		for key, value := range languageVariables.registered[langs[i]] {
+			vars[key] = value
+		}
+	}
+	return vars
+}
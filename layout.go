@@ -0,0 +1,158 @@
+package gosseract
+
+import (
+	"image"
+	"math"
+	"strings"
+
+	"github.com/chennqqi/gosseract/v2/document"
+)
+
+// layoutElement is a block, paragraph, line or word of the page layout walked by the bridge.
+// Elements come in reading order, each block, paragraph and line followed by its children.
+type layoutElement struct {
+	level      PageIteratorLevel
+	box        image.Rectangle
+	text       string
+	confidence float64
+	bold       bool
+	dictionary bool
+	numeric    bool
+	blockType  document.BlockType
+	polygon    []image.Point
+
+	// whether the word is a superscript, subscript or drop cap
+	superscript bool
+	subscript   bool
+	dropcap     bool
+
+	// orientation and deskew angle in radians of the block containing the word,
+	// and the baseline of the word, if any
+	orientation document.Orientation
+	deskew      float64
+	baseline    []image.Point
+}
+
+// buildDocument assembles the flat layout elements into the tree of document.Document.
+func buildDocument(elements []layoutElement) *document.Document {
+	builder := &document.Builder{}
+	for _, e := range elements {
+		switch e.level {
+		case RIL_BLOCK:
+			builder.Block(document.Block{Box: e.box, Type: e.blockType, Polygon: e.polygon})
+		case RIL_PARA:
+			builder.Paragraph(document.Paragraph{Box: e.box})
+		case RIL_TEXTLINE:
+			builder.Line(document.Line{Box: e.box})
+		case RIL_WORD:
+			angle := wordAngle(e.orientation, e.deskew, e.baseline)
+			builder.Word(document.Word{
+				Box:         e.box,
+				Text:        e.text,
+				Confidence:  e.confidence,
+				Bold:        e.bold,
+				Orientation: e.orientation,
+				Angle:       angle,
+				Quad:        rotatedQuad(e.box, angle),
+
+				FromDictionary: e.dictionary,
+				Numeric:        e.numeric,
+				Superscript:    e.superscript,
+				Subscript:      e.subscript,
+				Dropcap:        e.dropcap,
+			})
+		}
+	}
+	return builder.Document()
+}
+
+// dropUnrecognized drops the words tesseract hasn't recognized when it's cancelled, which it fakes by blanks,
+// and the lines, paragraphs and blocks left empty. Blocks of images are kept.
+func dropUnrecognized(doc *document.Document) {
+	blocks := doc.Blocks[:0]
+	for _, block := range doc.Blocks {
+		paragraphs := block.Paragraphs[:0]
+		for _, para := range block.Paragraphs {
+			lines := para.Lines[:0]
+			for _, line := range para.Lines {
+				words := line.Words[:0]
+				for _, word := range line.Words {
+					if strings.TrimSpace(word.Text) != "" {
+						words = append(words, word)
+					}
+				}
+				if line.Words = words; len(words) != 0 {
+					lines = append(lines, line)
+				}
+			}
+			if para.Lines = lines; len(lines) != 0 {
+				paragraphs = append(paragraphs, para)
+			}
+		}
+		if block.Paragraphs = paragraphs; len(paragraphs) != 0 || block.Type.IsImage() {
+			blocks = append(blocks, block)
+		}
+	}
+	doc.Blocks = blocks
+}
+
+// wordAngle returns the rotation of a word in degrees, counterclockwise.
+// The baseline gives the skew of each word, as long as it agrees with the orientation of the block,
+// otherwise the deskew angle of the block is used.
+func wordAngle(orientation document.Orientation, deskew float64, baseline []image.Point) float64 {
+	base := orientation.Angle()
+	if len(baseline) == 2 && baseline[0] != baseline[1] {
+		// Flip y, which goes down in images, to make angles counterclockwise.
+		angle := math.Atan2(float64(baseline[0].Y-baseline[1].Y), float64(baseline[1].X-baseline[0].X)) * 180 / math.Pi
+		if skew := normalizeAngle(angle - base); math.Abs(skew) <= 45 {
+			return normalizeAngle(base + skew)
+		}
+	}
+	return normalizeAngle(base - deskew*180/math.Pi)
+}
+
+// normalizeAngle returns the angle in degrees within (-180, 180].
+func normalizeAngle(angle float64) float64 {
+	angle = math.Mod(angle, 360)
+	if angle > 180 {
+		angle -= 360
+	} else if angle <= -180 {
+		angle += 360
+	}
+	return angle
+}
+
+// rotatedQuad returns the corners of the rectangle rotated by angle which is enclosed by box,
+// clockwise from the top-left corner as the text reads.
+func rotatedQuad(box image.Rectangle, angle float64) [4]image.Point {
+	rad := angle * math.Pi / 180
+	cos, sin := math.Cos(rad), math.Sin(rad)
+	c, s := math.Abs(cos), math.Abs(sin)
+	w, h := float64(box.Dx()), float64(box.Dy())
+	// Solve the size of the rotated rectangle along and across the text, whose bounding box is w x h:
+	// w = length*c + thickness*s, h = length*s + thickness*c
+	det := c*c - s*s
+	length, thickness := 0.0, 0.0
+	if math.Abs(det) > 0.1 {
+		length, thickness = (w*c-h*s)/det, (h*c-w*s)/det
+	}
+	if length <= 0 || thickness <= 0 {
+		// Nearly diagonal, or the box doesn't fit the angle, then snap to the nearest right angle.
+		snapped := math.Round(angle/90) * 90
+		if snapped == angle {
+			return [4]image.Point{box.Min, image.Pt(box.Max.X, box.Min.Y), box.Max, image.Pt(box.Min.X, box.Max.Y)}
+		}
+		return rotatedQuad(box, snapped)
+	}
+	// Unit vectors along the text, and across it toward the bottom of the text, in image coordinates.
+	ux, uy := cos, -sin
+	vx, vy := sin, cos
+	cx, cy := float64(box.Min.X)+w/2, float64(box.Min.Y)+h/2
+	corner := func(along, across float64) image.Point {
+		return image.Pt(
+			int(math.Round(cx+along*length/2*ux+across*thickness/2*vx)),
+			int(math.Round(cy+along*length/2*uy+across*thickness/2*vy)),
+		)
+	}
+	return [4]image.Point{corner(-1, -1), corner(1, -1), corner(1, 1), corner(-1, 1)}
+}
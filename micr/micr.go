@@ -0,0 +1,107 @@
+// Package micr parses the MICR E-13B line of bank checks, as recognized by tesseract with
+// gosseract.PresetMICR, into the routing, account and check numbers, validating the routing number.
+package micr
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The symbols of E-13B, as "mcr.traineddata" represents them.
+const (
+	Transit = 'A'
+	Amount  = 'B'
+	OnUs    = 'C'
+	Dash    = 'D'
+)
+
+// MICR is the fields of a MICR line.
+type MICR struct {
+	// RoutingNumber is the 9 digits of ABA routing transit number in the United States,
+	// or the transit and institution numbers joined by "-" in Canada, such as "12345-001".
+	RoutingNumber string `json:"routing_number"`
+
+	// RoutingValid is whether the check digit of the routing number is valid.
+	// Canadian routing numbers, having no check digit, are always valid.
+	RoutingValid bool `json:"routing_valid"`
+
+	// AccountNumber is the on-us field before its symbol, with dashes as "-".
+	AccountNumber string `json:"account_number"`
+
+	// CheckNumber is the serial number of the check, from the auxiliary on-us field of business checks
+	// or after the account number of personal checks, empty if there's none.
+	CheckNumber string `json:"check_number,omitempty"`
+
+	// AmountCents is the amount encoded by the bank of first deposit, zero if not encoded yet.
+	AmountCents int64 `json:"amount_cents,omitempty"`
+
+	// Line is the MICR line as parsed, without spaces.
+	Line string `json:"line"`
+}
+
+// ErrNoRoutingNumber is returned by Parse if the line has no routing number between the transit symbols.
+var ErrNoRoutingNumber = errors.New("no routing number found")
+
+var (
+	usRouting       = regexp.MustCompile(`A(\d{9})A`)
+	canadianRouting = regexp.MustCompile(`A(\d{5})D(\d{3})A`)
+	auxiliaryOnUs   = regexp.MustCompile(`^C(\d+)C`)
+	amountField     = regexp.MustCompile(`B(\d{10})B$`)
+	onUsField       = regexp.MustCompile(`^([\dD]+)C(\d*)`)
+)
+
+// Parse parses the MICR line, with the symbols represented as "mcr.traineddata" does, see Transit,
+// or as the Unicode characters of U+2446 to U+2449. Spaces are ignored.
+func Parse(line string) (*MICR, error) {
+	line = strings.NewReplacer("⑆", "A", "⑇", "B", "⑈", "C", "⑉", "D").Replace(line)
+	line = strings.Join(strings.Fields(line), "")
+	m := &MICR{Line: line}
+
+	rest := ""
+	if loc := usRouting.FindStringSubmatchIndex(line); loc != nil {
+		m.RoutingNumber = line[loc[2]:loc[3]]
+		m.RoutingValid = ValidRoutingNumber(m.RoutingNumber)
+		rest = line[loc[1]:]
+		line = line[:loc[0]]
+	} else if loc := canadianRouting.FindStringSubmatchIndex(line); loc != nil {
+		m.RoutingNumber = line[loc[2]:loc[3]] + "-" + line[loc[4]:loc[5]]
+		m.RoutingValid = true
+		rest = line[loc[1]:]
+		line = line[:loc[0]]
+	} else {
+		return nil, ErrNoRoutingNumber
+	}
+
+	if sub := auxiliaryOnUs.FindStringSubmatch(line); sub != nil {
+		m.CheckNumber = sub[1]
+	}
+	if sub := amountField.FindStringSubmatch(rest); sub != nil {
+		m.AmountCents, _ = strconv.ParseInt(sub[1], 10, 64)
+		rest = strings.TrimSuffix(rest, sub[0])
+	}
+	if sub := onUsField.FindStringSubmatch(rest); sub != nil {
+		m.AccountNumber = strings.ReplaceAll(sub[1], "D", "-")
+		if m.CheckNumber == "" {
+			m.CheckNumber = sub[2]
+		}
+	} else {
+		m.AccountNumber = strings.ReplaceAll(strings.Trim(rest, "ABC"), "D", "-")
+	}
+	return m, nil
+}
+
+// ValidRoutingNumber reports whether the ABA routing number of 9 digits has the valid check digit,
+// i.e. 3, 7 and 1 times its digits sum up to a multiple of 10.
+func ValidRoutingNumber(routing string) bool {
+	if len(routing) != 9 || strings.Trim(routing, "0123456789") != "" {
+		return false
+	}
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i, c := range routing {
+		sum += int(c-'0') * weights[i%3]
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,52 @@
+package micr
+
+import (
+	"testing"
+
+	. "github.com/otiai10/mint"
+)
+
+func TestParse(t *testing.T) {
+	m, err := Parse("A123456780A 123456789C 1001")
+	Expect(t, err).ToBe(nil)
+	Expect(t, m.RoutingNumber).ToBe("123456780")
+	Expect(t, m.RoutingValid).ToBe(true)
+	Expect(t, m.AccountNumber).ToBe("123456789")
+	Expect(t, m.CheckNumber).ToBe("1001")
+	Expect(t, m.AmountCents).ToBe(int64(0))
+
+	When(t, "it's a business check with the amount encoded", func(t *testing.T) {
+		m, err := Parse("⑈001234⑈ ⑆011000015⑆ 1234⑉5678⑈ ⑇0000012550⑇")
+		Expect(t, err).ToBe(nil)
+		Expect(t, m.CheckNumber).ToBe("001234")
+		Expect(t, m.RoutingNumber).ToBe("011000015")
+		Expect(t, m.RoutingValid).ToBe(true)
+		Expect(t, m.AccountNumber).ToBe("1234-5678")
+		Expect(t, m.AmountCents).ToBe(int64(12550))
+	})
+
+	When(t, "it's a Canadian check", func(t *testing.T) {
+		m, err := Parse("C000123C A12345D001A 9876543C")
+		Expect(t, err).ToBe(nil)
+		Expect(t, m.RoutingNumber).ToBe("12345-001")
+		Expect(t, m.AccountNumber).ToBe("9876543")
+		Expect(t, m.CheckNumber).ToBe("000123")
+	})
+
+	When(t, "the routing number is misrecognized", func(t *testing.T) {
+		m, err := Parse("A123456789A123456789C1001")
+		Expect(t, err).ToBe(nil)
+		Expect(t, m.RoutingValid).ToBe(false)
+	})
+
+	When(t, "the line has no routing number", func(t *testing.T) {
+		_, err := Parse("123456789C1001")
+		Expect(t, err).ToBe(ErrNoRoutingNumber)
+	})
+}
+
+func TestValidRoutingNumber(t *testing.T) {
+	Expect(t, ValidRoutingNumber("011000015")).ToBe(true)
+	Expect(t, ValidRoutingNumber("011000016")).ToBe(false)
+	Expect(t, ValidRoutingNumber("01100001")).ToBe(false)
+}
@@ -0,0 +1,288 @@
+// Package mrz parses machine readable zones (MRZ) of passports, ID cards and visas, as specified by ICAO 9303,
+// from the text recognized by tesseract, validating their check digits.
+package mrz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Format is the layout of an MRZ, by its lines and characters per line.
+type Format string
+
+const (
+	// TD1 is 3 lines of 30 characters, of ID cards.
+	TD1 Format = "TD1"
+	// TD2 is 2 lines of 36 characters, of ID cards and visas (MRV-B).
+	TD2 Format = "TD2"
+	// TD3 is 2 lines of 44 characters, of passports and visas (MRV-A).
+	TD3 Format = "TD3"
+)
+
+// MRZ is the fields of a machine readable zone.
+type MRZ struct {
+	Format         Format `json:"format"`
+	DocumentType   string `json:"document_type"`
+	IssuingCountry string `json:"issuing_country"`
+	Surname        string `json:"surname"`
+	GivenNames     string `json:"given_names"`
+	DocumentNumber string `json:"document_number"`
+	Nationality    string `json:"nationality"`
+	BirthDate      Date   `json:"birth_date"`
+	Sex            string `json:"sex"`
+	ExpiryDate     Date   `json:"expiry_date"`
+	OptionalData   string `json:"optional_data,omitempty"`
+	OptionalData2  string `json:"optional_data2,omitempty"`
+
+	// Checks is the result of each check digit, in the order of the MRZ.
+	Checks []Check `json:"checks"`
+
+	// Lines are the lines of the MRZ as parsed, after the fixes of misrecognitions.
+	Lines []string `json:"lines"`
+}
+
+// Check is the result of a check digit over a field.
+type Check struct {
+	Field string `json:"field"`
+	Valid bool   `json:"valid"`
+}
+
+// Valid reports whether all the check digits are valid.
+func (m *MRZ) Valid() bool {
+	for _, c := range m.Checks {
+		if !c.Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// Failures returns the fields whose check digit is invalid, which are likely misrecognized.
+func (m *MRZ) Failures() []string {
+	failures := []string{}
+	for _, c := range m.Checks {
+		if !c.Valid {
+			failures = append(failures, c.Field)
+		}
+	}
+	return failures
+}
+
+// Date is a date of MRZ, i.e. YYMMDD, with the century guessed, see Time.
+type Date struct {
+	Raw string `json:"raw"`
+	// Year is the year of 4 digits, guessed from 2 digits of Raw.
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Day   int `json:"day"`
+}
+
+// Time returns the date as time.Time in UTC, or the zero time if it's unknown.
+func (d Date) Time() time.Time {
+	if d.Year == 0 {
+		return time.Time{}
+	}
+	return time.Date(d.Year, time.Month(d.Month), d.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// ErrNotFound is returned by Parse if the text has no line of an MRZ.
+var ErrNotFound = errors.New("no MRZ found")
+
+// now is replaceable in tests, to guess centuries of dates.
+var now = time.Now
+
+// Parse finds and parses an MRZ in the text recognized, e.g. by Client.Text with the whitelist
+// of "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789<". Spaces are ignored, and letters misrecognized
+// in numeric positions, such as O for 0, are fixed before validating check digits.
+// It returns an error if lines are missing, while check digit failures are reported by MRZ.Checks.
+func Parse(text string) (*MRZ, error) {
+	lines := candidates(text)
+	for i := range lines {
+		switch length := len(lines[i]); {
+		case length == 44 && i+1 < len(lines) && len(lines[i+1]) == 44:
+			return parseTD3(lines[i], lines[i+1]), nil
+		case length == 36 && i+1 < len(lines) && len(lines[i+1]) == 36:
+			return parseTD2(lines[i], lines[i+1]), nil
+		case length == 30 && i+2 < len(lines) && len(lines[i+1]) == 30 && len(lines[i+2]) == 30:
+			return parseTD1(lines[i], lines[i+1], lines[i+2]), nil
+		}
+	}
+	if len(lines) != 0 {
+		return nil, fmt.Errorf("incomplete MRZ: %d lines of %d characters", len(lines), len(lines[0]))
+	}
+	return nil, ErrNotFound
+}
+
+// candidates returns the lines of the text which look like lines of MRZ.
+func candidates(text string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.NewReplacer(" ", "", "\t", "", "«", "<", "‹", "<").Replace(strings.ToUpper(strings.TrimSpace(line)))
+		if len(line) < 30 || strings.Trim(line, "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789<") != "" || !strings.Contains(line, "<") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func parseTD3(line1, line2 string) *MRZ {
+	line2 = digits(line2, 9, 10, 13, 20, 21, 28, 42, 44)
+	m := &MRZ{Format: TD3, Lines: []string{line1, line2}}
+	m.DocumentType, m.IssuingCountry = field(line1[0:2]), field(line1[2:5])
+	m.Surname, m.GivenNames = names(line1[5:44])
+	m.DocumentNumber = field(line2[0:9])
+	m.check("document_number", line2[0:9], line2[9])
+	m.Nationality = field(line2[10:13])
+	m.BirthDate = birthDate(line2[13:19])
+	m.check("birth_date", line2[13:19], line2[19])
+	m.Sex = field(line2[20:21])
+	m.ExpiryDate = expiryDate(line2[21:27])
+	m.check("expiry_date", line2[21:27], line2[27])
+	m.OptionalData = field(line2[28:42])
+	// The check digit of the optional data can be filler if the optional data is empty.
+	if line2[42] != '<' || m.OptionalData != "" {
+		m.check("optional_data", line2[28:42], line2[42])
+	}
+	m.check("composite", line2[0:10]+line2[13:20]+line2[21:43], line2[43])
+	return m
+}
+
+func parseTD2(line1, line2 string) *MRZ {
+	line2 = digits(line2, 9, 10, 13, 20, 21, 28, 35, 36)
+	m := &MRZ{Format: TD2, Lines: []string{line1, line2}}
+	m.DocumentType, m.IssuingCountry = field(line1[0:2]), field(line1[2:5])
+	m.Surname, m.GivenNames = names(line1[5:36])
+	m.DocumentNumber = field(line2[0:9])
+	m.Nationality = field(line2[10:13])
+	m.BirthDate = birthDate(line2[13:19])
+	m.Sex = field(line2[20:21])
+	m.ExpiryDate = expiryDate(line2[21:27])
+	m.OptionalData = field(line2[28:35])
+	number, digit := line2[0:9], line2[9]
+	// Document numbers longer than 9 characters continue in the optional data, followed by the check digit.
+	if rest := strings.SplitN(line2[28:35], "<", 2)[0]; digit == '<' && len(rest) > 1 {
+		number, digit = number+rest[:len(rest)-1], rest[len(rest)-1]
+		m.DocumentNumber = field(number)
+		m.OptionalData = field(strings.TrimPrefix(line2[28:35], rest))
+	}
+	m.check("document_number", number, digit)
+	m.check("birth_date", line2[13:19], line2[19])
+	m.check("expiry_date", line2[21:27], line2[27])
+	// Visas (MRV-B) have no composite check digit.
+	if !strings.HasPrefix(m.DocumentType, "V") {
+		m.check("composite", line2[0:10]+line2[13:20]+line2[21:35], line2[35])
+	}
+	return m
+}
+
+func parseTD1(line1, line2, line3 string) *MRZ {
+	line2 = digits(line2, 0, 7, 8, 15, 29, 30)
+	m := &MRZ{Format: TD1, Lines: []string{line1, line2, line3}}
+	m.DocumentType, m.IssuingCountry = field(line1[0:2]), field(line1[2:5])
+	m.DocumentNumber = field(line1[5:14])
+	m.OptionalData = field(line1[15:30])
+	number, digit := line1[5:14], line1[14]
+	// Document numbers longer than 9 characters continue in the optional data, followed by the check digit.
+	if rest := strings.SplitN(line1[15:30], "<", 2)[0]; digit == '<' && len(rest) > 1 {
+		number, digit = number+rest[:len(rest)-1], rest[len(rest)-1]
+		m.DocumentNumber = field(number)
+		m.OptionalData = field(strings.TrimPrefix(line1[15:30], rest))
+	}
+	m.check("document_number", number, digit)
+	m.BirthDate = birthDate(line2[0:6])
+	m.check("birth_date", line2[0:6], line2[6])
+	m.Sex = field(line2[7:8])
+	m.ExpiryDate = expiryDate(line2[8:14])
+	m.check("expiry_date", line2[8:14], line2[14])
+	m.Nationality = field(line2[15:18])
+	m.OptionalData2 = field(line2[18:29])
+	m.check("composite", line1[5:30]+line2[0:7]+line2[8:15]+line2[18:29], line2[29])
+	m.Surname, m.GivenNames = names(line3)
+	return m
+}
+
+func (m *MRZ) check(name, data string, digit byte) {
+	m.Checks = append(m.Checks, Check{Field: name, Valid: checkDigit(data) == digit})
+}
+
+// checkDigit computes the check digit of data, weighting the values of characters by 7, 3 and 1 repeatedly.
+func checkDigit(data string) byte {
+	weights := [3]int{7, 3, 1}
+	sum := 0
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		value := 0
+		switch {
+		case '0' <= c && c <= '9':
+			value = int(c - '0')
+		case 'A' <= c && c <= 'Z':
+			value = int(c-'A') + 10
+		}
+		sum += value * weights[i%3]
+	}
+	return byte('0' + sum%10)
+}
+
+// digitFixes are letters often recognized in place of digits.
+var digitFixes = strings.NewReplacer("O", "0", "Q", "0", "D", "0", "I", "1", "L", "1", "Z", "2", "S", "5", "B", "8", "G", "6")
+
+// digits fixes the characters in the ranges of positions, given as pairs of start and end, which must be digits,
+// i.e. dates and check digits. Fillers are kept, because check digits of empty fields can be fillers.
+func digits(line string, ranges ...int) string {
+	b := []byte(line)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		start, end := ranges[i], ranges[i+1]
+		copy(b[start:end], digitFixes.Replace(string(b[start:end])))
+	}
+	return string(b)
+}
+
+func field(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(strings.TrimRight(s, "<"), "<", " "))
+}
+
+func names(s string) (surname, given string) {
+	parts := strings.SplitN(strings.TrimRight(s, "<"), "<<", 2)
+	surname = field(parts[0])
+	if len(parts) == 2 {
+		given = field(parts[1])
+	}
+	return surname, given
+}
+
+func parseDate(raw string) (Date, bool) {
+	d := Date{Raw: raw}
+	if _, err := fmt.Sscanf(raw, "%02d%02d%02d", &d.Year, &d.Month, &d.Day); err != nil || d.Month < 1 || d.Month > 12 || d.Day < 1 || d.Day > 31 {
+		return Date{Raw: raw}, false
+	}
+	return d, true
+}
+
+// birthDate guesses the century of the date of birth, which is never in the future.
+func birthDate(raw string) Date {
+	d, ok := parseDate(raw)
+	if !ok {
+		return d
+	}
+	d.Year += 2000
+	if d.Year > now().Year() {
+		d.Year -= 100
+	}
+	return d
+}
+
+// expiryDate guesses the century of the date of expiry, which is by 50 years from now.
+func expiryDate(raw string) Date {
+	d, ok := parseDate(raw)
+	if !ok {
+		return d
+	}
+	d.Year += 2000
+	if d.Year > now().Year()+50 {
+		d.Year -= 100
+	}
+	return d
+}
@@ -0,0 +1,86 @@
+package mrz
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/otiai10/mint"
+)
+
+func init() {
+	now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+}
+
+func TestParse_TD3(t *testing.T) {
+	m, err := Parse("PASSPORT\nP<UTOERIKSSON<<ANNA<MARIA<<<<<<<<<<<<<<<<<<<\nL898902C36UTO7408122F1204159ZE184226B<<<<<10\n")
+	Expect(t, err).ToBe(nil)
+	Expect(t, m.Format).ToBe(TD3)
+	Expect(t, m.DocumentType).ToBe("P")
+	Expect(t, m.IssuingCountry).ToBe("UTO")
+	Expect(t, m.Surname).ToBe("ERIKSSON")
+	Expect(t, m.GivenNames).ToBe("ANNA MARIA")
+	Expect(t, m.DocumentNumber).ToBe("L898902C3")
+	Expect(t, m.Nationality).ToBe("UTO")
+	Expect(t, m.BirthDate.Time()).ToBe(time.Date(1974, 8, 12, 0, 0, 0, 0, time.UTC))
+	Expect(t, m.Sex).ToBe("F")
+	Expect(t, m.ExpiryDate.Time()).ToBe(time.Date(2012, 4, 15, 0, 0, 0, 0, time.UTC))
+	Expect(t, m.OptionalData).ToBe("ZE184226B")
+	Expect(t, m.Valid()).ToBe(true)
+	Expect(t, len(m.Checks)).ToBe(5)
+
+	When(t, "letters are misrecognized in numeric positions", func(t *testing.T) {
+		m, err := Parse("P<UTOERIKSSON<<ANNA<MARIA<<<<<<<<<<<<<<<<<<<\nL898902C36UTO74O8122F12O4159ZE184226B<<<<<1O")
+		Expect(t, err).ToBe(nil)
+		Expect(t, m.BirthDate.Raw).ToBe("740812")
+		Expect(t, m.Valid()).ToBe(true)
+	})
+
+	When(t, "a field is misrecognized", func(t *testing.T) {
+		m, err := Parse("P<UTOERIKSSON<<ANNA<MARIA<<<<<<<<<<<<<<<<<<<\nL898902C46UTO7408122F1204159ZE184226B<<<<<10")
+		Expect(t, err).ToBe(nil)
+		Expect(t, m.Valid()).ToBe(false)
+		Expect(t, m.Failures()).ToBe([]string{"document_number", "composite"})
+	})
+}
+
+func TestParse_TD2(t *testing.T) {
+	m, err := Parse("I<UTOERIKSSON<<ANNA<MARIA<<<<<<<<<<<\nD231458907UTO7408122F1204159<<<<<<<6")
+	Expect(t, err).ToBe(nil)
+	Expect(t, m.Format).ToBe(TD2)
+	Expect(t, m.DocumentNumber).ToBe("D23145890")
+	Expect(t, m.Surname).ToBe("ERIKSSON")
+	Expect(t, m.Valid()).ToBe(true)
+}
+
+func TestParse_TD1(t *testing.T) {
+	m, err := Parse("I<UTOD231458907<<<<<<<<<<<<<<<\n7408122F1204159UTO<<<<<<<<<<<6\nERIKSSON<<ANNA<MARIA<<<<<<<<<<")
+	Expect(t, err).ToBe(nil)
+	Expect(t, m.Format).ToBe(TD1)
+	Expect(t, m.DocumentType).ToBe("I")
+	Expect(t, m.DocumentNumber).ToBe("D23145890")
+	Expect(t, m.Nationality).ToBe("UTO")
+	Expect(t, m.GivenNames).ToBe("ANNA MARIA")
+	Expect(t, m.BirthDate.Year).ToBe(1974)
+	Expect(t, m.Valid()).ToBe(true)
+
+	When(t, "the document number is longer than 9 characters", func(t *testing.T) {
+		m, err := Parse("I<UTOD23145890<AB1124<<<<<<<<<\n7408122F1204159UTO<<<<<<<<<<<8\nERIKSSON<<ANNA<MARIA<<<<<<<<<<")
+		Expect(t, err).ToBe(nil)
+		Expect(t, m.DocumentNumber).ToBe("D23145890AB112")
+		Expect(t, m.OptionalData).ToBe("")
+		Expect(t, m.Valid()).ToBe(true)
+	})
+}
+
+func TestParse_Error(t *testing.T) {
+	_, err := Parse("nothing here")
+	Expect(t, err).ToBe(ErrNotFound)
+	_, err = Parse("P<UTOERIKSSON<<ANNA<MARIA<<<<<<<<<<<<<<<<<<<")
+	Expect(t, err).Not().ToBe(nil)
+}
+
+func TestCheckDigit(t *testing.T) {
+	Expect(t, checkDigit("L898902C3")).ToBe(byte('6'))
+	Expect(t, checkDigit("740812")).ToBe(byte('2'))
+	Expect(t, checkDigit("<<<<<<")).ToBe(byte('0'))
+}
@@ -0,0 +1,79 @@
+#!/usr/bin/env python3
+"""Generates tables.go from the Unicode database of Python's unicodedata."""
+
+import sys
+import unicodedata
+
+
+def compat(c, tags):
+    d = unicodedata.decomposition(c)
+    if not d or not any(d.startswith(t) for t in tags):
+        return None
+    return "".join(chr(int(x, 16)) for x in d.split()[1:])
+
+
+def arabic():
+    table = {}
+    for cp in list(range(0xFB50, 0xFE00)) + list(range(0xFE70, 0xFF00)):
+        c = chr(cp)
+        folded = compat(c, ("<isolated>", "<final>", "<initial>", "<medial>"))
+        if folded:
+            table[c] = unicodedata.normalize("NFC", folded)
+    return table
+
+
+def kana():
+    narrow = {}
+    for cp in range(0xFF61, 0xFFA0):
+        c = chr(cp)
+        wide = compat(c, ("<narrow>",))
+        if wide:
+            narrow[c] = wide
+    voiced = {}
+    for cp in range(0x3040, 0x3100):
+        c = chr(cp)
+        for mark in ("゙", "゚"):
+            composed = unicodedata.normalize("NFC", c + mark)
+            if len(composed) == 1:
+                voiced[c + mark] = composed
+    return narrow, voiced
+
+
+def diacritics():
+    table = {}
+    ranges = [(0x00C0, 0x0250), (0x0370, 0x0400), (0x0400, 0x0530), (0x1E00, 0x2000)]
+    for lo, hi in ranges:
+        for cp in range(lo, hi):
+            c = chr(cp)
+            base = "".join(x for x in unicodedata.normalize("NFD", c) if unicodedata.category(x) != "Mn")
+            if base and base != c:
+                table[c] = base
+    return table
+
+
+def quote(s):
+    return '"' + "".join(ch if 0x20 <= ord(ch) < 0x7F and ch not in '"\\' else "\\u%04x" % ord(ch) for ch in s) + '"'
+
+
+def emit(out, name, doc, table, keytype):
+    out.write("// %s %s\n" % (name, doc))
+    out.write("var %s = map[%s]string{\n" % (name, keytype))
+    for k in sorted(table):
+        key = "0x%04x" % ord(k) if keytype == "rune" else quote(k)
+        out.write("\t%s: %s,\n" % (key, quote(table[k])))
+    out.write("}\n\n")
+
+
+def main():
+    narrow, voiced = kana()
+    out = sys.stdout
+    out.write("// Code generated by maketables.py from Unicode %s; DO NOT EDIT.\n\n" % unicodedata.unidata_version)
+    out.write("package normalize\n\n")
+    emit(out, "arabicPresentationForms", "maps Arabic presentation forms to the letters they represent.", arabic(), "rune")
+    emit(out, "halfwidthKana", "maps halfwidth katakana and marks to fullwidth.", narrow, "rune")
+    emit(out, "voicedKana", "composes kana followed by combining voiced sound marks.", voiced, "string")
+    emit(out, "diacriticFree", "maps precomposed letters to themselves without diacritics.", diacritics(), "rune")
+
+
+if __name__ == "__main__":
+    main()
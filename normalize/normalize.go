@@ -0,0 +1,148 @@
+// Package normalize normalizes text recognized in specific scripts, for search and comparison,
+// such as folding character widths of CJK text and presentation forms of Arabic.
+// Client.Normalize applies it to the text and documents assembled by the client.
+package normalize
+
+//go:generate sh -c "python3 maketables.py | gofmt > tables.go"
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chennqqi/gosseract/v2/document"
+)
+
+// Options specifies the normalizations to apply. The zero value leaves text as it is.
+type Options struct {
+
+	// Width folds fullwidth forms of ASCII into ASCII, and halfwidth katakana into fullwidth,
+	// which is the usual normalization of CJK text, e.g. "ＡＢＣ１２３" into "ABC123", "ｶﾞｷﾞ" into "ガギ".
+	Width bool
+
+	// ArabicPresentationForms folds the contextual forms of Arabic letters and ligatures,
+	// which some models and PDFs emit, into the letters they represent.
+	ArabicPresentationForms bool
+
+	// StripDiacritics removes diacritics, such as accents of Latin letters and harakat of Arabic,
+	// e.g. "café" into "cafe". Diacritics are preserved unless this is set.
+	StripDiacritics bool
+}
+
+// String returns s normalized.
+func (opts Options) String(s string) string {
+	if opts.ArabicPresentationForms {
+		s = mapTable(s, arabicPresentationForms)
+	}
+	if opts.Width {
+		s = foldWidth(s)
+	}
+	if opts.StripDiacritics {
+		s = stripDiacritics(s)
+	}
+	return s
+}
+
+// Document normalizes the text of each word of doc, keeping the text as recognized in Word.Original.
+func Document(doc *document.Document, opts Options) {
+	if opts == (Options{}) {
+		return
+	}
+	doc.EachWord(func(ref document.WordRef, word *document.Word) {
+		normalized := opts.String(word.Text)
+		if normalized == word.Text {
+			return
+		}
+		if word.Original == "" {
+			word.Original = word.Text
+		}
+		word.Text = normalized
+	})
+}
+
+func mapTable(s string, table map[rune]string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if mapped, ok := table[r]; ok {
+			b.WriteString(mapped)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// fullwidthSigns are fullwidth forms out of the block of ASCII variants.
+var fullwidthSigns = map[rune]rune{
+	'　': ' ', '￠': '¢', '￡': '£', '￢': '¬', '￣': '¯', '￤': '¦', '￥': '¥', '￦': '₩',
+}
+
+func foldWidth(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '！' && r <= '～':
+			b.WriteRune(r - 0xfee0)
+		case fullwidthSigns[r] != 0:
+			b.WriteRune(fullwidthSigns[r])
+		case halfwidthKana[r] != "":
+			b.WriteString(halfwidthKana[r])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return composeVoicedKana(b.String())
+}
+
+// composeVoicedKana composes kana followed by combining voiced sound marks, which halfwidth katakana is written with.
+func composeVoicedKana(s string) string {
+	if !strings.ContainsAny(s, "゙゚") {
+		return s
+	}
+	var b strings.Builder
+	for len(s) > 0 {
+		_, size := utf8.DecodeRuneInString(s)
+		if len(s) > size {
+			_, next := utf8.DecodeRuneInString(s[size:])
+			if composed, ok := voicedKana[s[:size+next]]; ok {
+				b.WriteString(composed)
+				s = s[size+next:]
+				continue
+			}
+		}
+		b.WriteString(s[:size])
+		s = s[size:]
+	}
+	return b.String()
+}
+
+// diacriticalMarks are combining marks to be stripped as diacritics, of Latin, Greek, Cyrillic, Hebrew and Arabic.
+// Other combining marks, e.g. voiced sound marks of kana, are not diacritics, but parts of letters.
+var diacriticalMarks = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x0300, Hi: 0x036f, Stride: 1},
+		{Lo: 0x0483, Hi: 0x0489, Stride: 1},
+		{Lo: 0x0591, Hi: 0x05c7, Stride: 1},
+		{Lo: 0x064b, Hi: 0x065f, Stride: 1},
+		{Lo: 0x0670, Hi: 0x0670, Stride: 1},
+		{Lo: 0x1ab0, Hi: 0x1aff, Stride: 1},
+		{Lo: 0x1dc0, Hi: 0x1dff, Stride: 1},
+		{Lo: 0x20d0, Hi: 0x20ff, Stride: 1},
+		{Lo: 0xfe20, Hi: 0xfe2f, Stride: 1},
+	},
+}
+
+func stripDiacritics(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case diacriticFree[r] != "":
+			b.WriteString(diacriticFree[r])
+		case unicode.Is(diacriticalMarks, r) && unicode.Is(unicode.Mn, r):
+			// drop it
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
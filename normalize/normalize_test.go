@@ -0,0 +1,44 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/chennqqi/gosseract/v2/document"
+	. "github.com/otiai10/mint"
+)
+
+func TestOptions_String(t *testing.T) {
+	Expect(t, Options{}.String("ＡＢＣ café")).ToBe("ＡＢＣ café")
+
+	When(t, "width is folded", func(t *testing.T) {
+		opts := Options{Width: true}
+		Expect(t, opts.String("ＡＢＣ１２３！　￥")).ToBe("ABC123! ¥")
+		Expect(t, opts.String("ｶﾞｷﾞｸﾞ ﾊﾟ ｱｲｳ")).ToBe("ガギグ パ アイウ")
+		Expect(t, opts.String("漢字かな")).ToBe("漢字かな")
+	})
+
+	When(t, "Arabic presentation forms are folded", func(t *testing.T) {
+		opts := Options{ArabicPresentationForms: true}
+		// "سلام" written with contextual forms
+		Expect(t, opts.String("ﺳﻠﺎﻡ")).ToBe("سلام")
+		Expect(t, opts.String("ﻻ")).ToBe("لا")
+	})
+
+	When(t, "diacritics are stripped", func(t *testing.T) {
+		opts := Options{StripDiacritics: true}
+		Expect(t, opts.String("café naïve Ærø")).ToBe("cafe naive Ærø")
+		Expect(t, opts.String("cafe\u0301")).ToBe("cafe")
+		Expect(t, opts.String("\u30ab\u3099")).ToBe("\u30ab\u3099")
+	})
+}
+
+func TestDocument(t *testing.T) {
+	doc := &document.Document{Blocks: []document.Block{{Paragraphs: []document.Paragraph{{Lines: []document.Line{{
+		Words: []document.Word{{Text: "ＡＢＣ"}, {Text: "abc"}},
+	}}}}}}}
+	Document(doc, Options{Width: true})
+	words := doc.Words()
+	Expect(t, words[0].Text).ToBe("ABC")
+	Expect(t, words[0].Original).ToBe("ＡＢＣ")
+	Expect(t, words[1].Original).ToBe("")
+}
@@ -0,0 +1,1675 @@
+// Code generated by maketables.py from Unicode 14.0.0; DO NOT EDIT.
+
+package normalize
+
+// arabicPresentationForms maps Arabic presentation forms to the letters they represent.
+var arabicPresentationForms = map[rune]string{
+	0xfb50: "\u0671",
+	0xfb51: "\u0671",
+	0xfb52: "\u067b",
+	0xfb53: "\u067b",
+	0xfb54: "\u067b",
+	0xfb55: "\u067b",
+	0xfb56: "\u067e",
+	0xfb57: "\u067e",
+	0xfb58: "\u067e",
+	0xfb59: "\u067e",
+	0xfb5a: "\u0680",
+	0xfb5b: "\u0680",
+	0xfb5c: "\u0680",
+	0xfb5d: "\u0680",
+	0xfb5e: "\u067a",
+	0xfb5f: "\u067a",
+	0xfb60: "\u067a",
+	0xfb61: "\u067a",
+	0xfb62: "\u067f",
+	0xfb63: "\u067f",
+	0xfb64: "\u067f",
+	0xfb65: "\u067f",
+	0xfb66: "\u0679",
+	0xfb67: "\u0679",
+	0xfb68: "\u0679",
+	0xfb69: "\u0679",
+	0xfb6a: "\u06a4",
+	0xfb6b: "\u06a4",
+	0xfb6c: "\u06a4",
+	0xfb6d: "\u06a4",
+	0xfb6e: "\u06a6",
+	0xfb6f: "\u06a6",
+	0xfb70: "\u06a6",
+	0xfb71: "\u06a6",
+	0xfb72: "\u0684",
+	0xfb73: "\u0684",
+	0xfb74: "\u0684",
+	0xfb75: "\u0684",
+	0xfb76: "\u0683",
+	0xfb77: "\u0683",
+	0xfb78: "\u0683",
+	0xfb79: "\u0683",
+	0xfb7a: "\u0686",
+	0xfb7b: "\u0686",
+	0xfb7c: "\u0686",
+	0xfb7d: "\u0686",
+	0xfb7e: "\u0687",
+	0xfb7f: "\u0687",
+	0xfb80: "\u0687",
+	0xfb81: "\u0687",
+	0xfb82: "\u068d",
+	0xfb83: "\u068d",
+	0xfb84: "\u068c",
+	0xfb85: "\u068c",
+	0xfb86: "\u068e",
+	0xfb87: "\u068e",
+	0xfb88: "\u0688",
+	0xfb89: "\u0688",
+	0xfb8a: "\u0698",
+	0xfb8b: "\u0698",
+	0xfb8c: "\u0691",
+	0xfb8d: "\u0691",
+	0xfb8e: "\u06a9",
+	0xfb8f: "\u06a9",
+	0xfb90: "\u06a9",
+	0xfb91: "\u06a9",
+	0xfb92: "\u06af",
+	0xfb93: "\u06af",
+	0xfb94: "\u06af",
+	0xfb95: "\u06af",
+	0xfb96: "\u06b3",
+	0xfb97: "\u06b3",
+	0xfb98: "\u06b3",
+	0xfb99: "\u06b3",
+	0xfb9a: "\u06b1",
+	0xfb9b: "\u06b1",
+	0xfb9c: "\u06b1",
+	0xfb9d: "\u06b1",
+	0xfb9e: "\u06ba",
+	0xfb9f: "\u06ba",
+	0xfba0: "\u06bb",
+	0xfba1: "\u06bb",
+	0xfba2: "\u06bb",
+	0xfba3: "\u06bb",
+	0xfba4: "\u06c0",
+	0xfba5: "\u06c0",
+	0xfba6: "\u06c1",
+	0xfba7: "\u06c1",
+	0xfba8: "\u06c1",
+	0xfba9: "\u06c1",
+	0xfbaa: "\u06be",
+	0xfbab: "\u06be",
+	0xfbac: "\u06be",
+	0xfbad: "\u06be",
+	0xfbae: "\u06d2",
+	0xfbaf: "\u06d2",
+	0xfbb0: "\u06d3",
+	0xfbb1: "\u06d3",
+	0xfbd3: "\u06ad",
+	0xfbd4: "\u06ad",
+	0xfbd5: "\u06ad",
+	0xfbd6: "\u06ad",
+	0xfbd7: "\u06c7",
+	0xfbd8: "\u06c7",
+	0xfbd9: "\u06c6",
+	0xfbda: "\u06c6",
+	0xfbdb: "\u06c8",
+	0xfbdc: "\u06c8",
+	0xfbdd: "\u0677",
+	0xfbde: "\u06cb",
+	0xfbdf: "\u06cb",
+	0xfbe0: "\u06c5",
+	0xfbe1: "\u06c5",
+	0xfbe2: "\u06c9",
+	0xfbe3: "\u06c9",
+	0xfbe4: "\u06d0",
+	0xfbe5: "\u06d0",
+	0xfbe6: "\u06d0",
+	0xfbe7: "\u06d0",
+	0xfbe8: "\u0649",
+	0xfbe9: "\u0649",
+	0xfbea: "\u0626\u0627",
+	0xfbeb: "\u0626\u0627",
+	0xfbec: "\u0626\u06d5",
+	0xfbed: "\u0626\u06d5",
+	0xfbee: "\u0626\u0648",
+	0xfbef: "\u0626\u0648",
+	0xfbf0: "\u0626\u06c7",
+	0xfbf1: "\u0626\u06c7",
+	0xfbf2: "\u0626\u06c6",
+	0xfbf3: "\u0626\u06c6",
+	0xfbf4: "\u0626\u06c8",
+	0xfbf5: "\u0626\u06c8",
+	0xfbf6: "\u0626\u06d0",
+	0xfbf7: "\u0626\u06d0",
+	0xfbf8: "\u0626\u06d0",
+	0xfbf9: "\u0626\u0649",
+	0xfbfa: "\u0626\u0649",
+	0xfbfb: "\u0626\u0649",
+	0xfbfc: "\u06cc",
+	0xfbfd: "\u06cc",
+	0xfbfe: "\u06cc",
+	0xfbff: "\u06cc",
+	0xfc00: "\u0626\u062c",
+	0xfc01: "\u0626\u062d",
+	0xfc02: "\u0626\u0645",
+	0xfc03: "\u0626\u0649",
+	0xfc04: "\u0626\u064a",
+	0xfc05: "\u0628\u062c",
+	0xfc06: "\u0628\u062d",
+	0xfc07: "\u0628\u062e",
+	0xfc08: "\u0628\u0645",
+	0xfc09: "\u0628\u0649",
+	0xfc0a: "\u0628\u064a",
+	0xfc0b: "\u062a\u062c",
+	0xfc0c: "\u062a\u062d",
+	0xfc0d: "\u062a\u062e",
+	0xfc0e: "\u062a\u0645",
+	0xfc0f: "\u062a\u0649",
+	0xfc10: "\u062a\u064a",
+	0xfc11: "\u062b\u062c",
+	0xfc12: "\u062b\u0645",
+	0xfc13: "\u062b\u0649",
+	0xfc14: "\u062b\u064a",
+	0xfc15: "\u062c\u062d",
+	0xfc16: "\u062c\u0645",
+	0xfc17: "\u062d\u062c",
+	0xfc18: "\u062d\u0645",
+	0xfc19: "\u062e\u062c",
+	0xfc1a: "\u062e\u062d",
+	0xfc1b: "\u062e\u0645",
+	0xfc1c: "\u0633\u062c",
+	0xfc1d: "\u0633\u062d",
+	0xfc1e: "\u0633\u062e",
+	0xfc1f: "\u0633\u0645",
+	0xfc20: "\u0635\u062d",
+	0xfc21: "\u0635\u0645",
+	0xfc22: "\u0636\u062c",
+	0xfc23: "\u0636\u062d",
+	0xfc24: "\u0636\u062e",
+	0xfc25: "\u0636\u0645",
+	0xfc26: "\u0637\u062d",
+	0xfc27: "\u0637\u0645",
+	0xfc28: "\u0638\u0645",
+	0xfc29: "\u0639\u062c",
+	0xfc2a: "\u0639\u0645",
+	0xfc2b: "\u063a\u062c",
+	0xfc2c: "\u063a\u0645",
+	0xfc2d: "\u0641\u062c",
+	0xfc2e: "\u0641\u062d",
+	0xfc2f: "\u0641\u062e",
+	0xfc30: "\u0641\u0645",
+	0xfc31: "\u0641\u0649",
+	0xfc32: "\u0641\u064a",
+	0xfc33: "\u0642\u062d",
+	0xfc34: "\u0642\u0645",
+	0xfc35: "\u0642\u0649",
+	0xfc36: "\u0642\u064a",
+	0xfc37: "\u0643\u0627",
+	0xfc38: "\u0643\u062c",
+	0xfc39: "\u0643\u062d",
+	0xfc3a: "\u0643\u062e",
+	0xfc3b: "\u0643\u0644",
+	0xfc3c: "\u0643\u0645",
+	0xfc3d: "\u0643\u0649",
+	0xfc3e: "\u0643\u064a",
+	0xfc3f: "\u0644\u062c",
+	0xfc40: "\u0644\u062d",
+	0xfc41: "\u0644\u062e",
+	0xfc42: "\u0644\u0645",
+	0xfc43: "\u0644\u0649",
+	0xfc44: "\u0644\u064a",
+	0xfc45: "\u0645\u062c",
+	0xfc46: "\u0645\u062d",
+	0xfc47: "\u0645\u062e",
+	0xfc48: "\u0645\u0645",
+	0xfc49: "\u0645\u0649",
+	0xfc4a: "\u0645\u064a",
+	0xfc4b: "\u0646\u062c",
+	0xfc4c: "\u0646\u062d",
+	0xfc4d: "\u0646\u062e",
+	0xfc4e: "\u0646\u0645",
+	0xfc4f: "\u0646\u0649",
+	0xfc50: "\u0646\u064a",
+	0xfc51: "\u0647\u062c",
+	0xfc52: "\u0647\u0645",
+	0xfc53: "\u0647\u0649",
+	0xfc54: "\u0647\u064a",
+	0xfc55: "\u064a\u062c",
+	0xfc56: "\u064a\u062d",
+	0xfc57: "\u064a\u062e",
+	0xfc58: "\u064a\u0645",
+	0xfc59: "\u064a\u0649",
+	0xfc5a: "\u064a\u064a",
+	0xfc5b: "\u0630\u0670",
+	0xfc5c: "\u0631\u0670",
+	0xfc5d: "\u0649\u0670",
+	0xfc5e: " \u064c\u0651",
+	0xfc5f: " \u064d\u0651",
+	0xfc60: " \u064e\u0651",
+	0xfc61: " \u064f\u0651",
+	0xfc62: " \u0650\u0651",
+	0xfc63: " \u0651\u0670",
+	0xfc64: "\u0626\u0631",
+	0xfc65: "\u0626\u0632",
+	0xfc66: "\u0626\u0645",
+	0xfc67: "\u0626\u0646",
+	0xfc68: "\u0626\u0649",
+	0xfc69: "\u0626\u064a",
+	0xfc6a: "\u0628\u0631",
+	0xfc6b: "\u0628\u0632",
+	0xfc6c: "\u0628\u0645",
+	0xfc6d: "\u0628\u0646",
+	0xfc6e: "\u0628\u0649",
+	0xfc6f: "\u0628\u064a",
+	0xfc70: "\u062a\u0631",
+	0xfc71: "\u062a\u0632",
+	0xfc72: "\u062a\u0645",
+	0xfc73: "\u062a\u0646",
+	0xfc74: "\u062a\u0649",
+	0xfc75: "\u062a\u064a",
+	0xfc76: "\u062b\u0631",
+	0xfc77: "\u062b\u0632",
+	0xfc78: "\u062b\u0645",
+	0xfc79: "\u062b\u0646",
+	0xfc7a: "\u062b\u0649",
+	0xfc7b: "\u062b\u064a",
+	0xfc7c: "\u0641\u0649",
+	0xfc7d: "\u0641\u064a",
+	0xfc7e: "\u0642\u0649",
+	0xfc7f: "\u0642\u064a",
+	0xfc80: "\u0643\u0627",
+	0xfc81: "\u0643\u0644",
+	0xfc82: "\u0643\u0645",
+	0xfc83: "\u0643\u0649",
+	0xfc84: "\u0643\u064a",
+	0xfc85: "\u0644\u0645",
+	0xfc86: "\u0644\u0649",
+	0xfc87: "\u0644\u064a",
+	0xfc88: "\u0645\u0627",
+	0xfc89: "\u0645\u0645",
+	0xfc8a: "\u0646\u0631",
+	0xfc8b: "\u0646\u0632",
+	0xfc8c: "\u0646\u0645",
+	0xfc8d: "\u0646\u0646",
+	0xfc8e: "\u0646\u0649",
+	0xfc8f: "\u0646\u064a",
+	0xfc90: "\u0649\u0670",
+	0xfc91: "\u064a\u0631",
+	0xfc92: "\u064a\u0632",
+	0xfc93: "\u064a\u0645",
+	0xfc94: "\u064a\u0646",
+	0xfc95: "\u064a\u0649",
+	0xfc96: "\u064a\u064a",
+	0xfc97: "\u0626\u062c",
+	0xfc98: "\u0626\u062d",
+	0xfc99: "\u0626\u062e",
+	0xfc9a: "\u0626\u0645",
+	0xfc9b: "\u0626\u0647",
+	0xfc9c: "\u0628\u062c",
+	0xfc9d: "\u0628\u062d",
+	0xfc9e: "\u0628\u062e",
+	0xfc9f: "\u0628\u0645",
+	0xfca0: "\u0628\u0647",
+	0xfca1: "\u062a\u062c",
+	0xfca2: "\u062a\u062d",
+	0xfca3: "\u062a\u062e",
+	0xfca4: "\u062a\u0645",
+	0xfca5: "\u062a\u0647",
+	0xfca6: "\u062b\u0645",
+	0xfca7: "\u062c\u062d",
+	0xfca8: "\u062c\u0645",
+	0xfca9: "\u062d\u062c",
+	0xfcaa: "\u062d\u0645",
+	0xfcab: "\u062e\u062c",
+	0xfcac: "\u062e\u0645",
+	0xfcad: "\u0633\u062c",
+	0xfcae: "\u0633\u062d",
+	0xfcaf: "\u0633\u062e",
+	0xfcb0: "\u0633\u0645",
+	0xfcb1: "\u0635\u062d",
+	0xfcb2: "\u0635\u062e",
+	0xfcb3: "\u0635\u0645",
+	0xfcb4: "\u0636\u062c",
+	0xfcb5: "\u0636\u062d",
+	0xfcb6: "\u0636\u062e",
+	0xfcb7: "\u0636\u0645",
+	0xfcb8: "\u0637\u062d",
+	0xfcb9: "\u0638\u0645",
+	0xfcba: "\u0639\u062c",
+	0xfcbb: "\u0639\u0645",
+	0xfcbc: "\u063a\u062c",
+	0xfcbd: "\u063a\u0645",
+	0xfcbe: "\u0641\u062c",
+	0xfcbf: "\u0641\u062d",
+	0xfcc0: "\u0641\u062e",
+	0xfcc1: "\u0641\u0645",
+	0xfcc2: "\u0642\u062d",
+	0xfcc3: "\u0642\u0645",
+	0xfcc4: "\u0643\u062c",
+	0xfcc5: "\u0643\u062d",
+	0xfcc6: "\u0643\u062e",
+	0xfcc7: "\u0643\u0644",
+	0xfcc8: "\u0643\u0645",
+	0xfcc9: "\u0644\u062c",
+	0xfcca: "\u0644\u062d",
+	0xfccb: "\u0644\u062e",
+	0xfccc: "\u0644\u0645",
+	0xfccd: "\u0644\u0647",
+	0xfcce: "\u0645\u062c",
+	0xfccf: "\u0645\u062d",
+	0xfcd0: "\u0645\u062e",
+	0xfcd1: "\u0645\u0645",
+	0xfcd2: "\u0646\u062c",
+	0xfcd3: "\u0646\u062d",
+	0xfcd4: "\u0646\u062e",
+	0xfcd5: "\u0646\u0645",
+	0xfcd6: "\u0646\u0647",
+	0xfcd7: "\u0647\u062c",
+	0xfcd8: "\u0647\u0645",
+	0xfcd9: "\u0647\u0670",
+	0xfcda: "\u064a\u062c",
+	0xfcdb: "\u064a\u062d",
+	0xfcdc: "\u064a\u062e",
+	0xfcdd: "\u064a\u0645",
+	0xfcde: "\u064a\u0647",
+	0xfcdf: "\u0626\u0645",
+	0xfce0: "\u0626\u0647",
+	0xfce1: "\u0628\u0645",
+	0xfce2: "\u0628\u0647",
+	0xfce3: "\u062a\u0645",
+	0xfce4: "\u062a\u0647",
+	0xfce5: "\u062b\u0645",
+	0xfce6: "\u062b\u0647",
+	0xfce7: "\u0633\u0645",
+	0xfce8: "\u0633\u0647",
+	0xfce9: "\u0634\u0645",
+	0xfcea: "\u0634\u0647",
+	0xfceb: "\u0643\u0644",
+	0xfcec: "\u0643\u0645",
+	0xfced: "\u0644\u0645",
+	0xfcee: "\u0646\u0645",
+	0xfcef: "\u0646\u0647",
+	0xfcf0: "\u064a\u0645",
+	0xfcf1: "\u064a\u0647",
+	0xfcf2: "\u0640\u064e\u0651",
+	0xfcf3: "\u0640\u064f\u0651",
+	0xfcf4: "\u0640\u0650\u0651",
+	0xfcf5: "\u0637\u0649",
+	0xfcf6: "\u0637\u064a",
+	0xfcf7: "\u0639\u0649",
+	0xfcf8: "\u0639\u064a",
+	0xfcf9: "\u063a\u0649",
+	0xfcfa: "\u063a\u064a",
+	0xfcfb: "\u0633\u0649",
+	0xfcfc: "\u0633\u064a",
+	0xfcfd: "\u0634\u0649",
+	0xfcfe: "\u0634\u064a",
+	0xfcff: "\u062d\u0649",
+	0xfd00: "\u062d\u064a",
+	0xfd01: "\u062c\u0649",
+	0xfd02: "\u062c\u064a",
+	0xfd03: "\u062e\u0649",
+	0xfd04: "\u062e\u064a",
+	0xfd05: "\u0635\u0649",
+	0xfd06: "\u0635\u064a",
+	0xfd07: "\u0636\u0649",
+	0xfd08: "\u0636\u064a",
+	0xfd09: "\u0634\u062c",
+	0xfd0a: "\u0634\u062d",
+	0xfd0b: "\u0634\u062e",
+	0xfd0c: "\u0634\u0645",
+	0xfd0d: "\u0634\u0631",
+	0xfd0e: "\u0633\u0631",
+	0xfd0f: "\u0635\u0631",
+	0xfd10: "\u0636\u0631",
+	0xfd11: "\u0637\u0649",
+	0xfd12: "\u0637\u064a",
+	0xfd13: "\u0639\u0649",
+	0xfd14: "\u0639\u064a",
+	0xfd15: "\u063a\u0649",
+	0xfd16: "\u063a\u064a",
+	0xfd17: "\u0633\u0649",
+	0xfd18: "\u0633\u064a",
+	0xfd19: "\u0634\u0649",
+	0xfd1a: "\u0634\u064a",
+	0xfd1b: "\u062d\u0649",
+	0xfd1c: "\u062d\u064a",
+	0xfd1d: "\u062c\u0649",
+	0xfd1e: "\u062c\u064a",
+	0xfd1f: "\u062e\u0649",
+	0xfd20: "\u062e\u064a",
+	0xfd21: "\u0635\u0649",
+	0xfd22: "\u0635\u064a",
+	0xfd23: "\u0636\u0649",
+	0xfd24: "\u0636\u064a",
+	0xfd25: "\u0634\u062c",
+	0xfd26: "\u0634\u062d",
+	0xfd27: "\u0634\u062e",
+	0xfd28: "\u0634\u0645",
+	0xfd29: "\u0634\u0631",
+	0xfd2a: "\u0633\u0631",
+	0xfd2b: "\u0635\u0631",
+	0xfd2c: "\u0636\u0631",
+	0xfd2d: "\u0634\u062c",
+	0xfd2e: "\u0634\u062d",
+	0xfd2f: "\u0634\u062e",
+	0xfd30: "\u0634\u0645",
+	0xfd31: "\u0633\u0647",
+	0xfd32: "\u0634\u0647",
+	0xfd33: "\u0637\u0645",
+	0xfd34: "\u0633\u062c",
+	0xfd35: "\u0633\u062d",
+	0xfd36: "\u0633\u062e",
+	0xfd37: "\u0634\u062c",
+	0xfd38: "\u0634\u062d",
+	0xfd39: "\u0634\u062e",
+	0xfd3a: "\u0637\u0645",
+	0xfd3b: "\u0638\u0645",
+	0xfd3c: "\u0627\u064b",
+	0xfd3d: "\u0627\u064b",
+	0xfd50: "\u062a\u062c\u0645",
+	0xfd51: "\u062a\u062d\u062c",
+	0xfd52: "\u062a\u062d\u062c",
+	0xfd53: "\u062a\u062d\u0645",
+	0xfd54: "\u062a\u062e\u0645",
+	0xfd55: "\u062a\u0645\u062c",
+	0xfd56: "\u062a\u0645\u062d",
+	0xfd57: "\u062a\u0645\u062e",
+	0xfd58: "\u062c\u0645\u062d",
+	0xfd59: "\u062c\u0645\u062d",
+	0xfd5a: "\u062d\u0645\u064a",
+	0xfd5b: "\u062d\u0645\u0649",
+	0xfd5c: "\u0633\u062d\u062c",
+	0xfd5d: "\u0633\u062c\u062d",
+	0xfd5e: "\u0633\u062c\u0649",
+	0xfd5f: "\u0633\u0645\u062d",
+	0xfd60: "\u0633\u0645\u062d",
+	0xfd61: "\u0633\u0645\u062c",
+	0xfd62: "\u0633\u0645\u0645",
+	0xfd63: "\u0633\u0645\u0645",
+	0xfd64: "\u0635\u062d\u062d",
+	0xfd65: "\u0635\u062d\u062d",
+	0xfd66: "\u0635\u0645\u0645",
+	0xfd67: "\u0634\u062d\u0645",
+	0xfd68: "\u0634\u062d\u0645",
+	0xfd69: "\u0634\u062c\u064a",
+	0xfd6a: "\u0634\u0645\u062e",
+	0xfd6b: "\u0634\u0645\u062e",
+	0xfd6c: "\u0634\u0645\u0645",
+	0xfd6d: "\u0634\u0645\u0645",
+	0xfd6e: "\u0636\u062d\u0649",
+	0xfd6f: "\u0636\u062e\u0645",
+	0xfd70: "\u0636\u062e\u0645",
+	0xfd71: "\u0637\u0645\u062d",
+	0xfd72: "\u0637\u0645\u062d",
+	0xfd73: "\u0637\u0645\u0645",
+	0xfd74: "\u0637\u0645\u064a",
+	0xfd75: "\u0639\u062c\u0645",
+	0xfd76: "\u0639\u0645\u0645",
+	0xfd77: "\u0639\u0645\u0645",
+	0xfd78: "\u0639\u0645\u0649",
+	0xfd79: "\u063a\u0645\u0645",
+	0xfd7a: "\u063a\u0645\u064a",
+	0xfd7b: "\u063a\u0645\u0649",
+	0xfd7c: "\u0641\u062e\u0645",
+	0xfd7d: "\u0641\u062e\u0645",
+	0xfd7e: "\u0642\u0645\u062d",
+	0xfd7f: "\u0642\u0645\u0645",
+	0xfd80: "\u0644\u062d\u0645",
+	0xfd81: "\u0644\u062d\u064a",
+	0xfd82: "\u0644\u062d\u0649",
+	0xfd83: "\u0644\u062c\u062c",
+	0xfd84: "\u0644\u062c\u062c",
+	0xfd85: "\u0644\u062e\u0645",
+	0xfd86: "\u0644\u062e\u0645",
+	0xfd87: "\u0644\u0645\u062d",
+	0xfd88: "\u0644\u0645\u062d",
+	0xfd89: "\u0645\u062d\u062c",
+	0xfd8a: "\u0645\u062d\u0645",
+	0xfd8b: "\u0645\u062d\u064a",
+	0xfd8c: "\u0645\u062c\u062d",
+	0xfd8d: "\u0645\u062c\u0645",
+	0xfd8e: "\u0645\u062e\u062c",
+	0xfd8f: "\u0645\u062e\u0645",
+	0xfd92: "\u0645\u062c\u062e",
+	0xfd93: "\u0647\u0645\u062c",
+	0xfd94: "\u0647\u0645\u0645",
+	0xfd95: "\u0646\u062d\u0645",
+	0xfd96: "\u0646\u062d\u0649",
+	0xfd97: "\u0646\u062c\u0645",
+	0xfd98: "\u0646\u062c\u0645",
+	0xfd99: "\u0646\u062c\u0649",
+	0xfd9a: "\u0646\u0645\u064a",
+	0xfd9b: "\u0646\u0645\u0649",
+	0xfd9c: "\u064a\u0645\u0645",
+	0xfd9d: "\u064a\u0645\u0645",
+	0xfd9e: "\u0628\u062e\u064a",
+	0xfd9f: "\u062a\u062c\u064a",
+	0xfda0: "\u062a\u062c\u0649",
+	0xfda1: "\u062a\u062e\u064a",
+	0xfda2: "\u062a\u062e\u0649",
+	0xfda3: "\u062a\u0645\u064a",
+	0xfda4: "\u062a\u0645\u0649",
+	0xfda5: "\u062c\u0645\u064a",
+	0xfda6: "\u062c\u062d\u0649",
+	0xfda7: "\u062c\u0645\u0649",
+	0xfda8: "\u0633\u062e\u0649",
+	0xfda9: "\u0635\u062d\u064a",
+	0xfdaa: "\u0634\u062d\u064a",
+	0xfdab: "\u0636\u062d\u064a",
+	0xfdac: "\u0644\u062c\u064a",
+	0xfdad: "\u0644\u0645\u064a",
+	0xfdae: "\u064a\u062d\u064a",
+	0xfdaf: "\u064a\u062c\u064a",
+	0xfdb0: "\u064a\u0645\u064a",
+	0xfdb1: "\u0645\u0645\u064a",
+	0xfdb2: "\u0642\u0645\u064a",
+	0xfdb3: "\u0646\u062d\u064a",
+	0xfdb4: "\u0642\u0645\u062d",
+	0xfdb5: "\u0644\u062d\u0645",
+	0xfdb6: "\u0639\u0645\u064a",
+	0xfdb7: "\u0643\u0645\u064a",
+	0xfdb8: "\u0646\u062c\u062d",
+	0xfdb9: "\u0645\u062e\u064a",
+	0xfdba: "\u0644\u062c\u0645",
+	0xfdbb: "\u0643\u0645\u0645",
+	0xfdbc: "\u0644\u062c\u0645",
+	0xfdbd: "\u0646\u062c\u062d",
+	0xfdbe: "\u062c\u062d\u064a",
+	0xfdbf: "\u062d\u062c\u064a",
+	0xfdc0: "\u0645\u062c\u064a",
+	0xfdc1: "\u0641\u0645\u064a",
+	0xfdc2: "\u0628\u062d\u064a",
+	0xfdc3: "\u0643\u0645\u0645",
+	0xfdc4: "\u0639\u062c\u0645",
+	0xfdc5: "\u0635\u0645\u0645",
+	0xfdc6: "\u0633\u062e\u064a",
+	0xfdc7: "\u0646\u062c\u064a",
+	0xfdf0: "\u0635\u0644\u06d2",
+	0xfdf1: "\u0642\u0644\u06d2",
+	0xfdf2: "\u0627\u0644\u0644\u0647",
+	0xfdf3: "\u0627\u0643\u0628\u0631",
+	0xfdf4: "\u0645\u062d\u0645\u062f",
+	0xfdf5: "\u0635\u0644\u0639\u0645",
+	0xfdf6: "\u0631\u0633\u0648\u0644",
+	0xfdf7: "\u0639\u0644\u064a\u0647",
+	0xfdf8: "\u0648\u0633\u0644\u0645",
+	0xfdf9: "\u0635\u0644\u0649",
+	0xfdfa: "\u0635\u0644\u0649 \u0627\u0644\u0644\u0647 \u0639\u0644\u064a\u0647 \u0648\u0633\u0644\u0645",
+	0xfdfb: "\u062c\u0644 \u062c\u0644\u0627\u0644\u0647",
+	0xfdfc: "\u0631\u06cc\u0627\u0644",
+	0xfe70: " \u064b",
+	0xfe71: "\u0640\u064b",
+	0xfe72: " \u064c",
+	0xfe74: " \u064d",
+	0xfe76: " \u064e",
+	0xfe77: "\u0640\u064e",
+	0xfe78: " \u064f",
+	0xfe79: "\u0640\u064f",
+	0xfe7a: " \u0650",
+	0xfe7b: "\u0640\u0650",
+	0xfe7c: " \u0651",
+	0xfe7d: "\u0640\u0651",
+	0xfe7e: " \u0652",
+	0xfe7f: "\u0640\u0652",
+	0xfe80: "\u0621",
+	0xfe81: "\u0622",
+	0xfe82: "\u0622",
+	0xfe83: "\u0623",
+	0xfe84: "\u0623",
+	0xfe85: "\u0624",
+	0xfe86: "\u0624",
+	0xfe87: "\u0625",
+	0xfe88: "\u0625",
+	0xfe89: "\u0626",
+	0xfe8a: "\u0626",
+	0xfe8b: "\u0626",
+	0xfe8c: "\u0626",
+	0xfe8d: "\u0627",
+	0xfe8e: "\u0627",
+	0xfe8f: "\u0628",
+	0xfe90: "\u0628",
+	0xfe91: "\u0628",
+	0xfe92: "\u0628",
+	0xfe93: "\u0629",
+	0xfe94: "\u0629",
+	0xfe95: "\u062a",
+	0xfe96: "\u062a",
+	0xfe97: "\u062a",
+	0xfe98: "\u062a",
+	0xfe99: "\u062b",
+	0xfe9a: "\u062b",
+	0xfe9b: "\u062b",
+	0xfe9c: "\u062b",
+	0xfe9d: "\u062c",
+	0xfe9e: "\u062c",
+	0xfe9f: "\u062c",
+	0xfea0: "\u062c",
+	0xfea1: "\u062d",
+	0xfea2: "\u062d",
+	0xfea3: "\u062d",
+	0xfea4: "\u062d",
+	0xfea5: "\u062e",
+	0xfea6: "\u062e",
+	0xfea7: "\u062e",
+	0xfea8: "\u062e",
+	0xfea9: "\u062f",
+	0xfeaa: "\u062f",
+	0xfeab: "\u0630",
+	0xfeac: "\u0630",
+	0xfead: "\u0631",
+	0xfeae: "\u0631",
+	0xfeaf: "\u0632",
+	0xfeb0: "\u0632",
+	0xfeb1: "\u0633",
+	0xfeb2: "\u0633",
+	0xfeb3: "\u0633",
+	0xfeb4: "\u0633",
+	0xfeb5: "\u0634",
+	0xfeb6: "\u0634",
+	0xfeb7: "\u0634",
+	0xfeb8: "\u0634",
+	0xfeb9: "\u0635",
+	0xfeba: "\u0635",
+	0xfebb: "\u0635",
+	0xfebc: "\u0635",
+	0xfebd: "\u0636",
+	0xfebe: "\u0636",
+	0xfebf: "\u0636",
+	0xfec0: "\u0636",
+	0xfec1: "\u0637",
+	0xfec2: "\u0637",
+	0xfec3: "\u0637",
+	0xfec4: "\u0637",
+	0xfec5: "\u0638",
+	0xfec6: "\u0638",
+	0xfec7: "\u0638",
+	0xfec8: "\u0638",
+	0xfec9: "\u0639",
+	0xfeca: "\u0639",
+	0xfecb: "\u0639",
+	0xfecc: "\u0639",
+	0xfecd: "\u063a",
+	0xfece: "\u063a",
+	0xfecf: "\u063a",
+	0xfed0: "\u063a",
+	0xfed1: "\u0641",
+	0xfed2: "\u0641",
+	0xfed3: "\u0641",
+	0xfed4: "\u0641",
+	0xfed5: "\u0642",
+	0xfed6: "\u0642",
+	0xfed7: "\u0642",
+	0xfed8: "\u0642",
+	0xfed9: "\u0643",
+	0xfeda: "\u0643",
+	0xfedb: "\u0643",
+	0xfedc: "\u0643",
+	0xfedd: "\u0644",
+	0xfede: "\u0644",
+	0xfedf: "\u0644",
+	0xfee0: "\u0644",
+	0xfee1: "\u0645",
+	0xfee2: "\u0645",
+	0xfee3: "\u0645",
+	0xfee4: "\u0645",
+	0xfee5: "\u0646",
+	0xfee6: "\u0646",
+	0xfee7: "\u0646",
+	0xfee8: "\u0646",
+	0xfee9: "\u0647",
+	0xfeea: "\u0647",
+	0xfeeb: "\u0647",
+	0xfeec: "\u0647",
+	0xfeed: "\u0648",
+	0xfeee: "\u0648",
+	0xfeef: "\u0649",
+	0xfef0: "\u0649",
+	0xfef1: "\u064a",
+	0xfef2: "\u064a",
+	0xfef3: "\u064a",
+	0xfef4: "\u064a",
+	0xfef5: "\u0644\u0622",
+	0xfef6: "\u0644\u0622",
+	0xfef7: "\u0644\u0623",
+	0xfef8: "\u0644\u0623",
+	0xfef9: "\u0644\u0625",
+	0xfefa: "\u0644\u0625",
+	0xfefb: "\u0644\u0627",
+	0xfefc: "\u0644\u0627",
+}
+
+// halfwidthKana maps halfwidth katakana and marks to fullwidth.
+var halfwidthKana = map[rune]string{
+	0xff61: "\u3002",
+	0xff62: "\u300c",
+	0xff63: "\u300d",
+	0xff64: "\u3001",
+	0xff65: "\u30fb",
+	0xff66: "\u30f2",
+	0xff67: "\u30a1",
+	0xff68: "\u30a3",
+	0xff69: "\u30a5",
+	0xff6a: "\u30a7",
+	0xff6b: "\u30a9",
+	0xff6c: "\u30e3",
+	0xff6d: "\u30e5",
+	0xff6e: "\u30e7",
+	0xff6f: "\u30c3",
+	0xff70: "\u30fc",
+	0xff71: "\u30a2",
+	0xff72: "\u30a4",
+	0xff73: "\u30a6",
+	0xff74: "\u30a8",
+	0xff75: "\u30aa",
+	0xff76: "\u30ab",
+	0xff77: "\u30ad",
+	0xff78: "\u30af",
+	0xff79: "\u30b1",
+	0xff7a: "\u30b3",
+	0xff7b: "\u30b5",
+	0xff7c: "\u30b7",
+	0xff7d: "\u30b9",
+	0xff7e: "\u30bb",
+	0xff7f: "\u30bd",
+	0xff80: "\u30bf",
+	0xff81: "\u30c1",
+	0xff82: "\u30c4",
+	0xff83: "\u30c6",
+	0xff84: "\u30c8",
+	0xff85: "\u30ca",
+	0xff86: "\u30cb",
+	0xff87: "\u30cc",
+	0xff88: "\u30cd",
+	0xff89: "\u30ce",
+	0xff8a: "\u30cf",
+	0xff8b: "\u30d2",
+	0xff8c: "\u30d5",
+	0xff8d: "\u30d8",
+	0xff8e: "\u30db",
+	0xff8f: "\u30de",
+	0xff90: "\u30df",
+	0xff91: "\u30e0",
+	0xff92: "\u30e1",
+	0xff93: "\u30e2",
+	0xff94: "\u30e4",
+	0xff95: "\u30e6",
+	0xff96: "\u30e8",
+	0xff97: "\u30e9",
+	0xff98: "\u30ea",
+	0xff99: "\u30eb",
+	0xff9a: "\u30ec",
+	0xff9b: "\u30ed",
+	0xff9c: "\u30ef",
+	0xff9d: "\u30f3",
+	0xff9e: "\u3099",
+	0xff9f: "\u309a",
+}
+
+// voicedKana composes kana followed by combining voiced sound marks.
+var voicedKana = map[string]string{
+	"\u3046\u3099": "\u3094",
+	"\u304b\u3099": "\u304c",
+	"\u304d\u3099": "\u304e",
+	"\u304f\u3099": "\u3050",
+	"\u3051\u3099": "\u3052",
+	"\u3053\u3099": "\u3054",
+	"\u3055\u3099": "\u3056",
+	"\u3057\u3099": "\u3058",
+	"\u3059\u3099": "\u305a",
+	"\u305b\u3099": "\u305c",
+	"\u305d\u3099": "\u305e",
+	"\u305f\u3099": "\u3060",
+	"\u3061\u3099": "\u3062",
+	"\u3064\u3099": "\u3065",
+	"\u3066\u3099": "\u3067",
+	"\u3068\u3099": "\u3069",
+	"\u306f\u3099": "\u3070",
+	"\u306f\u309a": "\u3071",
+	"\u3072\u3099": "\u3073",
+	"\u3072\u309a": "\u3074",
+	"\u3075\u3099": "\u3076",
+	"\u3075\u309a": "\u3077",
+	"\u3078\u3099": "\u3079",
+	"\u3078\u309a": "\u307a",
+	"\u307b\u3099": "\u307c",
+	"\u307b\u309a": "\u307d",
+	"\u309d\u3099": "\u309e",
+	"\u30a6\u3099": "\u30f4",
+	"\u30ab\u3099": "\u30ac",
+	"\u30ad\u3099": "\u30ae",
+	"\u30af\u3099": "\u30b0",
+	"\u30b1\u3099": "\u30b2",
+	"\u30b3\u3099": "\u30b4",
+	"\u30b5\u3099": "\u30b6",
+	"\u30b7\u3099": "\u30b8",
+	"\u30b9\u3099": "\u30ba",
+	"\u30bb\u3099": "\u30bc",
+	"\u30bd\u3099": "\u30be",
+	"\u30bf\u3099": "\u30c0",
+	"\u30c1\u3099": "\u30c2",
+	"\u30c4\u3099": "\u30c5",
+	"\u30c6\u3099": "\u30c7",
+	"\u30c8\u3099": "\u30c9",
+	"\u30cf\u3099": "\u30d0",
+	"\u30cf\u309a": "\u30d1",
+	"\u30d2\u3099": "\u30d3",
+	"\u30d2\u309a": "\u30d4",
+	"\u30d5\u3099": "\u30d6",
+	"\u30d5\u309a": "\u30d7",
+	"\u30d8\u3099": "\u30d9",
+	"\u30d8\u309a": "\u30da",
+	"\u30db\u3099": "\u30dc",
+	"\u30db\u309a": "\u30dd",
+	"\u30ef\u3099": "\u30f7",
+	"\u30f0\u3099": "\u30f8",
+	"\u30f1\u3099": "\u30f9",
+	"\u30f2\u3099": "\u30fa",
+	"\u30fd\u3099": "\u30fe",
+}
+
+// diacriticFree maps precomposed letters to themselves without diacritics.
+var diacriticFree = map[rune]string{
+	0x00c0: "A",
+	0x00c1: "A",
+	0x00c2: "A",
+	0x00c3: "A",
+	0x00c4: "A",
+	0x00c5: "A",
+	0x00c7: "C",
+	0x00c8: "E",
+	0x00c9: "E",
+	0x00ca: "E",
+	0x00cb: "E",
+	0x00cc: "I",
+	0x00cd: "I",
+	0x00ce: "I",
+	0x00cf: "I",
+	0x00d1: "N",
+	0x00d2: "O",
+	0x00d3: "O",
+	0x00d4: "O",
+	0x00d5: "O",
+	0x00d6: "O",
+	0x00d9: "U",
+	0x00da: "U",
+	0x00db: "U",
+	0x00dc: "U",
+	0x00dd: "Y",
+	0x00e0: "a",
+	0x00e1: "a",
+	0x00e2: "a",
+	0x00e3: "a",
+	0x00e4: "a",
+	0x00e5: "a",
+	0x00e7: "c",
+	0x00e8: "e",
+	0x00e9: "e",
+	0x00ea: "e",
+	0x00eb: "e",
+	0x00ec: "i",
+	0x00ed: "i",
+	0x00ee: "i",
+	0x00ef: "i",
+	0x00f1: "n",
+	0x00f2: "o",
+	0x00f3: "o",
+	0x00f4: "o",
+	0x00f5: "o",
+	0x00f6: "o",
+	0x00f9: "u",
+	0x00fa: "u",
+	0x00fb: "u",
+	0x00fc: "u",
+	0x00fd: "y",
+	0x00ff: "y",
+	0x0100: "A",
+	0x0101: "a",
+	0x0102: "A",
+	0x0103: "a",
+	0x0104: "A",
+	0x0105: "a",
+	0x0106: "C",
+	0x0107: "c",
+	0x0108: "C",
+	0x0109: "c",
+	0x010a: "C",
+	0x010b: "c",
+	0x010c: "C",
+	0x010d: "c",
+	0x010e: "D",
+	0x010f: "d",
+	0x0112: "E",
+	0x0113: "e",
+	0x0114: "E",
+	0x0115: "e",
+	0x0116: "E",
+	0x0117: "e",
+	0x0118: "E",
+	0x0119: "e",
+	0x011a: "E",
+	0x011b: "e",
+	0x011c: "G",
+	0x011d: "g",
+	0x011e: "G",
+	0x011f: "g",
+	0x0120: "G",
+	0x0121: "g",
+	0x0122: "G",
+	0x0123: "g",
+	0x0124: "H",
+	0x0125: "h",
+	0x0128: "I",
+	0x0129: "i",
+	0x012a: "I",
+	0x012b: "i",
+	0x012c: "I",
+	0x012d: "i",
+	0x012e: "I",
+	0x012f: "i",
+	0x0130: "I",
+	0x0134: "J",
+	0x0135: "j",
+	0x0136: "K",
+	0x0137: "k",
+	0x0139: "L",
+	0x013a: "l",
+	0x013b: "L",
+	0x013c: "l",
+	0x013d: "L",
+	0x013e: "l",
+	0x0143: "N",
+	0x0144: "n",
+	0x0145: "N",
+	0x0146: "n",
+	0x0147: "N",
+	0x0148: "n",
+	0x014c: "O",
+	0x014d: "o",
+	0x014e: "O",
+	0x014f: "o",
+	0x0150: "O",
+	0x0151: "o",
+	0x0154: "R",
+	0x0155: "r",
+	0x0156: "R",
+	0x0157: "r",
+	0x0158: "R",
+	0x0159: "r",
+	0x015a: "S",
+	0x015b: "s",
+	0x015c: "S",
+	0x015d: "s",
+	0x015e: "S",
+	0x015f: "s",
+	0x0160: "S",
+	0x0161: "s",
+	0x0162: "T",
+	0x0163: "t",
+	0x0164: "T",
+	0x0165: "t",
+	0x0168: "U",
+	0x0169: "u",
+	0x016a: "U",
+	0x016b: "u",
+	0x016c: "U",
+	0x016d: "u",
+	0x016e: "U",
+	0x016f: "u",
+	0x0170: "U",
+	0x0171: "u",
+	0x0172: "U",
+	0x0173: "u",
+	0x0174: "W",
+	0x0175: "w",
+	0x0176: "Y",
+	0x0177: "y",
+	0x0178: "Y",
+	0x0179: "Z",
+	0x017a: "z",
+	0x017b: "Z",
+	0x017c: "z",
+	0x017d: "Z",
+	0x017e: "z",
+	0x01a0: "O",
+	0x01a1: "o",
+	0x01af: "U",
+	0x01b0: "u",
+	0x01cd: "A",
+	0x01ce: "a",
+	0x01cf: "I",
+	0x01d0: "i",
+	0x01d1: "O",
+	0x01d2: "o",
+	0x01d3: "U",
+	0x01d4: "u",
+	0x01d5: "U",
+	0x01d6: "u",
+	0x01d7: "U",
+	0x01d8: "u",
+	0x01d9: "U",
+	0x01da: "u",
+	0x01db: "U",
+	0x01dc: "u",
+	0x01de: "A",
+	0x01df: "a",
+	0x01e0: "A",
+	0x01e1: "a",
+	0x01e2: "\u00c6",
+	0x01e3: "\u00e6",
+	0x01e6: "G",
+	0x01e7: "g",
+	0x01e8: "K",
+	0x01e9: "k",
+	0x01ea: "O",
+	0x01eb: "o",
+	0x01ec: "O",
+	0x01ed: "o",
+	0x01ee: "\u01b7",
+	0x01ef: "\u0292",
+	0x01f0: "j",
+	0x01f4: "G",
+	0x01f5: "g",
+	0x01f8: "N",
+	0x01f9: "n",
+	0x01fa: "A",
+	0x01fb: "a",
+	0x01fc: "\u00c6",
+	0x01fd: "\u00e6",
+	0x01fe: "\u00d8",
+	0x01ff: "\u00f8",
+	0x0200: "A",
+	0x0201: "a",
+	0x0202: "A",
+	0x0203: "a",
+	0x0204: "E",
+	0x0205: "e",
+	0x0206: "E",
+	0x0207: "e",
+	0x0208: "I",
+	0x0209: "i",
+	0x020a: "I",
+	0x020b: "i",
+	0x020c: "O",
+	0x020d: "o",
+	0x020e: "O",
+	0x020f: "o",
+	0x0210: "R",
+	0x0211: "r",
+	0x0212: "R",
+	0x0213: "r",
+	0x0214: "U",
+	0x0215: "u",
+	0x0216: "U",
+	0x0217: "u",
+	0x0218: "S",
+	0x0219: "s",
+	0x021a: "T",
+	0x021b: "t",
+	0x021e: "H",
+	0x021f: "h",
+	0x0226: "A",
+	0x0227: "a",
+	0x0228: "E",
+	0x0229: "e",
+	0x022a: "O",
+	0x022b: "o",
+	0x022c: "O",
+	0x022d: "o",
+	0x022e: "O",
+	0x022f: "o",
+	0x0230: "O",
+	0x0231: "o",
+	0x0232: "Y",
+	0x0233: "y",
+	0x0374: "\u02b9",
+	0x037e: ";",
+	0x0385: "\u00a8",
+	0x0386: "\u0391",
+	0x0387: "\u00b7",
+	0x0388: "\u0395",
+	0x0389: "\u0397",
+	0x038a: "\u0399",
+	0x038c: "\u039f",
+	0x038e: "\u03a5",
+	0x038f: "\u03a9",
+	0x0390: "\u03b9",
+	0x03aa: "\u0399",
+	0x03ab: "\u03a5",
+	0x03ac: "\u03b1",
+	0x03ad: "\u03b5",
+	0x03ae: "\u03b7",
+	0x03af: "\u03b9",
+	0x03b0: "\u03c5",
+	0x03ca: "\u03b9",
+	0x03cb: "\u03c5",
+	0x03cc: "\u03bf",
+	0x03cd: "\u03c5",
+	0x03ce: "\u03c9",
+	0x03d3: "\u03d2",
+	0x03d4: "\u03d2",
+	0x0400: "\u0415",
+	0x0401: "\u0415",
+	0x0403: "\u0413",
+	0x0407: "\u0406",
+	0x040c: "\u041a",
+	0x040d: "\u0418",
+	0x040e: "\u0423",
+	0x0419: "\u0418",
+	0x0439: "\u0438",
+	0x0450: "\u0435",
+	0x0451: "\u0435",
+	0x0453: "\u0433",
+	0x0457: "\u0456",
+	0x045c: "\u043a",
+	0x045d: "\u0438",
+	0x045e: "\u0443",
+	0x0476: "\u0474",
+	0x0477: "\u0475",
+	0x04c1: "\u0416",
+	0x04c2: "\u0436",
+	0x04d0: "\u0410",
+	0x04d1: "\u0430",
+	0x04d2: "\u0410",
+	0x04d3: "\u0430",
+	0x04d6: "\u0415",
+	0x04d7: "\u0435",
+	0x04da: "\u04d8",
+	0x04db: "\u04d9",
+	0x04dc: "\u0416",
+	0x04dd: "\u0436",
+	0x04de: "\u0417",
+	0x04df: "\u0437",
+	0x04e2: "\u0418",
+	0x04e3: "\u0438",
+	0x04e4: "\u0418",
+	0x04e5: "\u0438",
+	0x04e6: "\u041e",
+	0x04e7: "\u043e",
+	0x04ea: "\u04e8",
+	0x04eb: "\u04e9",
+	0x04ec: "\u042d",
+	0x04ed: "\u044d",
+	0x04ee: "\u0423",
+	0x04ef: "\u0443",
+	0x04f0: "\u0423",
+	0x04f1: "\u0443",
+	0x04f2: "\u0423",
+	0x04f3: "\u0443",
+	0x04f4: "\u0427",
+	0x04f5: "\u0447",
+	0x04f8: "\u042b",
+	0x04f9: "\u044b",
+	0x1e00: "A",
+	0x1e01: "a",
+	0x1e02: "B",
+	0x1e03: "b",
+	0x1e04: "B",
+	0x1e05: "b",
+	0x1e06: "B",
+	0x1e07: "b",
+	0x1e08: "C",
+	0x1e09: "c",
+	0x1e0a: "D",
+	0x1e0b: "d",
+	0x1e0c: "D",
+	0x1e0d: "d",
+	0x1e0e: "D",
+	0x1e0f: "d",
+	0x1e10: "D",
+	0x1e11: "d",
+	0x1e12: "D",
+	0x1e13: "d",
+	0x1e14: "E",
+	0x1e15: "e",
+	0x1e16: "E",
+	0x1e17: "e",
+	0x1e18: "E",
+	0x1e19: "e",
+	0x1e1a: "E",
+	0x1e1b: "e",
+	0x1e1c: "E",
+	0x1e1d: "e",
+	0x1e1e: "F",
+	0x1e1f: "f",
+	0x1e20: "G",
+	0x1e21: "g",
+	0x1e22: "H",
+	0x1e23: "h",
+	0x1e24: "H",
+	0x1e25: "h",
+	0x1e26: "H",
+	0x1e27: "h",
+	0x1e28: "H",
+	0x1e29: "h",
+	0x1e2a: "H",
+	0x1e2b: "h",
+	0x1e2c: "I",
+	0x1e2d: "i",
+	0x1e2e: "I",
+	0x1e2f: "i",
+	0x1e30: "K",
+	0x1e31: "k",
+	0x1e32: "K",
+	0x1e33: "k",
+	0x1e34: "K",
+	0x1e35: "k",
+	0x1e36: "L",
+	0x1e37: "l",
+	0x1e38: "L",
+	0x1e39: "l",
+	0x1e3a: "L",
+	0x1e3b: "l",
+	0x1e3c: "L",
+	0x1e3d: "l",
+	0x1e3e: "M",
+	0x1e3f: "m",
+	0x1e40: "M",
+	0x1e41: "m",
+	0x1e42: "M",
+	0x1e43: "m",
+	0x1e44: "N",
+	0x1e45: "n",
+	0x1e46: "N",
+	0x1e47: "n",
+	0x1e48: "N",
+	0x1e49: "n",
+	0x1e4a: "N",
+	0x1e4b: "n",
+	0x1e4c: "O",
+	0x1e4d: "o",
+	0x1e4e: "O",
+	0x1e4f: "o",
+	0x1e50: "O",
+	0x1e51: "o",
+	0x1e52: "O",
+	0x1e53: "o",
+	0x1e54: "P",
+	0x1e55: "p",
+	0x1e56: "P",
+	0x1e57: "p",
+	0x1e58: "R",
+	0x1e59: "r",
+	0x1e5a: "R",
+	0x1e5b: "r",
+	0x1e5c: "R",
+	0x1e5d: "r",
+	0x1e5e: "R",
+	0x1e5f: "r",
+	0x1e60: "S",
+	0x1e61: "s",
+	0x1e62: "S",
+	0x1e63: "s",
+	0x1e64: "S",
+	0x1e65: "s",
+	0x1e66: "S",
+	0x1e67: "s",
+	0x1e68: "S",
+	0x1e69: "s",
+	0x1e6a: "T",
+	0x1e6b: "t",
+	0x1e6c: "T",
+	0x1e6d: "t",
+	0x1e6e: "T",
+	0x1e6f: "t",
+	0x1e70: "T",
+	0x1e71: "t",
+	0x1e72: "U",
+	0x1e73: "u",
+	0x1e74: "U",
+	0x1e75: "u",
+	0x1e76: "U",
+	0x1e77: "u",
+	0x1e78: "U",
+	0x1e79: "u",
+	0x1e7a: "U",
+	0x1e7b: "u",
+	0x1e7c: "V",
+	0x1e7d: "v",
+	0x1e7e: "V",
+	0x1e7f: "v",
+	0x1e80: "W",
+	0x1e81: "w",
+	0x1e82: "W",
+	0x1e83: "w",
+	0x1e84: "W",
+	0x1e85: "w",
+	0x1e86: "W",
+	0x1e87: "w",
+	0x1e88: "W",
+	0x1e89: "w",
+	0x1e8a: "X",
+	0x1e8b: "x",
+	0x1e8c: "X",
+	0x1e8d: "x",
+	0x1e8e: "Y",
+	0x1e8f: "y",
+	0x1e90: "Z",
+	0x1e91: "z",
+	0x1e92: "Z",
+	0x1e93: "z",
+	0x1e94: "Z",
+	0x1e95: "z",
+	0x1e96: "h",
+	0x1e97: "t",
+	0x1e98: "w",
+	0x1e99: "y",
+	0x1e9b: "\u017f",
+	0x1ea0: "A",
+	0x1ea1: "a",
+	0x1ea2: "A",
+	0x1ea3: "a",
+	0x1ea4: "A",
+	0x1ea5: "a",
+	0x1ea6: "A",
+	0x1ea7: "a",
+	0x1ea8: "A",
+	0x1ea9: "a",
+	0x1eaa: "A",
+	0x1eab: "a",
+	0x1eac: "A",
+	0x1ead: "a",
+	0x1eae: "A",
+	0x1eaf: "a",
+	0x1eb0: "A",
+	0x1eb1: "a",
+	0x1eb2: "A",
+	0x1eb3: "a",
+	0x1eb4: "A",
+	0x1eb5: "a",
+	0x1eb6: "A",
+	0x1eb7: "a",
+	0x1eb8: "E",
+	0x1eb9: "e",
+	0x1eba: "E",
+	0x1ebb: "e",
+	0x1ebc: "E",
+	0x1ebd: "e",
+	0x1ebe: "E",
+	0x1ebf: "e",
+	0x1ec0: "E",
+	0x1ec1: "e",
+	0x1ec2: "E",
+	0x1ec3: "e",
+	0x1ec4: "E",
+	0x1ec5: "e",
+	0x1ec6: "E",
+	0x1ec7: "e",
+	0x1ec8: "I",
+	0x1ec9: "i",
+	0x1eca: "I",
+	0x1ecb: "i",
+	0x1ecc: "O",
+	0x1ecd: "o",
+	0x1ece: "O",
+	0x1ecf: "o",
+	0x1ed0: "O",
+	0x1ed1: "o",
+	0x1ed2: "O",
+	0x1ed3: "o",
+	0x1ed4: "O",
+	0x1ed5: "o",
+	0x1ed6: "O",
+	0x1ed7: "o",
+	0x1ed8: "O",
+	0x1ed9: "o",
+	0x1eda: "O",
+	0x1edb: "o",
+	0x1edc: "O",
+	0x1edd: "o",
+	0x1ede: "O",
+	0x1edf: "o",
+	0x1ee0: "O",
+	0x1ee1: "o",
+	0x1ee2: "O",
+	0x1ee3: "o",
+	0x1ee4: "U",
+	0x1ee5: "u",
+	0x1ee6: "U",
+	0x1ee7: "u",
+	0x1ee8: "U",
+	0x1ee9: "u",
+	0x1eea: "U",
+	0x1eeb: "u",
+	0x1eec: "U",
+	0x1eed: "u",
+	0x1eee: "U",
+	0x1eef: "u",
+	0x1ef0: "U",
+	0x1ef1: "u",
+	0x1ef2: "Y",
+	0x1ef3: "y",
+	0x1ef4: "Y",
+	0x1ef5: "y",
+	0x1ef6: "Y",
+	0x1ef7: "y",
+	0x1ef8: "Y",
+	0x1ef9: "y",
+	0x1f00: "\u03b1",
+	0x1f01: "\u03b1",
+	0x1f02: "\u03b1",
+	0x1f03: "\u03b1",
+	0x1f04: "\u03b1",
+	0x1f05: "\u03b1",
+	0x1f06: "\u03b1",
+	0x1f07: "\u03b1",
+	0x1f08: "\u0391",
+	0x1f09: "\u0391",
+	0x1f0a: "\u0391",
+	0x1f0b: "\u0391",
+	0x1f0c: "\u0391",
+	0x1f0d: "\u0391",
+	0x1f0e: "\u0391",
+	0x1f0f: "\u0391",
+	0x1f10: "\u03b5",
+	0x1f11: "\u03b5",
+	0x1f12: "\u03b5",
+	0x1f13: "\u03b5",
+	0x1f14: "\u03b5",
+	0x1f15: "\u03b5",
+	0x1f18: "\u0395",
+	0x1f19: "\u0395",
+	0x1f1a: "\u0395",
+	0x1f1b: "\u0395",
+	0x1f1c: "\u0395",
+	0x1f1d: "\u0395",
+	0x1f20: "\u03b7",
+	0x1f21: "\u03b7",
+	0x1f22: "\u03b7",
+	0x1f23: "\u03b7",
+	0x1f24: "\u03b7",
+	0x1f25: "\u03b7",
+	0x1f26: "\u03b7",
+	0x1f27: "\u03b7",
+	0x1f28: "\u0397",
+	0x1f29: "\u0397",
+	0x1f2a: "\u0397",
+	0x1f2b: "\u0397",
+	0x1f2c: "\u0397",
+	0x1f2d: "\u0397",
+	0x1f2e: "\u0397",
+	0x1f2f: "\u0397",
+	0x1f30: "\u03b9",
+	0x1f31: "\u03b9",
+	0x1f32: "\u03b9",
+	0x1f33: "\u03b9",
+	0x1f34: "\u03b9",
+	0x1f35: "\u03b9",
+	0x1f36: "\u03b9",
+	0x1f37: "\u03b9",
+	0x1f38: "\u0399",
+	0x1f39: "\u0399",
+	0x1f3a: "\u0399",
+	0x1f3b: "\u0399",
+	0x1f3c: "\u0399",
+	0x1f3d: "\u0399",
+	0x1f3e: "\u0399",
+	0x1f3f: "\u0399",
+	0x1f40: "\u03bf",
+	0x1f41: "\u03bf",
+	0x1f42: "\u03bf",
+	0x1f43: "\u03bf",
+	0x1f44: "\u03bf",
+	0x1f45: "\u03bf",
+	0x1f48: "\u039f",
+	0x1f49: "\u039f",
+	0x1f4a: "\u039f",
+	0x1f4b: "\u039f",
+	0x1f4c: "\u039f",
+	0x1f4d: "\u039f",
+	0x1f50: "\u03c5",
+	0x1f51: "\u03c5",
+	0x1f52: "\u03c5",
+	0x1f53: "\u03c5",
+	0x1f54: "\u03c5",
+	0x1f55: "\u03c5",
+	0x1f56: "\u03c5",
+	0x1f57: "\u03c5",
+	0x1f59: "\u03a5",
+	0x1f5b: "\u03a5",
+	0x1f5d: "\u03a5",
+	0x1f5f: "\u03a5",
+	0x1f60: "\u03c9",
+	0x1f61: "\u03c9",
+	0x1f62: "\u03c9",
+	0x1f63: "\u03c9",
+	0x1f64: "\u03c9",
+	0x1f65: "\u03c9",
+	0x1f66: "\u03c9",
+	0x1f67: "\u03c9",
+	0x1f68: "\u03a9",
+	0x1f69: "\u03a9",
+	0x1f6a: "\u03a9",
+	0x1f6b: "\u03a9",
+	0x1f6c: "\u03a9",
+	0x1f6d: "\u03a9",
+	0x1f6e: "\u03a9",
+	0x1f6f: "\u03a9",
+	0x1f70: "\u03b1",
+	0x1f71: "\u03b1",
+	0x1f72: "\u03b5",
+	0x1f73: "\u03b5",
+	0x1f74: "\u03b7",
+	0x1f75: "\u03b7",
+	0x1f76: "\u03b9",
+	0x1f77: "\u03b9",
+	0x1f78: "\u03bf",
+	0x1f79: "\u03bf",
+	0x1f7a: "\u03c5",
+	0x1f7b: "\u03c5",
+	0x1f7c: "\u03c9",
+	0x1f7d: "\u03c9",
+	0x1f80: "\u03b1",
+	0x1f81: "\u03b1",
+	0x1f82: "\u03b1",
+	0x1f83: "\u03b1",
+	0x1f84: "\u03b1",
+	0x1f85: "\u03b1",
+	0x1f86: "\u03b1",
+	0x1f87: "\u03b1",
+	0x1f88: "\u0391",
+	0x1f89: "\u0391",
+	0x1f8a: "\u0391",
+	0x1f8b: "\u0391",
+	0x1f8c: "\u0391",
+	0x1f8d: "\u0391",
+	0x1f8e: "\u0391",
+	0x1f8f: "\u0391",
+	0x1f90: "\u03b7",
+	0x1f91: "\u03b7",
+	0x1f92: "\u03b7",
+	0x1f93: "\u03b7",
+	0x1f94: "\u03b7",
+	0x1f95: "\u03b7",
+	0x1f96: "\u03b7",
+	0x1f97: "\u03b7",
+	0x1f98: "\u0397",
+	0x1f99: "\u0397",
+	0x1f9a: "\u0397",
+	0x1f9b: "\u0397",
+	0x1f9c: "\u0397",
+	0x1f9d: "\u0397",
+	0x1f9e: "\u0397",
+	0x1f9f: "\u0397",
+	0x1fa0: "\u03c9",
+	0x1fa1: "\u03c9",
+	0x1fa2: "\u03c9",
+	0x1fa3: "\u03c9",
+	0x1fa4: "\u03c9",
+	0x1fa5: "\u03c9",
+	0x1fa6: "\u03c9",
+	0x1fa7: "\u03c9",
+	0x1fa8: "\u03a9",
+	0x1fa9: "\u03a9",
+	0x1faa: "\u03a9",
+	0x1fab: "\u03a9",
+	0x1fac: "\u03a9",
+	0x1fad: "\u03a9",
+	0x1fae: "\u03a9",
+	0x1faf: "\u03a9",
+	0x1fb0: "\u03b1",
+	0x1fb1: "\u03b1",
+	0x1fb2: "\u03b1",
+	0x1fb3: "\u03b1",
+	0x1fb4: "\u03b1",
+	0x1fb6: "\u03b1",
+	0x1fb7: "\u03b1",
+	0x1fb8: "\u0391",
+	0x1fb9: "\u0391",
+	0x1fba: "\u0391",
+	0x1fbb: "\u0391",
+	0x1fbc: "\u0391",
+	0x1fbe: "\u03b9",
+	0x1fc1: "\u00a8",
+	0x1fc2: "\u03b7",
+	0x1fc3: "\u03b7",
+	0x1fc4: "\u03b7",
+	0x1fc6: "\u03b7",
+	0x1fc7: "\u03b7",
+	0x1fc8: "\u0395",
+	0x1fc9: "\u0395",
+	0x1fca: "\u0397",
+	0x1fcb: "\u0397",
+	0x1fcc: "\u0397",
+	0x1fcd: "\u1fbf",
+	0x1fce: "\u1fbf",
+	0x1fcf: "\u1fbf",
+	0x1fd0: "\u03b9",
+	0x1fd1: "\u03b9",
+	0x1fd2: "\u03b9",
+	0x1fd3: "\u03b9",
+	0x1fd6: "\u03b9",
+	0x1fd7: "\u03b9",
+	0x1fd8: "\u0399",
+	0x1fd9: "\u0399",
+	0x1fda: "\u0399",
+	0x1fdb: "\u0399",
+	0x1fdd: "\u1ffe",
+	0x1fde: "\u1ffe",
+	0x1fdf: "\u1ffe",
+	0x1fe0: "\u03c5",
+	0x1fe1: "\u03c5",
+	0x1fe2: "\u03c5",
+	0x1fe3: "\u03c5",
+	0x1fe4: "\u03c1",
+	0x1fe5: "\u03c1",
+	0x1fe6: "\u03c5",
+	0x1fe7: "\u03c5",
+	0x1fe8: "\u03a5",
+	0x1fe9: "\u03a5",
+	0x1fea: "\u03a5",
+	0x1feb: "\u03a5",
+	0x1fec: "\u03a1",
+	0x1fed: "\u00a8",
+	0x1fee: "\u00a8",
+	0x1fef: "`",
+	0x1ff2: "\u03c9",
+	0x1ff3: "\u03c9",
+	0x1ff4: "\u03c9",
+	0x1ff6: "\u03c9",
+	0x1ff7: "\u03c9",
+	0x1ff8: "\u039f",
+	0x1ff9: "\u039f",
+	0x1ffa: "\u03a9",
+	0x1ffb: "\u03a9",
+	0x1ffc: "\u03a9",
+	0x1ffd: "\u00b4",
+}
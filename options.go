@@ -0,0 +1,59 @@
+package gosseract
+
+// Options overrides the configuration of a Client for a single recognition, see Client.TextWithOptions.
+// The zero value of each field keeps the configuration of the client as it is.
+type Options struct {
+
+	// Languages to be detected instead of Client.Languages.
+	Languages []string
+
+	// PageSegMode to be used instead of the mode set by Client.SetPageSegMode.
+	// PSM_OSD_ONLY, the zero value, doesn't recognize text anyway, so it's treated as unspecified.
+	PageSegMode PageSegMode
+
+	// Whitelist and Blacklist of characters, see Client.SetWhitelist and Client.SetBlacklist.
+	Whitelist string
+	Blacklist string
+
+	// Variables to be set in addition to, or instead of, Client.Variables.
+	Variables map[SettableVariable]string
+
+	// Preprocess to be used instead of Client.Preprocess, if not nil.
+	Preprocess *PreprocessOptions
+
+	// Constraint is a regular expression the whole text must match, such as `[A-Z]{2}\d{6}`, decoding the text
+	// from the choices of the symbols rather than as tesseract reads it, see NewConstraint.
+	// Texts of no choices matching fail by ErrNoMatch.
+	Constraint string
+}
+
+// variables merges Whitelist and Blacklist into Variables.
+func (opts Options) variables() map[SettableVariable]string {
+	vars := map[SettableVariable]string{}
+	for key, value := range opts.Variables {
+		vars[key] = value
+	}
+	if opts.Whitelist != "" {
+		vars[TESSEDIT_CHAR_WHITELIST] = opts.Whitelist
+	}
+	if opts.Blacklist != "" {
+		vars[TESSEDIT_CHAR_BLACKLIST] = opts.Blacklist
+	}
+	if _, ok := vars[LSTM_CHOICE_MODE]; !ok && opts.Constraint != "" {
+		vars[LSTM_CHOICE_MODE] = "2"
+	}
+	return vars
+}
+
+// sameLanguages reports whether the languages of a and b are the same, in the same order.
+func sameLanguages(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
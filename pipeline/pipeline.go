@@ -0,0 +1,297 @@
+// Package pipeline defines the processing of images declaratively, as named Definitions of the configuration
+// and the preprocessing of the recognition, the filters of the text, the extractors of fields and the output,
+// registered in code or read from JSON, so that the command gosseract, by -pipeline, and the server,
+// by server.Profile.Pipeline, process images of the same kind identically.
+//
+//	[{
+//		"name": "invoices",
+//		"config": {"Languages": ["eng", "deu"], "Preprocess": {"Contrast": true}},
+//		"options": {"PageSegMode": 6},
+//		"filters": ["trim", "mask_pii"],
+//		"extractors": ["money"],
+//		"locale": "de-DE",
+//		"output": {"format": "json"}
+//	}]
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chennqqi/gosseract/v2"
+	"github.com/chennqqi/gosseract/v2/extract"
+	"github.com/chennqqi/gosseract/v2/store"
+)
+
+// Recognizer recognizes text of image data with options and the confidence,
+// which gosseract.Client, gosseract.Recognizer and gosseract.Pool implement.
+type Recognizer interface {
+	TextWithConfidence(ctx context.Context, data []byte, opts gosseract.Options) (string, float64, error)
+}
+
+// Filter transforms the text recognized, such as masking personally identifiable information.
+type Filter func(text string) string
+
+// Extractor extracts fields from the text filtered, in the locale of the pipeline, such as amounts of money.
+// The fields are marshaled into JSON of outputs.
+type Extractor func(text string, locale string) (interface{}, error)
+
+// Format is the format of outputs of pipelines.
+type Format string
+
+const (
+	// FormatText outputs the text, which is the default.
+	FormatText Format = "text"
+	// FormatJSON outputs Result as JSON.
+	FormatJSON Format = "json"
+)
+
+// Output specifies how results of the pipeline are written.
+type Output struct {
+	Format Format `json:"format,omitempty"`
+
+	// Encoding and Newline are the names of store.ParseEncoding and store.ParseNewline, UTF-8 with LF if empty.
+	Encoding string `json:"encoding,omitempty"`
+	Newline  string `json:"newline,omitempty"`
+}
+
+// Definition is a named pipeline.
+type Definition struct {
+	Name string `json:"name"`
+
+	// Config configures the recognizer running the pipeline, such as Languages and Preprocess,
+	// and Options each recognition, see gosseract.Client.TextWithOptions.
+	Config  gosseract.Config  `json:"config"`
+	Options gosseract.Options `json:"options"`
+
+	// Filters are the names of the filters applied to the text in order, see RegisterFilter,
+	// and Extractors those of the extractors of fields from the text filtered, see RegisterExtractor.
+	Filters    []string `json:"filters,omitempty"`
+	Extractors []string `json:"extractors,omitempty"`
+
+	// Locale of the extractors, such as "de-DE", inferred by each extractor if empty.
+	Locale string `json:"locale,omitempty"`
+
+	Output Output `json:"output"`
+}
+
+// Result is the result of a pipeline.
+type Result struct {
+	Pipeline   string  `json:"pipeline"`
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+
+	// Fields are the fields extracted by the names of the extractors.
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+var (
+	mu          sync.RWMutex
+	definitions = map[string]Definition{}
+	filters     = map[string]Filter{
+		"trim": strings.TrimSpace,
+		// join_lines joins the lines into a line, such as of labels and single fields.
+		"join_lines": func(text string) string { return strings.Join(strings.Fields(text), " ") },
+		"mask_pii": func(text string) string {
+			masked, _ := extract.MaskPII(text)
+			return masked
+		},
+	}
+	extractors = map[string]Extractor{
+		"money": func(text, locale string) (interface{}, error) { return extract.FindMoney(text, locale), nil },
+		"pii":   func(text, locale string) (interface{}, error) { return extract.FindPII(text), nil },
+	}
+)
+
+// RegisterFilter registers the filter by the name for Definition.Filters, replacing the one of the name, if any.
+// The filters "trim", "join_lines" and "mask_pii" are registered by default.
+func RegisterFilter(name string, filter Filter) {
+	mu.Lock()
+	defer mu.Unlock()
+	filters[name] = filter
+}
+
+// RegisterExtractor registers the extractor by the name for Definition.Extractors, replacing the one of the name,
+// if any. The extractors "money" of extract.FindMoney and "pii" of extract.FindPII are registered by default.
+func RegisterExtractor(name string, extractor Extractor) {
+	mu.Lock()
+	defer mu.Unlock()
+	extractors[name] = extractor
+}
+
+// Register registers the pipeline by its name, replacing the one of the name, if any.
+// Its filters and extractors must be registered beforehand.
+func Register(def Definition) error {
+	if def.Name == "" || strings.Contains(def.Name, "/") {
+		return fmt.Errorf("invalid name of pipeline %q", def.Name)
+	}
+	if _, err := def.Output.encoding(); err != nil {
+		return fmt.Errorf("invalid output of pipeline %q: %v", def.Name, err)
+	}
+	switch def.Output.Format {
+	case "", FormatText, FormatJSON:
+	default:
+		return fmt.Errorf("unknown format %q of pipeline %q", def.Output.Format, def.Name)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, name := range def.Filters {
+		if _, ok := filters[name]; !ok {
+			return fmt.Errorf("unknown filter %q of pipeline %q", name, def.Name)
+		}
+	}
+	for _, name := range def.Extractors {
+		if _, ok := extractors[name]; !ok {
+			return fmt.Errorf("unknown extractor %q of pipeline %q", name, def.Name)
+		}
+	}
+	definitions[def.Name] = def
+	return nil
+}
+
+// Load reads the JSON array of definitions, such as of a file of deployments, and registers them all.
+func Load(r io.Reader) error {
+	defs := []Definition{}
+	if err := json.NewDecoder(r).Decode(&defs); err != nil {
+		return fmt.Errorf("failed to read pipelines: %v", err)
+	}
+	for _, def := range defs {
+		if err := Register(def); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup returns the pipeline of the name.
+func Lookup(name string) (Definition, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	def, ok := definitions[name]
+	return def, ok
+}
+
+// Names returns the names of the pipelines registered in order.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run recognizes the image data by the recognizer, which must be configured by Config, such as built by
+// Config.Build, and processes the text by Process.
+func (def Definition) Run(ctx context.Context, rec Recognizer, data []byte) (*Result, error) {
+	text, confidence, err := rec.TextWithConfidence(ctx, data, def.Options)
+	if err != nil {
+		return nil, err
+	}
+	return def.Process(text, confidence)
+}
+
+// Process filters the text recognized and extracts the fields from it, for texts recognized otherwise than Run,
+// such as by gosseract.Speculate.
+func (def Definition) Process(text string, confidence float64) (*Result, error) {
+	filterFuncs, extractorFuncs, err := def.funcs()
+	if err != nil {
+		return nil, err
+	}
+	for _, filter := range filterFuncs {
+		text = filter(text)
+	}
+	result := &Result{Pipeline: def.Name, Text: text, Confidence: confidence}
+	for i, name := range def.Extractors {
+		fields, err := extractorFuncs[i](text, def.Locale)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %v", name, err)
+		}
+		if result.Fields == nil {
+			result.Fields = map[string]interface{}{}
+		}
+		result.Fields[name] = fields
+	}
+	return result, nil
+}
+
+// funcs looks up the filters and the extractors of the pipeline, in order, to run them without the lock,
+// so that they may register others, and registering isn't blocked while they run.
+func (def Definition) funcs() ([]Filter, []Extractor, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	filterFuncs := make([]Filter, 0, len(def.Filters))
+	for _, name := range def.Filters {
+		filter, ok := filters[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown filter %q of pipeline %q", name, def.Name)
+		}
+		filterFuncs = append(filterFuncs, filter)
+	}
+	extractorFuncs := make([]Extractor, 0, len(def.Extractors))
+	for _, name := range def.Extractors {
+		extractor, ok := extractors[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown extractor %q of pipeline %q", name, def.Name)
+		}
+		extractorFuncs = append(extractorFuncs, extractor)
+	}
+	return filterFuncs, extractorFuncs, nil
+}
+
+// encoding returns the encoding of the output.
+func (out Output) encoding() (store.Encoding, error) {
+	enc := store.Encoding{}
+	var err error
+	if out.Encoding != "" {
+		if enc, err = store.ParseEncoding(out.Encoding); err != nil {
+			return enc, err
+		}
+	}
+	if out.Newline != "" {
+		enc.Newline, err = store.ParseNewline(out.Newline)
+	}
+	return enc, err
+}
+
+// Writer writes results of the pipeline to w by its output, the texts separated by form feeds,
+// or JSON lines of the results, with the BOM of the encoding leading the output only.
+type Writer struct {
+	w      io.Writer
+	format Format
+	enc    store.Encoding
+	n      int
+}
+
+// NewWriter creates the Writer of the results of the pipeline.
+func (def Definition) NewWriter(w io.Writer) *Writer {
+	// The encoding is validated by Register.
+	enc, _ := def.Output.encoding()
+	return &Writer{w: w, format: def.Output.Format, enc: enc}
+}
+
+// Write writes the result.
+func (w *Writer) Write(result *Result) error {
+	text := result.Text + "\n"
+	if w.format == FormatJSON {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		text = string(b) + "\n"
+	} else if w.n != 0 {
+		text = "\f" + text
+	}
+	enc := w.enc
+	enc.BOM = enc.BOM && w.n == 0
+	w.n++
+	_, err := w.w.Write(enc.Encode(text))
+	return err
+}
@@ -0,0 +1,99 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chennqqi/gosseract/v2"
+	"github.com/chennqqi/gosseract/v2/extract"
+	. "github.com/otiai10/mint"
+)
+
+// fakeRecognizer answers the image data as the text, with the whitelist of the options if any.
+type fakeRecognizer struct{}
+
+func (fakeRecognizer) TextWithConfidence(ctx context.Context, data []byte, opts gosseract.Options) (string, float64, error) {
+	return string(data) + opts.Whitelist, 87, nil
+}
+
+func TestLoad(t *testing.T) {
+	err := Load(strings.NewReader(`[{
+		"name": "invoices",
+		"config": {"Languages": ["eng", "deu"]},
+		"options": {"Whitelist": " EUR"},
+		"filters": ["trim", "mask_pii"],
+		"extractors": ["money"],
+		"locale": "de-DE",
+		"output": {"format": "json", "encoding": "utf-8-bom"}
+	}]`))
+	Expect(t, err).ToBe(nil)
+	def, ok := Lookup("invoices")
+	Expect(t, ok).ToBe(true)
+	Expect(t, def.Config.Languages).ToBe([]string{"eng", "deu"})
+	Expect(t, def.Output.Format).ToBe(FormatJSON)
+	Expect(t, Names()).ToBe([]string{"invoices"})
+
+	When(t, "filters or extractors are unknown", func(t *testing.T) {
+		Expect(t, Register(Definition{Name: "a", Filters: []string{"unknown"}})).Not().ToBe(nil)
+		Expect(t, Register(Definition{Name: "a", Extractors: []string{"unknown"}})).Not().ToBe(nil)
+		Expect(t, Register(Definition{Name: "a/b"})).Not().ToBe(nil)
+		Expect(t, Register(Definition{Name: "a", Output: Output{Encoding: "latin1"}})).Not().ToBe(nil)
+		_, ok := Lookup("a")
+		Expect(t, ok).ToBe(false)
+	})
+}
+
+func TestDefinition_Run(t *testing.T) {
+	RegisterFilter("upper", strings.ToUpper)
+	RegisterExtractor("length", func(text, locale string) (interface{}, error) { return len(text), nil })
+	def := Definition{
+		Name:       "receipts",
+		Options:    gosseract.Options{Whitelist: " 12,50 EUR "},
+		Filters:    []string{"trim", "upper", "mask_pii"},
+		Extractors: []string{"money", "length"},
+		Locale:     "de-DE",
+	}
+	Expect(t, Register(def)).ToBe(nil)
+
+	result, err := def.Run(context.Background(), fakeRecognizer{}, []byte("  total mail a@example.com"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, result.Pipeline).ToBe("receipts")
+	Expect(t, strings.Contains(result.Text, "example")).ToBe(false)
+	Expect(t, strings.HasSuffix(result.Text, "12,50 EUR")).ToBe(true)
+	Expect(t, result.Confidence).ToBe(87.0)
+	Expect(t, result.Fields["length"]).ToBe(len(result.Text))
+	amounts := result.Fields["money"].([]extract.Money)
+	Expect(t, len(amounts)).ToBe(1)
+	Expect(t, amounts[0].Currency).ToBe("EUR")
+
+	When(t, "a filter registers another", func(t *testing.T) {
+		RegisterFilter("registering", func(text string) string {
+			RegisterFilter("registered", strings.ToLower)
+			return text
+		})
+		result, err := Definition{Name: "nested", Filters: []string{"registering"}}.Process("Text", 90)
+		Expect(t, err).ToBe(nil)
+		Expect(t, result.Text).ToBe("Text")
+		result, err = Definition{Name: "nested", Filters: []string{"registered"}}.Process("Text", 90)
+		Expect(t, err).ToBe(nil)
+		Expect(t, result.Text).ToBe("text")
+	})
+}
+
+func TestWriter(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	w := Definition{Output: Output{Newline: "crlf"}}.NewWriter(buf)
+	Expect(t, w.Write(&Result{Text: "a\nb"})).ToBe(nil)
+	Expect(t, w.Write(&Result{Text: "c"})).ToBe(nil)
+	Expect(t, buf.String()).ToBe("a\r\nb\r\n\fc\r\n")
+
+	When(t, "the format is JSON", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		w := Definition{Output: Output{Format: FormatJSON, Encoding: "utf-8-bom"}}.NewWriter(buf)
+		Expect(t, w.Write(&Result{Pipeline: "p", Text: "a"})).ToBe(nil)
+		Expect(t, w.Write(&Result{Pipeline: "p", Text: "b"})).ToBe(nil)
+		Expect(t, buf.String()).ToBe("\xef\xbb\xbf" + `{"pipeline":"p","text":"a","confidence":0}` + "\n" + `{"pipeline":"p","text":"b","confidence":0}` + "\n")
+	})
+}
@@ -0,0 +1,191 @@
+// Package plates recognizes license plates, combining preprocessing for photos of plates,
+// character whitelists and patterns of plate formats by countries, and candidates ranked by confidence.
+package plates
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chennqqi/gosseract/v2"
+	"github.com/chennqqi/gosseract/v2/document"
+)
+
+// Patterns are the formats of license plates by ISO 3166 country codes, matched against the text
+// in upper case without spaces and hyphens. Patterns can be added or replaced for other countries.
+var Patterns = map[string]*regexp.Regexp{
+	"BR": regexp.MustCompile(`^[A-Z]{3}[0-9][A-Z0-9][0-9]{2}$`),
+	"DE": regexp.MustCompile(`^[A-ZÄÖÜ]{1,3}[A-Z]{1,2}[1-9][0-9]{0,3}[EH]?$`),
+	"ES": regexp.MustCompile(`^[0-9]{4}[BCDFGHJKLMNPRSTVWXYZ]{3}$`),
+	"FR": regexp.MustCompile(`^[A-Z]{2}[0-9]{3}[A-Z]{2}$`),
+	"GB": regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z]{3}$`),
+	"IN": regexp.MustCompile(`^[A-Z]{2}[0-9]{1,2}[A-Z]{0,3}[0-9]{4}$`),
+	"IT": regexp.MustCompile(`^[A-Z]{2}[0-9]{3}[A-Z]{2}$`),
+	"NL": regexp.MustCompile(`^(?:[A-Z]{2}[0-9]{2}[A-Z]{2}|[0-9]{2}[A-Z]{2}[0-9]{2}|[0-9]{2}[A-Z]{3}[0-9]|[0-9][A-Z]{3}[0-9]{2}|` +
+		`[A-Z]{2}[0-9]{3}[A-Z]|[A-Z][0-9]{3}[A-Z]{2}|[A-Z]{3}[0-9]{2}[A-Z]|[0-9][A-Z]{2}[0-9]{3}|[0-9]{3}[A-Z]{2}[0-9])$`),
+	"PL": regexp.MustCompile(`^[A-Z]{2,3}[0-9A-Z]{4,5}$`),
+	"US": regexp.MustCompile(`^[A-Z0-9]{2,8}$`),
+}
+
+// whitelists are the characters of plates other than "A" to "Z" and "0" to "9" by countries.
+var whitelists = map[string]string{
+	"DE": "ÄÖÜ",
+}
+
+// Options returns the options to recognize plates of the country, see gosseract.Client.TextWithOptions.
+// Plates are usually photographed at an angle in uneven light, so they are rectified, normalized in contrast
+// and binarized locally, and characters are restricted to those of plates, with hyphens and spaces as separators.
+func Options(country string) gosseract.Options {
+	return gosseract.Options{
+		PageSegMode: gosseract.PSM_SINGLE_BLOCK,
+		Whitelist:   "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789- " + whitelists[strings.ToUpper(country)],
+		Preprocess: &gosseract.PreprocessOptions{
+			Rectify:  true,
+			Contrast: true,
+			Sauvola:  true,
+		},
+	}
+}
+
+// Candidate is a reading of a plate.
+type Candidate struct {
+	// Text of the plate in upper case without spaces and hyphens.
+	Text string `json:"text"`
+
+	// Confidence of the reading from 0 to 100, lowered by each character substituted to match the pattern.
+	Confidence float64 `json:"confidence"`
+
+	// Valid is whether Text matches the pattern of the country.
+	Valid bool `json:"valid"`
+}
+
+// substitutionPenalty lowers the confidence of candidates for each substituted character.
+const substitutionPenalty = 10
+
+// maxSubstitutions limits the ambiguous characters tried, not to explode candidates of long texts.
+const maxSubstitutions = 8
+
+// lookalikes are the pairs of letters and digits tesseract often confuses on plates.
+var lookalikes = map[rune]rune{
+	'O': '0', '0': 'O', 'I': '1', '1': 'I', 'B': '8', '8': 'B', 'S': '5', '5': 'S',
+	'Z': '2', '2': 'Z', 'G': '6', '6': 'G', 'D': '0', 'Q': '0',
+}
+
+// Read recognizes the plate in the image data by the client and returns the candidates, see Candidates.
+// The client is configured for plates of the country by Client.ApplyPreset, so use a client dedicated to plates.
+func Read(client *gosseract.Client, data []byte, country string) ([]Candidate, error) {
+	if err := client.ApplyPreset(Options(country)); err != nil {
+		return nil, err
+	}
+	if err := client.SetImageFromBytes(data); err != nil {
+		return nil, err
+	}
+	doc, err := client.Document()
+	if err != nil {
+		return nil, err
+	}
+	return Candidates(doc, country)
+}
+
+// Candidates returns the readings of the plate in the recognized document, ranked by validity and confidence.
+// Each line is read as a plate, and so are all the lines joined for plates of two lines.
+// Characters confused with lookalikes, such as "O" and "0", are substituted to match the pattern of the country.
+// It returns an error if the country has no pattern, see Patterns.
+func Candidates(doc *document.Document, country string) ([]Candidate, error) {
+	pattern, ok := Patterns[strings.ToUpper(country)]
+	if !ok {
+		return nil, fmt.Errorf("no pattern of plates for country %q", country)
+	}
+	readings := []Candidate{}
+	all, sum, count := "", 0.0, 0
+	for _, line := range doc.Rows() {
+		text, confidence := compact(line)
+		if text == "" {
+			continue
+		}
+		readings = append(readings, Candidate{Text: text, Confidence: confidence})
+		all += text
+		sum += confidence * float64(len(line.Words))
+		count += len(line.Words)
+	}
+	if len(readings) > 1 {
+		readings = append(readings, Candidate{Text: all, Confidence: sum / float64(count)})
+	}
+
+	best := map[string]Candidate{}
+	for _, reading := range readings {
+		for _, c := range variants(reading, pattern) {
+			if b, ok := best[c.Text]; !ok || c.Confidence > b.Confidence {
+				best[c.Text] = c
+			}
+		}
+	}
+	candidates := make([]Candidate, 0, len(best))
+	for _, c := range best {
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Valid != candidates[j].Valid {
+			return candidates[i].Valid
+		}
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		return candidates[i].Text < candidates[j].Text
+	})
+	return candidates, nil
+}
+
+// compact returns the text of the line in upper case without separators, and its mean confidence.
+func compact(line document.Line) (string, float64) {
+	text, sum := "", 0.0
+	for _, word := range line.Words {
+		text += word.Text
+		sum += word.Confidence
+	}
+	text = strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '.' || r == '·' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(text))
+	if len(line.Words) == 0 {
+		return text, 0
+	}
+	return text, sum / float64(len(line.Words))
+}
+
+// variants returns the reading as it is, and the substitutions of lookalikes matching the pattern.
+func variants(reading Candidate, pattern *regexp.Regexp) []Candidate {
+	reading.Valid = pattern.MatchString(reading.Text)
+	found := []Candidate{reading}
+	if reading.Valid {
+		return found
+	}
+	runes := []rune(reading.Text)
+	ambiguous := []int{}
+	for i, r := range runes {
+		if _, ok := lookalikes[r]; ok && len(ambiguous) < maxSubstitutions {
+			ambiguous = append(ambiguous, i)
+		}
+	}
+	for mask := 1; mask < 1<<len(ambiguous); mask++ {
+		variant := append([]rune{}, runes...)
+		substituted := 0
+		for bit, i := range ambiguous {
+			if mask&(1<<bit) != 0 {
+				variant[i] = lookalikes[runes[i]]
+				substituted++
+			}
+		}
+		if text := string(variant); pattern.MatchString(text) {
+			confidence := reading.Confidence - float64(substituted*substitutionPenalty)
+			if confidence < 0 {
+				confidence = 0
+			}
+			found = append(found, Candidate{Text: text, Confidence: confidence, Valid: true})
+		}
+	}
+	return found
+}
@@ -0,0 +1,49 @@
+package plates
+
+import (
+	"image"
+	"testing"
+
+	"github.com/chennqqi/gosseract/v2/document"
+	. "github.com/otiai10/mint"
+)
+
+func plateDocument(lines ...string) *document.Document {
+	b := &document.Builder{}
+	for i, text := range lines {
+		box := image.Rect(0, i*40, 200, i*40+30)
+		b.Line(document.Line{Box: box})
+		b.Word(document.Word{Box: box, Text: text, Confidence: 80})
+	}
+	return b.Document()
+}
+
+func TestCandidates(t *testing.T) {
+	candidates, err := Candidates(plateDocument("AB-123-CD"), "fr")
+	Expect(t, err).ToBe(nil)
+	Expect(t, candidates).ToBe([]Candidate{{Text: "AB123CD", Confidence: 80, Valid: true}})
+
+	When(t, "lookalikes are misrecognized", func(t *testing.T) {
+		candidates, err := Candidates(plateDocument("A8-I23-CD"), "FR")
+		Expect(t, err).ToBe(nil)
+		Expect(t, candidates[0]).ToBe(Candidate{Text: "AB123CD", Confidence: 60, Valid: true})
+		Expect(t, candidates[len(candidates)-1]).ToBe(Candidate{Text: "A8I23CD", Confidence: 80, Valid: false})
+	})
+
+	When(t, "the plate has two lines", func(t *testing.T) {
+		candidates, err := Candidates(plateDocument("AB12", "CDE"), "GB")
+		Expect(t, err).ToBe(nil)
+		Expect(t, candidates[0]).ToBe(Candidate{Text: "AB12CDE", Confidence: 80, Valid: true})
+	})
+
+	When(t, "the country is unknown", func(t *testing.T) {
+		_, err := Candidates(plateDocument("AB123"), "XX")
+		Expect(t, err).Not().ToBe(nil)
+	})
+}
+
+func TestOptions(t *testing.T) {
+	opts := Options("de")
+	Expect(t, opts.Whitelist).ToBe("ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789- ÄÖÜ")
+	Expect(t, opts.Preprocess.Contrast).ToBe(true)
+}
@@ -0,0 +1,69 @@
+package gosseract
+
+import (
+	"context"
+	"fmt"
+)
+
+// Admission describes an image about to be recognized, for Policy to admit or reject it.
+type Admission struct {
+	// Width and Height of the image in pixels, before any preprocessing.
+	Width, Height int
+
+	// Entropy is the Shannon entropy of the gray levels of the image in bits, from 0 to 8.
+	// Scans of documents are mostly of paper and ink, i.e. low entropy, while photos and noisy
+	// backgrounds, typical of CAPTCHAs, spread over the levels.
+	Entropy float64
+
+	// Metadata of the caller attached to the context by WithMetadata, such as the API key or the address of clients.
+	Metadata map[string]string
+}
+
+// Policy admits or rejects images before recognition, letting operators of public endpoints implement abuse controls,
+// such as refusing to solve CAPTCHAs, inside the pipeline. Returning an error rejects the image:
+// recognitions return the error as it is, so return PolicyError for errors.As to tell rejections from failures.
+// Policy is called once for each image set, before preprocessing, and is not called again for the same image once admitted.
+type Policy func(ctx context.Context, admission Admission) error
+
+// PolicyError is a rejection by Policy.
+type PolicyError struct {
+	Reason string
+}
+
+func (err *PolicyError) Error() string {
+	return fmt.Sprintf("rejected by policy: %s", err.Reason)
+}
+
+type metadataKey struct{}
+
+// WithMetadata returns the context carrying the metadata of the caller, passed to Policy as Admission.Metadata.
+func WithMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// MetadataFrom returns the metadata of the caller attached by WithMetadata, or nil.
+func MetadataFrom(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return metadata
+}
+
+const (
+	captchaMaxWidth   = 480
+	captchaMaxHeight  = 160
+	captchaMinAspect  = 1.5
+	captchaMinEntropy = 5.0
+)
+
+// RejectCaptchas is a Policy refusing obvious CAPTCHAs, i.e. small strips of few characters,
+// drawn over noisy or colorful backgrounds, rejecting them by PolicyError.
+// Small crops of clean text, such as single words cut from scans, have low entropy and are admitted.
+func RejectCaptchas(ctx context.Context, admission Admission) error {
+	w, h := admission.Width, admission.Height
+	if w == 0 || h == 0 || w > captchaMaxWidth || h > captchaMaxHeight {
+		return nil
+	}
+	if float64(w)/float64(h) >= captchaMinAspect && admission.Entropy >= captchaMinEntropy {
+		return &PolicyError{Reason: fmt.Sprintf("image of %dx%d with entropy %.1f looks like a CAPTCHA", w, h, admission.Entropy)}
+	}
+	return nil
+}
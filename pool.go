@@ -0,0 +1,258 @@
+package gosseract
+
+import (
+	"context"
+	"sync"
+)
+
+// Priority is the class of recognitions waiting for Pool, see WithPriority.
+type Priority int
+
+const (
+	// PriorityInteractive is for users waiting for results, such as of UIs, which is the default.
+	PriorityInteractive Priority = iota
+	// PriorityBatch is for backfills and pipelines, which wait for interactive recognitions,
+	// but get one of every BatchShare Recognizers released while both are waiting, not to starve.
+	PriorityBatch
+)
+
+// BatchShare is the starvation protection of PriorityBatch: when both classes are waiting,
+// one of every BatchShare Recognizers released goes to batch.
+const BatchShare = 5
+
+type priorityKey struct{}
+
+// WithPriority returns the context of recognitions of the priority, to wait for Pool in its class.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFrom returns the priority attached by WithPriority, or PriorityInteractive.
+func PriorityFrom(ctx context.Context) Priority {
+	priority, _ := ctx.Value(priorityKey{}).(Priority)
+	if priority != PriorityBatch {
+		return PriorityInteractive
+	}
+	return priority
+}
+
+// Pool recognizes images in parallel by Recognizers built from the same Config, as many as Tuning.Clients.
+// It's safe to share among goroutines; recognitions wait for an idle Recognizer, in order of arrival within
+// the class of their priority, see WithPriority.
+// Idle Recognizers are closed by Reclaim, and built again on demand, or ahead of traffic by Warmup.
+type Pool struct {
+	cfg     Config
+	mu      sync.Mutex
+	idle    []*Recognizer
+	waiters [2][]chan *Recognizer
+	// interactive recognitions handed Recognizers in a row while batch ones are waiting
+	streak int
+	size   int
+	// Recognizers alive, idle or in use, up to size
+	built     int
+	closed    chan struct{}
+	closeOnce sync.Once
+	drained   *sync.Cond
+	unhook    func()
+}
+
+// BuildPool builds the Recognizers of the pool by Config.Build, tuned by cfg.Tuning.
+// It's due to caller to Close the Pool.
+func (cfg Config) BuildPool() (*Pool, error) {
+	tuning := cfg.Tuning.resolve()
+	if err := tuning.check(); err != nil {
+		return nil, err
+	}
+	pool := &Pool{cfg: cfg, size: tuning.Clients, closed: make(chan struct{})}
+	pool.drained = sync.NewCond(&pool.mu)
+	if err := pool.Warmup(context.Background()); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	pool.unhook = onReclaim(pool.reclaim)
+	return pool, nil
+}
+
+// Size returns the number of Recognizers of the pool.
+func (pool *Pool) Size() int {
+	return pool.size
+}
+
+// Warmup builds the Recognizers closed by Reclaim, each warmed up by Client.Warmup, so that the next recognitions
+// don't wait for tesseract to load the models. Recognizers of BuildPool are warmed up already.
+// It returns ctx.Err() if ctx is done before all of them are built, and ErrClientClosed after Close.
+func (pool *Pool) Warmup(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pool.mu.Lock()
+		select {
+		case <-pool.closed:
+			pool.mu.Unlock()
+			return ErrClientClosed
+		default:
+		}
+		if pool.built == pool.size {
+			pool.mu.Unlock()
+			return nil
+		}
+		pool.built++
+		pool.mu.Unlock()
+		rec, err := pool.build()
+		if err != nil {
+			return err
+		}
+		pool.release(rec)
+	}
+}
+
+// build builds a Recognizer counted in built already, uncounting it if it fails.
+func (pool *Pool) build() (*Recognizer, error) {
+	rec, err := pool.cfg.Build()
+	if err != nil {
+		pool.mu.Lock()
+		pool.built--
+		pool.drained.Broadcast()
+		pool.mu.Unlock()
+		return nil, err
+	}
+	return rec, nil
+}
+
+// reclaim closes the idle Recognizers, registered to Reclaim.
+func (pool *Pool) reclaim() {
+	pool.mu.Lock()
+	idle := pool.idle
+	pool.idle = nil
+	pool.built -= len(idle)
+	pool.drained.Broadcast()
+	pool.mu.Unlock()
+	for _, rec := range idle {
+		rec.Close()
+	}
+}
+
+// TextWithOptions recognizes the image data by an idle Recognizer, see Client.TextWithOptions.
+// It waits in the class of the priority of ctx, see WithPriority.
+// It returns ctx.Err() if ctx is done while waiting, and ErrClientClosed after Close.
+func (pool *Pool) TextWithOptions(ctx context.Context, data []byte, opts Options) (string, error) {
+	rec, err := pool.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer pool.release(rec)
+	return rec.TextWithOptions(ctx, data, opts)
+}
+
+// TextWithConfidence recognizes the image data like TextWithOptions, along with the mean confidence of the words,
+// see Client.TextWithConfidence.
+func (pool *Pool) TextWithConfidence(ctx context.Context, data []byte, opts Options) (string, float64, error) {
+	rec, err := pool.acquire(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	defer pool.release(rec)
+	return rec.TextWithConfidence(ctx, data, opts)
+}
+
+func (pool *Pool) acquire(ctx context.Context) (*Recognizer, error) {
+	pool.mu.Lock()
+	select {
+	case <-pool.closed:
+		pool.mu.Unlock()
+		return nil, ErrClientClosed
+	default:
+	}
+	if n := len(pool.idle); n != 0 {
+		rec := pool.idle[n-1]
+		pool.idle = pool.idle[:n-1]
+		pool.mu.Unlock()
+		return rec, nil
+	}
+	if pool.built < pool.size {
+		// Build again one closed by Reclaim.
+		pool.built++
+		pool.mu.Unlock()
+		return pool.build()
+	}
+	priority := PriorityFrom(ctx)
+	waiter := make(chan *Recognizer, 1)
+	pool.waiters[priority] = append(pool.waiters[priority], waiter)
+	pool.mu.Unlock()
+
+	var err error
+	select {
+	case rec := <-waiter:
+		return rec, nil
+	case <-pool.closed:
+		err = ErrClientClosed
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	pool.mu.Lock()
+	queue := pool.waiters[priority]
+	for i, w := range queue {
+		if w == waiter {
+			pool.waiters[priority] = append(queue[:i], queue[i+1:]...)
+			pool.mu.Unlock()
+			return nil, err
+		}
+	}
+	pool.mu.Unlock()
+	// Handed a Recognizer just as giving up, which goes to the next.
+	pool.release(<-waiter)
+	return nil, err
+}
+
+// release hands the Recognizer to the next waiting, interactive first but batch once in BatchShare,
+// or makes it idle.
+func (pool *Pool) release(rec *Recognizer) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	interactive, batch := len(pool.waiters[PriorityInteractive]), len(pool.waiters[PriorityBatch])
+	next := -1
+	switch {
+	case interactive != 0 && (batch == 0 || pool.streak < BatchShare-1):
+		next = int(PriorityInteractive)
+		if batch != 0 {
+			pool.streak++
+		}
+	case batch != 0:
+		next = int(PriorityBatch)
+		pool.streak = 0
+	}
+	if next < 0 {
+		pool.idle = append(pool.idle, rec)
+		pool.drained.Broadcast()
+		return
+	}
+	waiter := pool.waiters[next][0]
+	pool.waiters[next] = pool.waiters[next][1:]
+	waiter <- rec
+}
+
+// Close waits for the recognitions in progress, and closes all the Recognizers of the pool.
+// Recognitions waiting return ErrClientClosed.
+func (pool *Pool) Close() (err error) {
+	pool.closeOnce.Do(func() {
+		pool.mu.Lock()
+		close(pool.closed)
+		for len(pool.idle) < pool.built {
+			pool.drained.Wait()
+		}
+		idle := pool.idle
+		pool.idle = nil
+		pool.built = 0
+		pool.mu.Unlock()
+		if pool.unhook != nil {
+			pool.unhook()
+		}
+		for _, rec := range idle {
+			if e := rec.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	})
+	return err
+}
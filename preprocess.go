@@ -0,0 +1,122 @@
+package gosseract
+
+import (
+	"image"
+	"math"
+)
+
+// PreprocessOptions specifies image preprocessing applied by gosseract itself,
+// before the image is handed to tesseract::TessBaseAPI.
+// The zero value passes the image as it is.
+type PreprocessOptions struct {
+
+	// MaxDimension caps the width and height of the image, and MaxPixels caps its area.
+	// Oversized images are downscaled to fit in them before any other step,
+	// protecting latency from huge photos. Zero means no limit.
+	// Bounding boxes are mapped back to the coordinates of the original image, see Client.ImageScale.
+	MaxDimension int
+	MaxPixels    int
+
+	// Rectify detects the document quadrilateral in the image, e.g. a page photographed
+	// at an angle with a phone camera, and warps it to a flat rectangle.
+	// The image is left untouched when no document is detected.
+	// NOTE: Bounding boxes are reported in the coordinates of the rectified image.
+	Rectify bool
+
+	// NormalizeBackground flattens shadows and uneven illumination across the page,
+	// typical for phone photos, by Leptonica's adaptive background normalization.
+	// It helps binarization to keep characters in shaded areas.
+	NormalizeBackground bool
+
+	// Contrast stretches the contrast locally, tile by tile, by Leptonica's contrast normalization,
+	// which brings out faded or low-contrast text, e.g. on photos of license plates or displays.
+	Contrast bool
+
+	// Sauvola binarizes the image by Sauvola's local thresholding, instead of the Otsu
+	// thresholding of tesseract, which performs poorly on stained or low-contrast documents.
+	// Client.SetThresholdingMethod turns this on for tesseract earlier than 5.0,
+	// which cannot select the thresholding method by itself.
+	Sauvola bool
+
+	// RemoveGridLines erases long horizontal and vertical rules, such as those of tables and forms,
+	// which fragment characters touching them. The image is converted to grayscale.
+	// The boxes of the rules erased are kept in document.Document.GridLines, to reconstruct tables.
+	RemoveGridLines bool
+
+	// JoinDots joins the dots of dot-matrix prints, such as dotted expiry dates and lot codes on packaging,
+	// into strokes tesseract can recognize, by morphological closing as large as the typical dot.
+	// The image is binarized.
+	JoinDots bool
+
+	// SevenSegment joins the segments of seven-segment digits, such as those of meters, multimeters and scoreboards,
+	// which tesseract otherwise reads as separate strokes. The image is binarized,
+	// and light digits on dark displays, such as LEDs, are inverted to dark on light.
+	SevenSegment bool
+}
+
+// merge returns the options with the fields of other set, i.e. not of the zero value, such as of presets.
+// Steps on are never turned off, and fields added to PreprocessOptions MUST be merged here as well.
+func (opts PreprocessOptions) merge(other PreprocessOptions) PreprocessOptions {
+	if other.MaxDimension != 0 {
+		opts.MaxDimension = other.MaxDimension
+	}
+	if other.MaxPixels != 0 {
+		opts.MaxPixels = other.MaxPixels
+	}
+	opts.Rectify = opts.Rectify || other.Rectify
+	opts.NormalizeBackground = opts.NormalizeBackground || other.NormalizeBackground
+	opts.Contrast = opts.Contrast || other.Contrast
+	opts.Sauvola = opts.Sauvola || other.Sauvola
+	opts.RemoveGridLines = opts.RemoveGridLines || other.RemoveGridLines
+	opts.JoinDots = opts.JoinDots || other.JoinDots
+	opts.SevenSegment = opts.SevenSegment || other.SevenSegment
+	return opts
+}
+
+// downscaleFactor returns the factor to scale an image of w x h down
+// within MaxDimension and MaxPixels, or 1 if the image already fits.
+func (opts PreprocessOptions) downscaleFactor(w, h int) float64 {
+	scale := 1.0
+	longest := w
+	if h > longest {
+		longest = h
+	}
+	if opts.MaxDimension > 0 && longest > opts.MaxDimension {
+		scale = float64(opts.MaxDimension) / float64(longest)
+	}
+	area := float64(w) * float64(h)
+	if opts.MaxPixels > 0 && area*scale*scale > float64(opts.MaxPixels) {
+		scale = math.Sqrt(float64(opts.MaxPixels) / area)
+	}
+	return scale
+}
+
+// scaleRect maps a rectangle on the original image to the image downscaled by scale.
+func scaleRect(r image.Rectangle, scale float64) image.Rectangle {
+	if scale == 1 || scale == 0 {
+		return r
+	}
+	return image.Rect(
+		int(math.Round(float64(r.Min.X)*scale)), int(math.Round(float64(r.Min.Y)*scale)),
+		int(math.Round(float64(r.Max.X)*scale)), int(math.Round(float64(r.Max.Y)*scale)),
+	)
+}
+
+// unscaleRect maps a rectangle on an image downscaled by scale back to the original image.
+func unscaleRect(r image.Rectangle, scale float64) image.Rectangle {
+	if scale == 1 || scale == 0 {
+		return r
+	}
+	return image.Rect(
+		int(math.Round(float64(r.Min.X)/scale)), int(math.Round(float64(r.Min.Y)/scale)),
+		int(math.Round(float64(r.Max.X)/scale)), int(math.Round(float64(r.Max.Y)/scale)),
+	)
+}
+
+// unscalePoint maps a point on an image downscaled by scale back to the original image.
+func unscalePoint(p image.Point, scale float64) image.Point {
+	if scale == 1 || scale == 0 {
+		return p
+	}
+	return image.Pt(int(math.Round(float64(p.X)/scale)), int(math.Round(float64(p.Y)/scale)))
+}
@@ -0,0 +1,85 @@
+package gosseract
+
+// Presets are Options tuned for specific kinds of text, to be used with Client.TextWithOptions
+// for a single recognition, or Client.ApplyPreset to configure a client for all the following ones.
+var (
+	// PresetMICR recognizes the MICR E-13B line at the bottom of bank checks, which requires "mcr.traineddata",
+	// e.g. from https://github.com/BigPino67/Tesseract-MICR-OCR, representing the symbols of transit, amount,
+	// on-us and dash by "A", "B", "C" and "D". The text is parsed by micr.Parse.
+	PresetMICR = Options{
+		Languages:   []string{"mcr"},
+		PageSegMode: PSM_SINGLE_LINE,
+		Whitelist:   "0123456789ABCD",
+	}
+
+	// PresetMRZ recognizes the machine readable zone of passports and ID cards, parsed by mrz.Parse.
+	PresetMRZ = Options{
+		PageSegMode: PSM_SINGLE_BLOCK,
+		Whitelist:   "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789<",
+	}
+
+	// PresetDotMatrix recognizes dot-matrix prints, such as receipts of impact printers and dotted expiry dates
+	// and lot codes on packaging, e.g. "EXP 12/2025 LOT A1234", joining their dots by PreprocessOptions.JoinDots.
+	PresetDotMatrix = Options{
+		PageSegMode: PSM_SINGLE_BLOCK,
+		Whitelist:   "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789/.:- ",
+		Preprocess:  &PreprocessOptions{Contrast: true, JoinDots: true},
+	}
+
+	// PresetSevenSegment recognizes seven-segment digits of LCD and LED displays, such as meters, multimeters
+	// and scoreboards, joining their segments by PreprocessOptions.SevenSegment. The languages of the client are kept,
+	// but models trained on the digits recognize them far better, see SevenSegmentModel.
+	PresetSevenSegment = Options{
+		PageSegMode: PSM_SINGLE_LINE,
+		Whitelist:   "0123456789.:- ",
+		Preprocess:  &PreprocessOptions{Contrast: true, SevenSegment: true},
+	}
+)
+
+// SevenSegmentModels are the names of traineddata of seven-segment digits, in order of preference, i.e.
+// "ssd" from https://github.com/Shreeshrii/tessdata_ssd and "letsgodigital" from https://github.com/arturaugusto/display_ocr.
+var SevenSegmentModels = []string{"ssd", "letsgodigital"}
+
+// SevenSegmentModel returns the first of SevenSegmentModels installed, or "" if none is, to hook it up to the preset:
+//
+//	preset := gosseract.PresetSevenSegment
+//	if model := gosseract.SevenSegmentModel(); model != "" {
+//		preset.Languages = []string{model}
+//	}
+//	client.ApplyPreset(preset)
+func SevenSegmentModel() string {
+	installed, err := GetAvailableLanguages()
+	if err != nil {
+		return ""
+	}
+	for _, model := range SevenSegmentModels {
+		for _, language := range installed {
+			if language == model {
+				return model
+			}
+		}
+	}
+	return ""
+}
+
+// ApplyPreset configures the client by the preset, or any Options, for all the following recognitions.
+// Unlike Client.TextWithOptions, the configuration is kept, and fields of the zero value are left as they are.
+func (client *Client) ApplyPreset(preset Options) error {
+	if len(preset.Languages) != 0 && !sameLanguages(preset.Languages, client.Languages) {
+		if err := client.SetLanguage(append([]string{}, preset.Languages...)...); err != nil {
+			return err
+		}
+	}
+	for key, value := range preset.variables() {
+		if err := client.SetVariable(key, value); err != nil {
+			return err
+		}
+	}
+	if preset.Preprocess != nil {
+		client.Preprocess = client.Preprocess.merge(*preset.Preprocess)
+	}
+	if preset.PageSegMode != PSM_OSD_ONLY {
+		return client.SetPageSegMode(preset.PageSegMode)
+	}
+	return nil
+}
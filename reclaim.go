@@ -0,0 +1,73 @@
+package gosseract
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var reclaimHooks = struct {
+	sync.Mutex
+	funcs []*func()
+}{}
+
+// Reclaim releases memory which gosseract can rebuild on demand, so that long-running services
+// can respond to memory pressure: the library-level caches of tesseract (see ClearPersistentCache),
+// idle Recognizers of Pool, and whatever functions registered by OnReclaim release.
+// Clients are not touched, because they are not safe to use concurrently; use Client.Reclaim for them.
+func Reclaim() {
+	ClearPersistentCache()
+	reclaimHooks.Lock()
+	funcs := append([]*func(){}, reclaimHooks.funcs...)
+	reclaimHooks.Unlock()
+	for _, f := range funcs {
+		(*f)()
+	}
+}
+
+// OnReclaim registers f to be called by Reclaim,
+// e.g. to drop idle clients pooled by the application, or to call Client.Reclaim on them.
+// Pool registers itself to drop its idle Recognizers.
+func OnReclaim(f func()) {
+	onReclaim(f)
+}
+
+// onReclaim registers f like OnReclaim, and returns the func to unregister it.
+func onReclaim(f func()) (remove func()) {
+	reclaimHooks.Lock()
+	defer reclaimHooks.Unlock()
+	hook := &f
+	reclaimHooks.funcs = append(reclaimHooks.funcs, hook)
+	return func() {
+		reclaimHooks.Lock()
+		defer reclaimHooks.Unlock()
+		for i, h := range reclaimHooks.funcs {
+			if h == hook {
+				reclaimHooks.funcs = append(reclaimHooks.funcs[:i:i], reclaimHooks.funcs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// WatchMemory checks the resident memory of this process every interval, and calls Reclaim
+// whenever it exceeds limit bytes, e.g. somewhat below the memory limit of the container.
+// It blocks until ctx is done, so run it in its own goroutine.
+// An error is returned immediately if the resident memory cannot be measured on this platform.
+func WatchMemory(ctx context.Context, limit uint64, interval time.Duration) error {
+	if _, err := residentMemory(); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if rss, err := residentMemory(); err == nil && rss > limit {
+				Reclaim()
+			}
+		}
+	}
+}
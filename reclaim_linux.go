@@ -0,0 +1,23 @@
+//go:build linux
+// +build linux
+
+package gosseract
+
+import (
+	"fmt"
+	"os"
+)
+
+// residentMemory returns the resident set size of this process in bytes,
+// including memory allocated by tesseract, which Go's runtime statistics cannot see.
+func residentMemory() (uint64, error) {
+	b, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0, err
+	}
+	var size, resident uint64
+	if _, err := fmt.Sscan(string(b), &size, &resident); err != nil {
+		return 0, fmt.Errorf("failed to parse /proc/self/statm: %v", err)
+	}
+	return resident * uint64(os.Getpagesize()), nil
+}
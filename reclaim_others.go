@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package gosseract
+
+import "fmt"
+
+// residentMemory returns the resident set size of this process in bytes,
+// including memory allocated by tesseract, which Go's runtime statistics cannot see.
+func residentMemory() (uint64, error) {
+	return 0, fmt.Errorf("measuring resident memory is not supported on this platform")
+}
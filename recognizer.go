@@ -0,0 +1,146 @@
+package gosseract
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chennqqi/gosseract/v2/normalize"
+)
+
+// Config is the configuration of a Recognizer, see Config.Build.
+type Config struct {
+
+	// Languages to be detected. If not specified, it's gonna be "eng".
+	Languages []string
+
+	// TessdataPrefix is the directory path to `tessdata`, see Client.TessdataPrefix.
+	TessdataPrefix string
+
+	// ConfigFilePath is a file path to the configuration for Tesseract, see Client.ConfigFilePath.
+	ConfigFilePath string
+
+	// PageSegMode to detect layout of characters.
+	// PSM_OSD_ONLY, the zero value, doesn't recognize text anyway, so it keeps the default of tesseract.
+	PageSegMode PageSegMode
+
+	// Variables to be set to tesseract::TessBaseAPI.
+	Variables map[SettableVariable]string
+
+	// Preprocess specifies image preprocessing applied before OCR.
+	Preprocess PreprocessOptions
+
+	// Normalize specifies script-specific normalization of results, see Client.Normalize.
+	Normalize normalize.Options
+
+	// Trim trims newlines from results, see Client.Trim.
+	Trim bool
+
+	// Grayscale converts color images into grayscale before tesseract, see Client.Grayscale.
+	Grayscale bool
+
+	// DisableOpenCL keeps tesseract from using OpenCL, see Client.DisableOpenCL.
+	DisableOpenCL bool
+
+	// TempDir is the directory to create temporary files in, see Client.TempDir.
+	TempDir string
+
+	// Policy admits or rejects images before recognition, see Client.Policy.
+	// It's a func, so it's left out of JSON, such as of server.Profile, to be set in code.
+	Policy Policy `json:"-"`
+
+	// Tuning is the concurrency of Pool built by Config.BuildPool.
+	Tuning Tuning
+}
+
+// Recognizer recognizes images with the configuration fixed by Config.Build.
+// Unlike Client, it has nothing to be changed after construction, so it's safe to share among goroutines.
+// Recognitions on the same Recognizer are serialized; build one for each goroutine to run them in parallel.
+type Recognizer struct {
+	mu     sync.Mutex
+	client *Client
+}
+
+// Build constructs a Recognizer with this configuration, initializing tesseract::TessBaseAPI at once,
+// so that errors of the configuration, such as unknown languages, are returned here.
+// It's due to caller to Close the Recognizer.
+func (cfg Config) Build() (*Recognizer, error) {
+	client := NewClient()
+	client.Trim = cfg.Trim
+	client.TessdataPrefix = cfg.TessdataPrefix
+	client.ConfigFilePath = cfg.ConfigFilePath
+	client.Preprocess = cfg.Preprocess
+	client.Normalize = cfg.Normalize
+	client.DisableOpenCL = cfg.DisableOpenCL
+	client.Grayscale = cfg.Grayscale
+	client.Policy = cfg.Policy
+	client.TempDir = cfg.TempDir
+	if len(cfg.Languages) != 0 {
+		client.Languages = append([]string{}, cfg.Languages...)
+	}
+	for key, value := range cfg.Variables {
+		client.Variables[key] = value
+	}
+	if err := client.Warmup(context.Background()); err != nil {
+		client.Close()
+		return nil, err
+	}
+	if cfg.PageSegMode != PSM_OSD_ONLY {
+		if err := client.SetPageSegMode(cfg.PageSegMode); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return &Recognizer{client: client}, nil
+}
+
+// Text recognizes the image data and returns text detected as string.
+func (rec *Recognizer) Text(data []byte) (string, error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if err := rec.client.SetImageFromBytes(data); err != nil {
+		return "", err
+	}
+	return rec.client.Text()
+}
+
+// TextWithOptions recognizes the image data with the configuration overridden by opts, see Client.TextWithOptions.
+func (rec *Recognizer) TextWithOptions(ctx context.Context, data []byte, opts Options) (string, error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.client.TextWithOptions(ctx, data, opts)
+}
+
+// TextWithConfidence recognizes the image data like TextWithOptions, along with the mean confidence of the words,
+// see Client.TextWithConfidence.
+func (rec *Recognizer) TextWithConfidence(ctx context.Context, data []byte, opts Options) (string, float64, error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.client.TextWithConfidence(ctx, data, opts)
+}
+
+// HOCRText recognizes the image data and returns hOCR text.
+func (rec *Recognizer) HOCRText(data []byte) (string, error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if err := rec.client.SetImageFromBytes(data); err != nil {
+		return "", err
+	}
+	return rec.client.HOCRText()
+}
+
+// GetBoundingBoxes recognizes the image data and returns bounding boxes at given level.
+func (rec *Recognizer) GetBoundingBoxes(data []byte, level PageIteratorLevel) ([]BoundingBox, error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if err := rec.client.SetImageFromBytes(data); err != nil {
+		return nil, err
+	}
+	return rec.client.GetBoundingBoxes(level)
+}
+
+// Close frees the resources of this Recognizer. This MUST be called for ANY Recognizer built by Config.Build.
+func (rec *Recognizer) Close() error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.client.Close()
+}
@@ -0,0 +1,124 @@
+package gosseract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2/normalize"
+)
+
+// Bundle is the exact inputs of a recognition, along with its outputs, to reproduce it by Replay,
+// such as of OCR bugs reported by users, see Client.Recorder.
+type Bundle struct {
+	Recorded time.Time `json:"recorded"`
+
+	// Build is of tesseract which recognized the image.
+	Build TesseractBuild `json:"build"`
+
+	// The configuration of the client.
+	Languages      []string                    `json:"languages"`
+	TessdataPrefix string                      `json:"tessdata_prefix,omitempty"`
+	ConfigFilePath string                      `json:"config_file_path,omitempty"`
+	PageSegMode    PageSegMode                 `json:"page_seg_mode"`
+	Variables      map[SettableVariable]string `json:"variables,omitempty"`
+	Preprocess     PreprocessOptions           `json:"preprocess"`
+	Normalize      normalize.Options           `json:"normalize"`
+	Trim           bool                        `json:"trim,omitempty"`
+	Grayscale      bool                        `json:"grayscale,omitempty"`
+	DisableOpenCL  bool                        `json:"disable_opencl,omitempty"`
+
+	// Options of the recognition, see Client.TextWithOptions, and Metadata of the caller, see WithMetadata.
+	Options  Options           `json:"options"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	Image []byte `json:"image"`
+
+	// The outputs of the recognition.
+	Text       string  `json:"text"`
+	Confidence float64 `json:"confidence"`
+	Err        string  `json:"error,omitempty"`
+}
+
+// Recorder receives the bundles of recognitions of Client.TextWithOptions and Client.TextWithConfidence,
+// such as RecordFailures. It's called with the lock of the client held, so it must not use the client.
+type Recorder func(ctx context.Context, bundle *Bundle)
+
+// RecordFailures returns the Recorder writing the bundles of recognitions failed, or less confident than
+// minConfidence from 0 to 100, into files of the directory, such as "20240501T123000.000-3f2a9c1b.json",
+// for users to attach to bug reports. Bundles failed to be written are dropped, not to fail the recognitions.
+func RecordFailures(dir string, minConfidence float64) Recorder {
+	return func(ctx context.Context, bundle *Bundle) {
+		if bundle.Err == "" && bundle.Confidence >= minConfidence {
+			return
+		}
+		fp, _ := ImageFingerprint(bundle.Image)
+		name := fmt.Sprintf("%s-%.8s.json", bundle.Recorded.UTC().Format("20060102T150405.000"), fp.Content)
+		file, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return
+		}
+		defer file.Close()
+		bundle.Write(file)
+	}
+}
+
+// Write writes the bundle as JSON.
+func (bundle *Bundle) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bundle)
+}
+
+// ReadBundle reads the bundle written by Bundle.Write.
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	bundle := &Bundle{}
+	if err := json.NewDecoder(r).Decode(bundle); err != nil {
+		return nil, fmt.Errorf("failed to read the bundle: %v", err)
+	}
+	if len(bundle.Image) == 0 {
+		return nil, fmt.Errorf("failed to read the bundle: no image")
+	}
+	return bundle, nil
+}
+
+// Replay recognizes the image of the bundle again by a client of the same configuration and options,
+// and returns the bundle of the replay, whose outputs and Build are to compare with those of the bundle,
+// along with the error of the replay, if any. Tessdata must be found at TessdataPrefix of the bundle.
+func Replay(ctx context.Context, bundle *Bundle) (*Bundle, error) {
+	client := NewClient()
+	defer client.Close()
+	client.TessdataPrefix = bundle.TessdataPrefix
+	client.ConfigFilePath = bundle.ConfigFilePath
+	client.Preprocess = bundle.Preprocess
+	client.Normalize = bundle.Normalize
+	client.Trim = bundle.Trim
+	client.Grayscale = bundle.Grayscale
+	client.DisableOpenCL = bundle.DisableOpenCL
+	if len(bundle.Languages) != 0 {
+		client.Languages = append([]string{}, bundle.Languages...)
+	}
+	for key, value := range bundle.Variables {
+		client.Variables[key] = value
+	}
+	var replayed *Bundle
+	client.Recorder = func(ctx context.Context, b *Bundle) { replayed = b }
+	// Initialization resets the mode, which is set afterwards as Config.Build does.
+	if err := client.Warmup(ctx); err != nil {
+		return nil, err
+	}
+	if bundle.PageSegMode != PSM_OSD_ONLY {
+		if err := client.SetPageSegMode(bundle.PageSegMode); err != nil {
+			return nil, err
+		}
+	}
+	_, _, err := client.TextWithConfidence(WithMetadata(ctx, bundle.Metadata), bundle.Image, bundle.Options)
+	if replayed == nil {
+		return nil, err
+	}
+	return replayed, err
+}
@@ -0,0 +1,296 @@
+// Package remote recognizes images by gosseract servers, see package server, once OCR is split out as a service
+// tier. Balancer spreads requests across the servers, checking their health, retrying requests failed by a server
+// on another one, and routing requests of the same tenant to the same server while it's healthy, so that caches
+// of servers, such as of fingerprints, serve tenants sending the same documents over again.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2"
+	"github.com/chennqqi/gosseract/v2/server"
+)
+
+// TenantKey is the key of the metadata of contexts routing requests stickily, see gosseract.WithMetadata.
+const TenantKey = "tenant"
+
+// DefaultHealthInterval is the interval of health checks of Balancer without HealthInterval.
+const DefaultHealthInterval = 10 * time.Second
+
+// ErrNoBackend is returned when no server is healthy, or all of them failed the request.
+var ErrNoBackend = errors.New("no healthy backend")
+
+// Options configures Balancer.
+type Options struct {
+	// Profile is the profile of the servers recognizing requests, the default one of the servers if empty.
+	Profile string
+
+	// HTTPClient sends requests, http.DefaultClient if nil. Its Timeout should be longer than recognitions.
+	HTTPClient *http.Client
+
+	// HealthInterval is the interval of checking the health of servers, DefaultHealthInterval if zero.
+	// Servers failing requests are marked unhealthy at once, and healthy again when they pass a check.
+	// Servers rate limiting requests by 429 stay healthy, while requests are retried on others.
+	HealthInterval time.Duration
+
+	// Retries is the number of other servers to retry requests failed by servers on, all of them if zero,
+	// and none if negative.
+	Retries int
+}
+
+// Balancer recognizes images by the servers, routing requests of tenants in the metadata of TenantKey to
+// the same server by rendezvous hashing, which moves only the tenants of a server unhealthy to the others,
+// and the other requests to the server of the fewest requests in flight.
+// It's safe to share among goroutines. It's due to caller to Close the Balancer.
+type Balancer struct {
+	opts     Options
+	client   *http.Client
+	backends []*backend
+
+	stop chan struct{}
+	done sync.WaitGroup
+	once sync.Once
+}
+
+// backend is a server balanced.
+type backend struct {
+	// inflight is first to be 64-bit aligned for atomic operations on 32-bit platforms, see sync/atomic.
+	inflight int64
+	url      string
+	healthy  int32
+}
+
+func (b *backend) isHealthy() bool {
+	return atomic.LoadInt32(&b.healthy) == 1
+}
+
+func (b *backend) setHealthy(healthy bool) {
+	v := int32(0)
+	if healthy {
+		v = 1
+	}
+	atomic.StoreInt32(&b.healthy, v)
+}
+
+// NewBalancer creates Balancer of the servers of the base URLs, such as "http://ocr-1:8080",
+// checking their health in the background. Servers are healthy until they fail.
+func NewBalancer(urls []string, opts Options) (*Balancer, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no backend to balance")
+	}
+	b := &Balancer{opts: opts, client: opts.HTTPClient, stop: make(chan struct{})}
+	if b.client == nil {
+		b.client = http.DefaultClient
+	}
+	for _, u := range urls {
+		if _, err := url.Parse(u); err != nil {
+			return nil, fmt.Errorf("invalid backend %q: %v", u, err)
+		}
+		b.backends = append(b.backends, &backend{url: strings.TrimSuffix(u, "/"), healthy: 1})
+	}
+	interval := opts.HealthInterval
+	if interval <= 0 {
+		interval = DefaultHealthInterval
+	}
+	b.done.Add(1)
+	go b.watch(interval)
+	return b, nil
+}
+
+// Text recognizes the image data by a server, retrying on others if it fails, see Options.Retries.
+// Requests of gosseract.PriorityBatch are sent as such, see server.PriorityHeader.
+// Errors of servers are returned as gosseract.PolicyError, gosseract.ImageError and context.DeadlineExceeded
+// of the recognitions, which are not retried, or as BackendError.
+func (b *Balancer) Text(ctx context.Context, data []byte) (string, error) {
+	candidates := b.route(gosseract.MetadataFrom(ctx)[TenantKey])
+	retries := b.opts.Retries
+	switch {
+	case retries < 0:
+		retries = 0
+	case retries == 0 || retries >= len(candidates):
+		retries = len(candidates) - 1
+	}
+	err := ErrNoBackend
+	for i := 0; i < len(candidates) && i <= retries; i++ {
+		var text string
+		text, err = b.send(ctx, candidates[i], data)
+		if err == nil || !retryable(err) || ctx.Err() != nil {
+			return text, err
+		}
+		// Servers rate limiting are busy rather than failing, and stay in rotation.
+		if !rateLimited(err) {
+			candidates[i].setHealthy(false)
+		}
+	}
+	return "", err
+}
+
+// route returns the healthy servers in order to try, by rendezvous hashing of the tenant, or by requests in flight.
+func (b *Balancer) route(tenant string) []*backend {
+	healthy := []*backend{}
+	for _, backend := range b.backends {
+		if backend.isHealthy() {
+			healthy = append(healthy, backend)
+		}
+	}
+	if tenant != "" {
+		weights := map[*backend]uint64{}
+		for _, backend := range healthy {
+			h := fnv.New64a()
+			io.WriteString(h, tenant+"\x00"+backend.url)
+			weights[backend] = h.Sum64()
+		}
+		sort.SliceStable(healthy, func(i, j int) bool { return weights[healthy[i]] > weights[healthy[j]] })
+		return healthy
+	}
+	sort.SliceStable(healthy, func(i, j int) bool {
+		return atomic.LoadInt64(&healthy[i].inflight) < atomic.LoadInt64(&healthy[j].inflight)
+	})
+	return healthy
+}
+
+// BackendError is a failure of a server, such as of the network or of the server closing, which is retried
+// on another one, or an error of the recognition not of gosseract errors.
+type BackendError struct {
+	URL string
+	// Status is the HTTP status of the response, zero if none is received.
+	Status  int
+	Message string
+}
+
+func (err *BackendError) Error() string {
+	if err.Status == 0 {
+		return fmt.Sprintf("backend %s: %s", err.URL, err.Message)
+	}
+	return fmt.Sprintf("backend %s: %s (%d)", err.URL, err.Message, err.Status)
+}
+
+// retryable reports whether the error is of the server, rather than of the request.
+func retryable(err error) bool {
+	var backendErr *BackendError
+	if !errors.As(err, &backendErr) {
+		return false
+	}
+	switch backendErr.Status {
+	case 0, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusTooManyRequests:
+		return true
+	}
+	return false
+}
+
+// rateLimited reports whether the error is of a server rejecting the request by rate limiting.
+func rateLimited(err error) bool {
+	var backendErr *BackendError
+	return errors.As(err, &backendErr) && backendErr.Status == http.StatusTooManyRequests
+}
+
+// send sends the request to the server, and maps the error of the response back to that of the recognition.
+func (b *Balancer) send(ctx context.Context, backend *backend, data []byte) (string, error) {
+	atomic.AddInt64(&backend.inflight, 1)
+	defer atomic.AddInt64(&backend.inflight, -1)
+	path := "/text"
+	if b.opts.Profile != "" {
+		path = "/profiles/" + url.PathEscape(b.opts.Profile) + "/text"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, backend.url+path, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if gosseract.PriorityFrom(ctx) == gosseract.PriorityBatch {
+		req.Header.Set(server.PriorityHeader, "batch")
+	}
+	res, err := b.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", &BackendError{URL: backend.url, Message: err.Error()}
+	}
+	defer res.Body.Close()
+	body := struct {
+		Text  string `json:"text"`
+		Error string `json:"error"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", &BackendError{URL: backend.url, Status: res.StatusCode, Message: fmt.Sprintf("invalid response: %v", err)}
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+		return body.Text, nil
+	case http.StatusForbidden:
+		return "", &gosseract.PolicyError{Reason: strings.TrimPrefix(body.Error, "rejected by policy: ")}
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge:
+		return "", &gosseract.ImageError{Reason: strings.TrimPrefix(body.Error, "invalid image: "), TooLarge: res.StatusCode == http.StatusRequestEntityTooLarge}
+	case http.StatusGatewayTimeout:
+		return "", fmt.Errorf("backend %s: %s: %w", backend.url, body.Error, context.DeadlineExceeded)
+	}
+	return "", &BackendError{URL: backend.url, Status: res.StatusCode, Message: body.Error}
+}
+
+// watch checks the health of the servers unhealthy at the interval until Close.
+func (b *Balancer) watch(interval time.Duration) {
+	defer b.done.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			b.CheckHealth(context.Background())
+		}
+	}
+}
+
+// CheckHealth checks the health of the servers unhealthy now by "GET /healthz", marking those passing healthy.
+// It's called at Options.HealthInterval in the background.
+func (b *Balancer) CheckHealth(ctx context.Context) {
+	for _, backend := range b.backends {
+		if backend.isHealthy() {
+			continue
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, backend.url+"/healthz", nil)
+		if err != nil {
+			continue
+		}
+		res, err := b.client.Do(req)
+		if err != nil {
+			continue
+		}
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		backend.setHealthy(res.StatusCode == http.StatusOK)
+	}
+}
+
+// Healthy returns the base URLs of the servers healthy now.
+func (b *Balancer) Healthy() []string {
+	urls := []string{}
+	for _, backend := range b.backends {
+		if backend.isHealthy() {
+			urls = append(urls, backend.url)
+		}
+	}
+	return urls
+}
+
+// Close stops checking the health of the servers.
+func (b *Balancer) Close() error {
+	b.once.Do(func() { close(b.stop) })
+	b.done.Wait()
+	return nil
+}
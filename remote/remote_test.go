@@ -0,0 +1,130 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chennqqi/gosseract/v2"
+	. "github.com/otiai10/mint"
+)
+
+// backendServer answers its name and the request, failing by the status while it's set.
+type backendServer struct {
+	*httptest.Server
+	name     string
+	status   int32
+	requests int32
+}
+
+func newBackendServer(name string) *backendServer {
+	b := &backendServer{name: name}
+	b.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status := atomic.LoadInt32(&b.status); status != 0 {
+			w.WriteHeader(int(status))
+			io.WriteString(w, `{"error":"failing"}`)
+			return
+		}
+		if r.URL.Path == "/healthz" {
+			io.WriteString(w, `{"profiles":["default"]}`)
+			return
+		}
+		atomic.AddInt32(&b.requests, 1)
+		data, _ := io.ReadAll(r.Body)
+		switch string(data) {
+		case "captcha":
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `{"error":"rejected by policy: captcha"}`)
+			return
+		case "bomb":
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			io.WriteString(w, `{"error":"invalid image: bomb"}`)
+			return
+		}
+		io.WriteString(w, `{"text":"`+name+` `+r.URL.Path+` `+r.Header.Get("X-Gosseract-Priority")+`"}`)
+	}))
+	return b
+}
+
+func TestBalancer(t *testing.T) {
+	a, b := newBackendServer("a"), newBackendServer("b")
+	defer a.Close()
+	defer b.Close()
+	balancer, err := NewBalancer([]string{a.URL, b.URL + "/"}, Options{Profile: "checks"})
+	Expect(t, err).ToBe(nil)
+	defer balancer.Close()
+
+	tenant := gosseract.WithMetadata(context.Background(), map[string]string{TenantKey: "acme"})
+	first, err := balancer.Text(tenant, []byte("image"))
+	Expect(t, err).ToBe(nil)
+	for i := 0; i < 3; i++ {
+		text, err := balancer.Text(tenant, []byte("image"))
+		Expect(t, err).ToBe(nil)
+		Expect(t, text).ToBe(first)
+	}
+	text, err := balancer.Text(gosseract.WithPriority(context.Background(), gosseract.PriorityBatch), []byte("image"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, text[1:]).ToBe(" /profiles/checks/text batch")
+
+	When(t, "a server fails", func(t *testing.T) {
+		sticky, other := a, b
+		if first[0] == 'b' {
+			sticky, other = b, a
+		}
+		atomic.StoreInt32(&sticky.status, http.StatusServiceUnavailable)
+		text, err := balancer.Text(tenant, []byte("image"))
+		Expect(t, err).ToBe(nil)
+		Expect(t, text[:1]).ToBe(other.name)
+		Expect(t, balancer.Healthy()).ToBe([]string{other.URL})
+
+		atomic.StoreInt32(&other.status, http.StatusServiceUnavailable)
+		_, err = balancer.Text(tenant, []byte("image"))
+		var failed *BackendError
+		Expect(t, errors.As(err, &failed)).ToBe(true)
+		Expect(t, failed.Status).ToBe(http.StatusServiceUnavailable)
+		_, err = balancer.Text(tenant, []byte("image"))
+		Expect(t, err).ToBe(ErrNoBackend)
+
+		atomic.StoreInt32(&sticky.status, 0)
+		atomic.StoreInt32(&other.status, 0)
+		balancer.CheckHealth(context.Background())
+		Expect(t, len(balancer.Healthy())).ToBe(2)
+		text, err = balancer.Text(tenant, []byte("image"))
+		Expect(t, err).ToBe(nil)
+		Expect(t, text).ToBe(first)
+	})
+
+	When(t, "a server rate limits", func(t *testing.T) {
+		sticky, other := a, b
+		if first[0] == 'b' {
+			sticky, other = b, a
+		}
+		atomic.StoreInt32(&sticky.status, http.StatusTooManyRequests)
+		text, err := balancer.Text(tenant, []byte("image"))
+		Expect(t, err).ToBe(nil)
+		Expect(t, text[:1]).ToBe(other.name)
+		// Busy, but not out of rotation.
+		Expect(t, len(balancer.Healthy())).ToBe(2)
+		atomic.StoreInt32(&sticky.status, 0)
+		text, err = balancer.Text(tenant, []byte("image"))
+		Expect(t, err).ToBe(nil)
+		Expect(t, text).ToBe(first)
+	})
+
+	When(t, "requests fail by themselves", func(t *testing.T) {
+		requests := atomic.LoadInt32(&a.requests) + atomic.LoadInt32(&b.requests)
+		_, err := balancer.Text(context.Background(), []byte("captcha"))
+		var rejected *gosseract.PolicyError
+		Expect(t, errors.As(err, &rejected)).ToBe(true)
+		Expect(t, rejected.Reason).ToBe("captcha")
+		_, err = balancer.Text(context.Background(), []byte("bomb"))
+		var invalid *gosseract.ImageError
+		Expect(t, errors.As(err, &invalid) && invalid.TooLarge).ToBe(true)
+		// Not retried on the other.
+		Expect(t, atomic.LoadInt32(&a.requests)+atomic.LoadInt32(&b.requests)).ToBe(requests + 2)
+	})
+}
@@ -0,0 +1,507 @@
+// Package server serves recognitions over HTTP for named configuration profiles, such as "invoices" and "checks",
+// each backed by its own warmed gosseract.Pool, so that one deployment serves different kinds of documents
+// without reinitializing tesseract for each request.
+//
+//	POST /text              the profile of the header X-Gosseract-Profile, or the default one
+//	POST /profiles/{name}/text
+//	POST /pipelines/{name}/text  the profile of the pipeline of the name, see Profile.Pipeline
+//	GET  /healthz                the names of the profiles, for balancers, such as remote.Balancer
+//
+// Requests are the image data, and responses are JSON of the text, such as {"profile":"checks","text":"..."},
+// along with the fields extracted by the pipeline of the profile, if any.
+// A share of requests of profiles may be recognized by a candidate configuration too, see Shadow.
+// Pools of profiles configured by files are rebuilt when the files change, see Profile.ConfigFile.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2"
+	"github.com/chennqqi/gosseract/v2/daemon"
+	"github.com/chennqqi/gosseract/v2/pipeline"
+)
+
+// ProfileHeader is the header selecting the profile of requests to "/text".
+const ProfileHeader = "X-Gosseract-Profile"
+
+// PriorityHeader marks requests of backfills and pipelines by "batch", to wait for interactive ones,
+// see gosseract.PriorityBatch.
+const PriorityHeader = "X-Gosseract-Priority"
+
+// DefaultMaxBytes is the limit of request bodies of profiles without Profile.MaxBytes.
+const DefaultMaxBytes = 32 << 20
+
+// DefaultReloadInterval is the interval of checking Profile.ConfigFile of profiles without Profile.ReloadInterval.
+const DefaultReloadInterval = 5 * time.Second
+
+// Presets are the presets by names for Profile.Preset.
+var Presets = map[string]gosseract.Options{
+	"micr":          gosseract.PresetMICR,
+	"mrz":           gosseract.PresetMRZ,
+	"dot_matrix":    gosseract.PresetDotMatrix,
+	"seven_segment": gosseract.PresetSevenSegment,
+}
+
+// Profile is a named configuration served by Server.
+type Profile struct {
+	Name string `json:"name"`
+
+	// Config configures the pool of the profile, such as Languages, TessdataPrefix and Tuning.Clients.
+	Config gosseract.Config `json:"config"`
+
+	// ConfigFile is the JSON file configuring the pool instead of Config, see daemon.LoadConfig, checked
+	// by ReloadInterval, DefaultReloadInterval if zero, to rebuild the pool when it changes by daemon.Runner:
+	// requests in flight finish on the old pool, and the pool keeps serving if the file is invalid.
+	// Config.Policy still applies, and Languages are those of Preset if not set. Fast and Shadow take
+	// the Languages of the file when the server is built. Pipeline must be empty.
+	ConfigFile     string        `json:"config_file,omitempty"`
+	ReloadInterval time.Duration `json:"reload_interval,omitempty"`
+
+	// Preset is the name of the preset of Presets to recognize requests with, if any, and Options
+	// overrides the configuration further, see gosseract.Client.TextWithOptions.
+	// Languages of the preset are those of the pool, unless Config.Languages are set.
+	Preset  string            `json:"preset,omitempty"`
+	Options gosseract.Options `json:"options"`
+
+	// MaxBytes limits the size of request bodies, DefaultMaxBytes if zero,
+	// and Timeout the time of each recognition, no limit if zero.
+	MaxBytes int64         `json:"max_bytes,omitempty"`
+	Timeout  time.Duration `json:"timeout,omitempty"`
+
+	// Fast configures the pool of a fast model, such as of tessdata_fast, raced against the pool of Config
+	// for each request, returning the fast text if its confidence is MinConfidence at least, see gosseract.Speculate.
+	// Languages are those of Config if not set.
+	Fast          *gosseract.Config `json:"fast,omitempty"`
+	MinConfidence float64           `json:"min_confidence,omitempty"`
+
+	// Pipeline is the name of the pipeline registered to process requests by, see pipeline.Register,
+	// so that they're processed as by the command gosseract with -pipeline. Config and Options are those of
+	// the pipeline, except Config.Tuning if set, and Preset must be empty.
+	Pipeline string `json:"pipeline,omitempty"`
+
+	// Shadow is the candidate configuration recognizing a share of the requests in the background, if any.
+	Shadow *Shadow `json:"shadow,omitempty"`
+}
+
+// recognizer is what Server needs of gosseract.Pool.
+type recognizer interface {
+	TextWithOptions(ctx context.Context, data []byte, opts gosseract.Options) (string, error)
+	TextWithConfidence(ctx context.Context, data []byte, opts gosseract.Options) (string, float64, error)
+	Close() error
+}
+
+type profile struct {
+	Profile
+	rec recognizer
+	// the pool of Profile.Fast, if any
+	fast recognizer
+	// the definition of Profile.Pipeline, if any
+	pipeline *pipeline.Definition
+	// the candidate of Profile.Shadow, if any
+	shadow *shadow
+}
+
+// Server is the http.Handler of the profiles.
+type Server struct {
+	profiles map[string]*profile
+	// the profiles by the names of their pipelines
+	pipelines map[string]*profile
+	// the name of the profile of requests naming none
+	fallback string
+	// shadows waits for the recognitions of candidates in progress, and no more are added once closed
+	shadows sync.WaitGroup
+	mu      sync.Mutex
+	closed  bool
+	// watchers are the goroutines reloading Profile.ConfigFile until stopWatching
+	watchers     sync.WaitGroup
+	stopWatching context.CancelFunc
+}
+
+// New builds the pools of the profiles, the first of which is the default one,
+// warming them up so that the first requests don't pay for initialization. It's due to caller to Close the Server.
+func New(profiles ...Profile) (*Server, error) {
+	return newServer(profiles, func(cfg gosseract.Config) (recognizer, error) {
+		return cfg.BuildPool()
+	})
+}
+
+func newServer(profiles []Profile, build func(cfg gosseract.Config) (recognizer, error)) (*Server, error) {
+	if len(profiles) == 0 {
+		return nil, errors.New("no profile to serve")
+	}
+	srv := &Server{profiles: map[string]*profile{}, pipelines: map[string]*profile{}, fallback: profiles[0].Name}
+	watching, stop := context.WithCancel(context.Background())
+	srv.stopWatching = stop
+	for _, p := range profiles {
+		if p.Name == "" || strings.Contains(p.Name, "/") {
+			srv.Close()
+			return nil, fmt.Errorf("invalid name of profile %q", p.Name)
+		}
+		if _, ok := srv.profiles[p.Name]; ok {
+			srv.Close()
+			return nil, fmt.Errorf("duplicate profile %q", p.Name)
+		}
+		def, err := p.definition()
+		if err != nil {
+			srv.Close()
+			return nil, err
+		}
+		cfg, opts, err := p.resolve()
+		if err != nil {
+			srv.Close()
+			return nil, err
+		}
+		var rec recognizer
+		if p.ConfigFile != "" {
+			var runner *daemon.Runner
+			if runner, err = srv.watch(watching, p, build); err == nil {
+				rec, cfg = runner, runner.Config()
+			}
+		} else {
+			rec, err = build(cfg)
+		}
+		if err != nil {
+			srv.Close()
+			return nil, fmt.Errorf("failed to build profile %q: %v", p.Name, err)
+		}
+		p.Config, p.Options = cfg, opts
+		srv.profiles[p.Name] = &profile{Profile: p, rec: rec, pipeline: def}
+		if def != nil {
+			if _, ok := srv.pipelines[def.Name]; !ok {
+				srv.pipelines[def.Name] = srv.profiles[p.Name]
+			}
+		}
+		if p.Fast != nil {
+			fast := *p.Fast
+			if len(fast.Languages) == 0 {
+				fast.Languages = cfg.Languages
+			}
+			if srv.profiles[p.Name].fast, err = build(fast); err != nil {
+				srv.Close()
+				return nil, fmt.Errorf("failed to build the fast pool of profile %q: %v", p.Name, err)
+			}
+		}
+		if p.Shadow != nil {
+			candidate := p.Shadow.Config
+			if len(candidate.Languages) == 0 {
+				candidate.Languages = cfg.Languages
+			}
+			rec, err := build(candidate)
+			if err != nil {
+				srv.Close()
+				return nil, fmt.Errorf("failed to build the shadow pool of profile %q: %v", p.Name, err)
+			}
+			srv.profiles[p.Name].shadow = &shadow{Shadow: *p.Shadow, rec: rec}
+		}
+	}
+	return srv, nil
+}
+
+// watch builds the pool of Profile.ConfigFile by daemon.Runner, reloading the file in the background until ctx is done.
+func (srv *Server) watch(ctx context.Context, p Profile, build func(cfg gosseract.Config) (recognizer, error)) (*daemon.Runner, error) {
+	if p.Pipeline != "" {
+		return nil, fmt.Errorf("both config file %q and pipeline %q", p.ConfigFile, p.Pipeline)
+	}
+	configure := func(cfg *gosseract.Config) {
+		cfg.Policy = p.Config.Policy
+		if len(cfg.Languages) == 0 {
+			cfg.Languages = Presets[p.Preset].Languages
+		}
+	}
+	runner, err := daemon.NewWith(p.ConfigFile, configure, func(cfg gosseract.Config) (daemon.Pool, error) {
+		return build(cfg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	runner.OnReload = func(cfg gosseract.Config, err error) {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "profile %s: %v\n", p.Name, err)
+		}
+	}
+	interval := p.ReloadInterval
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	srv.watchers.Add(1)
+	go func() {
+		defer srv.watchers.Done()
+		runner.Watch(ctx, interval)
+	}()
+	return runner, nil
+}
+
+// definition returns the pipeline of the profile, or nil if it has none.
+func (p Profile) definition() (*pipeline.Definition, error) {
+	if p.Pipeline == "" {
+		return nil, nil
+	}
+	if p.Preset != "" {
+		return nil, fmt.Errorf("profile %q has both preset %q and pipeline %q", p.Name, p.Preset, p.Pipeline)
+	}
+	def, ok := pipeline.Lookup(p.Pipeline)
+	if !ok {
+		return nil, fmt.Errorf("unknown pipeline %q of profile %q", p.Pipeline, p.Name)
+	}
+	return &def, nil
+}
+
+// resolve applies the preset, or the pipeline, to the configuration and the options of the profile.
+func (p Profile) resolve() (gosseract.Config, gosseract.Options, error) {
+	cfg, opts := p.Config, p.Options
+	if def, err := p.definition(); err != nil || def != nil {
+		if def != nil {
+			cfg, opts = def.Config, def.Options
+			if p.Config.Tuning != (gosseract.Tuning{}) {
+				cfg.Tuning = p.Config.Tuning
+			}
+		}
+		return cfg, opts, err
+	}
+	if p.Preset == "" {
+		return cfg, opts, nil
+	}
+	preset, ok := Presets[p.Preset]
+	if !ok {
+		return cfg, opts, fmt.Errorf("unknown preset %q of profile %q", p.Preset, p.Name)
+	}
+	if len(cfg.Languages) == 0 {
+		cfg.Languages = preset.Languages
+	}
+	if opts.PageSegMode == gosseract.PSM_OSD_ONLY {
+		opts.PageSegMode = preset.PageSegMode
+	}
+	if opts.Whitelist == "" {
+		opts.Whitelist = preset.Whitelist
+	}
+	if opts.Blacklist == "" {
+		opts.Blacklist = preset.Blacklist
+	}
+	if opts.Preprocess == nil {
+		opts.Preprocess = preset.Preprocess
+	}
+	vars := map[gosseract.SettableVariable]string{}
+	for key, value := range preset.Variables {
+		vars[key] = value
+	}
+	for key, value := range opts.Variables {
+		vars[key] = value
+	}
+	opts.Variables = vars
+	return cfg, opts, nil
+}
+
+// Profiles returns the names of the profiles served.
+func (srv *Server) Profiles() []string {
+	names := make([]string, 0, len(srv.profiles))
+	for name := range srv.profiles {
+		names = append(names, name)
+	}
+	return names
+}
+
+type response struct {
+	Profile string `json:"profile"`
+	Text    string `json:"text,omitempty"`
+	Error   string `json:"error,omitempty"`
+
+	// Fast is whether the text is of the fast pool, see Profile.Fast.
+	Fast bool `json:"fast,omitempty"`
+
+	// Pipeline is the name of the pipeline of the profile, and Fields the fields it extracted, if any.
+	Pipeline string                 `json:"pipeline,omitempty"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+}
+
+// ServeHTTP recognizes the image of the request by the profile selected.
+// Callers are identified to gosseract.Policy by the metadata "profile" and "remote_addr", see gosseract.WithMetadata.
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, ok := "", false
+	switch {
+	case r.URL.Path == "/healthz":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string][]string{"profiles": srv.Profiles()})
+		return
+	case r.URL.Path == "/text":
+		name, ok = r.Header.Get(ProfileHeader), true
+		if name == "" {
+			name = srv.fallback
+		}
+	case strings.HasPrefix(r.URL.Path, "/profiles/") && strings.HasSuffix(r.URL.Path, "/text"):
+		name, ok = strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/profiles/"), "/text"), true
+	case strings.HasPrefix(r.URL.Path, "/pipelines/") && strings.HasSuffix(r.URL.Path, "/text"):
+		name = strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/pipelines/"), "/text")
+		p, found := srv.pipelines[name]
+		if !found {
+			reply(w, http.StatusNotFound, response{Pipeline: name, Error: "unknown pipeline"})
+			return
+		}
+		name, ok = p.Name, true
+	}
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		reply(w, http.StatusMethodNotAllowed, response{Profile: name, Error: "method not allowed"})
+		return
+	}
+	p, ok := srv.profiles[name]
+	if !ok {
+		reply(w, http.StatusNotFound, response{Profile: name, Error: "unknown profile"})
+		return
+	}
+
+	limit := p.MaxBytes
+	if limit == 0 {
+		limit = DefaultMaxBytes
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		reply(w, http.StatusRequestEntityTooLarge, response{Profile: name, Error: err.Error()})
+		return
+	}
+	if len(data) == 0 {
+		reply(w, http.StatusBadRequest, response{Profile: name, Error: "empty image"})
+		return
+	}
+	ctx := gosseract.WithMetadata(r.Context(), map[string]string{"profile": name, "remote_addr": r.RemoteAddr})
+	if strings.EqualFold(r.Header.Get(PriorityHeader), "batch") {
+		ctx = gosseract.WithPriority(ctx, gosseract.PriorityBatch)
+	}
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	res, err := p.recognize(ctx, data)
+	if err != nil {
+		reply(w, status(err), response{Profile: name, Error: err.Error()})
+		return
+	}
+	if p.shadow != nil && p.shadow.sampled() {
+		srv.shadow(ctx, p, data, res, time.Since(start))
+	}
+	if p.pipeline == nil {
+		reply(w, http.StatusOK, response{Profile: name, Text: res.Text, Fast: res.Fast})
+		return
+	}
+	processed, err := p.pipeline.Process(res.Text, res.Confidence)
+	if err != nil {
+		reply(w, http.StatusInternalServerError, response{Profile: name, Pipeline: p.Pipeline, Error: err.Error()})
+		return
+	}
+	reply(w, http.StatusOK, response{Profile: name, Text: processed.Text, Fast: res.Fast, Pipeline: p.Pipeline, Fields: processed.Fields})
+}
+
+// shadow recognizes the image by the candidate of the profile in the background, as a batch recognition
+// not to hold up interactive ones, and reports the comparison with the result of the primary.
+func (srv *Server) shadow(ctx context.Context, p *profile, data []byte, primary gosseract.Speculation, duration time.Duration) {
+	timeout := p.shadow.Timeout
+	if timeout == 0 {
+		timeout = p.Timeout
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.closed {
+		return
+	}
+	// The candidate outlives the request.
+	shadowCtx := gosseract.WithPriority(gosseract.WithMetadata(context.Background(), gosseract.MetadataFrom(ctx)), gosseract.PriorityBatch)
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		shadowCtx, cancel = context.WithTimeout(shadowCtx, timeout)
+	}
+	srv.shadows.Add(1)
+	go func() {
+		defer srv.shadows.Done()
+		defer cancel()
+		p.shadow.compare(shadowCtx, p.Name, data, p.Options, primary, duration)
+	}()
+}
+
+// ShadowStats returns the summaries of the comparisons of the profiles of Shadow by their names.
+func (srv *Server) ShadowStats() map[string]ShadowStats {
+	stats := map[string]ShadowStats{}
+	for name, p := range srv.profiles {
+		if p.shadow != nil {
+			stats[name] = p.shadow.summary()
+		}
+	}
+	return stats
+}
+
+// recognize recognizes the image by the pool of the profile, or by the race of it and the fast one.
+func (p *profile) recognize(ctx context.Context, data []byte) (gosseract.Speculation, error) {
+	if p.fast == nil && (p.pipeline != nil || p.shadow != nil) {
+		text, confidence, err := p.rec.TextWithConfidence(ctx, data, p.Options)
+		return gosseract.Speculation{Text: text, Confidence: confidence}, err
+	}
+	if p.fast == nil {
+		text, err := p.rec.TextWithOptions(ctx, data, p.Options)
+		return gosseract.Speculation{Text: text}, err
+	}
+	return gosseract.Speculate(ctx, p.fast, p.rec, data, p.Options, p.MinConfidence)
+}
+
+// status maps errors of recognitions to HTTP statuses.
+func status(err error) int {
+	var rejected *gosseract.PolicyError
+	var invalid *gosseract.ImageError
+	switch {
+	case errors.As(err, &rejected):
+		return http.StatusForbidden
+	case errors.As(err, &invalid) && invalid.TooLarge:
+		return http.StatusRequestEntityTooLarge
+	case errors.As(err, &invalid):
+		return http.StatusBadRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, gosseract.ErrClientClosed):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+func reply(w http.ResponseWriter, code int, res response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(res)
+}
+
+// Close closes the pools of all the profiles, waiting for the recognitions in progress, including those of shadows.
+// Requests after Close are not shadowed.
+func (srv *Server) Close() (err error) {
+	srv.mu.Lock()
+	srv.closed = true
+	srv.mu.Unlock()
+	srv.shadows.Wait()
+	srv.stopWatching()
+	srv.watchers.Wait()
+	for _, p := range srv.profiles {
+		recs := []recognizer{p.rec, p.fast}
+		if p.shadow != nil {
+			recs = append(recs, p.shadow.rec)
+		}
+		for _, rec := range recs {
+			if rec == nil {
+				continue
+			}
+			if e := rec.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,307 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2"
+	"github.com/chennqqi/gosseract/v2/daemon"
+	"github.com/chennqqi/gosseract/v2/pipeline"
+	. "github.com/otiai10/mint"
+)
+
+// fakePool answers its languages and the whitelist of requests.
+type fakePool struct {
+	cfg    gosseract.Config
+	delay  time.Duration
+	hold   chan struct{}
+	closed bool
+}
+
+func (p *fakePool) TextWithOptions(ctx context.Context, data []byte, opts gosseract.Options) (string, error) {
+	if gosseract.PriorityFrom(ctx) == gosseract.PriorityBatch {
+		return "batch", nil
+	}
+	switch string(data) {
+	case "captcha":
+		return "", &gosseract.PolicyError{Reason: "captcha"}
+	case "malformed", "bomb":
+		return "", &gosseract.ImageError{Reason: string(data), TooLarge: string(data) == "bomb"}
+	}
+	if p.hold != nil {
+		<-p.hold
+	}
+	select {
+	case <-time.After(p.delay):
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	return strings.Join(p.cfg.Languages, "+") + " " + opts.Whitelist, nil
+}
+
+func (p *fakePool) TextWithConfidence(ctx context.Context, data []byte, opts gosseract.Options) (string, float64, error) {
+	text, err := p.TextWithOptions(ctx, data, opts)
+	if p.cfg.TessdataPrefix == "fast" {
+		return "fast " + text, 50, err
+	}
+	return text, 90, err
+}
+
+func (p *fakePool) Close() error {
+	p.closed = true
+	return nil
+}
+
+func newTestServer(t *testing.T, profiles ...Profile) (*Server, []*fakePool) {
+	pools := []*fakePool{}
+	srv, err := newServer(profiles, func(cfg gosseract.Config) (recognizer, error) {
+		p := &fakePool{cfg: cfg}
+		if cfg.TessdataPrefix == "slow" {
+			p.delay = time.Second
+		}
+		pools = append(pools, p)
+		return p, nil
+	})
+	Expect(t, err).ToBe(nil)
+	return srv, pools
+}
+
+func post(srv *Server, path, profile, body string) (int, response) {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if profile != "" {
+		req.Header.Set(ProfileHeader, profile)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	res := response{}
+	json.NewDecoder(bytes.NewReader(rec.Body.Bytes())).Decode(&res)
+	return rec.Code, res
+}
+
+func TestServer(t *testing.T) {
+	srv, pools := newTestServer(t,
+		Profile{Name: "documents", Config: gosseract.Config{Languages: []string{"eng", "deu"}}},
+		Profile{Name: "checks", Preset: "micr", MaxBytes: 8},
+		Profile{Name: "slow", Config: gosseract.Config{Languages: []string{"eng"}, TessdataPrefix: "slow"}, Timeout: 10 * time.Millisecond},
+	)
+	Expect(t, len(pools)).ToBe(3)
+	Expect(t, pools[1].cfg.Languages).ToBe([]string{"mcr"})
+
+	code, res := post(srv, "/text", "", "image")
+	Expect(t, code).ToBe(http.StatusOK)
+	Expect(t, res.Profile).ToBe("documents")
+	Expect(t, res.Text).ToBe("eng+deu ")
+
+	code, res = post(srv, "/text", "checks", "image")
+	Expect(t, code).ToBe(http.StatusOK)
+	Expect(t, res.Text).ToBe("mcr 0123456789ABCD")
+
+	code, res = post(srv, "/profiles/checks/text", "", "image")
+	Expect(t, code).ToBe(http.StatusOK)
+	Expect(t, res.Profile).ToBe("checks")
+
+	req := httptest.NewRequest(http.MethodPost, "/text", strings.NewReader("image"))
+	req.Header.Set(PriorityHeader, "batch")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	Expect(t, strings.Contains(rec.Body.String(), `"text":"batch"`)).ToBe(true)
+
+	When(t, "requests are refused", func(t *testing.T) {
+		code, _ := post(srv, "/text", "unknown", "image")
+		Expect(t, code).ToBe(http.StatusNotFound)
+		code, _ = post(srv, "/profiles/checks/text", "", "too large image")
+		Expect(t, code).ToBe(http.StatusRequestEntityTooLarge)
+		code, _ = post(srv, "/text", "", "captcha")
+		Expect(t, code).ToBe(http.StatusForbidden)
+		code, _ = post(srv, "/text", "", "malformed")
+		Expect(t, code).ToBe(http.StatusBadRequest)
+		code, _ = post(srv, "/text", "", "bomb")
+		Expect(t, code).ToBe(http.StatusRequestEntityTooLarge)
+		code, _ = post(srv, "/text", "slow", "image")
+		Expect(t, code).ToBe(http.StatusGatewayTimeout)
+		code, _ = post(srv, "/text", "", "")
+		Expect(t, code).ToBe(http.StatusBadRequest)
+	})
+
+	Expect(t, srv.Close()).ToBe(nil)
+	Expect(t, pools[0].closed).ToBe(true)
+}
+
+func TestServer_Fast(t *testing.T) {
+	srv, pools := newTestServer(t,
+		Profile{Name: "confident", Config: gosseract.Config{Languages: []string{"eng"}, TessdataPrefix: "slow"},
+			Fast: &gosseract.Config{TessdataPrefix: "fast"}, MinConfidence: 40},
+		Profile{Name: "strict", Config: gosseract.Config{Languages: []string{"eng"}},
+			Fast: &gosseract.Config{TessdataPrefix: "fast"}, MinConfidence: 80},
+	)
+	defer srv.Close()
+	Expect(t, len(pools)).ToBe(4)
+	Expect(t, pools[1].cfg.Languages).ToBe([]string{"eng"})
+
+	code, res := post(srv, "/text", "confident", "image")
+	Expect(t, code).ToBe(http.StatusOK)
+	Expect(t, res.Fast).ToBe(true)
+	Expect(t, res.Text).ToBe("fast eng ")
+
+	code, res = post(srv, "/text", "strict", "image")
+	Expect(t, code).ToBe(http.StatusOK)
+	Expect(t, res.Fast).ToBe(false)
+	Expect(t, res.Text).ToBe("eng ")
+}
+
+func TestServer_Pipeline(t *testing.T) {
+	Expect(t, pipeline.Register(pipeline.Definition{
+		Name:       "invoices",
+		Config:     gosseract.Config{Languages: []string{"deu"}},
+		Options:    gosseract.Options{Whitelist: "12,50 EUR"},
+		Filters:    []string{"trim"},
+		Extractors: []string{"money"},
+		Locale:     "de-DE",
+	})).ToBe(nil)
+	srv, pools := newTestServer(t,
+		Profile{Name: "default"},
+		Profile{Name: "billing", Pipeline: "invoices", Config: gosseract.Config{Tuning: gosseract.Tuning{Clients: 2}}},
+	)
+	defer srv.Close()
+	Expect(t, pools[1].cfg.Languages).ToBe([]string{"deu"})
+	Expect(t, pools[1].cfg.Tuning.Clients).ToBe(2)
+
+	code, res := post(srv, "/pipelines/invoices/text", "", "image")
+	Expect(t, code).ToBe(http.StatusOK)
+	Expect(t, res.Profile).ToBe("billing")
+	Expect(t, res.Pipeline).ToBe("invoices")
+	Expect(t, res.Text).ToBe("deu 12,50 EUR")
+	Expect(t, len(res.Fields["money"].([]interface{}))).ToBe(1)
+
+	code, res = post(srv, "/text", "billing", "image")
+	Expect(t, code).ToBe(http.StatusOK)
+	Expect(t, res.Pipeline).ToBe("invoices")
+
+	code, _ = post(srv, "/pipelines/unknown/text", "", "image")
+	Expect(t, code).ToBe(http.StatusNotFound)
+	_, err := newServer([]Profile{{Name: "a", Pipeline: "unknown"}}, func(cfg gosseract.Config) (recognizer, error) { return &fakePool{}, nil })
+	Expect(t, err).Not().ToBe(nil)
+}
+
+func TestServer_Shadow(t *testing.T) {
+	mu := sync.Mutex{}
+	comparisons := []Comparison{}
+	srv, pools := newTestServer(t, Profile{
+		Name:    "default",
+		Config:  gosseract.Config{Languages: []string{"eng"}},
+		Options: gosseract.Options{Whitelist: "abc"},
+		Shadow: &Shadow{Config: gosseract.Config{TessdataPrefix: "fast"}, Percent: 50, Report: func(c Comparison) {
+			mu.Lock()
+			defer mu.Unlock()
+			comparisons = append(comparisons, c)
+		}},
+	})
+	Expect(t, pools[1].cfg.Languages).ToBe([]string{"eng"})
+	for i := 0; i < 4; i++ {
+		code, res := post(srv, "/text", "", "image")
+		Expect(t, code).ToBe(http.StatusOK)
+		Expect(t, res.Text).ToBe("eng abc")
+	}
+	Expect(t, srv.Close()).ToBe(nil)
+	Expect(t, pools[1].closed).ToBe(true)
+	Because(t, "nothing is shadowed after Close", func(t *testing.T) {
+		srv.shadow(context.Background(), srv.profiles["default"], []byte("image"), gosseract.Speculation{}, 0)
+		srv.shadows.Wait()
+	})
+
+	Expect(t, len(comparisons)).ToBe(2)
+	c := comparisons[0]
+	Expect(t, c.Primary).ToBe("eng abc")
+	// Candidates wait for interactive recognitions.
+	Expect(t, c.Candidate).ToBe("fast batch")
+	Expect(t, c.CandidateConfidence-c.PrimaryConfidence).ToBe(-40.0)
+	Expect(t, c.DiffRate).ToBe(0.7)
+	stats := srv.ShadowStats()
+	Expect(t, stats["default"].Compared).ToBe(int64(2))
+	Expect(t, stats["default"].MeanConfidenceDelta).ToBe(-40.0)
+
+	When(t, "texts are compared", func(t *testing.T) {
+		Expect(t, diffRate([]rune(""), []rune(""))).ToBe(0.0)
+		Expect(t, diffRate([]rune("kitten"), []rune("sitting"))).ToBe(3.0 / 7)
+	})
+}
+
+func TestNew_InvalidProfiles(t *testing.T) {
+	build := func(cfg gosseract.Config) (recognizer, error) { return &fakePool{}, nil }
+	_, err := newServer(nil, build)
+	Expect(t, err).Not().ToBe(nil)
+	_, err = newServer([]Profile{{Name: "a"}, {Name: "a"}}, build)
+	Expect(t, err).Not().ToBe(nil)
+	_, err = newServer([]Profile{{Name: "a", Preset: "unknown"}}, build)
+	Expect(t, err).Not().ToBe(nil)
+	_, err = newServer([]Profile{{Name: "a", ConfigFile: filepath.Join(t.TempDir(), "missing.json")}}, build)
+	Expect(t, err).Not().ToBe(nil)
+}
+
+func TestServer_ConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gosseract.json")
+	Expect(t, os.WriteFile(path, []byte(`{"languages": ["eng"]}`), 0o644)).ToBe(nil)
+	mu := sync.Mutex{}
+	pools := []*fakePool{}
+	hold := make(chan struct{})
+	srv, err := newServer([]Profile{{Name: "default", ConfigFile: path, ReloadInterval: 5 * time.Millisecond}}, func(cfg gosseract.Config) (recognizer, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		p := &fakePool{cfg: cfg}
+		if len(pools) == 0 {
+			p.hold = hold
+		}
+		pools = append(pools, p)
+		return p, nil
+	})
+	Expect(t, err).ToBe(nil)
+
+	// A request in flight on the first pool.
+	done := make(chan string)
+	go func() {
+		_, res := post(srv, "/text", "", "image")
+		done <- res.Text
+	}()
+	time.Sleep(10 * time.Millisecond)
+	Expect(t, os.WriteFile(path, []byte(`{"languages": ["eng", "deu"]}`), 0o644)).ToBe(nil)
+	runner := srv.profiles["default"].rec.(*daemon.Runner)
+	for i := 0; i < 200 && len(runner.Config().Languages) != 2; i++ {
+		time.Sleep(5 * time.Millisecond)
+	}
+	_, res := post(srv, "/text", "", "image")
+	Expect(t, res.Text).ToBe("eng+deu ")
+
+	close(hold)
+	Expect(t, <-done).ToBe("eng ")
+	Expect(t, srv.Close()).ToBe(nil)
+	Expect(t, len(pools)).ToBe(2)
+	Expect(t, pools[0].closed).ToBe(true)
+	Expect(t, pools[1].closed).ToBe(true)
+}
+
+func TestProfile_JSON(t *testing.T) {
+	allow := func(ctx context.Context, admission gosseract.Admission) error { return nil }
+	profile := Profile{
+		Name:   "eng",
+		Config: gosseract.Config{Languages: []string{"eng"}, Policy: allow},
+		Fast:   &gosseract.Config{Policy: allow},
+		Shadow: &Shadow{Config: gosseract.Config{Policy: allow}, Percent: 10, Report: func(Comparison) {}},
+	}
+	b, err := json.Marshal(profile)
+	Expect(t, err).ToBe(nil)
+	decoded := Profile{}
+	Expect(t, json.Unmarshal(b, &decoded)).ToBe(nil)
+	Expect(t, decoded.Name).ToBe("eng")
+	Expect(t, decoded.Config.Languages[0]).ToBe("eng")
+	Expect(t, decoded.Config.Policy == nil).ToBe(true)
+	Expect(t, decoded.Shadow.Percent).ToBe(10.0)
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2"
+)
+
+// Shadow is a candidate configuration of a profile, such as a new model or tuned variables, recognizing a share
+// of the requests of the profile again in the background to compare with the primary, see Profile.Shadow.
+// Responses are always of the primary, so candidates can be rolled out by their comparisons safely.
+type Shadow struct {
+	// Config configures the pool of the candidate, and Options overrides it as Profile.Options,
+	// which are those of the profile if nil. Languages are those of the profile if not set.
+	Config  gosseract.Config   `json:"config"`
+	Options *gosseract.Options `json:"options,omitempty"`
+
+	// Percent is the share of the requests recognized by the candidate from 0 to 100, spread evenly.
+	Percent float64 `json:"percent"`
+
+	// Timeout limits the time of each recognition of the candidate, the Timeout of the profile if zero.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// Report receives the comparison of each request shadowed, LogComparisons(os.Stderr) if nil.
+	// It's called by goroutines of the candidate, so it must be safe to call concurrently.
+	Report func(Comparison) `json:"-"`
+}
+
+// Comparison is the results of the primary and the candidate of a request shadowed.
+type Comparison struct {
+	Profile string    `json:"profile"`
+	Time    time.Time `json:"time"`
+
+	Primary             string        `json:"primary"`
+	PrimaryConfidence   float64       `json:"primary_confidence"`
+	PrimaryDuration     time.Duration `json:"primary_duration"`
+	Candidate           string        `json:"candidate"`
+	CandidateConfidence float64       `json:"candidate_confidence"`
+	CandidateDuration   time.Duration `json:"candidate_duration"`
+
+	// DiffRate is the edit distance between the texts in characters over the length of the longer one,
+	// from 0 of the same texts to 1, or 1 if the candidate failed.
+	DiffRate float64 `json:"diff_rate"`
+
+	// Error is of the candidate, empty if recognized.
+	Error string `json:"error,omitempty"`
+}
+
+// ShadowStats is the summary of the comparisons of a profile so far, see Server.ShadowStats.
+type ShadowStats struct {
+	// Compared is the number of the requests shadowed, and Failed those the candidate failed to recognize.
+	Compared int64 `json:"compared"`
+	Failed   int64 `json:"failed"`
+
+	// MeanDiffRate is the mean Comparison.DiffRate, and MeanConfidenceDelta the mean confidence of the candidate
+	// minus that of the primary, of the requests the candidate recognized.
+	MeanDiffRate        float64 `json:"mean_diff_rate"`
+	MeanConfidenceDelta float64 `json:"mean_confidence_delta"`
+}
+
+// LogComparisons returns the Shadow.Report writing comparisons to w as JSON lines.
+func LogComparisons(w io.Writer) func(Comparison) {
+	mu := sync.Mutex{}
+	return func(c Comparison) {
+		b, err := json.Marshal(c)
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write(append(b, '\n'))
+	}
+}
+
+var stderrComparisons = LogComparisons(os.Stderr)
+
+// shadow is the candidate of a profile running.
+type shadow struct {
+	Shadow
+	rec recognizer
+
+	// requests counts the requests of the profile to sample them
+	requests int64
+
+	mu sync.Mutex
+	// the sums of the comparisons recognized
+	stats                       ShadowStats
+	diffRates, confidenceDeltas float64
+}
+
+// sampled reports whether the next request is shadowed, by the share of Percent spread evenly.
+func (s *shadow) sampled() bool {
+	n := atomic.AddInt64(&s.requests, 1)
+	return int64(float64(n)*s.Percent/100) > int64(float64(n-1)*s.Percent/100)
+}
+
+// compare recognizes the image by the candidate and reports the comparison with the primary.
+func (s *shadow) compare(ctx context.Context, name string, data []byte, opts gosseract.Options, primary gosseract.Speculation, duration time.Duration) {
+	if s.Options != nil {
+		opts = *s.Options
+	}
+	start := time.Now()
+	text, confidence, err := s.rec.TextWithConfidence(ctx, data, opts)
+	c := Comparison{
+		Profile: name, Time: start.UTC(),
+		Primary: primary.Text, PrimaryConfidence: primary.Confidence, PrimaryDuration: duration,
+		Candidate: text, CandidateConfidence: confidence, CandidateDuration: time.Since(start),
+		DiffRate: 1,
+	}
+	if err != nil {
+		c.Error = err.Error()
+	} else {
+		c.DiffRate = diffRate([]rune(primary.Text), []rune(text))
+	}
+
+	s.mu.Lock()
+	s.stats.Compared++
+	if err != nil {
+		s.stats.Failed++
+	} else {
+		s.diffRates += c.DiffRate
+		s.confidenceDeltas += c.CandidateConfidence - c.PrimaryConfidence
+	}
+	s.mu.Unlock()
+
+	report := s.Report
+	if report == nil {
+		report = stderrComparisons
+	}
+	report(c)
+}
+
+func (s *shadow) summary() ShadowStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	if recognized := stats.Compared - stats.Failed; recognized != 0 {
+		stats.MeanDiffRate = s.diffRates / float64(recognized)
+		stats.MeanConfidenceDelta = s.confidenceDeltas / float64(recognized)
+	}
+	return stats
+}
+
+// diffRate returns the Levenshtein distance between a and b over the length of the longer one.
+func diffRate(a, b []rune) float64 {
+	longer := len(a)
+	if len(b) > longer {
+		longer = len(b)
+	}
+	if longer == 0 {
+		return 0
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return float64(prev[len(b)]) / float64(longer)
+}
@@ -0,0 +1,63 @@
+package gosseract
+
+import (
+	"context"
+)
+
+// ConfidentRecognizer recognizes text of image data along with the mean confidence of the words,
+// which Client, Recognizer and Pool implement.
+type ConfidentRecognizer interface {
+	TextWithConfidence(ctx context.Context, data []byte, opts Options) (string, float64, error)
+}
+
+// Speculation is the result of Speculate.
+type Speculation struct {
+	Text       string
+	Confidence float64
+
+	// Fast is whether the text is of the fast recognizer.
+	Fast bool
+}
+
+// Speculate races the fast and the best recognizers, such as Pools of tessdata_fast and tessdata_best,
+// for the same image, and returns whichever finishes first with the confidence of minConfidence at least,
+// from 0 to 100, cancelling the other, which trades CPU for tail latency of interactive endpoints.
+// If neither is confident enough, the result of the best is returned, or of the fast if the best fails.
+// The recognizers must not be the same Client or Recognizer, which serialize recognitions.
+func Speculate(ctx context.Context, fast, best ConfidentRecognizer, data []byte, opts Options, minConfidence float64) (Speculation, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type outcome struct {
+		Speculation
+		err error
+	}
+	outcomes := make(chan outcome, 2)
+	run := func(rec ConfidentRecognizer, isFast bool) {
+		text, confidence, err := rec.TextWithConfidence(ctx, data, opts)
+		outcomes <- outcome{Speculation{Text: text, Confidence: confidence, Fast: isFast}, err}
+	}
+	go run(fast, true)
+	go run(best, false)
+
+	var fastOutcome, bestOutcome outcome
+	for i := 0; i < 2; i++ {
+		o := <-outcomes
+		if o.err == nil && o.Confidence >= minConfidence {
+			cancel()
+			// Wait for the other not to leave it recognizing, since callers may close the recognizers.
+			if i == 0 {
+				<-outcomes
+			}
+			return o.Speculation, nil
+		}
+		if o.Fast {
+			fastOutcome = o
+		} else {
+			bestOutcome = o
+		}
+	}
+	if bestOutcome.err == nil || fastOutcome.err != nil {
+		return bestOutcome.Speculation, bestOutcome.err
+	}
+	return fastOutcome.Speculation, nil
+}
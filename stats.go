@@ -0,0 +1,49 @@
+package gosseract
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// nativeStats counts allocations on the C++ side, which are invisible to Go heap profiles.
+// Fields are updated atomically.
+var nativeStats struct {
+	clients        int64
+	pixImages      int64
+	pixImagesBytes int64
+	iterators      int64
+}
+
+// Stats represents outstanding native allocations made by gosseract,
+// i.e. memory which lives in tesseract and Leptonica, not in Go heap.
+type Stats struct {
+	// Clients is the number of TessBaseAPI constructed by NewClient and not closed yet.
+	Clients int64 `json:"clients"`
+	// PixImages is the number of Leptonica images held by clients, including preprocessed ones,
+	// and PixImagesBytes is the total size of their pixel data.
+	PixImages      int64 `json:"pix_images"`
+	PixImagesBytes int64 `json:"pix_images_bytes"`
+	// Iterators is the number of tesseract::ResultIterator of ResultIterator not closed yet.
+	// Iterators walked inside of other methods, such as GetBoundingBoxes, are deleted before they return.
+	Iterators int64 `json:"iterators"`
+}
+
+// NativeStats returns outstanding native allocations at the moment.
+// Leaks of them, e.g. clients never closed, show up as ever-growing numbers.
+func NativeStats() Stats {
+	return Stats{
+		Clients:        atomic.LoadInt64(&nativeStats.clients),
+		PixImages:      atomic.LoadInt64(&nativeStats.pixImages),
+		PixImagesBytes: atomic.LoadInt64(&nativeStats.pixImagesBytes),
+		Iterators:      atomic.LoadInt64(&nativeStats.iterators),
+	}
+}
+
+// PublishNativeStats publishes NativeStats as an expvar with the given name, e.g. "gosseract",
+// to be served at /debug/vars along with Go's memstats.
+// Like expvar.Publish, it panics if the name is already registered.
+func PublishNativeStats(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return NativeStats()
+	}))
+}
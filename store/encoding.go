@@ -0,0 +1,108 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// Charset is the character encoding of text outputs.
+type Charset string
+
+const (
+	// UTF8 is the default.
+	UTF8 Charset = "utf-8"
+	// UTF16LE is UTF-16 of little endian, which Windows calls "Unicode".
+	UTF16LE Charset = "utf-16le"
+	// UTF16BE is UTF-16 of big endian.
+	UTF16BE Charset = "utf-16be"
+)
+
+// Newline is the line separator of text outputs.
+type Newline string
+
+const (
+	// LF is the default.
+	LF Newline = "\n"
+	// CRLF is for Windows consumers, such as Notepad of old and legacy importers.
+	CRLF Newline = "\r\n"
+)
+
+// Encoding is how texts are encoded into outputs, for downstream systems picky about the charset, the BOM
+// and newlines. The zero value is UTF-8 without BOM and with LF, as texts are recognized.
+type Encoding struct {
+	Charset Charset
+	// BOM prepends the byte order mark of the charset, such as for Excel to read UTF-8 CSVs.
+	BOM     bool
+	Newline Newline
+}
+
+// ParseEncoding parses the name of an encoding: "utf-8", "utf-8-bom", "utf-16le", "utf-16be",
+// or "utf-16", i.e. little endian with BOM as Windows writes it. Names are case-insensitive.
+// Newline is left LF, see ParseNewline.
+func ParseEncoding(name string) (Encoding, error) {
+	switch strings.ToLower(name) {
+	case "", "utf-8", "utf8":
+		return Encoding{Charset: UTF8}, nil
+	case "utf-8-bom", "utf8-bom", "utf-8-sig":
+		return Encoding{Charset: UTF8, BOM: true}, nil
+	case "utf-16", "utf16":
+		return Encoding{Charset: UTF16LE, BOM: true}, nil
+	case "utf-16le":
+		return Encoding{Charset: UTF16LE}, nil
+	case "utf-16be":
+		return Encoding{Charset: UTF16BE}, nil
+	}
+	return Encoding{}, fmt.Errorf("unknown encoding %q", name)
+}
+
+// ParseNewline parses the name of a newline: "lf" or "crlf", case-insensitively.
+func ParseNewline(name string) (Newline, error) {
+	switch strings.ToLower(name) {
+	case "", "lf":
+		return LF, nil
+	case "crlf":
+		return CRLF, nil
+	}
+	return "", fmt.Errorf("unknown newline %q", name)
+}
+
+// Encode encodes the text, converting its newlines of either style into enc.Newline.
+func (enc Encoding) Encode(text string) []byte {
+	if enc.Newline == CRLF {
+		text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+	} else {
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+	}
+	var order binary.ByteOrder
+	switch enc.Charset {
+	case UTF16LE:
+		order = binary.LittleEndian
+	case UTF16BE:
+		order = binary.BigEndian
+	default:
+		if enc.BOM {
+			return append([]byte("\xef\xbb\xbf"), text...)
+		}
+		return []byte(text)
+	}
+	units := utf16.Encode([]rune(text))
+	if enc.BOM {
+		units = append([]uint16{0xFEFF}, units...)
+	}
+	b := make([]byte, 2*len(units))
+	for i, u := range units {
+		order.PutUint16(b[2*i:], u)
+	}
+	return b
+}
+
+// ContentType returns the content type of plain texts of the encoding, such as "text/plain; charset=utf-16le".
+func (enc Encoding) ContentType() string {
+	charset := enc.Charset
+	if charset == "" {
+		charset = UTF8
+	}
+	return "text/plain; charset=" + string(charset)
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GCS is an OutputStore of objects in a bucket of Google Cloud Storage, uploaded by the JSON API
+// without depending on the Google Cloud SDK. Objects are named "<Prefix><name>".
+type GCS struct {
+	Bucket string
+
+	// Prefix is prepended to names, such as "ocr/".
+	Prefix string
+
+	// Token returns the OAuth 2.0 access token of requests, such as of the TokenSource of
+	// golang.org/x/oauth2/google, or of "gcloud auth print-access-token".
+	Token func() (string, error)
+
+	// Endpoint is the URL of the API, "https://storage.googleapis.com" if empty.
+	Endpoint string
+
+	// Client sends requests, a client of DefaultTimeout if nil.
+	Client *http.Client
+}
+
+// Put uploads the object of the name by a single request. The content is read into memory first,
+// since the API needs the length of media uploads.
+func (g *GCS) Put(ctx context.Context, name, contentType string, r io.Reader) error {
+	clean, err := cleanName(name)
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	endpoint := g.Endpoint
+	if endpoint == "" {
+		endpoint = "https://storage.googleapis.com"
+	}
+	u := strings.TrimSuffix(endpoint, "/") + "/upload/storage/v1/b/" + url.PathEscape(g.Bucket) +
+		"/o?uploadType=media&name=" + url.QueryEscape(g.Prefix+clean)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+	if g.Token != nil {
+		token, err := g.Token()
+		if err != nil {
+			return fmt.Errorf("failed to get token of GCS: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := g.Client
+	if client == nil {
+		client = defaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("failed to put %s to GCS: %s: %s", name, res.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
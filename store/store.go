@@ -0,0 +1,74 @@
+// Package store puts outputs of recognitions, such as sidecar texts, hOCR and PDFs, wherever deployments need them,
+// i.e. local directories, Amazon S3 or compatible storages, and Google Cloud Storage, by OutputStore.
+package store
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OutputStore stores outputs by names, such as "scans/page-1.txt", with "/" as the separator.
+// Outputs of the same names are overwritten. Put returns when the ctx is done, if not yet stored.
+type OutputStore interface {
+	Put(ctx context.Context, name, contentType string, r io.Reader) error
+}
+
+// DefaultTimeout is the timeout of requests to storages when the Client of S3 or GCS is nil.
+const DefaultTimeout = 2 * time.Minute
+
+// defaultClient sends requests of S3 and GCS of nil Client. Unlike http.DefaultClient, it times out
+// requests to storages which don't respond.
+var defaultClient = &http.Client{Timeout: DefaultTimeout}
+
+// Dir is an OutputStore of files in the directory, creating subdirectories of names as needed.
+// The content type is not stored.
+type Dir string
+
+// Put writes the file of the name atomically, i.e. readers see either the old file or the whole new one.
+func (dir Dir) Put(ctx context.Context, name, contentType string, r io.Reader) error {
+	clean, err := cleanName(name)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	dest := filepath.Join(string(dir), filepath.FromSlash(clean))
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// cleanName cleans the name, which must be relative and within the store, such as not "../secret".
+func cleanName(name string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if name == "" || clean == "." || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid name of output %q", name)
+	}
+	return clean, nil
+}
@@ -0,0 +1,33 @@
+package subtitles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"time"
+)
+
+// WriteSRT writes the cues in SubRip (SRT) format.
+func WriteSRT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	for i, cue := range cues {
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", i+1, timestamp(cue.Start, ','), timestamp(cue.End, ','), cue.Text)
+	}
+	return bw.Flush()
+}
+
+// WriteWebVTT writes the cues in WebVTT format.
+func WriteWebVTT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "WEBVTT\n\n")
+	for _, cue := range cues {
+		fmt.Fprintf(bw, "%s --> %s\n%s\n\n", timestamp(cue.Start, '.'), timestamp(cue.End, '.'), cue.Text)
+	}
+	return bw.Flush()
+}
+
+// timestamp formats d as hh:mm:ss followed by the separator and milliseconds.
+func timestamp(d time.Duration, separator rune) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, separator, ms%1000)
+}
@@ -0,0 +1,210 @@
+// Package subtitles extracts burned-in subtitles from video frames as timed cues,
+// written out as SRT or WebVTT. Decoding videos into frames, e.g. by ffmpeg, is up to the caller.
+package subtitles
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/chennqqi/gosseract/v2"
+)
+
+// Frame is a decoded frame of a video at the time from the start.
+type Frame struct {
+	Time  time.Duration
+	Image image.Image
+}
+
+// Cue is a subtitle shown from Start until End.
+type Cue struct {
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+	Text  string        `json:"text"`
+}
+
+// Options specifies how subtitles are detected in frames.
+type Options struct {
+	// Band is the height of the band at the bottom of frames where subtitles are, as a fraction of the height.
+	// Zero means 0.3.
+	Band float64
+
+	// Colors of the text of subtitles, nil for white and yellow.
+	Colors []color.Color
+
+	// Tolerance is the largest difference of each of red, green and blue channels from Colors,
+	// to take pixels as text. Zero means 60.
+	Tolerance int
+}
+
+var defaultColors = []color.Color{color.RGBA{255, 255, 255, 255}, color.RGBA{255, 255, 0, 255}}
+
+func (opts Options) withDefaults() Options {
+	if opts.Band <= 0 || opts.Band > 1 {
+		opts.Band = 0.3
+	}
+	if opts.Colors == nil {
+		opts.Colors = defaultColors
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 60
+	}
+	return opts
+}
+
+// Mask cuts out the bottom band of the frame and paints pixels of the colors of subtitles black,
+// and the others white, so that tesseract reads subtitles above any video behind them.
+func Mask(img image.Image, opts Options) *image.Gray {
+	opts = opts.withDefaults()
+	bounds := img.Bounds()
+	top := bounds.Max.Y - int(float64(bounds.Dy())*opts.Band)
+	band := image.Rect(bounds.Min.X, top, bounds.Max.X, bounds.Max.Y)
+	colors := make([][3]int, 0, len(opts.Colors))
+	for _, c := range opts.Colors {
+		r, g, b, _ := c.RGBA()
+		colors = append(colors, [3]int{int(r >> 8), int(g >> 8), int(b >> 8)})
+	}
+	mask := image.NewGray(image.Rect(0, 0, band.Dx(), band.Dy()))
+	for y := band.Min.Y; y < band.Max.Y; y++ {
+		for x := band.Min.X; x < band.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			px := [3]int{int(r >> 8), int(g >> 8), int(b >> 8)}
+			value := uint8(255)
+			for _, c := range colors {
+				if abs(px[0]-c[0]) <= opts.Tolerance && abs(px[1]-c[1]) <= opts.Tolerance && abs(px[2]-c[2]) <= opts.Tolerance {
+					value = 0
+					break
+				}
+			}
+			mask.Pix[(y-band.Min.Y)*mask.Stride+(x-band.Min.X)] = value
+		}
+	}
+	return mask
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Extract recognizes the subtitles of the frames, in order of time, by the client and returns the cues.
+// Frames are masked by Mask, and consecutive frames of similar text are merged by Tracker.
+func Extract(ctx context.Context, client *gosseract.Client, frames []Frame, opts Options) ([]Cue, error) {
+	tracker := &Tracker{}
+	buf := bytes.NewBuffer(nil)
+	for _, frame := range frames {
+		buf.Reset()
+		if err := png.Encode(buf, Mask(frame.Image, opts)); err != nil {
+			return nil, err
+		}
+		text, err := client.TextWithOptions(ctx, buf.Bytes(), gosseract.Options{PageSegMode: gosseract.PSM_SINGLE_BLOCK})
+		if err != nil {
+			return nil, err
+		}
+		tracker.Add(frame.Time, text)
+	}
+	if len(frames) != 0 {
+		tracker.Flush(frames[len(frames)-1].Time)
+	}
+	return tracker.Cues(), nil
+}
+
+// Tracker merges the texts of consecutive frames into cues, deduplicating frames showing the same subtitle.
+// Texts of frames differing by a few characters, as OCR of the same subtitle often does, are taken
+// as the same subtitle, whose text is the one recognized most often. The zero value is ready to use.
+type Tracker struct {
+	cues    []Cue
+	current *Cue
+	counts  map[string]int
+}
+
+// Add adds the text recognized in the frame at the time, empty if the frame has no subtitle.
+// It returns the cue finished by the frame, if any.
+func (t *Tracker) Add(at time.Duration, text string) *Cue {
+	text = strings.Join(strings.Fields(text), " ")
+	if t.current != nil && text != "" && similar(t.current.Text, text) {
+		t.counts[text]++
+		if t.counts[text] > t.counts[t.current.Text] {
+			t.current.Text = text
+		}
+		return nil
+	}
+	finished := t.finish(at)
+	if text != "" {
+		t.current = &Cue{Start: at, Text: text}
+		t.counts = map[string]int{text: 1}
+	}
+	return finished
+}
+
+// Flush finishes the cue being shown at the time, returning it if any.
+func (t *Tracker) Flush(at time.Duration) *Cue {
+	return t.finish(at)
+}
+
+// Cues returns the cues finished so far.
+func (t *Tracker) Cues() []Cue {
+	return append([]Cue{}, t.cues...)
+}
+
+func (t *Tracker) finish(at time.Duration) *Cue {
+	if t.current == nil {
+		return nil
+	}
+	cue := *t.current
+	cue.End = at
+	t.cues = append(t.cues, cue)
+	t.current, t.counts = nil, nil
+	return &cue
+}
+
+// similar reports whether the texts differ by at most a fifth of the longer one, or a character.
+func similar(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	limit := len(ra)
+	if len(rb) > limit {
+		limit = len(rb)
+	}
+	limit /= 5
+	if limit < 1 {
+		limit = 1
+	}
+	return distance(ra, rb) <= limit
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,60 @@
+package subtitles
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	. "github.com/otiai10/mint"
+)
+
+func TestMask(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	img.Set(1, 9, color.RGBA{250, 250, 250, 255})
+	img.Set(2, 9, color.RGBA{240, 220, 10, 255})
+	img.Set(3, 9, color.RGBA{40, 40, 200, 255})
+	img.Set(1, 0, color.RGBA{255, 255, 255, 255})
+
+	mask := Mask(img, Options{})
+	Expect(t, mask.Bounds()).ToBe(image.Rect(0, 0, 10, 3))
+	Expect(t, mask.GrayAt(1, 2).Y).ToBe(uint8(0))
+	Expect(t, mask.GrayAt(2, 2).Y).ToBe(uint8(0))
+	Expect(t, mask.GrayAt(3, 2).Y).ToBe(uint8(255))
+	Expect(t, mask.GrayAt(0, 0).Y).ToBe(uint8(255))
+
+	When(t, "colors are specified", func(t *testing.T) {
+		mask := Mask(img, Options{Band: 0.1, Colors: []color.Color{color.RGBA{40, 40, 200, 255}}, Tolerance: 10})
+		Expect(t, mask.Bounds()).ToBe(image.Rect(0, 0, 10, 1))
+		Expect(t, mask.GrayAt(1, 0).Y).ToBe(uint8(255))
+		Expect(t, mask.GrayAt(3, 0).Y).ToBe(uint8(0))
+	})
+}
+
+func TestTracker(t *testing.T) {
+	tracker := &Tracker{}
+	Expect(t, tracker.Add(0, "")).ToBe((*Cue)(nil))
+	Expect(t, tracker.Add(1*time.Second, "Hello, world")).ToBe((*Cue)(nil))
+	Expect(t, tracker.Add(2*time.Second, "Hel1o, world")).ToBe((*Cue)(nil))
+	Expect(t, tracker.Add(3*time.Second, "Hello,  world\n")).ToBe((*Cue)(nil))
+	cue := tracker.Add(4*time.Second, "Goodbye")
+	Expect(t, cue).Deeply().ToBe(&Cue{Start: 1 * time.Second, End: 4 * time.Second, Text: "Hello, world"})
+	Expect(t, tracker.Add(5*time.Second, "")).Deeply().ToBe(&Cue{Start: 4 * time.Second, End: 5 * time.Second, Text: "Goodbye"})
+	Expect(t, tracker.Flush(6*time.Second)).ToBe((*Cue)(nil))
+	Expect(t, len(tracker.Cues())).ToBe(2)
+}
+
+func TestWriteSRT(t *testing.T) {
+	cues := []Cue{
+		{Start: 1500 * time.Millisecond, End: 4 * time.Second, Text: "Hello"},
+		{Start: time.Hour + 2*time.Minute, End: time.Hour + 2*time.Minute + 3*time.Second, Text: "World"},
+	}
+	buf := bytes.NewBuffer(nil)
+	Expect(t, WriteSRT(buf, cues)).ToBe(nil)
+	Expect(t, buf.String()).ToBe("1\n00:00:01,500 --> 00:00:04,000\nHello\n\n2\n01:02:00,000 --> 01:02:03,000\nWorld\n\n")
+
+	buf.Reset()
+	Expect(t, WriteWebVTT(buf, cues[:1])).ToBe(nil)
+	Expect(t, buf.String()).ToBe("WEBVTT\n\n00:00:01.500 --> 00:00:04.000\nHello\n\n")
+}
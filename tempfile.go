@@ -0,0 +1,35 @@
+package gosseract
+
+import (
+	"fmt"
+	"os"
+)
+
+// CreateTemp creates a new temporary file for this client, e.g. to pass generated contents
+// to tesseract, which reads some parameters only from files, such as SetConfigFile.
+// Files are created with mode 0600 in a private directory (mode 0700) under client.TempDir,
+// and removed all together on Close. The caller is responsible for closing the file.
+// It returns ErrClientClosed after Close, since nothing would remove the files.
+func (client *Client) CreateTemp(pattern string) (*os.File, error) {
+	if client.closed {
+		return nil, ErrClientClosed
+	}
+	if client.tempDir == "" {
+		dir, err := os.MkdirTemp(client.TempDir, "gosseract-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary directory: %v", err)
+		}
+		client.tempDir = dir
+	}
+	return os.CreateTemp(client.tempDir, pattern)
+}
+
+// removeTempDir removes all temporary files created by CreateTemp.
+func (client *Client) removeTempDir() error {
+	if client.tempDir == "" {
+		return nil
+	}
+	err := os.RemoveAll(client.tempDir)
+	client.tempDir = ""
+	return err
+}
@@ -2,8 +2,13 @@
 extern "C" {
 #endif
 
+// Size of buffers to receive error messages, such as of C++ exceptions.
+#define ERRBUF_SIZE 512
+
 typedef void* TessBaseAPI;
 typedef void* PixImage;
+typedef void* Monitor;
+typedef void* Iterator;
 
 struct bounding_box {
     int x1, y1, x2, y2;
@@ -17,26 +22,110 @@ struct bounding_boxes {
     struct bounding_box* boxes;
 };
 
+// layout_element is a block, paragraph, line or word of the page layout.
+struct layout_element {
+    int level;
+    int x1, y1, x2, y2;
+    // text, confidence and font attributes of words
+    char* text;
+    float confidence;
+    bool bold;
+    // whether words are found in the dictionaries, or the numeric DAWG, rather than guessed
+    bool from_dictionary, numeric;
+    // whether words are superscripts, subscripts or drop caps
+    bool superscript, subscript, dropcap;
+    // orientation of the block containing words, and the baseline of words
+    int orientation;
+    float deskew_angle;
+    bool has_baseline;
+    int bx1, by1, bx2, by2;
+    // type of blocks as tesseract::PolyBlockType, and their outline as x and y pairs
+    int block_type;
+    int polygon_length;
+    int* polygon;
+};
+
+struct layout {
+    int length;
+    struct layout_element* elements;
+};
+
+// symbol_choice is a text a symbol may be, with its confidence.
+struct symbol_choice {
+    char* text;
+    float confidence;
+};
+
+// symbol is a symbol recognized, of the word of the index in the page, with its choices, the best first.
+struct symbol {
+    int word;
+    int x1, y1, x2, y2;
+    int length;
+    struct symbol_choice* choices;
+};
+
+struct symbols {
+    int length;
+    struct symbol* symbols;
+};
+
 TessBaseAPI Create(void);
 
 void Free(TessBaseAPI);
 void Clear(TessBaseAPI);
 void ClearPersistentCache(TessBaseAPI);
 int Init(TessBaseAPI, char*, char*, char*, char*);
-struct bounding_boxes* GetBoundingBoxes(TessBaseAPI, int);
-struct bounding_boxes* GetBoundingBoxesVerbose(TessBaseAPI);
+struct bounding_boxes* GetBoundingBoxes(TessBaseAPI, int, char*);
+struct bounding_boxes* GetBoundingBoxesVerbose(TessBaseAPI, char*);
+struct layout* GetLayout(TessBaseAPI, char*);
+void FreeLayout(struct layout*);
+struct symbols* GetSymbols(TessBaseAPI, char*);
+void FreeSymbols(struct symbols*);
+Iterator GetResultIterator(TessBaseAPI, char*);
+bool ResultIteratorNext(Iterator, int);
+char* ResultIteratorText(Iterator, int, char*);
+float ResultIteratorConfidence(Iterator, int);
+bool ResultIteratorBoundingBox(Iterator, int, int*, int*, int*, int*);
+void DeleteResultIterator(Iterator);
 bool SetVariable(TessBaseAPI, char*, char*);
+bool GetVariableAsString(TessBaseAPI, char*, char*, int);
 void SetPixImage(TessBaseAPI a, PixImage pix);
+PixImage ThresholdedPixImage(TessBaseAPI, char*);
+void SetRectangle(TessBaseAPI, int, int, int, int);
 void SetPageSegMode(TessBaseAPI, int);
 int GetPageSegMode(TessBaseAPI);
-char* UTF8Text(TessBaseAPI);
-char* HOCRText(TessBaseAPI);
+int Recognize(TessBaseAPI, char*);
+int RecognizeWithMonitor(TessBaseAPI, Monitor, char*);
+char* UTF8Text(TessBaseAPI, char*);
+char* HOCRText(TessBaseAPI, char*);
+int MeanTextConf(TessBaseAPI);
 const char* Version(TessBaseAPI);
 const char* GetDataPath();
+bool OpenCLAvailable(void);
+
+Monitor CreateMonitor(int deadline_msecs);
+void CancelMonitor(Monitor);
+void DestroyMonitor(Monitor);
 
 PixImage CreatePixImageByFilePath(char*);
 PixImage CreatePixImageFromBytes(unsigned char*, int);
+int ReadPixImageHeader(unsigned char* data, int size, int* width, int* height);
+int ReadPixImageHeaderByFilePath(char* imagepath, int* width, int* height);
 void DestroyPixImage(PixImage pix);
+int PixImageWidth(PixImage pix);
+int PixImageHeight(PixImage pix);
+long PixImageBytes(PixImage pix);
+double PixImageEntropy(PixImage pix);
+
+PixImage ScalePixImage(PixImage pix, float scale);
+PixImage RectifyPixImage(PixImage pix);
+PixImage NormalizeBackgroundPixImage(PixImage pix);
+PixImage ContrastNormalizePixImage(PixImage pix);
+PixImage SauvolaBinarizePixImage(PixImage pix);
+PixImage OtsuBinarizePixImage(PixImage pix);
+PixImage RemoveGridLinesPixImage(PixImage pix, int** lines, int* count);
+PixImage JoinDotsPixImage(PixImage pix);
+PixImage JoinSegmentsPixImage(PixImage pix);
 
 #ifdef __cplusplus
 }
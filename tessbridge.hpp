@@ -1,15 +1,42 @@
 #if __FreeBSD__ >= 10
 #include "/usr/local/include/leptonica/allheaders.h"
 #include "/usr/local/include/tesseract/baseapi.h"
+#include "/usr/local/include/tesseract/ocrclass.h"
 #else
 #include <leptonica/allheaders.h>
 #include <tesseract/baseapi.h>
+#include <tesseract/ocrclass.h>
 #endif
 
+#include <math.h>
 #include <stdio.h>
+#include <string.h>
 #include <unistd.h>
+#include <algorithm>
+#include <atomic>
+#include <exception>
+#include <vector>
 #include "tessbridge.h"
 
+// ETEXT_DESC is moved into namespace tesseract since 5.0.
+#if defined(TESSERACT_MAJOR_VERSION) && TESSERACT_MAJOR_VERSION >= 5
+typedef tesseract::ETEXT_DESC ETEXT_DESC;
+#endif
+
+// catchException writes the message of the C++ exception being handled to errbuf,
+// so that it's returned as an error on Go side, instead of terminating the process.
+// This MUST be called only inside of catch blocks.
+static void catchException(char* errbuf) {
+    size_t len = strnlen(errbuf, ERRBUF_SIZE - 1);
+    try {
+        throw;
+    } catch (const std::exception& e) {
+        snprintf(errbuf + len, ERRBUF_SIZE - len, "%s", e.what());
+    } catch (...) {
+        snprintf(errbuf + len, ERRBUF_SIZE - len, "unknown exception");
+    }
+}
+
 TessBaseAPI Create() {
     tesseract::TessBaseAPI* api = new tesseract::TessBaseAPI();
     return (void*)api;
@@ -52,12 +79,18 @@ int Init(TessBaseAPI a, char* tessdataprefix, char* languages, char* configfilep
     // }}}
 
     int ret;
-    if (configfilepath != NULL) {
-        char* configs[] = {configfilepath};
-        int configs_size = 1;
-        ret = api->Init(tessdataprefix, languages, tesseract::OEM_DEFAULT, configs, configs_size, NULL, NULL, false);
-    } else {
-        ret = api->Init(tessdataprefix, languages);
+    std::exception_ptr thrown = nullptr;
+    try {
+        if (configfilepath != NULL) {
+            char* configs[] = {configfilepath};
+            int configs_size = 1;
+            ret = api->Init(tessdataprefix, languages, tesseract::OEM_DEFAULT, configs, configs_size, NULL, NULL, false);
+        } else {
+            ret = api->Init(tessdataprefix, languages);
+        }
+    } catch (...) {
+        ret = -1;
+        thrown = std::current_exception();
     }
 
     // {{{ Restore default stderr
@@ -67,6 +100,15 @@ int Init(TessBaseAPI a, char* tessdataprefix, char* languages, char* configfilep
     setbuf(stderr, NULL);
     // }}}
 
+    if (thrown) {
+        // Rethrow to catch it again after stderr is restored, not to mix the message with stderr.
+        try {
+            std::rethrow_exception(thrown);
+        } catch (...) {
+            catchException(errbuf);
+        }
+    }
+
     return ret;
 }
 
@@ -95,17 +137,124 @@ int GetPageSegMode(TessBaseAPI a) {
     return api->GetPageSegMode();
 }
 
-char* UTF8Text(TessBaseAPI a) {
+int Recognize(TessBaseAPI a, char* errbuf) {
     tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
-    return api->GetUTF8Text();
+    try {
+        return api->Recognize(NULL);
+    } catch (...) {
+        catchException(errbuf);
+        return -1;
+    }
 }
 
-char* HOCRText(TessBaseAPI a) {
+// monitor wraps ETEXT_DESC, which tesseract polls during recognition,
+// with a flag to be set from another thread to cancel it.
+struct monitor {
+    ETEXT_DESC desc;
+    std::atomic<bool> cancelled;
+};
+
+static bool monitorCancelled(void* cancel_this, int words) {
+    return ((monitor*)cancel_this)->cancelled.load();
+}
+
+Monitor CreateMonitor(int deadline_msecs) {
+    monitor* m = new monitor();
+    m->cancelled.store(false);
+    m->desc.cancel = monitorCancelled;
+    m->desc.cancel_this = (void*)m;
+    if (deadline_msecs > 0) {
+        m->desc.set_deadline_msecs(deadline_msecs);
+    }
+    return (void*)m;
+}
+
+void CancelMonitor(Monitor m) {
+    ((monitor*)m)->cancelled.store(true);
+}
+
+void DestroyMonitor(Monitor m) {
+    delete (monitor*)m;
+}
+
+int RecognizeWithMonitor(TessBaseAPI a, Monitor m, char* errbuf) {
+    tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
+    try {
+        return api->Recognize(&((monitor*)m)->desc);
+    } catch (...) {
+        catchException(errbuf);
+        return -1;
+    }
+}
+
+bool GetVariableAsString(TessBaseAPI a, char* name, char* value, int size) {
     tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
-    return api->GetHOCRText(0);
+    // Each type of variables has its own getter, which fails for variables of other types.
+    const char* s = api->GetStringVariable(name);
+    if (s != NULL) {
+        snprintf(value, size, "%s", s);
+        return true;
+    }
+    int i;
+    if (api->GetIntVariable(name, &i)) {
+        snprintf(value, size, "%d", i);
+        return true;
+    }
+    bool b;
+    if (api->GetBoolVariable(name, &b)) {
+        snprintf(value, size, "%d", b ? 1 : 0);
+        return true;
+    }
+    double d;
+    if (api->GetDoubleVariable(name, &d)) {
+        snprintf(value, size, "%.17g", d);
+        return true;
+    }
+    return false;
 }
 
-bounding_boxes* GetBoundingBoxesVerbose(TessBaseAPI a) {
+// ThresholdedPixImage thresholds the image set, returning the binary image to be destroyed by the caller.
+PixImage ThresholdedPixImage(TessBaseAPI a, char* errbuf) {
+    tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
+    try {
+        return (void*)api->GetThresholdedImage();
+    } catch (...) {
+        catchException(errbuf);
+        return NULL;
+    }
+}
+
+void SetRectangle(TessBaseAPI a, int left, int top, int width, int height) {
+    tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
+    api->SetRectangle(left, top, width, height);
+}
+
+char* UTF8Text(TessBaseAPI a, char* errbuf) {
+    tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
+    try {
+        return api->GetUTF8Text();
+    } catch (...) {
+        catchException(errbuf);
+        return NULL;
+    }
+}
+
+char* HOCRText(TessBaseAPI a, char* errbuf) {
+    tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
+    try {
+        return api->GetHOCRText(0);
+    } catch (...) {
+        catchException(errbuf);
+        return NULL;
+    }
+}
+
+int MeanTextConf(TessBaseAPI a) {
+    tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
+    return api->MeanTextConf();
+}
+
+bounding_boxes* GetBoundingBoxesVerbose(TessBaseAPI a, char* errbuf) {
     using namespace tesseract;
     tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
     struct bounding_boxes* box_array;
@@ -116,61 +265,67 @@ bounding_boxes* GetBoundingBoxesVerbose(TessBaseAPI a) {
     int capacity = 1000;
     box_array->boxes = (bounding_box*)malloc(capacity * sizeof(bounding_box));
     box_array->length = 0;
-    api->Recognize(NULL);
     int block_num = 0;
     int par_num = 0;
     int line_num = 0;
     int word_num = 0;
 
-    ResultIterator* res_it = api->GetIterator();
-    while (!res_it->Empty(RIL_BLOCK)) {
-        if (res_it->Empty(RIL_WORD)) {
-            res_it->Next(RIL_WORD);
-            continue;
-        }
-        // Add rows for any new block/paragraph/textline.
-        if (res_it->IsAtBeginningOf(RIL_BLOCK)) {
-            block_num++;
-            par_num = 0;
-            line_num = 0;
-            word_num = 0;
-        }
-        if (res_it->IsAtBeginningOf(RIL_PARA)) {
-            par_num++;
-            line_num = 0;
-            word_num = 0;
-        }
-        if (res_it->IsAtBeginningOf(RIL_TEXTLINE)) {
-            line_num++;
-            word_num = 0;
-        }
-        word_num++;
+    ResultIterator* res_it = NULL;
+    try {
+        api->Recognize(NULL);
+        res_it = api->GetIterator();
+        while (res_it != NULL && !res_it->Empty(RIL_BLOCK)) {
+            if (res_it->Empty(RIL_WORD)) {
+                res_it->Next(RIL_WORD);
+                continue;
+            }
+            // Add rows for any new block/paragraph/textline.
+            if (res_it->IsAtBeginningOf(RIL_BLOCK)) {
+                block_num++;
+                par_num = 0;
+                line_num = 0;
+                word_num = 0;
+            }
+            if (res_it->IsAtBeginningOf(RIL_PARA)) {
+                par_num++;
+                line_num = 0;
+                word_num = 0;
+            }
+            if (res_it->IsAtBeginningOf(RIL_TEXTLINE)) {
+                line_num++;
+                word_num = 0;
+            }
+            word_num++;
 
-        if (box_array->length >= realloc_threshold) {
-            capacity += realloc_raise;
-            box_array->boxes = (bounding_box*)realloc(box_array->boxes, capacity * sizeof(bounding_box));
-            realloc_threshold += realloc_raise;
-        }
+            if (box_array->length >= realloc_threshold) {
+                capacity += realloc_raise;
+                box_array->boxes = (bounding_box*)realloc(box_array->boxes, capacity * sizeof(bounding_box));
+                realloc_threshold += realloc_raise;
+            }
 
-        box_array->boxes[box_array->length].word = res_it->GetUTF8Text(RIL_WORD);
-        box_array->boxes[box_array->length].confidence = res_it->Confidence(RIL_WORD);
-        res_it->BoundingBox(RIL_WORD, &box_array->boxes[box_array->length].x1, &box_array->boxes[box_array->length].y1,
-                            &box_array->boxes[box_array->length].x2, &box_array->boxes[box_array->length].y2);
+            box_array->boxes[box_array->length].word = res_it->GetUTF8Text(RIL_WORD);
+            box_array->boxes[box_array->length].confidence = res_it->Confidence(RIL_WORD);
+            res_it->BoundingBox(RIL_WORD, &box_array->boxes[box_array->length].x1, &box_array->boxes[box_array->length].y1,
+                                &box_array->boxes[box_array->length].x2, &box_array->boxes[box_array->length].y2);
 
-        // block, para, line, word numbers
-        box_array->boxes[box_array->length].block_num = block_num;
-        box_array->boxes[box_array->length].par_num = par_num;
-        box_array->boxes[box_array->length].line_num = line_num;
-        box_array->boxes[box_array->length].word_num = word_num;
+            // block, para, line, word numbers
+            box_array->boxes[box_array->length].block_num = block_num;
+            box_array->boxes[box_array->length].par_num = par_num;
+            box_array->boxes[box_array->length].line_num = line_num;
+            box_array->boxes[box_array->length].word_num = word_num;
 
-        box_array->length++;
-        res_it->Next(RIL_WORD);
+            box_array->length++;
+            res_it->Next(RIL_WORD);
+        }
+    } catch (...) {
+        catchException(errbuf);
     }
+    delete res_it;
 
     return box_array;
 }
 
-bounding_boxes* GetBoundingBoxes(TessBaseAPI a, int pageIteratorLevel) {
+bounding_boxes* GetBoundingBoxes(TessBaseAPI a, int pageIteratorLevel, char* errbuf) {
     tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
     struct bounding_boxes* box_array;
     box_array = (bounding_boxes*)malloc(sizeof(bounding_boxes));
@@ -180,28 +335,247 @@ bounding_boxes* GetBoundingBoxes(TessBaseAPI a, int pageIteratorLevel) {
     int capacity = 1000;
     box_array->boxes = (bounding_box*)malloc(capacity * sizeof(bounding_box));
     box_array->length = 0;
-    api->Recognize(NULL);
-    tesseract::ResultIterator* ri = api->GetIterator();
+    tesseract::ResultIterator* ri = NULL;
     tesseract::PageIteratorLevel level = (tesseract::PageIteratorLevel)pageIteratorLevel;
 
-    if (ri != 0) {
-        do {
-            if (box_array->length >= realloc_threshold) {
-                capacity += realloc_raise;
-                box_array->boxes = (bounding_box*)realloc(box_array->boxes, capacity * sizeof(bounding_box));
-                realloc_threshold += realloc_raise;
-            }
-            box_array->boxes[box_array->length].word = ri->GetUTF8Text(level);
-            box_array->boxes[box_array->length].confidence = ri->Confidence(level);
-            ri->BoundingBox(level, &box_array->boxes[box_array->length].x1, &box_array->boxes[box_array->length].y1,
-                            &box_array->boxes[box_array->length].x2, &box_array->boxes[box_array->length].y2);
-            box_array->length++;
-        } while (ri->Next(level));
+    try {
+        api->Recognize(NULL);
+        ri = api->GetIterator();
+        if (ri != 0) {
+            do {
+                if (box_array->length >= realloc_threshold) {
+                    capacity += realloc_raise;
+                    box_array->boxes = (bounding_box*)realloc(box_array->boxes, capacity * sizeof(bounding_box));
+                    realloc_threshold += realloc_raise;
+                }
+                box_array->boxes[box_array->length].word = ri->GetUTF8Text(level);
+                box_array->boxes[box_array->length].confidence = ri->Confidence(level);
+                ri->BoundingBox(level, &box_array->boxes[box_array->length].x1, &box_array->boxes[box_array->length].y1,
+                                &box_array->boxes[box_array->length].x2, &box_array->boxes[box_array->length].y2);
+                box_array->length++;
+            } while (ri->Next(level));
+        }
+    } catch (...) {
+        catchException(errbuf);
     }
+    delete ri;
 
     return box_array;
 }
 
+static layout_element layoutElement(tesseract::ResultIterator* it, tesseract::PageIteratorLevel level) {
+    using namespace tesseract;
+    layout_element e;
+    memset(&e, 0, sizeof(e));
+    e.level = level;
+    it->BoundingBox(level, &e.x1, &e.y1, &e.x2, &e.y2);
+    if (level == RIL_WORD) {
+        e.text = it->GetUTF8Text(level);
+        e.confidence = it->Confidence(level);
+        tesseract::Orientation orientation;
+        WritingDirection writing_direction;
+        TextlineOrder textline_order;
+        it->Orientation(&orientation, &writing_direction, &textline_order, &e.deskew_angle);
+        e.orientation = orientation;
+        e.has_baseline = it->Baseline(level, &e.bx1, &e.by1, &e.bx2, &e.by2);
+        // Font attributes are known only to the legacy engine, otherwise NULL is returned.
+        bool bold, italic, underlined, monospace, serif, smallcaps;
+        int pointsize, font_id;
+        if (it->WordFontAttributes(&bold, &italic, &underlined, &monospace, &serif, &smallcaps, &pointsize, &font_id) != NULL) {
+            e.bold = bold;
+        }
+        e.from_dictionary = it->WordIsFromDictionary();
+        e.numeric = it->WordIsNumeric();
+        // Attributes of the first symbol, since tesseract splits superscripts and subscripts into words of their own.
+        e.superscript = it->SymbolIsSuperscript();
+        e.subscript = it->SymbolIsSubscript();
+        e.dropcap = it->SymbolIsDropcap();
+    }
+    if (level == RIL_BLOCK) {
+        e.block_type = it->BlockType();
+        Pta* pta = it->BlockPolygon();
+        if (pta != NULL) {
+            e.polygon_length = ptaGetCount(pta);
+            e.polygon = (int*)malloc(2 * e.polygon_length * sizeof(int));
+            for (int i = 0; i < e.polygon_length; i++) {
+                ptaGetIPt(pta, i, &e.polygon[2 * i], &e.polygon[2 * i + 1]);
+            }
+            ptaDestroy(&pta);
+        }
+    }
+    return e;
+}
+
+// GetLayout walks the result of the last recognition in reading order,
+// emitting each block, paragraph and line followed by its children.
+layout* GetLayout(TessBaseAPI a, char* errbuf) {
+    using namespace tesseract;
+    tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
+    std::vector<layout_element> elements;
+    ResultIterator* res_it = NULL;
+    try {
+        res_it = api->GetIterator();
+        while (res_it != NULL && !res_it->Empty(RIL_BLOCK)) {
+            if (res_it->Empty(RIL_WORD)) {
+                // Blocks of pictures have no words, but are emitted for their position.
+                PolyBlockType type = res_it->BlockType();
+                if (type == PT_FLOWING_IMAGE || type == PT_HEADING_IMAGE || type == PT_PULLOUT_IMAGE) {
+                    elements.push_back(layoutElement(res_it, RIL_BLOCK));
+                }
+                res_it->Next(RIL_WORD);
+                continue;
+            }
+            if (res_it->IsAtBeginningOf(RIL_BLOCK)) {
+                elements.push_back(layoutElement(res_it, RIL_BLOCK));
+            }
+            if (res_it->IsAtBeginningOf(RIL_PARA)) {
+                elements.push_back(layoutElement(res_it, RIL_PARA));
+            }
+            if (res_it->IsAtBeginningOf(RIL_TEXTLINE)) {
+                elements.push_back(layoutElement(res_it, RIL_TEXTLINE));
+            }
+            elements.push_back(layoutElement(res_it, RIL_WORD));
+            res_it->Next(RIL_WORD);
+        }
+    } catch (...) {
+        catchException(errbuf);
+    }
+    delete res_it;
+
+    layout* result = (layout*)malloc(sizeof(layout));
+    result->length = elements.size();
+    result->elements = (layout_element*)malloc((elements.size() + 1) * sizeof(layout_element));
+    for (size_t i = 0; i < elements.size(); i++) {
+        result->elements[i] = elements[i];
+    }
+    return result;
+}
+
+void FreeLayout(layout* l) {
+    for (int i = 0; i < l->length; i++) {
+        delete[] l->elements[i].text;
+        free(l->elements[i].polygon);
+    }
+    free(l->elements);
+    free(l);
+}
+
+// GetSymbols walks the symbols of the last recognition in reading order with their choices,
+// which LSTM reports only if lstm_choice_mode is set before the recognition.
+symbols* GetSymbols(TessBaseAPI a, char* errbuf) {
+    using namespace tesseract;
+    tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
+    std::vector<symbol> elements;
+    ResultIterator* res_it = NULL;
+    int word = -1;
+    try {
+        res_it = api->GetIterator();
+        while (res_it != NULL && !res_it->Empty(RIL_BLOCK)) {
+            if (res_it->Empty(RIL_SYMBOL)) {
+                res_it->Next(RIL_SYMBOL);
+                continue;
+            }
+            if (res_it->IsAtBeginningOf(RIL_WORD)) {
+                word++;
+            }
+            symbol s;
+            memset(&s, 0, sizeof(s));
+            s.word = word;
+            res_it->BoundingBox(RIL_SYMBOL, &s.x1, &s.y1, &s.x2, &s.y2);
+            std::vector<symbol_choice> choices;
+            char* best = res_it->GetUTF8Text(RIL_SYMBOL);
+            if (best != NULL) {
+                symbol_choice choice = {strdup(best), res_it->Confidence(RIL_SYMBOL)};
+                choices.push_back(choice);
+                delete[] best;
+            }
+            ChoiceIterator ci(*res_it);
+            do {
+                const char* text = ci.GetUTF8Text();
+                if (text == NULL || (!choices.empty() && strcmp(text, choices[0].text) == 0)) {
+                    continue;
+                }
+                symbol_choice choice = {strdup(text), ci.Confidence()};
+                choices.push_back(choice);
+            } while (ci.Next());
+            s.length = choices.size();
+            s.choices = (symbol_choice*)malloc((choices.size() + 1) * sizeof(symbol_choice));
+            for (size_t i = 0; i < choices.size(); i++) {
+                s.choices[i] = choices[i];
+            }
+            elements.push_back(s);
+            res_it->Next(RIL_SYMBOL);
+        }
+    } catch (...) {
+        catchException(errbuf);
+    }
+    delete res_it;
+
+    symbols* result = (symbols*)malloc(sizeof(symbols));
+    result->length = elements.size();
+    result->symbols = (symbol*)malloc((elements.size() + 1) * sizeof(symbol));
+    for (size_t i = 0; i < elements.size(); i++) {
+        result->symbols[i] = elements[i];
+    }
+    return result;
+}
+
+void FreeSymbols(symbols* s) {
+    for (int i = 0; i < s->length; i++) {
+        for (int j = 0; j < s->symbols[i].length; j++) {
+            free(s->symbols[i].choices[j].text);
+        }
+        free(s->symbols[i].choices);
+    }
+    free(s->symbols);
+    free(s);
+}
+
+Iterator GetResultIterator(TessBaseAPI a, char* errbuf) {
+    tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
+    try {
+        return (void*)api->GetIterator();
+    } catch (...) {
+        catchException(errbuf);
+    }
+    return NULL;
+}
+
+bool ResultIteratorNext(Iterator it, int level) {
+    tesseract::ResultIterator* ri = (tesseract::ResultIterator*)it;
+    return ri->Next((tesseract::PageIteratorLevel)level);
+}
+
+// ResultIteratorText returns the text of the element at the level, which MUST be freed by free.
+char* ResultIteratorText(Iterator it, int level, char* errbuf) {
+    tesseract::ResultIterator* ri = (tesseract::ResultIterator*)it;
+    char* copied = NULL;
+    try {
+        char* text = ri->GetUTF8Text((tesseract::PageIteratorLevel)level);
+        if (text != NULL) {
+            copied = strdup(text);
+            delete[] text;
+        }
+    } catch (...) {
+        catchException(errbuf);
+    }
+    return copied;
+}
+
+float ResultIteratorConfidence(Iterator it, int level) {
+    tesseract::ResultIterator* ri = (tesseract::ResultIterator*)it;
+    return ri->Confidence((tesseract::PageIteratorLevel)level);
+}
+
+bool ResultIteratorBoundingBox(Iterator it, int level, int* x1, int* y1, int* x2, int* y2) {
+    tesseract::ResultIterator* ri = (tesseract::ResultIterator*)it;
+    return ri->BoundingBox((tesseract::PageIteratorLevel)level, x1, y1, x2, y2);
+}
+
+void DeleteResultIterator(Iterator it) {
+    delete (tesseract::ResultIterator*)it;
+}
+
 const char* Version(TessBaseAPI a) {
     tesseract::TessBaseAPI* api = (tesseract::TessBaseAPI*)a;
     const char* v = api->Version();
@@ -218,11 +592,468 @@ PixImage CreatePixImageFromBytes(unsigned char* data, int size) {
     return (void*)image;
 }
 
+// ReadPixImageHeader reads the dimensions of the image data from its header without decoding the pixels,
+// returning 0 on success, or 1 for data of unknown formats or malformed headers.
+int ReadPixImageHeader(unsigned char* data, int size, int* width, int* height) {
+    l_int32 format, w, h, bps, spp, iscmap;
+    if (pixReadHeaderMem(data, (size_t)size, &format, &w, &h, &bps, &spp, &iscmap) != 0) {
+        return 1;
+    }
+    *width = w;
+    *height = h;
+    return 0;
+}
+
+int ReadPixImageHeaderByFilePath(char* imagepath, int* width, int* height) {
+    l_int32 format, w, h, bps, spp, iscmap;
+    if (pixReadHeader(imagepath, &format, &w, &h, &bps, &spp, &iscmap) != 0) {
+        return 1;
+    }
+    *width = w;
+    *height = h;
+    return 0;
+}
+
 void DestroyPixImage(PixImage pix) {
     Pix* img = (Pix*)pix;
     pixDestroy(&img);
 }
 
+int PixImageWidth(PixImage pix) {
+    return pixGetWidth((Pix*)pix);
+}
+
+int PixImageHeight(PixImage pix) {
+    return pixGetHeight((Pix*)pix);
+}
+
+long PixImageBytes(PixImage pix) {
+    Pix* img = (Pix*)pix;
+    return 4L * pixGetWpl(img) * pixGetHeight(img);
+}
+
+// PixImageEntropy returns the Shannon entropy of the gray levels of the image in bits,
+// sampling a million pixels at most, which is plenty for the histogram.
+double PixImageEntropy(PixImage pix) {
+    Pix* src = (Pix*)pix;
+    if (src == NULL) {
+        return 0;
+    }
+    Pix* gray = pixConvertTo8(src, 0);
+    if (gray == NULL) {
+        return 0;
+    }
+    int w = pixGetWidth(gray), h = pixGetHeight(gray);
+    int step = (int)sqrt((double)w * h / 1000000.0) + 1;
+    double histogram[256] = {0};
+    double total = 0;
+    for (int y = 0; y < h; y += step) {
+        for (int x = 0; x < w; x += step) {
+            l_uint32 value = 0;
+            pixGetPixel(gray, x, y, &value);
+            histogram[value & 0xff]++;
+            total++;
+        }
+    }
+    pixDestroy(&gray);
+    double entropy = 0;
+    for (int i = 0; i < 256; i++) {
+        if (histogram[i] > 0) {
+            double p = histogram[i] / total;
+            entropy -= p * log2(p);
+        }
+    }
+    return entropy;
+}
+
+PixImage ScalePixImage(PixImage pix, float scale) {
+    Pix* src = (Pix*)pix;
+    if (src == NULL) {
+        return NULL;
+    }
+    // Leptonica scales the resolution along with the pixels,
+    // so that tesseract still knows the size of characters in points.
+    Pix* scaled = pixScale(src, scale, scale);
+    return (void*)scaled;
+}
+
+PixImage RectifyPixImage(PixImage pix) {
+    Pix* src = (Pix*)pix;
+    if (src == NULL) {
+        return NULL;
+    }
+    int w = pixGetWidth(src);
+    int h = pixGetHeight(src);
+
+    // Separate the page, which is brighter than its background, by global Otsu threshold,
+    // and take the largest connected component as the document.
+    Pix* gray = pixConvertTo8(src, 0);
+    if (gray == NULL) {
+        return NULL;
+    }
+    Pix* binary = NULL;
+    pixOtsuAdaptiveThreshold(gray, w, h, 0, 0, 0.0, NULL, &binary);
+    pixDestroy(&gray);
+    if (binary == NULL) {
+        return NULL;
+    }
+    pixInvert(binary, binary);
+    Pix* mask = pixOpenBrick(NULL, binary, 5, 5);
+    pixDestroy(&binary);
+    if (mask == NULL) {
+        return NULL;
+    }
+    Pixa* pixa = NULL;
+    Boxa* boxa = pixConnComp(mask, &pixa, 8);
+    pixDestroy(&mask);
+    if (boxa == NULL) {
+        return NULL;
+    }
+    int largest = -1, bx = 0, by = 0, bw = 0, bh = 0;
+    for (int i = 0; i < boxaGetCount(boxa); i++) {
+        int x, y, cw, ch;
+        boxaGetBoxGeometry(boxa, i, &x, &y, &cw, &ch);
+        if (largest < 0 || cw * ch > bw * bh) {
+            largest = i;
+            bx = x, by = y, bw = cw, bh = ch;
+        }
+    }
+    boxaDestroy(&boxa);
+    // Too small to be a photographed page.
+    if (largest < 0 || (double)bw * bh < 0.2 * w * h) {
+        pixaDestroy(&pixa);
+        return NULL;
+    }
+
+    // Find the corners as the extreme points of the component along both diagonals.
+    Pix* comp = pixaGetPix(pixa, largest, L_CLONE);
+    pixaDestroy(&pixa);
+    l_uint32* data = pixGetData(comp);
+    int wpl = pixGetWpl(comp);
+    int tl[2] = {0, 0}, tr[2] = {0, 0}, br[2] = {0, 0}, bl[2] = {0, 0};
+    bool found = false;
+    for (int y = 0; y < bh; y++) {
+        l_uint32* line = data + y * wpl;
+        for (int x = 0; x < bw; x++) {
+            if (((line[x >> 5] >> (31 - (x & 31))) & 1) == 0) {
+                continue;
+            }
+            int px = bx + x, py = by + y;
+            if (!found) {
+                tl[0] = tr[0] = br[0] = bl[0] = px;
+                tl[1] = tr[1] = br[1] = bl[1] = py;
+                found = true;
+                continue;
+            }
+            if (px + py < tl[0] + tl[1]) tl[0] = px, tl[1] = py;
+            if (px + py > br[0] + br[1]) br[0] = px, br[1] = py;
+            if (px - py > tr[0] - tr[1]) tr[0] = px, tr[1] = py;
+            if (px - py < bl[0] - bl[1]) bl[0] = px, bl[1] = py;
+        }
+    }
+    pixDestroy(&comp);
+    if (!found) {
+        return NULL;
+    }
+
+    // Nothing to do if the document already fills the image.
+    int tx = w / 50, ty = h / 50;
+    if (tl[0] <= tx && tl[1] <= ty && tr[0] >= w - 1 - tx && tr[1] <= ty && br[0] >= w - 1 - tx &&
+        br[1] >= h - 1 - ty && bl[0] <= tx && bl[1] >= h - 1 - ty) {
+        return NULL;
+    }
+
+    double top = hypot(tr[0] - tl[0], tr[1] - tl[1]), bottom = hypot(br[0] - bl[0], br[1] - bl[1]);
+    double left = hypot(bl[0] - tl[0], bl[1] - tl[1]), right = hypot(br[0] - tr[0], br[1] - tr[1]);
+    double dw = top > bottom ? top : bottom;
+    double dh = left > right ? left : right;
+    // The warped image keeps the size of the source, so shrink the destination to fit in it.
+    double fit = 1.0;
+    if (dw > w) fit = w / dw;
+    if (dh * fit > h) fit = h / dh;
+    dw *= fit, dh *= fit;
+    if (dw < 32 || dh < 32) {
+        return NULL;
+    }
+
+    Pta* ptas = ptaCreate(4);
+    ptaAddPt(ptas, tl[0], tl[1]);
+    ptaAddPt(ptas, tr[0], tr[1]);
+    ptaAddPt(ptas, br[0], br[1]);
+    ptaAddPt(ptas, bl[0], bl[1]);
+    Pta* ptad = ptaCreate(4);
+    ptaAddPt(ptad, 0, 0);
+    ptaAddPt(ptad, dw - 1, 0);
+    ptaAddPt(ptad, dw - 1, dh - 1);
+    ptaAddPt(ptad, 0, dh - 1);
+    Pix* warped = pixProjectivePta(src, ptad, ptas, L_BRING_IN_WHITE);
+    ptaDestroy(&ptas);
+    ptaDestroy(&ptad);
+    if (warped == NULL) {
+        return NULL;
+    }
+    Box* box = boxCreate(0, 0, (int)dw, (int)dh);
+    Pix* rectified = pixClipRectangle(warped, box, NULL);
+    boxDestroy(&box);
+    pixDestroy(&warped);
+    if (rectified != NULL) {
+        pixCopyResolution(rectified, src);
+    }
+    return (void*)rectified;
+}
+
+PixImage NormalizeBackgroundPixImage(PixImage pix) {
+    Pix* src = (Pix*)pix;
+    if (src == NULL || pixGetDepth(src) == 1) {
+        return NULL;
+    }
+    // Background normalization accepts only 8 bpp grayscale or 32 bpp RGB without colormap.
+    Pix* base = pixGetDepth(src) == 32 ? pixClone(src) : pixConvertTo8(src, 0);
+    if (base == NULL) {
+        return NULL;
+    }
+    Pix* normalized = pixBackgroundNormSimple(base, NULL, NULL);
+    pixDestroy(&base);
+    if (normalized != NULL) {
+        pixCopyResolution(normalized, src);
+    }
+    return (void*)normalized;
+}
+
+PixImage ContrastNormalizePixImage(PixImage pix) {
+    Pix* src = (Pix*)pix;
+    if (src == NULL || pixGetDepth(src) == 1) {
+        return NULL;
+    }
+    Pix* gray = pixConvertTo8(src, 0);
+    if (gray == NULL) {
+        return NULL;
+    }
+    // Tiles of 10x10 pixels, skipping tiles with less difference than 40 as flat background.
+    Pix* normalized = pixContrastNorm(NULL, gray, 10, 10, 40, 2, 2);
+    pixDestroy(&gray);
+    if (normalized != NULL) {
+        pixCopyResolution(normalized, src);
+    }
+    return (void*)normalized;
+}
+
+PixImage SauvolaBinarizePixImage(PixImage pix) {
+    Pix* src = (Pix*)pix;
+    if (src == NULL || pixGetDepth(src) == 1) {
+        return NULL;
+    }
+    Pix* gray = pixConvertTo8(src, 0);
+    if (gray == NULL) {
+        return NULL;
+    }
+    // Same window (0.33 inch) and k factor (0.34) as tesseract 5 uses by default.
+    int res = pixGetYRes(src) < 70 ? 300 : pixGetYRes(src);
+    int whsize = (int)(0.33 * res / 2);
+    if (whsize < 7) {
+        whsize = 7;
+    }
+    Pix* binary = NULL;
+    pixSauvolaBinarize(gray, whsize, 0.34, 1, NULL, NULL, NULL, &binary);
+    pixDestroy(&gray);
+    if (binary != NULL) {
+        pixCopyResolution(binary, src);
+    }
+    return (void*)binary;
+}
+
+PixImage OtsuBinarizePixImage(PixImage pix) {
+    Pix* src = (Pix*)pix;
+    if (src == NULL || pixGetDepth(src) == 1) {
+        return NULL;
+    }
+    Pix* gray = pixConvertTo8(src, 0);
+    if (gray == NULL) {
+        return NULL;
+    }
+    // A single tile of the whole image, i.e. the global Otsu threshold as tesseract computes.
+    Pix* binary = NULL;
+    pixOtsuAdaptiveThreshold(gray, pixGetWidth(gray), pixGetHeight(gray), 0, 0, 0.0, NULL, &binary);
+    pixDestroy(&gray);
+    if (binary != NULL) {
+        pixCopyResolution(binary, src);
+    }
+    return (void*)binary;
+}
+
+// RemoveGridLinesPixImage erases long horizontal and vertical rules, such as those of tables and forms,
+// from the grayscale of the image, and returns the boxes of the rules erased as x1, y1, x2, y2 in lines,
+// which the caller must free.
+PixImage RemoveGridLinesPixImage(PixImage pix, int** lines, int* count) {
+    *lines = NULL;
+    *count = 0;
+    Pix* src = (Pix*)pix;
+    if (src == NULL) {
+        return NULL;
+    }
+    Pix* gray = pixConvertTo8(src, 0);
+    if (gray == NULL) {
+        return NULL;
+    }
+    int w = pixGetWidth(gray);
+    int h = pixGetHeight(gray);
+    Pix* binary = NULL;
+    pixOtsuAdaptiveThreshold(gray, w, h, 0, 0, 0.0, NULL, &binary);
+    if (binary == NULL) {
+        pixDestroy(&gray);
+        return NULL;
+    }
+    // Rules are runs of ink far longer than strokes of characters, i.e. 1/15 of the page at least.
+    int hlen = w / 15 > 20 ? w / 15 : 20;
+    int vlen = h / 15 > 20 ? h / 15 : 20;
+    Pix* horizontal = pixOpenBrick(NULL, binary, hlen, 1);
+    Pix* vertical = pixOpenBrick(NULL, binary, 1, vlen);
+    pixDestroy(&binary);
+    Pix* rules = pixOr(NULL, horizontal, vertical);
+    pixDestroy(&horizontal);
+    pixDestroy(&vertical);
+    if (rules == NULL) {
+        pixDestroy(&gray);
+        return NULL;
+    }
+    Boxa* boxa = pixConnCompBB(rules, 8);
+    int n = boxa != NULL ? boxaGetCount(boxa) : 0;
+    if (n == 0) {
+        boxaDestroy(&boxa);
+        pixDestroy(&rules);
+        pixDestroy(&gray);
+        return NULL;
+    }
+    *lines = (int*)malloc(4 * n * sizeof(int));
+    for (int i = 0; i < n; i++) {
+        int x, y, bw, bh;
+        boxaGetBoxGeometry(boxa, i, &x, &y, &bw, &bh);
+        (*lines)[4 * i] = x;
+        (*lines)[4 * i + 1] = y;
+        (*lines)[4 * i + 2] = x + bw;
+        (*lines)[4 * i + 3] = y + bh;
+    }
+    *count = n;
+    boxaDestroy(&boxa);
+    // Widen the rules a little to erase their anti-aliased edges too.
+    Pix* mask = pixDilateBrick(NULL, rules, 3, 3);
+    pixDestroy(&rules);
+    pixSetMasked(gray, mask, 255);
+    pixDestroy(&mask);
+    pixCopyResolution(gray, src);
+    return (void*)gray;
+}
+
+// JoinDotsPixImage binarizes the image and joins the dots of dot-matrix prints into strokes,
+// by closing with a brick as large as the typical dot, i.e. the median size of the connected components.
+PixImage JoinDotsPixImage(PixImage pix) {
+    Pix* src = (Pix*)pix;
+    if (src == NULL) {
+        return NULL;
+    }
+    Pix* binary = NULL;
+    if (pixGetDepth(src) == 1) {
+        binary = pixClone(src);
+    } else {
+        Pix* gray = pixConvertTo8(src, 0);
+        if (gray == NULL) {
+            return NULL;
+        }
+        pixOtsuAdaptiveThreshold(gray, pixGetWidth(gray), pixGetHeight(gray), 0, 0, 0.0, NULL, &binary);
+        pixDestroy(&gray);
+    }
+    if (binary == NULL) {
+        return NULL;
+    }
+    Boxa* boxa = pixConnCompBB(binary, 8);
+    int n = boxa != NULL ? boxaGetCount(boxa) : 0;
+    std::vector<int> sizes;
+    for (int i = 0; i < n; i++) {
+        int x, y, w, h;
+        boxaGetBoxGeometry(boxa, i, &x, &y, &w, &h);
+        sizes.push_back(w > h ? w : h);
+    }
+    boxaDestroy(&boxa);
+    if (sizes.empty()) {
+        pixDestroy(&binary);
+        return NULL;
+    }
+    std::nth_element(sizes.begin(), sizes.begin() + sizes.size() / 2, sizes.end());
+    int size = sizes[sizes.size() / 2];
+    // Gaps between dots are about as wide as dots, and diagonal ones 1.4 times, while gaps between characters
+    // are a column of dots missing, i.e. twice as wide at least, which the brick doesn't bridge.
+    size = size < 2 ? 2 : (size > 16 ? 16 : size);
+    int brick = size + size / 2 + 1;
+    Pix* joined = pixCloseBrick(NULL, binary, brick, brick);
+    pixDestroy(&binary);
+    if (joined != NULL) {
+        pixCopyResolution(joined, src);
+    }
+    return (void*)joined;
+}
+
+// JoinSegmentsPixImage binarizes the image of seven-segment digits, inverting light digits on dark displays such as LEDs,
+// and joins the segments of each digit, by closing with a brick as tall as the half of the typical segment,
+// i.e. the median length of the connected components, and barely wider, not to join neighbouring digits.
+PixImage JoinSegmentsPixImage(PixImage pix) {
+    Pix* src = (Pix*)pix;
+    if (src == NULL) {
+        return NULL;
+    }
+    Pix* binary = NULL;
+    if (pixGetDepth(src) == 1) {
+        binary = pixCopy(NULL, src);
+    } else {
+        Pix* gray = pixConvertTo8(src, 0);
+        if (gray == NULL) {
+            return NULL;
+        }
+        pixOtsuAdaptiveThreshold(gray, pixGetWidth(gray), pixGetHeight(gray), 0, 0, 0.0, NULL, &binary);
+        pixDestroy(&gray);
+    }
+    if (binary == NULL) {
+        return NULL;
+    }
+    // Digits cover less than the half of displays, so the majority of foreground is the lit background.
+    l_int32 count = 0;
+    pixCountPixels(binary, &count, NULL);
+    if ((long)count * 2 > (long)pixGetWidth(binary) * pixGetHeight(binary)) {
+        pixInvert(binary, binary);
+    }
+    Boxa* boxa = pixConnCompBB(binary, 8);
+    int n = boxa != NULL ? boxaGetCount(boxa) : 0;
+    std::vector<int> sizes;
+    for (int i = 0; i < n; i++) {
+        int x, y, w, h;
+        boxaGetBoxGeometry(boxa, i, &x, &y, &w, &h);
+        sizes.push_back(w > h ? w : h);
+    }
+    boxaDestroy(&boxa);
+    if (sizes.empty()) {
+        pixDestroy(&binary);
+        return NULL;
+    }
+    std::nth_element(sizes.begin(), sizes.begin() + sizes.size() / 2, sizes.end());
+    int length = sizes[sizes.size() / 2];
+    // Gaps between segments are a fraction of their length, at the corners of digits mostly vertical,
+    // while digits are apart by the half of the length at least.
+    int height = length / 2 < 3 ? 3 : length / 2;
+    int width = length / 5 < 2 ? 2 : length / 5;
+    Pix* joined = pixCloseBrick(NULL, binary, width, height);
+    pixDestroy(&binary);
+    if (joined != NULL) {
+        pixCopyResolution(joined, src);
+    }
+    return (void*)joined;
+}
+
+// OpenCLAvailable reports whether tesseract is built with OpenCL and has selected an OpenCL device,
+// rather than the native CPU. getOpenCLDevice returns 0 if tesseract is built without OpenCL.
+bool OpenCLAvailable() {
+    void* device = NULL;
+    return tesseract::TessBaseAPI::getOpenCLDevice(&device) > 0 && device != NULL;
+}
+
 const char* GetDataPath() {
     static tesseract::TessBaseAPI api;
     api.Init(nullptr, nullptr);
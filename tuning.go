@@ -0,0 +1,215 @@
+package gosseract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Tuning coordinates the concurrency of tesseract, i.e. OpenMP threads within each recognition,
+// with the number of clients recognizing in parallel, such as 1 thread × N clients for throughput of batches,
+// or 4 threads × N/4 clients for latency of large pages. Which is faster depends on the machine and the pages,
+// see AutoTune.
+type Tuning struct {
+
+	// Clients is the number of Recognizers of Pool. Zero means runtime.NumCPU() divided by Threads.
+	Clients int `json:"clients"`
+
+	// Threads is the number of OpenMP threads of each recognition, i.e. OMP_THREAD_LIMIT.
+	// OpenMP runtimes read it once by the process, so it must be set in the environment of the process,
+	// see Tuning.Environ, and Config.BuildPool fails if it's not. Zero means OMP_THREAD_LIMIT as it is.
+	Threads int `json:"threads"`
+}
+
+// ThreadLimit returns OMP_THREAD_LIMIT of the process, or zero if it's not set.
+func ThreadLimit() int {
+	n, err := strconv.Atoi(os.Getenv("OMP_THREAD_LIMIT"))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Environ returns the environment variables to run processes of this tuning, such as exec.Cmd.Env,
+// Dockerfiles and systemd units.
+func (t Tuning) Environ() []string {
+	if t.Threads == 0 {
+		return nil
+	}
+	return []string{"OMP_THREAD_LIMIT=" + strconv.Itoa(t.Threads)}
+}
+
+func (t Tuning) String() string {
+	return fmt.Sprintf("%d threads × %d clients", t.Threads, t.Clients)
+}
+
+// resolve fills zero fields by defaults.
+func (t Tuning) resolve() Tuning {
+	if t.Threads == 0 {
+		t.Threads = ThreadLimit()
+	}
+	if t.Clients == 0 {
+		t.Clients = runtime.NumCPU()
+		if t.Threads > 1 {
+			t.Clients /= t.Threads
+		}
+		if t.Clients < 1 {
+			t.Clients = 1
+		}
+	}
+	return t
+}
+
+func (t Tuning) check() error {
+	if t.Clients < 1 {
+		return fmt.Errorf("invalid tuning of %d clients", t.Clients)
+	}
+	if limit := ThreadLimit(); t.Threads != limit {
+		return fmt.Errorf("tuning of %d threads needs OMP_THREAD_LIMIT=%d in the environment of the process, but it's %q",
+			t.Threads, t.Threads, os.Getenv("OMP_THREAD_LIMIT"))
+	}
+	return nil
+}
+
+// TuningCandidates returns the tunings using all the CPUs, i.e. 1 thread × cpus clients, 2 threads × cpus/2 clients,
+// and so on up to 4 threads, beyond which tesseract hardly parallelizes.
+func TuningCandidates(cpus int) []Tuning {
+	candidates := []Tuning{}
+	for threads := 1; threads <= 4 && (threads == 1 || threads <= cpus); threads *= 2 {
+		clients := cpus / threads
+		if clients < 1 {
+			clients = 1
+		}
+		candidates = append(candidates, Tuning{Clients: clients, Threads: threads})
+	}
+	return candidates
+}
+
+// TuningResult is the throughput of a tuning measured by AutoTune.
+type TuningResult struct {
+	Tuning         Tuning        `json:"tuning"`
+	Pages          int           `json:"pages"`
+	Elapsed        time.Duration `json:"elapsed"`
+	PagesPerSecond float64       `json:"pages_per_second"`
+}
+
+// AutoTune benchmarks the candidates, TuningCandidates(runtime.NumCPU()) if nil, on this machine,
+// recognizing the sample pages by Pool of cfg with each tuning, and returns the fastest tuning and the results of all.
+// Since OMP_THREAD_LIMIT can't change within a process, each candidate is benchmarked by a child process
+// of the executable running AutoTune, which must call RunAutoTuneChild first in main to run the benchmark.
+// Samples should be typical pages of the workload, and at least a few.
+func AutoTune(ctx context.Context, cfg Config, samples [][]byte, candidates []Tuning) (Tuning, []TuningResult, error) {
+	if len(samples) == 0 {
+		return Tuning{}, nil, fmt.Errorf("no sample pages to tune by")
+	}
+	if candidates == nil {
+		candidates = TuningCandidates(runtime.NumCPU())
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return Tuning{}, nil, err
+	}
+	results := []TuningResult{}
+	best := TuningResult{}
+	for _, tuning := range candidates {
+		cfg.Tuning = tuning
+		input, err := json.Marshal(autoTuneRequest{Config: cfg, Samples: samples})
+		if err != nil {
+			return Tuning{}, nil, err
+		}
+		cmd := exec.CommandContext(ctx, executable)
+		cmd.Env = append(append(os.Environ(), autoTuneEnv+"=1"), tuning.Environ()...)
+		cmd.Stdin = bytes.NewReader(input)
+		stderr := bytes.NewBuffer(nil)
+		cmd.Stderr = stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return Tuning{}, results, fmt.Errorf("failed to benchmark %v: %v: %s", tuning, err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		result := TuningResult{}
+		if err := json.Unmarshal(output, &result); err != nil {
+			return Tuning{}, results, fmt.Errorf("failed to benchmark %v, see RunAutoTuneChild: %v", tuning, err)
+		}
+		results = append(results, result)
+		if result.PagesPerSecond > best.PagesPerSecond {
+			best = result
+		}
+	}
+	return best.Tuning, results, nil
+}
+
+// autoTuneEnv marks the child processes of AutoTune.
+const autoTuneEnv = "GOSSERACT_AUTOTUNE"
+
+type autoTuneRequest struct {
+	Config  Config   `json:"config"`
+	Samples [][]byte `json:"samples"`
+}
+
+// RunAutoTuneChild runs the benchmark requested by AutoTune and exits, if the process is a child of AutoTune,
+// and returns at once otherwise. Executables calling AutoTune must call it first in main, before parsing flags
+// or any other work, since the children are the executable itself, see the command gosseract with -autotune.
+func RunAutoTuneChild() {
+	if os.Getenv(autoTuneEnv) == "" {
+		return
+	}
+	if err := autoTuneChild(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// autoTuneChild benchmarks the tuning requested by AutoTune through stdin, writing the result to stdout.
+func autoTuneChild() error {
+	req := autoTuneRequest{}
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		return err
+	}
+	pool, err := req.Config.BuildPool()
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	result, err := benchmarkPool(context.Background(), pool, req.Samples)
+	if err != nil {
+		return err
+	}
+	result.Tuning = req.Config.Tuning.resolve()
+	return json.NewEncoder(os.Stdout).Encode(result)
+}
+
+// benchmarkPool recognizes the samples in parallel by the pool, repeated to keep all the Recognizers busy twice at least.
+func benchmarkPool(ctx context.Context, pool *Pool, samples [][]byte) (TuningResult, error) {
+	pages := len(samples)
+	if pages < 2*pool.Size() {
+		pages = 2 * pool.Size()
+	}
+	errs := make(chan error, pages)
+	wg := sync.WaitGroup{}
+	start := time.Now()
+	for i := 0; i < pages; i++ {
+		wg.Add(1)
+		go func(data []byte) {
+			defer wg.Done()
+			_, err := pool.TextWithOptions(ctx, data, Options{})
+			errs <- err
+		}(samples[i%len(samples)])
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			return TuningResult{}, err
+		}
+	}
+	return TuningResult{Pages: pages, Elapsed: elapsed, PagesPerSecond: float64(pages) / elapsed.Seconds()}, nil
+}
@@ -0,0 +1,37 @@
+package gosseract
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"sync"
+)
+
+var (
+	warmupOnce sync.Once
+	warmupData []byte
+)
+
+// warmupImage returns a tiny PNG image used by Warmup.
+// It has a few glyph-like bars, so that tesseract runs through layout analysis and recognition,
+// not just giving up on an empty page.
+func warmupImage() []byte {
+	warmupOnce.Do(func() {
+		img := image.NewGray(image.Rect(0, 0, 96, 32))
+		for i := range img.Pix {
+			img.Pix[i] = 0xff
+		}
+		for _, x := range []int{12, 30, 48, 66} {
+			for y := 8; y < 24; y++ {
+				for dx := 0; dx < 4; dx++ {
+					img.SetGray(x+dx, y, color.Gray{Y: 0})
+				}
+			}
+		}
+		buf := bytes.NewBuffer(nil)
+		png.Encode(buf, img)
+		warmupData = buf.Bytes()
+	})
+	return warmupData
+}
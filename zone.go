@@ -0,0 +1,58 @@
+package gosseract
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/chennqqi/gosseract/v2/geometry"
+)
+
+// Zone is a region of the image to recognize by Client.TextOfZones, such as a field of a template of forms.
+type Zone struct {
+	Name string
+	// Rect is the region on the image set, which is clipped to the image.
+	Rect image.Rectangle
+
+	// Options overrides the configuration for the zone, such as PSM_SINGLE_LINE, Whitelist and Constraint,
+	// see Client.TextWithOptions. Languages are ignored. Zones share the thresholded image, so those overriding
+	// Preprocess or the variables of thresholding, such as THRESHOLDING_METHOD, fail.
+	Options Options
+}
+
+// thresholdingVariables are the variables the thresholded image depends on, which zones can't override.
+var thresholdingVariables = []SettableVariable{
+	THRESHOLDING_METHOD,
+	"thresholding_window_size",
+	"thresholding_kfactor",
+	"thresholding_tile_size",
+	"thresholding_smooth_kernel_size",
+	"thresholding_score_fraction",
+}
+
+// check returns the error of the options of the zone which would change the thresholded image shared.
+func (zone Zone) check() error {
+	if zone.Options.Preprocess != nil {
+		return fmt.Errorf("zone %q can't override Preprocess of the image shared by zones", zone.Name)
+	}
+	for _, key := range thresholdingVariables {
+		if _, ok := zone.Options.Variables[key]; ok {
+			return fmt.Errorf("zone %q can't override %s of the image shared by zones", zone.Name, key)
+		}
+	}
+	return nil
+}
+
+// ZoneOf returns the zone of the region, such as of a template of forms or of a detection, named by its label.
+// Zones are rectangular, so regions rotated or polygonal are recognized by their bounds.
+func ZoneOf(region geometry.LabeledRegion, opts Options) Zone {
+	return Zone{Name: region.Label, Rect: region.Bounds(), Options: opts}
+}
+
+// ZoneText is the text of a zone recognized.
+type ZoneText struct {
+	Name       string
+	Text       string
+	Confidence float64
+	// Err is why the zone isn't recognized, such as ErrNoMatch for its Constraint, while the others are.
+	Err error
+}